@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"shanraq.com/internal/services/fx"
+)
+
+func main() {
+	var (
+		databaseURL = flag.String("database", os.Getenv("DATABASE_URL"), "PostgreSQL connection string")
+		file        = flag.String("file", "", "Rate file to load (.csv or ECB eurofxref .xml)")
+		source      = flag.String("source", "", "Source label stored with each rate (defaults to the file name)")
+		dryRun      = flag.Bool("dry-run", false, "Parse and print rates without writing to the database")
+	)
+
+	flag.Parse()
+
+	if *file == "" {
+		log.Fatal("rate file is required (-file)")
+	}
+
+	rates, err := fx.LoadFile(*file)
+	if err != nil {
+		log.Fatalf("load rates: %v", err)
+	}
+	if *source != "" {
+		for idx := range rates {
+			rates[idx].Source = *source
+		}
+	}
+
+	if *dryRun {
+		for _, r := range rates {
+			log.Printf("%s %.6f per %s (as of %s)", r.Currency, r.PerBase, fx.BaseCurrency, r.AsOf.Format("2006-01-02"))
+		}
+		log.Printf("parsed %d rates", len(rates))
+		return
+	}
+
+	if *databaseURL == "" {
+		log.Fatal("database URL is required (-database or DATABASE_URL)")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	db, err := sql.Open("pgx", *databaseURL)
+	if err != nil {
+		log.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+
+	svc, err := fx.NewSQLService(db)
+	if err != nil {
+		log.Fatalf("init fx service: %v", err)
+	}
+	count, err := svc.Upsert(ctx, rates)
+	if err != nil {
+		log.Fatalf("store rates: %v", err)
+	}
+
+	log.Printf("loaded %d rates from %s", count, *file)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/google/uuid"
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"shanraq.com/internal/config"
+	"shanraq.com/internal/importer"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("load config: %v", err)
+	}
+
+	var (
+		databaseURL = flag.String("database", os.Getenv("DATABASE_URL"), "PostgreSQL connection string")
+		file        = flag.String("file", "", "Listing feed to import (.csv or RESO .json)")
+		agency      = flag.String("agency", "", "Agency ID the listings belong to")
+		format      = flag.String("format", "", "Feed format: csv or reso (detected from the file name by default)")
+		publish     = flag.Bool("publish", false, "Publish newly created listings instead of leaving them as drafts")
+		actor       = flag.String("actor", "", "E-mail recorded on the status history when publishing")
+		chunkSize   = flag.Int("chunk-size", cfg.Seed.ChunkSize, "Rows resolved per batch (defaults to SEED_CHUNK_SIZE)")
+		reportPath  = flag.String("report", "", "Write the JSON row report to this file")
+		dryRun      = flag.Bool("dry-run", false, "Parse and validate the feed without writing to the database")
+	)
+
+	flag.Parse()
+
+	if *file == "" {
+		log.Fatal("feed file is required (-file)")
+	}
+	feedFormat, err := importer.DetectFormat(*file, "")
+	if *format != "" {
+		feedFormat, err = importer.ParseFormat(*format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("open feed: %v", err)
+	}
+	defer f.Close()
+
+	if *dryRun {
+		rows, rejected, err := importer.Parse(f, feedFormat)
+		if err != nil {
+			log.Fatalf("parse feed: %v", err)
+		}
+		for _, row := range rejected {
+			log.Printf("line %d (%s): %s", row.Line, row.Ref, row.Error)
+		}
+		log.Printf("parsed %d rows, %d rejected", len(rows), len(rejected))
+		return
+	}
+
+	agencyID, err := uuid.Parse(*agency)
+	if err != nil {
+		log.Fatal("agency ID is required (-agency)")
+	}
+	if *databaseURL == "" {
+		log.Fatal("database URL is required (-database or DATABASE_URL)")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Seed.Timeout)
+	defer cancel()
+
+	db, err := sql.Open("pgx", *databaseURL)
+	if err != nil {
+		log.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+
+	listings, err := listingservice.NewSQLService(db)
+	if err != nil {
+		log.Fatalf("init listing service: %v", err)
+	}
+	report, err := importer.New(listings, *chunkSize).Import(ctx, agencyID, f, feedFormat, importer.Options{Publish: *publish, ActorEmail: *actor})
+	if err != nil {
+		log.Fatalf("import listings: %v", err)
+	}
+
+	for _, row := range report.Errors() {
+		log.Printf("line %d (%s): %s", row.Line, row.Ref, row.Error)
+	}
+	if *reportPath != "" {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			log.Fatalf("encode report: %v", err)
+		}
+		if err := os.WriteFile(*reportPath, out, 0o644); err != nil {
+			log.Fatalf("write report: %v", err)
+		}
+	}
+	log.Printf("imported %s: %d created, %d updated, %d failed", *file, report.Created, report.Updated, report.Failed)
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -16,10 +16,23 @@ import (
 	"shanraq.com/internal/database"
 	"shanraq.com/internal/httpserver"
 	"shanraq.com/internal/logging"
+	"shanraq.com/internal/notify"
 	agencyservice "shanraq.com/internal/services/agency"
+	dedupeservice "shanraq.com/internal/services/dedupe"
+	favoriteservice "shanraq.com/internal/services/favorite"
+	"shanraq.com/internal/services/finance"
+	"shanraq.com/internal/services/fx"
+	leadservice "shanraq.com/internal/services/lead"
 	listingservice "shanraq.com/internal/services/listing"
+	mediaservice "shanraq.com/internal/services/media"
+	offerservice "shanraq.com/internal/services/offer"
+	placementservice "shanraq.com/internal/services/placement"
+	rentalservice "shanraq.com/internal/services/rental"
+	savedsearchservice "shanraq.com/internal/services/savedsearch"
 	transportservice "shanraq.com/internal/services/transport"
+	viewingservice "shanraq.com/internal/services/viewing"
 	workspaceservice "shanraq.com/internal/services/workspace"
+	"shanraq.com/internal/storage"
 	"shanraq.com/internal/web"
 )
 
@@ -32,9 +45,16 @@ type App struct {
 	transportSvc transportservice.Service
 	agencySvc    agencyservice.Service
 	listingSvc   listingservice.Service
+	duplicates   dedupeservice.Service
 	authRegistry *auth.ProviderRegistry
 	sessions     *session.Manager
 	workspaces   workspaceservice.Service
+	fxSvc        fx.Service
+	mediaSvc     mediaservice.Service
+	searches     savedsearchservice.Service
+	offers       offerservice.Service
+	outbox       notify.Outbox
+	sender       notify.Sender
 }
 
 // New wires the core application dependencies.
@@ -45,6 +65,9 @@ func New() (*App, error) {
 	}
 
 	normalizeConfig(&cfg)
+	if !cfg.App.Development() && cfg.Viewing.FeedSecret == config.DevelopmentFeedSecret {
+		return nil, fmt.Errorf("VIEWING_FEED_SECRET must be set when APP_ENV is %q", cfg.App.Env)
+	}
 
 	logger := logging.New(cfg.App.Env)
 
@@ -55,8 +78,38 @@ func New() (*App, error) {
 
 	var transportSvc transportservice.Service = transportservice.NewInMemoryService()
 	var agencySvc agencyservice.Service = agencyservice.NewInMemoryService()
-	var listingSvc listingservice.Service = listingservice.NewInMemoryService()
+	listingMemory := listingservice.NewInMemoryService()
+	var listingSvc listingservice.Service = listingMemory
+	var placementSvc placementservice.Service = placementservice.NewInMemoryService(listingMemory)
+	duplicateMemory := dedupeservice.NewInMemoryService(listingMemory)
+	listingMemory.CollapseMerged(duplicateMemory)
+	var duplicateSvc dedupeservice.Service = duplicateMemory
 	var workspaceSvc workspaceservice.Service = workspaceservice.NewInMemoryService()
+	var fxSvc fx.Service = fx.NewInMemoryService()
+
+	store, err := storage.New(cfg.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("init blob storage: %w", err)
+	}
+	var mediaSvc mediaservice.Service = mediaservice.NewInMemoryService(store)
+	var searchSvc savedsearchservice.Service = savedsearchservice.NewInMemoryService()
+	var outbox notify.Outbox = notify.NewInMemoryOutbox()
+	var favoriteSvc favoriteservice.Service = favoriteservice.NewInMemoryService()
+	var rentalSvc rentalservice.Service = rentalservice.NewInMemoryService()
+	var viewingSvc viewingservice.Service = viewingservice.NewInMemoryService()
+	var leadSvc leadservice.Service = leadservice.NewInMemoryService()
+	var offerSvc offerservice.Service = offerservice.NewInMemoryService()
+
+	mortgageDefaults, err := finance.LoadFile(cfg.Finance.DefaultsFile)
+	if err != nil {
+		logger.Warn().Err(err).Msg("load mortgage defaults; using built-in fallback")
+		mortgageDefaults = finance.NewTable(nil)
+	}
+
+	sender, err := notify.NewSender(cfg.Notify)
+	if err != nil {
+		return nil, fmt.Errorf("init notify sender: %w", err)
+	}
 
 	var db *sql.DB
 	if cfg.Database.URL != "" {
@@ -79,6 +132,61 @@ func New() (*App, error) {
 			} else {
 				listingSvc = svc
 			}
+			if svc, err := placementservice.NewSQLService(conn, listingSvc); err != nil {
+				logger.Warn().Err(err).Msg("init placement sql service")
+			} else {
+				placementSvc = svc
+			}
+			if svc, err := dedupeservice.NewSQLService(conn, listingSvc); err != nil {
+				logger.Warn().Err(err).Msg("init duplicate sql service")
+			} else {
+				duplicateSvc = svc
+			}
+			if svc, err := fx.NewSQLService(conn); err != nil {
+				logger.Warn().Err(err).Msg("init fx sql service")
+			} else {
+				fxSvc = svc
+			}
+			if svc, err := mediaservice.NewSQLService(conn, store); err != nil {
+				logger.Warn().Err(err).Msg("init media sql service")
+			} else {
+				mediaSvc = svc
+			}
+			if svc, err := savedsearchservice.NewSQLService(conn); err != nil {
+				logger.Warn().Err(err).Msg("init saved search sql service")
+			} else {
+				searchSvc = svc
+			}
+			if svc, err := notify.NewSQLOutbox(conn); err != nil {
+				logger.Warn().Err(err).Msg("init notification outbox")
+			} else {
+				outbox = svc
+			}
+			if svc, err := favoriteservice.NewSQLService(conn); err != nil {
+				logger.Warn().Err(err).Msg("init favorite sql service")
+			} else {
+				favoriteSvc = svc
+			}
+			if svc, err := rentalservice.NewSQLService(conn); err != nil {
+				logger.Warn().Err(err).Msg("init rental sql service")
+			} else {
+				rentalSvc = svc
+			}
+			if svc, err := viewingservice.NewSQLService(conn); err != nil {
+				logger.Warn().Err(err).Msg("init viewing sql service")
+			} else {
+				viewingSvc = svc
+			}
+			if svc, err := leadservice.NewSQLService(conn); err != nil {
+				logger.Warn().Err(err).Msg("init lead sql service")
+			} else {
+				leadSvc = svc
+			}
+			if svc, err := offerservice.NewSQLService(conn); err != nil {
+				logger.Warn().Err(err).Msg("init offer sql service")
+			} else {
+				offerSvc = svc
+			}
 		}
 	}
 	authRegistry := auth.NewRegistry(cfg.Auth.SupportedProviders...)
@@ -100,6 +208,18 @@ func New() (*App, error) {
 		AuthRegistry:     authRegistry,
 		SessionManager:   sessionManager,
 		WorkspaceService: workspaceSvc,
+		FXService:        fxSvc,
+		MediaService:     mediaSvc,
+		SavedSearches:    searchSvc,
+		Favorites:        favoriteSvc,
+		Rentals:          rentalSvc,
+		Viewings:         viewingSvc,
+		Leads:            leadSvc,
+		Offers:           offerSvc,
+		Placements:       placementSvc,
+		Duplicates:       duplicateSvc,
+		Outbox:           outbox,
+		MortgageDefaults: mortgageDefaults,
 	})
 
 	server := httpserver.New(cfg.HTTP, router, logger)
@@ -112,9 +232,16 @@ func New() (*App, error) {
 		transportSvc: transportSvc,
 		agencySvc:    agencySvc,
 		listingSvc:   listingSvc,
+		duplicates:   duplicateSvc,
 		authRegistry: authRegistry,
 		sessions:     sessionManager,
 		workspaces:   workspaceSvc,
+		fxSvc:        fxSvc,
+		mediaSvc:     mediaSvc,
+		searches:     searchSvc,
+		offers:       offerSvc,
+		outbox:       outbox,
+		sender:       sender,
 	}, nil
 }
 
@@ -122,6 +249,10 @@ func New() (*App, error) {
 func (a *App) Run(ctx context.Context) error {
 	errCh := make(chan error, 1)
 
+	if a.cfg.Scheduling.EnableJobs {
+		go a.runJobs(ctx)
+	}
+
 	go func() {
 		if err := a.server.Start(); err != nil {
 			errCh <- err
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"shanraq.com/internal/dedupe"
+	"shanraq.com/internal/notify"
+	savedsearchservice "shanraq.com/internal/services/savedsearch"
+)
+
+// runJobs runs offer expiry and the alert jobs every Scheduling.Interval and duplicate detection
+// every Scheduling.DedupeInterval until ctx is done.
+func (a *App) runJobs(ctx context.Context) {
+	interval := a.cfg.Scheduling.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	dedupeInterval := a.cfg.Scheduling.DedupeInterval
+	if dedupeInterval <= 0 {
+		dedupeInterval = time.Hour
+	}
+	matcher := savedsearchservice.NewMatcher(a.searches, a.listingSvc, a.outbox, a.cfg.HTTP.PublicBaseURL)
+	detector := dedupe.New(a.listingSvc, a.mediaSvc, a.duplicates)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	dedupeTicker := time.NewTicker(dedupeInterval)
+	defer dedupeTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.runOfferExpiry(ctx)
+			a.runAlerts(ctx, matcher)
+		case <-dedupeTicker.C:
+			a.runDedupe(ctx, detector)
+		}
+	}
+}
+
+// runAlerts queues saved-search alerts and delivers pending notifications.
+func (a *App) runAlerts(ctx context.Context, matcher *savedsearchservice.Matcher) {
+	queued, err := matcher.Run(ctx, time.Now().UTC())
+	if err != nil {
+		a.logger.Warn().Err(err).Msg("saved_search_matcher")
+	} else if queued > 0 {
+		a.logger.Info().Int("alerts", queued).Msg("saved_search_alerts_queued")
+	}
+
+	sent, err := notify.Dispatch(ctx, a.outbox, a.sender, a.cfg.Notify.BatchSize, a.cfg.Notify.MaxAttempts)
+	if err != nil {
+		a.logger.Warn().Err(err).Msg("notify_dispatch")
+	} else if sent > 0 {
+		a.logger.Info().Int("sent", sent).Msg("notifications_sent")
+	}
+}
+
+// runOfferExpiry closes offers whose terms ran out without an answer.
+func (a *App) runOfferExpiry(ctx context.Context) {
+	expired, err := a.offers.ExpireDue(ctx, time.Now().UTC())
+	if err != nil {
+		a.logger.Warn().Err(err).Msg("offer_expiry")
+	} else if expired > 0 {
+		a.logger.Info().Int("expired", expired).Msg("offers_expired")
+	}
+}
+
+// runDedupe flags probable cross-agency duplicate listings for review.
+func (a *App) runDedupe(ctx context.Context, detector *dedupe.Detector) {
+	pending, err := detector.Run(ctx)
+	if err != nil {
+		a.logger.Warn().Err(err).Msg("duplicate_detection")
+	} else if pending > 0 {
+		a.logger.Info().Int("pending", pending).Msg("duplicate_listings_pending_review")
+	}
+}
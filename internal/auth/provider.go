@@ -20,6 +20,16 @@ type Identity struct {
 	AccessToken string
 }
 
+// Key returns a stable identifier for the user: the provider subject when present,
+// otherwise the e-mail address. It is empty when the provider returned neither, and
+// such identities must not be signed in.
+func (i Identity) Key() string {
+	if i.Subject != "" {
+		return i.Subject
+	}
+	return i.Email
+}
+
 // Provider describes the behaviour required to implement login flows.
 type Provider interface {
 	AuthCodeURL(state string) (string, error)
@@ -29,6 +39,9 @@ type Provider interface {
 // ErrNotConfigured indicates that no external provider has been configured.
 var ErrNotConfigured = errors.New("auth provider not configured")
 
+// ErrIncompleteIdentity indicates that a provider returned neither a subject nor an e-mail.
+var ErrIncompleteIdentity = errors.New("identity has neither subject nor email")
+
 // NoopProvider is a placeholder until an actual implementation (Google OAuth, Meta, etc.) is wired in.
 type NoopProvider struct{}
 
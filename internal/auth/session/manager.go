@@ -42,6 +42,9 @@ func NewManager(ttl time.Duration, cookieName string) *Manager {
 
 // Create issues a session for the supplied identity and writes a cookie.
 func (m *Manager) Create(w http.ResponseWriter, identity auth.Identity) (string, error) {
+	if identity.Key() == "" {
+		return "", auth.ErrIncompleteIdentity
+	}
 	token, err := randomToken(32)
 	if err != nil {
 		return "", err
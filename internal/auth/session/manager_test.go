@@ -1,6 +1,7 @@
 package session
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -40,6 +41,18 @@ func TestManagerCreateAndIdentity(t *testing.T) {
 	}
 }
 
+func TestManagerCreateRejectsIncompleteIdentity(t *testing.T) {
+	manager := NewManager(1*time.Hour, "")
+
+	resp := httptest.NewRecorder()
+	if _, err := manager.Create(resp, auth.Identity{Provider: "google", FullName: "No Keys"}); !errors.Is(err, auth.ErrIncompleteIdentity) {
+		t.Fatalf("Create() error = %v, want ErrIncompleteIdentity", err)
+	}
+	if cookies := resp.Result().Cookies(); len(cookies) != 0 {
+		t.Fatalf("Create() set %d cookies for an incomplete identity", len(cookies))
+	}
+}
+
 func TestManagerDestroy(t *testing.T) {
 	manager := NewManager(1*time.Hour, "")
 
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"fmt"
+	"net/netip"
+	"strings"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
@@ -19,6 +22,11 @@ type (
 		Features   Features   `envconfig:"FEATURES"`
 		Seed       Seed       `envconfig:"SEED"`
 		Scheduling Scheduling `envconfig:"SCHEDULING"`
+		Storage    Storage    `envconfig:"STORAGE"`
+		Notify     Notify     `envconfig:"NOTIFY"`
+		Leads      Leads      `envconfig:"LEADS"`
+		Finance    Finance    `envconfig:"FINANCE"`
+		Viewing    Viewing    `envconfig:"VIEWING"`
 	}
 
 	App struct {
@@ -36,6 +44,7 @@ type (
 		PublicBaseURL  string        `envconfig:"PUBLIC_BASE_URL" default:"http://localhost:8080"`
 		DashboardURL   string        `envconfig:"DASHBOARD_URL" default:"http://localhost:8080/dashboard"`
 		AllowedOrigins []string      `envconfig:"ALLOWED_ORIGINS" default:"*"`
+		TrustedProxies ProxyList     `envconfig:"TRUSTED_PROXIES"`
 	}
 
 	Database struct {
@@ -64,6 +73,7 @@ type (
 		CallbackURL        string   `envconfig:"CALLBACK_URL" default:"http://localhost:8080/auth/callback"`
 		AllowedDomains     []string `envconfig:"ALLOWED_DOMAINS"`
 		JWTSigningKey      string   `envconfig:"JWT_SIGNING_KEY" default:"development-secret"`
+		AdminEmails        []string `envconfig:"ADMIN_EMAILS"`
 	}
 
 	Geo struct {
@@ -95,15 +105,93 @@ type (
 	}
 
 	Scheduling struct {
-		EnableJobs bool          `envconfig:"ENABLE_JOBS" default:"true"`
-		Timezone   string        `envconfig:"TIMEZONE" default:"UTC"`
-		Interval   time.Duration `envconfig:"INTERVAL" default:"1m"`
+		EnableJobs     bool          `envconfig:"ENABLE_JOBS" default:"true"`
+		Timezone       string        `envconfig:"TIMEZONE" default:"UTC"`
+		Interval       time.Duration `envconfig:"INTERVAL" default:"1m"`
+		DedupeInterval time.Duration `envconfig:"DEDUPE_INTERVAL" default:"1h"`
+	}
+
+	Storage struct {
+		Driver      string `envconfig:"DRIVER" default:"local"`
+		LocalDir    string `envconfig:"LOCAL_DIR" default:"data/media"`
+		PublicURL   string `envconfig:"PUBLIC_URL" default:"/media"`
+		MaxUploadMB int64  `envconfig:"MAX_UPLOAD_MB" default:"20"`
+	}
+
+	Notify struct {
+		Driver       string `envconfig:"DRIVER" default:"file"`
+		FileDir      string `envconfig:"FILE_DIR" default:"data/outbox"`
+		From         string `envconfig:"FROM" default:"Shanraq Alerts <alerts@shanraq.com>"`
+		SMTPHost     string `envconfig:"SMTP_HOST"`
+		SMTPPort     int    `envconfig:"SMTP_PORT" default:"587"`
+		SMTPUsername string `envconfig:"SMTP_USERNAME"`
+		SMTPPassword string `envconfig:"SMTP_PASSWORD"`
+		BatchSize    int    `envconfig:"BATCH_SIZE" default:"50"`
+		MaxAttempts  int    `envconfig:"MAX_ATTEMPTS" default:"5"`
+	}
+
+	Leads struct {
+		MaxPerIP      int           `envconfig:"MAX_PER_IP" default:"10"`
+		MaxPerSession int           `envconfig:"MAX_PER_SESSION" default:"5"`
+		Window        time.Duration `envconfig:"WINDOW" default:"1h"`
+	}
+
+	Finance struct {
+		DefaultsFile string `envconfig:"DEFAULTS_FILE" default:"data/finance/mortgage-defaults.csv"`
+	}
+
+	Viewing struct {
+		FeedSecret string `envconfig:"FEED_SECRET" default:"development-feed-secret"`
 	}
 )
 
+// DevelopmentFeedSecret is the VIEWING_FEED_SECRET default, refused outside development.
+const DevelopmentFeedSecret = "development-feed-secret"
+
+// Development reports whether the environment is a local or development one.
+func (a App) Development() bool {
+	switch a.Env {
+	case "debug", "development", "local":
+		return true
+	default:
+		return false
+	}
+}
+
 // Load reads configuration from environment variables.
 func Load() (Config, error) {
 	var cfg Config
 	err := envconfig.Process("", &cfg)
 	return cfg, err
 }
+
+// ProxyList holds the reverse proxies, as addresses or CIDR ranges, whose
+// forwarding headers may name the client.
+type ProxyList []netip.Prefix
+
+// Decode parses a comma-separated list such as "10.0.0.0/8,192.0.2.10".
+func (p *ProxyList) Decode(value string) error {
+	var list ProxyList
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return fmt.Errorf("trusted proxy %q: %w", entry, err)
+			}
+			list = append(list, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return fmt.Errorf("trusted proxy %q: %w", entry, err)
+		}
+		addr = addr.Unmap()
+		list = append(list, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	*p = list
+	return nil
+}
@@ -0,0 +1,237 @@
+// Package dedupe finds listings that different agencies published for the same
+// property and flags them for editorial review.
+package dedupe
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+
+	dedupeservice "shanraq.com/internal/services/dedupe"
+	listingservice "shanraq.com/internal/services/listing"
+	mediaservice "shanraq.com/internal/services/media"
+)
+
+// DefaultThreshold is the score from which a pair is flagged as a probable duplicate.
+const DefaultThreshold = 0.6
+
+// Reasons recorded on flagged pairs.
+const (
+	ReasonAddress  = "address"
+	ReasonLocation = "location"
+	ReasonArea     = "area"
+	ReasonBedrooms = "bedrooms"
+	ReasonImages   = "images"
+)
+
+const (
+	// nearbyKm and sameSpotKm bound the distance at which coordinates count as
+	// the same building.
+	sameSpotKm = 0.05
+	nearbyKm   = 0.15
+	// areaTolerance is the relative difference in floor area still treated as equal.
+	areaTolerance = 0.03
+	// maxHashDistance is the number of differing perceptual-hash bits still treated
+	// as the same photo.
+	maxHashDistance = 6
+)
+
+// Fingerprint holds the normalized attributes two listings are compared on.
+type Fingerprint struct {
+	ListingID uuid.UUID
+	AgencyID  uuid.UUID
+	Type      listingservice.ListingType
+	Block     string
+	Address   string
+	Location  *listingservice.GeoPoint
+	AreaSqM   float64
+	Bedrooms  int
+	ImageURL  string
+	Images    []string
+}
+
+// NewFingerprint normalizes a listing and the perceptual hashes of its photos.
+func NewFingerprint(l listingservice.Listing, hashes []string) Fingerprint {
+	fp := Fingerprint{
+		ListingID: l.ID,
+		AgencyID:  l.AgencyID,
+		Type:      l.Type,
+		Block:     strings.ToUpper(l.Country) + "|" + NormalizeAddress(l.City),
+		Address:   NormalizeAddress(strings.Join([]string{l.Neighborhood, l.City, l.Region, l.Country}, " ")),
+		AreaSqM:   l.AreaSqM,
+		Bedrooms:  l.Bedrooms,
+		ImageURL:  strings.TrimSpace(l.ImageURL),
+		Images:    hashes,
+	}
+	if l.Location != nil && l.Location.Valid() {
+		loc := *l.Location
+		fp.Location = &loc
+	}
+	return fp
+}
+
+// NormalizeAddress lowercases the value, folds accents and collapses punctuation
+// and whitespace so that "Dubai Marina, Dubai" and "dubai  marina - DUBAI" agree.
+func NormalizeAddress(value string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	if folded, _, err := transform.String(t, value); err == nil {
+		value = folded
+	}
+	fields := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// Compare scores how likely two fingerprints describe the same property, from 0 to
+// 1, and names the signals that matched. Listings of different types never match.
+func Compare(a, b Fingerprint) (float64, []string) {
+	if a.Type != b.Type {
+		return 0, nil
+	}
+	var (
+		score   float64
+		reasons []string
+	)
+	add := func(weight float64, reason string) {
+		score += weight
+		reasons = append(reasons, reason)
+	}
+	if a.Address != "" && a.Address == b.Address {
+		add(0.2, ReasonAddress)
+	}
+	if a.Location != nil && b.Location != nil {
+		switch km := listingservice.HaversineKm(*a.Location, *b.Location); {
+		case km <= sameSpotKm:
+			add(0.3, ReasonLocation)
+		case km <= nearbyKm:
+			add(0.15, ReasonLocation)
+		}
+	}
+	if a.AreaSqM > 0 && b.AreaSqM > 0 && math.Abs(a.AreaSqM-b.AreaSqM) <= areaTolerance*math.Max(a.AreaSqM, b.AreaSqM) {
+		add(0.15, ReasonArea)
+	}
+	if a.Bedrooms == b.Bedrooms {
+		add(0.1, ReasonBedrooms)
+	}
+	if sharesImage(a, b) {
+		add(0.35, ReasonImages)
+	}
+	return math.Min(math.Round(score*100)/100, 1), reasons
+}
+
+// sharesImage reports whether the listings use the same hero image or any pair of
+// near-identical photos.
+func sharesImage(a, b Fingerprint) bool {
+	if a.ImageURL != "" && a.ImageURL == b.ImageURL {
+		return true
+	}
+	for _, x := range a.Images {
+		for _, y := range b.Images {
+			if d := mediaservice.HashDistance(x, y); d >= 0 && d <= maxHashDistance {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Detector compares published listings and flags probable cross-agency duplicates.
+type Detector struct {
+	listings   listingservice.Service
+	media      mediaservice.Service
+	duplicates dedupeservice.Service
+	threshold  float64
+}
+
+// New creates a detector flagging pairs scoring at least DefaultThreshold into the
+// review queue.
+func New(listings listingservice.Service, media mediaservice.Service, duplicates dedupeservice.Service) *Detector {
+	return &Detector{listings: listings, media: media, duplicates: duplicates, threshold: DefaultThreshold}
+}
+
+// Run fingerprints every published listing, compares listings of different agencies
+// in the same country and city, and flags pairs at or above the threshold. The older
+// listing of a pair is proposed as canonical. It returns how many pairs are awaiting
+// review after the run; pairs a reviewer already resolved are left alone.
+func (d *Detector) Run(ctx context.Context) (int, error) {
+	listings, err := d.published(ctx)
+	if err != nil {
+		return 0, err
+	}
+	ids := make([]uuid.UUID, 0, len(listings))
+	for _, l := range listings {
+		ids = append(ids, l.ID)
+	}
+	hashes, err := d.media.PerceptualHashes(ctx, ids)
+	if err != nil {
+		return 0, fmt.Errorf("load image hashes: %w", err)
+	}
+
+	blocks := make(map[string][]int)
+	fingerprints := make([]Fingerprint, len(listings))
+	for idx, l := range listings {
+		fingerprints[idx] = NewFingerprint(l, hashes[l.ID])
+		blocks[fingerprints[idx].Block] = append(blocks[fingerprints[idx].Block], idx)
+	}
+
+	pending := 0
+	for _, members := range blocks {
+		for i := 0; i < len(members); i++ {
+			for j := i + 1; j < len(members); j++ {
+				a, b := members[i], members[j]
+				if fingerprints[a].AgencyID == uuid.Nil || fingerprints[a].AgencyID == fingerprints[b].AgencyID {
+					continue
+				}
+				score, reasons := Compare(fingerprints[a], fingerprints[b])
+				if score < d.threshold {
+					continue
+				}
+				canonical, duplicate := listings[a], listings[b]
+				if duplicate.CreatedAt.Before(canonical.CreatedAt) {
+					canonical, duplicate = duplicate, canonical
+				}
+				pair, err := d.duplicates.Flag(ctx, dedupeservice.Input{
+					ListingID:   canonical.ID,
+					DuplicateID: duplicate.ID,
+					Score:       score,
+					Reasons:     reasons,
+				})
+				if err != nil {
+					return pending, fmt.Errorf("flag duplicate %s/%s: %w", canonical.ID, duplicate.ID, err)
+				}
+				if pair.Status == dedupeservice.StatusPending {
+					pending++
+				}
+			}
+		}
+	}
+	return pending, nil
+}
+
+// published pages through every published listing.
+func (d *Detector) published(ctx context.Context) ([]listingservice.Listing, error) {
+	var out []listingservice.Listing
+	for offset := 0; ; offset += listingservice.MaxPageSize {
+		page, total, err := d.listings.List(ctx, listingservice.ListFilter{
+			Status: listingservice.StatusPublished,
+			Limit:  listingservice.MaxPageSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list published listings: %w", err)
+		}
+		out = append(out, page...)
+		if len(page) == 0 || offset+len(page) >= total {
+			break
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,154 @@
+package dedupe
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+
+	dedupeservice "shanraq.com/internal/services/dedupe"
+	listingservice "shanraq.com/internal/services/listing"
+	mediaservice "shanraq.com/internal/services/media"
+	"shanraq.com/internal/storage"
+)
+
+func photo(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			fx, fy := float64(x)/float64(width), float64(y)/float64(height)
+			shade := uint8(127 + 127*math.Sin(9*fx+5*fy*fy))
+			img.SetNRGBA(x, y, color.NRGBA{R: shade, G: uint8(255 * fy), B: 90, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestNormalizeAddress(t *testing.T) {
+	if got, want := NormalizeAddress("  Östermalm, Stockholm -- SE "), "ostermalm stockholm se"; got != want {
+		t.Errorf("NormalizeAddress() = %q, want %q", got, want)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	base := listingservice.Listing{
+		Type: listingservice.ListingTypeResidential, Country: "AE", City: "Dubai", Neighborhood: "Dubai Marina",
+		AreaSqM: 120, Bedrooms: 2, Location: &listingservice.GeoPoint{Lat: 25.08, Lng: 55.14},
+	}
+	copyListing := base
+	copyListing.Neighborhood = "dubai marina."
+	copyListing.AreaSqM = 121
+	copyListing.Location = &listingservice.GeoPoint{Lat: 25.0801, Lng: 55.1401}
+
+	score, reasons := Compare(NewFingerprint(base, nil), NewFingerprint(copyListing, nil))
+	if score < DefaultThreshold || len(reasons) != 4 {
+		t.Errorf("Compare(copy) = %v %v, want a probable duplicate on four signals", score, reasons)
+	}
+
+	other := base
+	other.Type = listingservice.ListingTypeCommercial
+	if score, _ := Compare(NewFingerprint(base, nil), NewFingerprint(other, nil)); score != 0 {
+		t.Errorf("Compare(different type) = %v, want 0", score)
+	}
+}
+
+func TestDetectorFlagsCrossAgencyDuplicates(t *testing.T) {
+	ctx := context.Background()
+	listings := listingservice.NewInMemoryService()
+	duplicates := dedupeservice.NewInMemoryService(listings)
+	listings.CollapseMerged(duplicates)
+	media := mediaservice.NewInMemoryService(storage.NewLocalStore(t.TempDir(), "/media"))
+
+	create := func(agencyID uuid.UUID, area float64, pic []byte) listingservice.Listing {
+		t.Helper()
+		l, err := listings.Create(ctx, listingservice.CreateInput{
+			Title: "Marina apartment", Type: listingservice.ListingTypeResidential, Country: "AE", City: "Dubai",
+			Neighborhood: "Dubai Marina", Price: 2500000, Currency: "AED", Bedrooms: 2, AreaSqM: area, AgencyID: agencyID,
+		})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		for _, status := range []listingservice.Status{listingservice.StatusReview, listingservice.StatusPublished} {
+			if _, err := listings.Transition(ctx, l.ID, listingservice.TransitionInput{Status: status, ActorEmail: "ops@example.com"}); err != nil {
+				t.Fatalf("Transition(%s) error = %v", status, err)
+			}
+		}
+		if pic != nil {
+			if _, err := media.Upload(ctx, mediaservice.UploadInput{ListingID: l.ID, Data: pic}); err != nil {
+				t.Fatalf("Upload() error = %v", err)
+			}
+		}
+		return l
+	}
+	agencyA, agencyB := uuid.New(), uuid.New()
+	original := create(agencyA, 120, photo(t, 640, 480))
+	copied := create(agencyB, 120.5, photo(t, 320, 240))
+	sameAgency := create(agencyA, 120, photo(t, 800, 600))
+	unrelated := create(agencyB, 300, nil)
+
+	detector := New(listings, media, duplicates)
+	pending, err := detector.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if pending != 2 {
+		t.Fatalf("Run() pending = %d, want 2", pending)
+	}
+	pairs, _, err := duplicates.List(ctx, dedupeservice.Filter{ListingID: copied.ID})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var pair dedupeservice.Duplicate
+	for _, p := range pairs {
+		if p.Involves(original.ID) {
+			pair = p
+		}
+		if p.Involves(unrelated.ID) {
+			t.Errorf("unrelated listing flagged: %+v", p)
+		}
+	}
+	if pair.ListingID != original.ID || pair.DuplicateID != copied.ID {
+		t.Fatalf("pair = %+v, want the older listing canonical", pair)
+	}
+	if !containsReason(pair.Reasons, ReasonImages) {
+		t.Errorf("reasons = %v, want matching images", pair.Reasons)
+	}
+	if flagged, _, _ := duplicates.List(ctx, dedupeservice.Filter{ListingID: sameAgency.ID}); len(flagged) != 1 {
+		t.Errorf("listing of the same agency flagged %d times, want only against the other agency", len(flagged))
+	}
+
+	if _, err := duplicates.Resolve(ctx, pair.ID, dedupeservice.Resolution{Status: dedupeservice.StatusMerged, ActorEmail: "editor@example.com"}); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if pending, err = detector.Run(ctx); err != nil || pending != 1 {
+		t.Fatalf("rerun pending = %d, %v; want the merged pair left resolved", pending, err)
+	}
+	results, _, err := listings.List(ctx, listingservice.ListFilter{Country: "AE", City: "Dubai", CollapseDuplicates: true})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	for _, l := range results {
+		if l.ID == copied.ID {
+			t.Error("merged duplicate still appears in collapsed results")
+		}
+	}
+}
+
+func containsReason(reasons []string, want string) bool {
+	for _, r := range reasons {
+		if r == want {
+			return true
+		}
+	}
+	return false
+}
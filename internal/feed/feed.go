@@ -0,0 +1,269 @@
+// Package feed renders syndication feeds in Atom 1.0 and RSS 2.0 from one
+// format-neutral model.
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+	"time"
+)
+
+// Format names a feed dialect.
+type Format string
+
+const (
+	FormatAtom Format = "atom"
+	FormatRSS  Format = "rss"
+)
+
+// ContentType is the media type the format is served with.
+func (f Format) ContentType() string {
+	if f == FormatRSS {
+		return "application/rss+xml; charset=utf-8"
+	}
+	return "application/atom+xml; charset=utf-8"
+}
+
+// Feed is a channel of items. Link is the HTML page the feed mirrors; Self is the
+// feed's own absolute URL and doubles as its Atom ID.
+type Feed struct {
+	Title    string
+	Subtitle string
+	Link     string
+	Self     string
+	Author   string
+	Language string
+	Updated  time.Time
+	Items    []Item
+}
+
+// Item is one entry of a feed. ID must be stable across edits.
+type Item struct {
+	ID         string
+	Title      string
+	Summary    string
+	Link       string
+	Image      string
+	Categories []string
+	Published  time.Time
+	Updated    time.Time
+}
+
+// LastUpdated is the latest Updated time of the items, or the zero time for an
+// empty feed.
+func LastUpdated(items []Item) time.Time {
+	var latest time.Time
+	for _, item := range items {
+		if item.Updated.After(latest) {
+			latest = item.Updated
+		}
+	}
+	return latest
+}
+
+// Write encodes the feed in the format with the XML declaration.
+func Write(w io.Writer, f Feed, format Format) error {
+	var doc any
+	switch format {
+	case FormatAtom:
+		doc = f.atom()
+	case FormatRSS:
+		doc = f.rss()
+	default:
+		return fmt.Errorf("unsupported feed format %q", format)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"feed"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	Lang     string      `xml:"xml:lang,attr,omitempty"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Author   *atomPerson `xml:"author,omitempty"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr,omitempty"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Links      []atomLink     `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+}
+
+func (f Feed) atom() atomFeed {
+	doc := atomFeed{
+		Xmlns:    "http://www.w3.org/2005/Atom",
+		Lang:     f.Language,
+		ID:       f.Self,
+		Title:    f.Title,
+		Subtitle: f.Subtitle,
+		Updated:  f.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Rel: "self", Type: FormatAtom.mediaType(), Href: f.Self},
+			{Rel: "alternate", Type: "text/html", Href: f.Link},
+		},
+		Entries: make([]atomEntry, 0, len(f.Items)),
+	}
+	if f.Author != "" {
+		doc.Author = &atomPerson{Name: f.Author}
+	}
+	for _, item := range f.Items {
+		entry := atomEntry{
+			ID:      item.ID,
+			Title:   item.Title,
+			Updated: item.Updated.UTC().Format(time.RFC3339),
+			Links:   []atomLink{{Rel: "alternate", Type: "text/html", Href: item.Link}},
+		}
+		if !item.Published.IsZero() {
+			entry.Published = item.Published.UTC().Format(time.RFC3339)
+		}
+		if item.Image != "" {
+			entry.Links = append(entry.Links, atomLink{Rel: "enclosure", Type: imageType(item.Image), Href: item.Image})
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		if item.Summary != "" {
+			entry.Summary = &atomText{Type: "text", Body: item.Summary}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return doc
+}
+
+type rssDoc struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language,omitempty"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Self          rssSelf   `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssSelf struct {
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+	Href string `xml:"href,attr"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int    `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	GUID        rssGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Description string        `xml:"description,omitempty"`
+	Categories  []string      `xml:"category"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+func (f Feed) rss() rssDoc {
+	channel := rssChannel{
+		Title:         f.Title,
+		Link:          f.Link,
+		Description:   f.Subtitle,
+		Language:      f.Language,
+		LastBuildDate: f.Updated.UTC().Format(time.RFC1123Z),
+		Self:          rssSelf{Rel: "self", Type: FormatRSS.mediaType(), Href: f.Self},
+		Items:         make([]rssItem, 0, len(f.Items)),
+	}
+	if channel.Description == "" {
+		channel.Description = f.Title
+	}
+	for _, item := range f.Items {
+		published := item.Published
+		if published.IsZero() {
+			published = item.Updated
+		}
+		entry := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{Value: item.ID},
+			PubDate:     published.UTC().Format(time.RFC1123Z),
+			Description: item.Summary,
+			Categories:  item.Categories,
+		}
+		if item.Image != "" {
+			// The image size is unknown without fetching it; 0 is the accepted
+			// placeholder for enclosure lengths.
+			entry.Enclosure = &rssEnclosure{URL: item.Image, Type: imageType(item.Image)}
+		}
+		channel.Items = append(channel.Items, entry)
+	}
+	return rssDoc{Version: "2.0", Atom: "http://www.w3.org/2005/Atom", Channel: channel}
+}
+
+func (f Format) mediaType() string {
+	return strings.TrimSuffix(f.ContentType(), "; charset=utf-8")
+}
+
+// imageType guesses an enclosure's media type from its extension, defaulting to
+// JPEG which most listing photos are.
+func imageType(rawURL string) string {
+	clean := rawURL
+	if idx := strings.IndexAny(clean, "?#"); idx >= 0 {
+		clean = clean[:idx]
+	}
+	if t := mime.TypeByExtension(strings.ToLower(path.Ext(clean))); strings.HasPrefix(t, "image/") {
+		return t
+	}
+	return "image/jpeg"
+}
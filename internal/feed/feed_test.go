@@ -0,0 +1,80 @@
+package feed
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleFeed() Feed {
+	published := time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)
+	items := []Item{
+		{
+			ID:         "urn:uuid:1",
+			Title:      "Alfama loft & terrace",
+			Summary:    "EUR 450,000 · Lisbon, PT",
+			Link:       "https://shanraq.example/listings/alfama-loft",
+			Image:      "https://cdn.example.com/loft.webp?w=1200",
+			Categories: []string{"residential", "PT"},
+			Published:  published,
+			Updated:    published.Add(48 * time.Hour),
+		},
+		{ID: "urn:uuid:2", Title: "Chiado office", Link: "https://shanraq.example/listings/chiado-office", Updated: published},
+	}
+	return Feed{
+		Title:   "New listings in PT · Shanraq",
+		Link:    "https://shanraq.example/",
+		Self:    "https://shanraq.example/feeds/listings.atom?country=PT",
+		Author:  "Shanraq",
+		Updated: LastUpdated(items),
+		Items:   items,
+	}
+}
+
+func TestWriteAtom(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, sampleFeed(), FormatAtom); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	doc := buf.String()
+	mustContain := []string{
+		`<feed xmlns="http://www.w3.org/2005/Atom">`,
+		"<id>https://shanraq.example/feeds/listings.atom?country=PT</id>",
+		"<updated>2026-05-03T09:30:00Z</updated>",
+		"<title>Alfama loft &amp; terrace</title>",
+		"<published>2026-05-01T09:30:00Z</published>",
+		`<link rel="enclosure" type="image/webp" href="https://cdn.example.com/loft.webp?w=1200">`,
+		`<category term="PT">`,
+	}
+	for _, token := range mustContain {
+		if !strings.Contains(doc, token) {
+			t.Fatalf("atom feed missing %q:\n%s", token, doc)
+		}
+	}
+	if strings.Count(doc, "<published>") != 1 {
+		t.Fatal("entries without a publish time must omit <published>")
+	}
+}
+
+func TestWriteRSS(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, sampleFeed(), FormatRSS); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	doc := buf.String()
+	mustContain := []string{
+		`<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">`,
+		"<lastBuildDate>Sun, 03 May 2026 09:30:00 +0000</lastBuildDate>",
+		`<atom:link rel="self" type="application/rss+xml"`,
+		"<description>New listings in PT · Shanraq</description>",
+		`<guid isPermaLink="false">urn:uuid:1</guid>`,
+		"<pubDate>Fri, 01 May 2026 09:30:00 +0000</pubDate>",
+		`<enclosure url="https://cdn.example.com/loft.webp?w=1200" length="0" type="image/webp">`,
+	}
+	for _, token := range mustContain {
+		if !strings.Contains(doc, token) {
+			t.Fatalf("rss feed missing %q:\n%s", token, doc)
+		}
+	}
+}
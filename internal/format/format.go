@@ -0,0 +1,156 @@
+// Package format renders money, numbers and areas for a request's locale and
+// region.
+package format
+
+import (
+	"math"
+	"net/http"
+	"strings"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+
+	"shanraq.com/internal/i18n"
+)
+
+// SqFtPerSqM converts square metres to square feet.
+const SqFtPerSqM = 10.7639104
+
+// AreaUnit is the unit areas are shown in.
+type AreaUnit string
+
+const (
+	SquareMetres AreaUnit = "sqm"
+	SquareFeet   AreaUnit = "sqft"
+)
+
+// squareFeetRegions market property in square feet.
+var squareFeetRegions = map[string]bool{"US": true, "GB": true, "LR": true, "MM": true}
+
+// symbolPlacement describes where a locale writes the currency symbol.
+type symbolPlacement struct {
+	suffix bool
+	space  bool
+}
+
+// placements follow CLDR currency patterns for the supported locales; other
+// locales put the symbol first.
+var placements = map[string]symbolPlacement{
+	"en": {},
+	"ja": {},
+	"pt": {space: true},
+	"sv": {suffix: true, space: true},
+	"ar": {suffix: true, space: true},
+}
+
+// Formatter renders values for one locale and region. The zero value formats like
+// DefaultLocale with square metres.
+type Formatter struct {
+	Locale string
+	Region string
+}
+
+// New returns a formatter for a supported locale and an optional ISO 3166 region.
+func New(locale, region string) Formatter {
+	if !i18n.Supported(locale) {
+		locale = i18n.DefaultLocale
+	}
+	return Formatter{Locale: locale, Region: strings.ToUpper(strings.TrimSpace(region))}
+}
+
+// FromRequest builds the formatter for a request: the negotiated locale, and the
+// region from ?region= or the region subtag of the preferred Accept-Language entry.
+func FromRequest(r *http.Request) Formatter {
+	return New(i18n.Negotiate(r), requestRegion(r))
+}
+
+func requestRegion(r *http.Request) string {
+	if region, err := language.ParseRegion(strings.TrimSpace(r.URL.Query().Get("region"))); err == nil {
+		return region.String()
+	}
+	tags, _, err := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	if err != nil || len(tags) == 0 {
+		return ""
+	}
+	// Only an explicit subtag counts: "en" alone says nothing about where the
+	// visitor lives.
+	if region, confidence := tags[0].Region(); confidence == language.Exact {
+		return region.String()
+	}
+	return ""
+}
+
+// AreaUnit reports the unit areas are shown in for the formatter's region.
+func (f Formatter) AreaUnit() AreaUnit {
+	if squareFeetRegions[f.Region] {
+		return SquareFeet
+	}
+	return SquareMetres
+}
+
+// Number renders a value with the locale's digits, grouping and decimal
+// separators and the given number of decimals.
+func (f Formatter) Number(v float64, decimals int) string {
+	return f.printer().Sprint(number.Decimal(v, number.Scale(decimals)))
+}
+
+// Money renders an amount with the currency's symbol for the locale. Amounts are
+// rounded to the currency's minor unit, and whole amounts drop the decimals, so
+// "JPY" never shows any and "EUR 1250" renders as "€1,250". Unknown currencies
+// fall back to their code.
+func (f Formatter) Money(amount float64, code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	decimals, symbol := 2, code
+	if unit, err := currency.ParseISO(code); err == nil {
+		decimals, _ = currency.Standard.Rounding(unit)
+		symbol = f.printer().Sprint(currency.Symbol(unit))
+	}
+	scale := math.Pow10(decimals)
+	amount = math.Round(amount*scale) / scale
+	if amount == math.Trunc(amount) {
+		decimals = 0
+	}
+	value := f.Number(amount, decimals)
+	if symbol == "" {
+		return value
+	}
+
+	placement := placements[f.Locale]
+	separator := ""
+	// Codes such as "SEK" always need a space to stay readable. The space does not
+	// break so the amount stays on one line.
+	if placement.space || isLetters(symbol) {
+		separator = "\u00a0"
+	}
+	if placement.suffix {
+		return value + separator + symbol
+	}
+	return symbol + separator + value
+}
+
+// Area renders an area given in square metres in the region's unit.
+func (f Formatter) Area(sqm float64) string {
+	if f.AreaUnit() == SquareFeet {
+		return f.Number(math.Round(sqm*SqFtPerSqM), 0) + "\u00a0sq\u00a0ft"
+	}
+	return f.Number(math.Round(sqm), 0) + "\u00a0m²"
+}
+
+func (f Formatter) printer() *message.Printer {
+	locale := f.Locale
+	if locale == "" {
+		locale = i18n.DefaultLocale
+	}
+	return message.NewPrinter(language.Make(locale))
+}
+
+func isLetters(s string) bool {
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return s != ""
+}
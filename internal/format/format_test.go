@@ -0,0 +1,70 @@
+package format
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMoney(t *testing.T) {
+	cases := []struct {
+		locale, region string
+		amount         float64
+		currency       string
+		want           string
+	}{
+		{"en", "", 215000000, "JPY", "¥215,000,000"},
+		{"en", "", 215000000.4, "jpy", "¥215,000,000"},
+		{"en", "", 1250000, "USD", "$1,250,000"},
+		{"en", "", 1250.5, "EUR", "€1,250.50"},
+		{"en", "", 4500000, "SEK", "SEK\u00a04,500,000"},
+		{"sv", "SE", 4500000, "SEK", "4\u00a0500\u00a0000\u00a0kr"},
+		{"pt", "", 1250000.75, "EUR", "€\u00a01.250.000,75"},
+		{"ja", "JP", 98000000, "JPY", "￥98,000,000"},
+		{"en", "", 12.3456, "KWD", "KWD\u00a012.346"},
+		{"en", "", 100, "XYZ", "XYZ\u00a0100"},
+		{"xx", "", 100, "USD", "$100"},
+	}
+	for _, tc := range cases {
+		if got := New(tc.locale, tc.region).Money(tc.amount, tc.currency); got != tc.want {
+			t.Errorf("New(%q).Money(%v, %s) = %q, want %q", tc.locale, tc.amount, tc.currency, got, tc.want)
+		}
+	}
+	if got := New("ar", "AE").Money(1500, "AED"); got != "١٬٥٠٠\u00a0د.إ.\u200f" {
+		t.Errorf("Money(ar) = %q, want Arabic digits and symbol", got)
+	}
+}
+
+func TestArea(t *testing.T) {
+	if got := New("en", "").Area(120.4); got != "120\u00a0m²" {
+		t.Errorf("Area() = %q, want 120 m²", got)
+	}
+	if got := New("en", "US").Area(100); got != "1,076\u00a0sq\u00a0ft" {
+		t.Errorf("Area(US) = %q, want 1,076 sq ft", got)
+	}
+	if got := New("sv", "SE").Area(1250); got != "1\u00a0250\u00a0m²" {
+		t.Errorf("Area(sv) = %q, want 1 250 m²", got)
+	}
+}
+
+func TestFromRequest(t *testing.T) {
+	cases := []struct {
+		target, header string
+		locale, region string
+		unit           AreaUnit
+	}{
+		{"/", "", "en", "", SquareMetres},
+		{"/", "en", "en", "", SquareMetres},
+		{"/", "en-US,en;q=0.8", "en", "US", SquareFeet},
+		{"/", "sv-SE", "sv", "SE", SquareMetres},
+		{"/?region=gb", "sv-SE", "sv", "GB", SquareFeet},
+		{"/?lang=ja&region=nowhere", "pt-BR", "ja", "BR", SquareMetres},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRequest("GET", tc.target, nil)
+		r.Header.Set("Accept-Language", tc.header)
+		f := FromRequest(r)
+		if f.Locale != tc.locale || f.Region != tc.region || f.AreaUnit() != tc.unit {
+			t.Errorf("FromRequest(%s, %q) = %+v (%s), want %s/%s (%s)", tc.target, tc.header, f, f.AreaUnit(), tc.locale, tc.region, tc.unit)
+		}
+	}
+}
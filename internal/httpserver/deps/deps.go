@@ -0,0 +1,53 @@
+// Package deps carries the services and settings the HTTP handlers are built from,
+// so routers take one value instead of long positional argument lists.
+package deps
+
+import (
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/auth"
+	"shanraq.com/internal/auth/session"
+	"shanraq.com/internal/config"
+	"shanraq.com/internal/notify"
+	agencyservice "shanraq.com/internal/services/agency"
+	dedupeservice "shanraq.com/internal/services/dedupe"
+	favoriteservice "shanraq.com/internal/services/favorite"
+	"shanraq.com/internal/services/finance"
+	"shanraq.com/internal/services/fx"
+	leadservice "shanraq.com/internal/services/lead"
+	listingservice "shanraq.com/internal/services/listing"
+	mediaservice "shanraq.com/internal/services/media"
+	offerservice "shanraq.com/internal/services/offer"
+	placementservice "shanraq.com/internal/services/placement"
+	rentalservice "shanraq.com/internal/services/rental"
+	savedsearchservice "shanraq.com/internal/services/savedsearch"
+	transportservice "shanraq.com/internal/services/transport"
+	viewingservice "shanraq.com/internal/services/viewing"
+	workspaceservice "shanraq.com/internal/services/workspace"
+	"shanraq.com/internal/web"
+)
+
+// Deps aggregates dependencies required to build the HTTP router.
+type Deps struct {
+	Logger           zerolog.Logger
+	Config           config.Config
+	Renderer         *web.Renderer
+	TransportService transportservice.Service
+	AgencyService    agencyservice.Service
+	ListingService   listingservice.Service
+	AuthRegistry     *auth.ProviderRegistry
+	SessionManager   *session.Manager
+	WorkspaceService workspaceservice.Service
+	FXService        fx.Service
+	MediaService     mediaservice.Service
+	SavedSearches    savedsearchservice.Service
+	Favorites        favoriteservice.Service
+	Rentals          rentalservice.Service
+	Viewings         viewingservice.Service
+	Leads            leadservice.Service
+	Offers           offerservice.Service
+	Placements       placementservice.Service
+	Duplicates       dedupeservice.Service
+	Outbox           notify.Outbox
+	MortgageDefaults finance.Table
+}
@@ -81,6 +81,11 @@ func Router(cfg config.Config, logger zerolog.Logger, registry *auth.ProviderReg
 				respondJSON(w, http.StatusBadGateway, map[string]string{"error": "exchange_failed"})
 				return
 			}
+			if identity.Key() == "" {
+				logger.Error().Str("provider", providerName).Msg("auth_identity_incomplete")
+				respondJSON(w, http.StatusBadGateway, map[string]string{"error": "incomplete_identity"})
+				return
+			}
 
 			if sessions != nil {
 				if _, err := sessions.Create(w, identity); err != nil {
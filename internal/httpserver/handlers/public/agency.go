@@ -0,0 +1,108 @@
+package public
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/config"
+	"shanraq.com/internal/format"
+	"shanraq.com/internal/i18n"
+	agencyservice "shanraq.com/internal/services/agency"
+	listingservice "shanraq.com/internal/services/listing"
+	"shanraq.com/internal/sitemap"
+	"shanraq.com/internal/web"
+)
+
+// agencyListingsLimit caps the published listings shown on an agency profile.
+const agencyListingsLimit = 12
+
+// agencyPage renders the public profile of the {id} agency with its realtors and
+// published listings.
+func agencyPage(
+	cfg config.Config,
+	logger zerolog.Logger,
+	renderer *web.Renderer,
+	listingSvc listingservice.Service,
+	agencySvc agencyservice.Service,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if renderer == nil || agencySvc == nil {
+			http.NotFound(w, r)
+			return
+		}
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		agencies, err := agencySvc.ListAgencies(r.Context())
+		if err != nil {
+			logger.Error().Err(err).Msg("get_agency_page")
+			http.Error(w, "unable to load agency", http.StatusInternalServerError)
+			return
+		}
+		var agency *agencyservice.Agency
+		for idx := range agencies {
+			if agencies[idx].ID == id {
+				agency = &agencies[idx]
+				break
+			}
+		}
+		if agency == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		locale := i18n.Negotiate(r)
+		w.Header().Add("Vary", "Accept-Language")
+		w.Header().Set("Content-Language", locale)
+
+		var members []agencyservice.Realtor
+		if realtors, err := agencySvc.ListRealtors(r.Context()); err != nil {
+			logger.Warn().Err(err).Msg("fetch_agency_realtors")
+		} else {
+			for _, realtor := range realtors {
+				if realtor.AgencyID == agency.ID {
+					members = append(members, realtor)
+				}
+			}
+		}
+
+		data := &web.AgencyPageData{
+			Agency:   web.MapAgencies([]agencyservice.Agency{*agency})[0],
+			Realtors: web.MapRealtors(members),
+		}
+		if listingSvc != nil {
+			listings, _, err := listingSvc.List(r.Context(), listingservice.ListFilter{
+				AgencyID: agency.ID,
+				Viewer:   listingservice.Viewer{},
+				Sort:     listingservice.SortNewest,
+				Limit:    agencyListingsLimit,
+			})
+			if err != nil {
+				logger.Warn().Err(err).Msg("fetch_agency_listings")
+			} else {
+				if err := listingSvc.Localize(r.Context(), listings, locale); err != nil {
+					logger.Warn().Err(err).Msg("localize_agency_listings")
+				}
+				data.Listings = web.MapListings(listings, format.FromRequest(r))
+			}
+		}
+		data.BrandName = strings.Title(strings.TrimSpace(cfg.App.Name))
+		data.Lang = locale
+		data.Dir = i18n.Direction(locale)
+		data.CanonicalURL = sitemap.LocalizedURL(cfg.HTTP.PublicBaseURL, web.AgencyURL(*agency), locale)
+		data.StructuredData = []any{web.AgencyJSONLD(*agency, members, cfg.HTTP.PublicBaseURL)}
+
+		w.Header().Set("X-App-Name", cfg.App.Name)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := renderer.RenderAgency(w, data); err != nil {
+			logger.Error().Err(err).Msg("render_agency")
+			http.Error(w, "unable to render", http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package public
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/config"
+	"shanraq.com/internal/format"
+	"shanraq.com/internal/i18n"
+	agencyservice "shanraq.com/internal/services/agency"
+	"shanraq.com/internal/services/fx"
+	listingservice "shanraq.com/internal/services/listing"
+	"shanraq.com/internal/web"
+)
+
+// comparePage renders /compare?ids=a,b,c side by side. Unknown or hidden listings
+// answer 404, malformed id lists 400.
+func comparePage(
+	cfg config.Config,
+	logger zerolog.Logger,
+	renderer *web.Renderer,
+	listingSvc listingservice.Service,
+	agencySvc agencyservice.Service,
+	fxSvc fx.Service,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if renderer == nil || listingSvc == nil || fxSvc == nil {
+			http.NotFound(w, r)
+			return
+		}
+		ids, err := listingservice.ParseCompareIDs(r.URL.Query()["ids"])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		filter, err := listingservice.ParseFilter(url.Values{"currency": r.URL.Query()["currency"]})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		viewer := pageViewer(r, logger, agencySvc)
+		listings := make([]listingservice.Listing, 0, len(ids))
+		for _, id := range ids {
+			listing, err := listingSvc.Get(r.Context(), id)
+			if err != nil || !viewer.CanSee(listing) {
+				if err == nil || errors.Is(err, listingservice.ErrNotFound) {
+					http.NotFound(w, r)
+					return
+				}
+				logger.Error().Err(err).Str("id", id.String()).Msg("get_compare_listing")
+				http.Error(w, "unable to load listings", http.StatusInternalServerError)
+				return
+			}
+			listings = append(listings, listing)
+		}
+
+		locale := i18n.Negotiate(r)
+		w.Header().Add("Vary", "Accept-Language")
+		w.Header().Set("Content-Language", locale)
+		if err := listingSvc.Localize(r.Context(), listings, locale); err != nil {
+			logger.Warn().Err(err).Msg("localize_compare_listings")
+		}
+
+		table, err := fxSvc.Table(r.Context())
+		if err != nil {
+			logger.Error().Err(err).Msg("load_fx_table")
+			http.Error(w, "unable to load exchange rates", http.StatusInternalServerError)
+			return
+		}
+		comparison, err := listingservice.Compare(listings, table, filter.Currency, format.FromRequest(r))
+		if err != nil {
+			if errors.Is(err, fx.ErrUnknownCurrency) {
+				http.Error(w, "unknown currency", http.StatusBadRequest)
+				return
+			}
+			logger.Error().Err(err).Msg("compare_listings")
+			http.Error(w, "unable to compare listings", http.StatusInternalServerError)
+			return
+		}
+
+		data := &web.ComparePageData{Currency: comparison.Currency}
+		data.Columns, data.Rows = web.MapComparison(comparison)
+		data.BrandName = strings.Title(strings.TrimSpace(cfg.App.Name))
+		data.Lang = locale
+		data.Dir = i18n.Direction(locale)
+
+		w.Header().Set("X-App-Name", cfg.App.Name)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := renderer.RenderCompare(w, data); err != nil {
+			logger.Error().Err(err).Msg("render_compare")
+			http.Error(w, "unable to render", http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package public
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/config"
+	"shanraq.com/internal/feed"
+	"shanraq.com/internal/format"
+	agencyservice "shanraq.com/internal/services/agency"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+// feedSize is the number of newest listings a feed carries.
+const feedSize = 50
+
+// listingsFeed serves the newest published listings as Atom or RSS, narrowed by
+// country=, type= and agency=. Responses carry an ETag and Last-Modified so
+// readers can poll with conditional requests.
+func listingsFeed(
+	cfg config.Config,
+	logger zerolog.Logger,
+	listingSvc listingservice.Service,
+	agencySvc agencyservice.Service,
+	kind feed.Format,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if listingSvc == nil {
+			http.NotFound(w, r)
+			return
+		}
+		query := r.URL.Query()
+		filter, err := listingservice.ParseFilter(url.Values{
+			"country":   {query.Get("country")},
+			"type":      {query.Get("type")},
+			"agency_id": {query.Get("agency")},
+		})
+		if err != nil {
+			http.Error(w, strings.ReplaceAll(err.Error(), "agency_id", "agency"), http.StatusBadRequest)
+			return
+		}
+		filter.Viewer = listingservice.Viewer{}
+		filter.Sort = listingservice.SortNewest
+		filter.Limit = feedSize
+
+		listings, _, err := listingSvc.List(r.Context(), filter)
+		if err != nil {
+			logger.Error().Err(err).Msg("listings_feed")
+			http.Error(w, "unable to build feed", http.StatusInternalServerError)
+			return
+		}
+
+		base := strings.TrimRight(cfg.HTTP.PublicBaseURL, "/")
+		brand := strings.Title(strings.TrimSpace(cfg.App.Name))
+		f := format.FromRequest(r)
+		items := make([]feed.Item, 0, len(listings))
+		for _, l := range listings {
+			items = append(items, listingItem(l, base, f))
+		}
+		out := feed.Feed{
+			Title:    feedTitle(r, logger, agencySvc, filter, brand),
+			Subtitle: "Newly published property listings on " + brand,
+			Link:     base + "/",
+			Self:     base + r.URL.Path + canonicalFeedQuery(filter),
+			Author:   brand,
+			Language: "en",
+			Updated:  feed.LastUpdated(items),
+			Items:    items,
+		}
+		if out.Updated.IsZero() {
+			out.Updated = time.Unix(0, 0)
+		}
+
+		var buf bytes.Buffer
+		if err := feed.Write(&buf, out, kind); err != nil {
+			logger.Error().Err(err).Msg("encode_listings_feed")
+			http.Error(w, "unable to build feed", http.StatusInternalServerError)
+			return
+		}
+		sum := sha256.Sum256(buf.Bytes())
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:12])+`"`)
+		w.Header().Set("Content-Type", kind.ContentType())
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		http.ServeContent(w, r, "", out.Updated, bytes.NewReader(buf.Bytes()))
+	}
+}
+
+// listingItem turns a listing into a feed entry. Price changes bump UpdatedAt, so
+// readers see reductions as updated entries.
+func listingItem(l listingservice.Listing, base string, f format.Formatter) feed.Item {
+	item := feed.Item{
+		ID:      "urn:uuid:" + l.ID.String(),
+		Title:   l.Title,
+		Link:    base + l.DetailsURL,
+		Image:   l.ImageURL,
+		Updated: l.UpdatedAt,
+	}
+	if l.PublishedAt != nil {
+		item.Published = *l.PublishedAt
+	}
+	parts := []string{l.FormattedPrice(f)}
+	if area := l.FormattedArea(f); area != "" {
+		parts = append(parts, area)
+	}
+	parts = append(parts, l.LocationString())
+	if l.Summary != "" {
+		parts = append(parts, l.Summary)
+	}
+	item.Summary = strings.Join(parts, " · ")
+	item.Categories = append(item.Categories, string(l.Type), l.Country)
+	return item
+}
+
+// feedTitle names the feed after its filters, e.g.
+// "New residential listings in PT from Atlas Heritage Homes · Shanraq".
+func feedTitle(r *http.Request, logger zerolog.Logger, agencySvc agencyservice.Service, filter listingservice.ListFilter, brand string) string {
+	title := "New listings"
+	if filter.Type != "" {
+		title = "New " + string(filter.Type) + " listings"
+	}
+	if filter.Country != "" {
+		title += " in " + filter.Country
+	}
+	if filter.AgencyID != uuid.Nil && agencySvc != nil {
+		agencies, err := agencySvc.ListAgencies(r.Context())
+		if err != nil {
+			logger.Warn().Err(err).Msg("fetch_feed_agency")
+		}
+		for _, a := range agencies {
+			if a.ID == filter.AgencyID {
+				title += " from " + a.Name
+				break
+			}
+		}
+	}
+	return title + " · " + brand
+}
+
+// canonicalFeedQuery rebuilds the query from the parsed filters so the self link,
+// and with it the Atom feed ID, does not vary with spelling or unrelated parameters.
+func canonicalFeedQuery(filter listingservice.ListFilter) string {
+	canonical := url.Values{}
+	if filter.Country != "" {
+		canonical.Set("country", filter.Country)
+	}
+	if filter.Type != "" {
+		canonical.Set("type", string(filter.Type))
+	}
+	if filter.AgencyID != uuid.Nil {
+		canonical.Set("agency", filter.AgencyID.String())
+	}
+	if len(canonical) == 0 {
+		return ""
+	}
+	return "?" + canonical.Encode()
+}
@@ -0,0 +1,153 @@
+package public
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/auth/session"
+	"shanraq.com/internal/config"
+	"shanraq.com/internal/format"
+	"shanraq.com/internal/i18n"
+	agencyservice "shanraq.com/internal/services/agency"
+	"shanraq.com/internal/services/finance"
+	listingservice "shanraq.com/internal/services/listing"
+	mediaservice "shanraq.com/internal/services/media"
+	"shanraq.com/internal/sitemap"
+	"shanraq.com/internal/web"
+)
+
+// listingPage renders the detail page of the {slug} listing. Listings the visitor is
+// not allowed to see answer 404 exactly like missing ones.
+func listingPage(
+	cfg config.Config,
+	logger zerolog.Logger,
+	renderer *web.Renderer,
+	listingSvc listingservice.Service,
+	agencySvc agencyservice.Service,
+	mediaSvc mediaservice.Service,
+	mortgageDefaults finance.Table,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if renderer == nil || listingSvc == nil {
+			http.NotFound(w, r)
+			return
+		}
+		slug := strings.ToLower(strings.TrimSpace(chi.URLParam(r, "slug")))
+
+		viewer := pageViewer(r, logger, agencySvc)
+		listing, err := listingSvc.GetBySlug(r.Context(), slug)
+		if err != nil || !viewer.CanSee(listing) {
+			if err == nil || errors.Is(err, listingservice.ErrNotFound) {
+				http.NotFound(w, r)
+				return
+			}
+			logger.Error().Err(err).Str("slug", slug).Msg("get_listing_page")
+			http.Error(w, "unable to load listing", http.StatusInternalServerError)
+			return
+		}
+
+		locale := i18n.Negotiate(r)
+		w.Header().Add("Vary", "Accept-Language")
+		w.Header().Set("Content-Language", locale)
+		localized := []listingservice.Listing{listing}
+		if err := listingSvc.Localize(r.Context(), localized, locale); err != nil {
+			logger.Warn().Err(err).Str("slug", slug).Msg("localize_listing_page")
+		}
+		listing = localized[0]
+
+		formatter := format.FromRequest(r)
+		data := &web.ListingPageData{Listing: web.MapListingDetail(listing, formatter)}
+		data.BrandName = strings.Title(strings.TrimSpace(cfg.App.Name))
+		data.Lang = locale
+		data.Dir = i18n.Direction(locale)
+		data.CanonicalURL = sitemap.LocalizedURL(cfg.HTTP.PublicBaseURL, listing.DetailsURL, locale)
+		data.StructuredData = []any{web.ListingJSONLD(listing, cfg.HTTP.PublicBaseURL)}
+
+		var media []mediaservice.Media
+		if mediaSvc != nil {
+			if media, err = mediaSvc.List(r.Context(), listing.ID); err != nil {
+				logger.Warn().Err(err).Str("slug", slug).Msg("fetch_listing_media")
+			}
+		}
+		data.Gallery = web.MapGallery(media, listing.ImageURL)
+
+		if !listing.IsRental() && listing.Price > 0 {
+			mortgage, err := finance.Calculate(mortgageDefaults, finance.MortgageInput{
+				Country:  listing.Country,
+				Currency: listing.Currency,
+				Price:    listing.Price,
+			})
+			if err != nil {
+				logger.Warn().Err(err).Str("slug", slug).Msg("estimate_listing_mortgage")
+			} else {
+				data.Mortgage = web.MapMortgageEstimate(mortgage, formatter)
+			}
+		}
+
+		if agencySvc != nil {
+			data.Agency, data.Realtors = listingContacts(r, logger, agencySvc, listing)
+		}
+
+		w.Header().Set("X-App-Name", cfg.App.Name)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := renderer.RenderListing(w, data); err != nil {
+			logger.Error().Err(err).Msg("render_listing")
+			http.Error(w, "unable to render", http.StatusInternalServerError)
+		}
+	}
+}
+
+// pageViewer resolves which unpublished listings the visitor may see. Lookup
+// failures degrade to the anonymous viewer.
+func pageViewer(r *http.Request, logger zerolog.Logger, agencySvc agencyservice.Service) listingservice.Viewer {
+	viewer := listingservice.Viewer{}
+	if identity, ok := session.IdentityFromContext(r.Context()); ok && agencySvc != nil {
+		ids, err := agencySvc.MemberAgencyIDs(r.Context(), identity.Email)
+		if err != nil {
+			logger.Warn().Err(err).Msg("resolve_viewer")
+		}
+		viewer.AgencyIDs = ids
+	}
+	return viewer
+}
+
+// listingContacts finds the agency behind a listing and its realtors, matching by
+// agency ID and falling back to the agency name carried on demo listings.
+func listingContacts(r *http.Request, logger zerolog.Logger, agencySvc agencyservice.Service, listing listingservice.Listing) (*web.AgencyCard, []web.RealtorCard) {
+	agencies, err := agencySvc.ListAgencies(r.Context())
+	if err != nil {
+		logger.Warn().Err(err).Msg("fetch_listing_agency")
+		return nil, nil
+	}
+	var agency *agencyservice.Agency
+	for idx := range agencies {
+		candidate := agencies[idx]
+		if (listing.AgencyID != uuid.Nil && candidate.ID == listing.AgencyID) ||
+			(listing.AgencyID == uuid.Nil && strings.EqualFold(candidate.Name, listing.AgencyName)) {
+			agency = &candidate
+			break
+		}
+	}
+	if agency == nil {
+		return nil, nil
+	}
+	card := web.MapAgencies([]agencyservice.Agency{*agency})[0]
+
+	realtors, err := agencySvc.ListRealtors(r.Context())
+	if err != nil {
+		logger.Warn().Err(err).Msg("fetch_listing_realtors")
+		return &card, nil
+	}
+	members := make([]agencyservice.Realtor, 0, len(realtors))
+	for _, realtor := range realtors {
+		if realtor.AgencyID == agency.ID {
+			members = append(members, realtor)
+		}
+	}
+	return &card, web.MapRealtors(members)
+}
@@ -0,0 +1,51 @@
+package public
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/config"
+	agencyservice "shanraq.com/internal/services/agency"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+// mediaFiles serves uploads from the local blob store. Every blob lives at
+// listings/{listingID}/{mediaID}/{file} and is only served to visitors who may see
+// the listing, so photos of drafts and archived listings stay private even to
+// someone who knows the URL. Anything else, directory listings included, is 404.
+func mediaFiles(cfg config.Config, logger zerolog.Logger, listingSvc listingservice.Service, agencySvc agencyservice.Service) http.Handler {
+	prefix := strings.TrimSuffix(cfg.Storage.PublicURL, "/") + "/"
+	files := http.StripPrefix(prefix, http.FileServer(http.Dir(cfg.Storage.LocalDir)))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(path.Clean(r.URL.Path), prefix)
+		parts := strings.Split(key, "/")
+		if len(parts) != 4 || parts[0] != "listings" || listingSvc == nil {
+			http.NotFound(w, r)
+			return
+		}
+		id, err := uuid.Parse(parts[1])
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		listing, err := listingSvc.Get(r.Context(), id)
+		if err != nil || !pageViewer(r, logger, agencySvc).CanSee(listing) {
+			if err == nil || errors.Is(err, listingservice.ErrNotFound) {
+				http.NotFound(w, r)
+				return
+			}
+			logger.Error().Err(err).Str("id", id.String()).Msg("get_media_listing")
+			http.Error(w, "unable to load media", http.StatusInternalServerError)
+			return
+		}
+		if !listing.Status.Public() {
+			w.Header().Set("Cache-Control", "private, no-store")
+		}
+		files.ServeHTTP(w, r)
+	})
+}
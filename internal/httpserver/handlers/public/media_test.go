@@ -0,0 +1,62 @@
+package public
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/config"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+func TestMediaFilesHideUnpublishedListings(t *testing.T) {
+	ctx := context.Background()
+	listings := listingservice.NewInMemoryService()
+	all, _, err := listings.List(ctx, listingservice.ListFilter{Limit: 2})
+	if err != nil || len(all) != 2 {
+		t.Fatalf("List() = %d listings, %v; want 2", len(all), err)
+	}
+	published, archived := all[0], all[1]
+	if _, err := listings.Transition(ctx, archived.ID, listingservice.TransitionInput{Status: listingservice.StatusArchived, ActorEmail: "agent@example.com"}); err != nil {
+		t.Fatalf("Transition(archived) error = %v", err)
+	}
+
+	var cfg config.Config
+	cfg.Storage.LocalDir = t.TempDir()
+	cfg.Storage.PublicURL = "/media"
+	blob := func(listingID uuid.UUID) string {
+		key := filepath.Join("listings", listingID.String(), uuid.NewString(), "original.jpg")
+		file := filepath.Join(cfg.Storage.LocalDir, key)
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatalf("MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(file, []byte("jpeg"), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		return "/media/" + filepath.ToSlash(key)
+	}
+	handler := mediaFiles(cfg, zerolog.Nop(), listings, nil)
+
+	cases := map[string]struct {
+		path string
+		want int
+	}{
+		"published listing": {blob(published.ID), http.StatusOK},
+		"archived listing":  {blob(archived.ID), http.StatusNotFound},
+		"listing directory": {"/media/listings/" + published.ID.String() + "/", http.StatusNotFound},
+		"store root":        {"/media/", http.StatusNotFound},
+	}
+	for name, tc := range cases {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if rec.Code != tc.want {
+			t.Errorf("%s: GET %s = %d, want %d", name, tc.path, rec.Code, tc.want)
+		}
+	}
+}
@@ -5,24 +5,35 @@ import (
 	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 
-	"shanraq.com/internal/config"
-	agencyservice "shanraq.com/internal/services/agency"
+	"shanraq.com/internal/feed"
+	"shanraq.com/internal/format"
+	"shanraq.com/internal/httpserver/deps"
+	"shanraq.com/internal/i18n"
 	listingservice "shanraq.com/internal/services/listing"
+	mediaservice "shanraq.com/internal/services/media"
+	placementservice "shanraq.com/internal/services/placement"
 	transportservice "shanraq.com/internal/services/transport"
+	"shanraq.com/internal/sitemap"
 	"shanraq.com/internal/web"
 )
 
 // Router exposes the public-facing endpoints.
-func Router(
-	cfg config.Config,
-	logger zerolog.Logger,
-	renderer *web.Renderer,
-	listingSvc listingservice.Service,
-	agencySvc agencyservice.Service,
-	transportSvc transportservice.Service,
-) chi.Router {
+func Router(d deps.Deps) chi.Router {
+	var (
+		cfg              = d.Config
+		logger           = d.Logger
+		renderer         = d.Renderer
+		listingSvc       = d.ListingService
+		agencySvc        = d.AgencyService
+		transportSvc     = d.TransportService
+		mediaSvc         = d.MediaService
+		fxSvc            = d.FXService
+		placementSvc     = d.Placements
+		mortgageDefaults = d.MortgageDefaults
+	)
 	r := chi.NewRouter()
 
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
@@ -35,13 +46,30 @@ func Router(
 			data.PageTitle = "Global Real Estate Platform · "
 			data.Description = "Discover, list, and manage properties and logistics partners across the world with Shanraq."
 			data.PageID = "home"
+			locale := i18n.Negotiate(r)
+			data.Lang = locale
+			data.Dir = i18n.Direction(locale)
+			data.CanonicalURL = sitemap.LocalizedURL(cfg.HTTP.PublicBaseURL, "/", locale)
+			w.Header().Add("Vary", "Accept-Language")
+			w.Header().Set("Content-Language", locale)
 
-			if listingSvc != nil {
-				featuredListings, err := listingSvc.Featured(r.Context(), 6)
+			if placementSvc != nil {
+				featuredListings, err := placementSvc.Featured(r.Context(), placementservice.FeaturedQuery{
+					Limit:   6,
+					Country: r.URL.Query().Get("country"),
+					Locale:  locale,
+				})
 				if err != nil {
 					logger.Warn().Err(err).Msg("fetch_featured_listings")
 				} else {
-					data.FeaturedListings = web.MapListings(featuredListings)
+					if err := listingSvc.Localize(r.Context(), featuredListings, locale); err != nil {
+						logger.Warn().Err(err).Msg("localize_featured_listings")
+					}
+					data.FeaturedListings = web.MapListings(featuredListings, format.FromRequest(r))
+					for _, l := range featuredListings {
+						data.StructuredData = append(data.StructuredData, web.ListingJSONLD(l, cfg.HTTP.PublicBaseURL))
+					}
+					applyCoverThumbnails(r, logger, mediaSvc, featuredListings, data.FeaturedListings)
 				}
 			}
 			if agencySvc != nil {
@@ -50,6 +78,9 @@ func Router(
 					logger.Warn().Err(err).Msg("fetch_featured_agencies")
 				} else {
 					data.FeaturedAgencies = web.MapAgencies(agencies)
+					for _, a := range agencies {
+						data.StructuredData = append(data.StructuredData, web.AgencyJSONLD(a, nil, cfg.HTTP.PublicBaseURL))
+					}
 				}
 				if realtors, err := agencySvc.FeaturedRealtors(r.Context(), 4); err == nil {
 					data.FeaturedRealtors = web.MapRealtors(realtors)
@@ -61,6 +92,9 @@ func Router(
 					logger.Warn().Err(err).Msg("fetch_featured_transport")
 				} else {
 					data.FeaturedTransport = web.MapTransportCompanies(companies)
+					for _, c := range companies {
+						data.StructuredData = append(data.StructuredData, web.TransportJSONLD(c, cfg.HTTP.PublicBaseURL))
+					}
 				}
 			}
 
@@ -75,6 +109,19 @@ func Router(
 		_, _ = w.Write([]byte("Welcome to Shanraq Real Estate"))
 	})
 
+	r.Get("/listings/{slug}", listingPage(cfg, logger, renderer, listingSvc, agencySvc, mediaSvc, mortgageDefaults))
+	r.Get("/compare", comparePage(cfg, logger, renderer, listingSvc, agencySvc, fxSvc))
+	r.Get("/agencies/{id}", agencyPage(cfg, logger, renderer, listingSvc, agencySvc))
+	r.Get("/transport/{slug}", transportPage(cfg, logger, renderer, transportSvc))
+
+	source := sitemapSource{listings: listingSvc, agencies: agencySvc, transport: transportSvc}
+	r.Get("/sitemap.xml", sitemapIndex(cfg, logger, source))
+	r.Get(sitemapDir+"/{name}", sitemapFile(cfg, logger, source))
+	r.Get("/robots.txt", robotsTxt(cfg))
+
+	r.Get("/feeds/listings.atom", listingsFeed(cfg, logger, listingSvc, agencySvc, feed.FormatAtom))
+	r.Get("/feeds/listings.rss", listingsFeed(cfg, logger, listingSvc, agencySvc, feed.FormatRSS))
+
 	r.Get("/dashboard", func(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/dashboard/", http.StatusTemporaryRedirect)
 	})
@@ -85,5 +132,32 @@ func Router(
 	dashboardHandler := http.StripPrefix("/dashboard/", http.FileServer(http.Dir("web/dashboard")))
 	r.Handle("/dashboard/*", dashboardHandler)
 
+	if cfg.Storage.Driver == "local" && strings.HasPrefix(cfg.Storage.PublicURL, "/") {
+		prefix := strings.TrimSuffix(cfg.Storage.PublicURL, "/") + "/"
+		r.Handle(prefix+"*", mediaFiles(cfg, logger, listingSvc, agencySvc))
+	}
+
 	return r
 }
+
+// applyCoverThumbnails swaps the listing image for the generated thumbnail of its
+// cover photo when one has been uploaded.
+func applyCoverThumbnails(r *http.Request, logger zerolog.Logger, mediaSvc mediaservice.Service, listings []listingservice.Listing, cards []web.ListingCard) {
+	if mediaSvc == nil || len(listings) == 0 {
+		return
+	}
+	ids := make([]uuid.UUID, 0, len(listings))
+	for _, l := range listings {
+		ids = append(ids, l.ID)
+	}
+	covers, err := mediaSvc.Covers(r.Context(), ids)
+	if err != nil {
+		logger.Warn().Err(err).Msg("fetch_listing_covers")
+		return
+	}
+	for idx, l := range listings {
+		if cover, ok := covers[l.ID]; ok {
+			cards[idx].Thumbnail = cover.ThumbnailURL()
+		}
+	}
+}
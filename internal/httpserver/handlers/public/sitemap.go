@@ -0,0 +1,192 @@
+package public
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/config"
+	agencyservice "shanraq.com/internal/services/agency"
+	listingservice "shanraq.com/internal/services/listing"
+	transportservice "shanraq.com/internal/services/transport"
+	"shanraq.com/internal/sitemap"
+	"shanraq.com/internal/web"
+)
+
+// sitemapDir is where the per-section sitemaps are served from.
+const sitemapDir = "/sitemaps"
+
+// sitemapSource enumerates the public pages of each sitemap section. Only listings
+// anonymous visitors can see and active transport companies are included.
+type sitemapSource struct {
+	listings  listingservice.Service
+	agencies  agencyservice.Service
+	transport transportservice.Service
+}
+
+func (s sitemapSource) total(ctx context.Context, kind sitemap.Kind) (int, error) {
+	switch kind {
+	case sitemap.KindPages:
+		return 1, nil
+	case sitemap.KindListings:
+		if s.listings == nil {
+			return 0, nil
+		}
+		_, total, err := s.listings.List(ctx, listingservice.ListFilter{Viewer: listingservice.Viewer{}, Limit: 1})
+		return total, err
+	case sitemap.KindAgencies:
+		if s.agencies == nil {
+			return 0, nil
+		}
+		agencies, err := s.agencies.ListAgencies(ctx)
+		return len(agencies), err
+	case sitemap.KindTransport:
+		if s.transport == nil {
+			return 0, nil
+		}
+		_, total, err := s.transport.List(ctx, transportservice.ListFilter{ActiveOnly: true, Limit: 1})
+		return total, err
+	}
+	return 0, fmt.Errorf("unknown sitemap section %q", kind)
+}
+
+// entries returns up to sitemap.PerFile pages of the section starting at offset.
+func (s sitemapSource) entries(ctx context.Context, kind sitemap.Kind, offset int) ([]sitemap.Entry, error) {
+	var out []sitemap.Entry
+	switch kind {
+	case sitemap.KindPages:
+		if offset == 0 {
+			out = append(out, sitemap.Entry{Path: "/"})
+		}
+	case sitemap.KindListings:
+		if s.listings == nil {
+			return nil, nil
+		}
+		filter := listingservice.ListFilter{Viewer: listingservice.Viewer{}, Sort: listingservice.SortNewest, Limit: listingservice.MaxPageSize, Offset: offset}
+		for len(out) < sitemap.PerFile {
+			if remaining := sitemap.PerFile - len(out); remaining < filter.Limit {
+				filter.Limit = remaining
+			}
+			page, total, err := s.listings.List(ctx, filter)
+			if err != nil {
+				return nil, err
+			}
+			for _, l := range page {
+				out = append(out, sitemap.Entry{Path: l.DetailsURL, LastMod: l.UpdatedAt})
+			}
+			filter.Offset += len(page)
+			if len(page) == 0 || filter.Offset >= total {
+				break
+			}
+		}
+	case sitemap.KindAgencies:
+		if s.agencies == nil {
+			return nil, nil
+		}
+		agencies, err := s.agencies.ListAgencies(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range window(agencies, offset) {
+			out = append(out, sitemap.Entry{Path: web.AgencyURL(a)})
+		}
+	case sitemap.KindTransport:
+		if s.transport == nil {
+			return nil, nil
+		}
+		companies, _, err := s.transport.List(ctx, transportservice.ListFilter{ActiveOnly: true, Limit: sitemap.PerFile, Offset: offset})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range companies {
+			out = append(out, sitemap.Entry{Path: web.TransportURL(c), LastMod: c.UpdatedAt})
+		}
+	}
+	return out, nil
+}
+
+func window[T any](items []T, offset int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if len(items) > sitemap.PerFile {
+		items = items[:sitemap.PerFile]
+	}
+	return items
+}
+
+// sitemapIndex serves /sitemap.xml, pointing at one sitemap per section, locale and
+// page of sitemap.PerFile URLs.
+func sitemapIndex(cfg config.Config, logger zerolog.Logger, source sitemapSource) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var files []sitemap.File
+		for _, kind := range sitemap.Kinds {
+			total, err := source.total(r.Context(), kind)
+			if err != nil {
+				logger.Error().Err(err).Str("section", string(kind)).Msg("sitemap_index")
+				http.Error(w, "unable to build sitemap", http.StatusInternalServerError)
+				return
+			}
+			files = append(files, sitemap.Files(kind, total)...)
+		}
+		writeSitemap(w, logger, sitemap.NewIndex(cfg.HTTP.PublicBaseURL, sitemapDir, files))
+	}
+}
+
+// sitemapFile serves one section sitemap such as /sitemaps/listings-sv-2.xml.
+func sitemapFile(cfg config.Config, logger zerolog.Logger, source sitemapSource) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		file, err := sitemap.ParseName(chi.URLParam(r, "name"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		entries, err := source.entries(r.Context(), file.Kind, file.Offset())
+		if err != nil {
+			logger.Error().Err(err).Str("sitemap", file.Name()).Msg("sitemap_file")
+			http.Error(w, "unable to build sitemap", http.StatusInternalServerError)
+			return
+		}
+		if len(entries) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		writeSitemap(w, logger, sitemap.NewURLSet(cfg.HTTP.PublicBaseURL, file.Locale, entries))
+	}
+}
+
+func writeSitemap(w http.ResponseWriter, logger zerolog.Logger, doc any) {
+	var buf bytes.Buffer
+	if err := sitemap.Write(&buf, doc); err != nil {
+		logger.Error().Err(err).Msg("encode_sitemap")
+		http.Error(w, "unable to build sitemap", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// robotsTxt keeps crawlers on the public pages and points them at the sitemap.
+func robotsTxt(cfg config.Config) http.HandlerFunc {
+	body := strings.Join([]string{
+		"User-agent: *",
+		"Allow: /",
+		"Disallow: /api/",
+		"Disallow: /auth/",
+		"Disallow: /dashboard/",
+		"Disallow: /compare",
+		"",
+		"Sitemap: " + strings.TrimRight(cfg.HTTP.PublicBaseURL, "/") + "/sitemap.xml",
+		"",
+	}, "\n")
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte(body))
+	}
+}
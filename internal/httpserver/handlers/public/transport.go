@@ -0,0 +1,81 @@
+package public
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/config"
+	"shanraq.com/internal/i18n"
+	transportservice "shanraq.com/internal/services/transport"
+	"shanraq.com/internal/sitemap"
+	"shanraq.com/internal/web"
+)
+
+// transportPage renders the public profile of the {slug} moving company. Inactive
+// companies answer 404.
+func transportPage(
+	cfg config.Config,
+	logger zerolog.Logger,
+	renderer *web.Renderer,
+	transportSvc transportservice.Service,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if renderer == nil || transportSvc == nil {
+			http.NotFound(w, r)
+			return
+		}
+		slug := strings.ToLower(strings.TrimSpace(chi.URLParam(r, "slug")))
+		company, found, err := companyBySlug(r.Context(), transportSvc, slug)
+		if err != nil {
+			logger.Error().Err(err).Str("slug", slug).Msg("get_transport_page")
+			http.Error(w, "unable to load company", http.StatusInternalServerError)
+			return
+		}
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+
+		locale := i18n.Negotiate(r)
+		w.Header().Add("Vary", "Accept-Language")
+		w.Header().Set("Content-Language", locale)
+
+		data := &web.TransportPageData{Company: web.MapTransportCompanies([]transportservice.Company{company})[0]}
+		data.BrandName = strings.Title(strings.TrimSpace(cfg.App.Name))
+		data.Lang = locale
+		data.Dir = i18n.Direction(locale)
+		data.CanonicalURL = sitemap.LocalizedURL(cfg.HTTP.PublicBaseURL, web.TransportURL(company), locale)
+		data.StructuredData = []any{web.TransportJSONLD(company, cfg.HTTP.PublicBaseURL)}
+
+		w.Header().Set("X-App-Name", cfg.App.Name)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := renderer.RenderTransport(w, data); err != nil {
+			logger.Error().Err(err).Msg("render_transport")
+			http.Error(w, "unable to render", http.StatusInternalServerError)
+		}
+	}
+}
+
+// companyBySlug pages through the active companies looking for the slug.
+func companyBySlug(ctx context.Context, svc transportservice.Service, slug string) (transportservice.Company, bool, error) {
+	filter := transportservice.ListFilter{ActiveOnly: true, Limit: 100}
+	for {
+		companies, total, err := svc.List(ctx, filter)
+		if err != nil {
+			return transportservice.Company{}, false, err
+		}
+		for _, c := range companies {
+			if c.Slug == slug {
+				return c, true, nil
+			}
+		}
+		filter.Offset += len(companies)
+		if len(companies) == 0 || filter.Offset >= total {
+			return transportservice.Company{}, false, nil
+		}
+	}
+}
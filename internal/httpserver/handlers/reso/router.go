@@ -0,0 +1,248 @@
+package reso
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/config"
+	"shanraq.com/internal/reso"
+	agencyservice "shanraq.com/internal/services/agency"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+// basePath is where the router is mounted; OData context and next links use it.
+const basePath = "/api/reso"
+
+type collectionResponse struct {
+	Context  string        `json:"@odata.context"`
+	Count    *int          `json:"@odata.count,omitempty"`
+	Value    []reso.Record `json:"value"`
+	NextLink string        `json:"@odata.nextLink,omitempty"`
+}
+
+// Router exposes read-only RESO Web API resources for syndication partners. Only
+// listings visible to anonymous visitors are syndicated.
+func Router(cfg config.Config, logger zerolog.Logger, listings listingservice.Service, agencies agencyservice.Service) chi.Router {
+	r := chi.NewRouter()
+	baseURL := cfg.HTTP.PublicBaseURL
+
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, map[string]any{
+			"@odata.context": basePath + "/$metadata",
+			"value": []map[string]string{
+				{"name": "Property", "kind": "EntitySet", "url": "Property"},
+				{"name": "Member", "kind": "EntitySet", "url": "Member"},
+			},
+		})
+	})
+
+	r.Get("/Property", func(w http.ResponseWriter, r *http.Request) {
+		query, err := reso.ParseQuery(r.URL.Query(), reso.PropertyFields)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_query", err.Error())
+			return
+		}
+		filter, exact := reso.ExactListingFilter(query)
+		if exact {
+			page, total, err := listingPage(r, listings, filter, query)
+			if err != nil {
+				logger.Error().Err(err).Msg("reso_list_properties_failed")
+				respondError(w, http.StatusInternalServerError, "list_failed", "unable to list properties")
+				return
+			}
+			records := make([]reso.Record, 0, len(page))
+			for _, l := range page {
+				records = append(records, reso.Property(l, baseURL))
+			}
+			// The service already filtered and paged; only $select is left to apply.
+			selected, _ := reso.Query{Select: query.Select, Top: len(records)}.Apply(records)
+			respondPage(w, r, "Property", query, selected, total)
+			return
+		}
+		found, err := publishedListings(r, listings, filter)
+		if err != nil {
+			logger.Error().Err(err).Msg("reso_list_properties_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed", "unable to list properties")
+			return
+		}
+		records := make([]reso.Record, 0, len(found))
+		for _, l := range found {
+			records = append(records, reso.Property(l, baseURL))
+		}
+		respondCollection(w, r, "Property", query, records)
+	})
+
+	r.Get("/Property('{key}')", func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(chi.URLParam(r, "key"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_id", "ListingKey must be a UUID")
+			return
+		}
+		listing, err := listings.Get(r.Context(), id)
+		if err != nil || !(listingservice.Viewer{}).CanSee(listing) {
+			if err == nil || errors.Is(err, listingservice.ErrNotFound) {
+				respondError(w, http.StatusNotFound, "not_found", "property not found")
+				return
+			}
+			logger.Error().Err(err).Str("id", id.String()).Msg("reso_get_property_failed")
+			respondError(w, http.StatusInternalServerError, "get_failed", "unable to load property")
+			return
+		}
+		respondEntity(w, r, "Property", reso.PropertyFields, reso.Property(listing, baseURL))
+	})
+
+	r.Get("/Member", func(w http.ResponseWriter, r *http.Request) {
+		query, err := reso.ParseQuery(r.URL.Query(), reso.MemberFields)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_query", err.Error())
+			return
+		}
+		realtors, err := agencies.ListRealtors(r.Context())
+		if err != nil {
+			logger.Error().Err(err).Msg("reso_list_members_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed", "unable to list members")
+			return
+		}
+		records := make([]reso.Record, 0, len(realtors))
+		for _, realtor := range realtors {
+			records = append(records, reso.Member(realtor))
+		}
+		respondCollection(w, r, "Member", query, records)
+	})
+
+	r.Get("/Member('{key}')", func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(chi.URLParam(r, "key"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_id", "MemberKey must be a UUID")
+			return
+		}
+		realtors, err := agencies.ListRealtors(r.Context())
+		if err != nil {
+			logger.Error().Err(err).Msg("reso_get_member_failed")
+			respondError(w, http.StatusInternalServerError, "get_failed", "unable to load member")
+			return
+		}
+		for _, realtor := range realtors {
+			if realtor.ID == id {
+				respondEntity(w, r, "Member", reso.MemberFields, reso.Member(realtor))
+				return
+			}
+		}
+		respondError(w, http.StatusNotFound, "not_found", "member not found")
+	})
+
+	return r
+}
+
+// MaxScan caps how many listings a /Property query reads when its $filter or
+// $orderby cannot be handed to the listing service; matches beyond it are neither
+// returned nor counted.
+const MaxScan = 2000
+
+// listingPage fetches one $top/$skip page of an exactly pushed-down query along
+// with the total number of matches.
+func listingPage(r *http.Request, svc listingservice.Service, filter listingservice.ListFilter, query reso.Query) ([]listingservice.Listing, int, error) {
+	filter.Viewer = listingservice.Viewer{}
+	filter.Sort = listingservice.SortNewest
+	filter.Offset = query.Skip
+	filter.Limit = query.Top
+	if query.Top == 0 {
+		// Only the count was asked for; the smallest page still reports it.
+		filter.Limit = 1
+	}
+	page, total, err := svc.List(r.Context(), filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if query.Top == 0 {
+		page = nil
+	}
+	return page, total, nil
+}
+
+// publishedListings pages through the listings matching the pushed-down filter,
+// stopping after MaxScan.
+func publishedListings(r *http.Request, svc listingservice.Service, filter listingservice.ListFilter) ([]listingservice.Listing, error) {
+	filter.Viewer = listingservice.Viewer{}
+	filter.Sort = listingservice.SortNewest
+	filter.Limit = listingservice.MaxPageSize
+	var out []listingservice.Listing
+	for filter.Offset = 0; filter.Offset < MaxScan; filter.Offset += filter.Limit {
+		page, total, err := svc.List(r.Context(), filter)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, page...)
+		if len(page) == 0 || filter.Offset+len(page) >= total {
+			break
+		}
+	}
+	if len(out) > MaxScan {
+		out = out[:MaxScan]
+	}
+	return out, nil
+}
+
+func respondCollection(w http.ResponseWriter, r *http.Request, resource string, query reso.Query, records []reso.Record) {
+	page, total := query.Apply(records)
+	respondPage(w, r, resource, query, page, total)
+}
+
+// respondPage writes a collection page, adding the count and a next link.
+func respondPage(w http.ResponseWriter, r *http.Request, resource string, query reso.Query, page []reso.Record, total int) {
+	response := collectionResponse{
+		Context: basePath + "/$metadata#" + resource,
+		Value:   page,
+	}
+	if query.Count {
+		response.Count = &total
+	}
+	if next := query.Skip + len(page); query.Top > 0 && next < total {
+		values := cloneValues(r.URL.Query())
+		values.Set("$skip", strconv.Itoa(next))
+		response.NextLink = basePath + "/" + resource + "?" + values.Encode()
+	}
+	respondJSON(w, http.StatusOK, response)
+}
+
+// respondEntity returns a single resource, honouring $select.
+func respondEntity(w http.ResponseWriter, r *http.Request, resource string, fields []string, record reso.Record) {
+	query, err := reso.ParseQuery(url.Values{"$select": r.URL.Query()["$select"]}, fields)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_query", err.Error())
+		return
+	}
+	page, _ := query.Apply([]reso.Record{record})
+	entity := page[0]
+	entity["@odata.context"] = basePath + "/$metadata#" + resource + "/$entity"
+	respondJSON(w, http.StatusOK, entity)
+}
+
+func cloneValues(values url.Values) url.Values {
+	out := make(url.Values, len(values))
+	for key, vals := range values {
+		out[key] = append([]string(nil), vals...)
+	}
+	return out
+}
+
+func respondJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("OData-Version", "4.0")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+// respondError uses the OData error body partners' clients expect.
+func respondError(w http.ResponseWriter, status int, code, message string) {
+	respondJSON(w, status, map[string]any{
+		"error": map[string]string{"code": code, "message": message},
+	})
+}
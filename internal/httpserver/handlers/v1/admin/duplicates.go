@@ -0,0 +1,158 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/auth/session"
+	dedupeservice "shanraq.com/internal/services/dedupe"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+type mergeRequest struct {
+	KeepID uuid.UUID `json:"keep_id"`
+}
+
+// mountDuplicates registers the review queue for probable duplicate listings.
+func mountDuplicates(r chi.Router, logger zerolog.Logger, svc dedupeservice.Service, listingSvc listingservice.Service) {
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		filter := dedupeservice.Filter{Status: dedupeservice.StatusPending}
+		if raw := strings.ToLower(strings.TrimSpace(query.Get("status"))); raw != "" {
+			filter.Status = dedupeservice.Status(raw)
+			if raw == "all" {
+				filter.Status = ""
+			} else if !filter.Status.Valid() {
+				respondError(w, http.StatusBadRequest, "unknown_status")
+				return
+			}
+		}
+		if raw := strings.TrimSpace(query.Get("listing_id")); raw != "" {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				respondError(w, http.StatusBadRequest, "invalid_listing_id")
+				return
+			}
+			filter.ListingID = id
+		}
+		filter.Limit, _ = strconv.Atoi(query.Get("limit"))
+		filter.Offset, _ = strconv.Atoi(query.Get("offset"))
+		if filter.Offset < 0 {
+			filter.Offset = 0
+		}
+
+		pairs, total, err := svc.List(r.Context(), filter)
+		if err != nil {
+			logger.Error().Err(err).Msg("list_duplicates_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		listings, err := pairListings(r.Context(), listingSvc, pairs)
+		if err != nil {
+			logger.Error().Err(err).Msg("load_duplicate_listings_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"data":     pairs,
+			"listings": listings,
+			"meta":     map[string]any{"total": total, "count": len(pairs), "offset": filter.Offset},
+		})
+	})
+
+	r.Post("/{id}/merge", func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_id")
+			return
+		}
+		var payload mergeRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		defer r.Body.Close()
+
+		identity, _ := session.IdentityFromContext(r.Context())
+		pair, err := svc.Resolve(r.Context(), id, dedupeservice.Resolution{
+			Status:     dedupeservice.StatusMerged,
+			KeepID:     payload.KeepID,
+			ActorEmail: identity.Email,
+		})
+		if err != nil {
+			respondDuplicateError(w, logger, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, pair)
+	})
+
+	r.Post("/{id}/distinct", func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_id")
+			return
+		}
+		identity, _ := session.IdentityFromContext(r.Context())
+		pair, err := svc.Resolve(r.Context(), id, dedupeservice.Resolution{
+			Status:     dedupeservice.StatusDistinct,
+			ActorEmail: identity.Email,
+		})
+		if err != nil {
+			respondDuplicateError(w, logger, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, pair)
+	})
+}
+
+// pairListings loads both sides of every pair on the page, whatever their status,
+// in as few listing queries as the listing page size allows.
+func pairListings(ctx context.Context, svc listingservice.Service, pairs []dedupeservice.Duplicate) (map[uuid.UUID]listingservice.Listing, error) {
+	listings := make(map[uuid.UUID]listingservice.Listing, 2*len(pairs))
+	if len(pairs) == 0 {
+		return listings, nil
+	}
+	ids := make([]uuid.UUID, 0, 2*len(pairs))
+	seen := make(map[uuid.UUID]struct{}, 2*len(pairs))
+	for _, pair := range pairs {
+		for _, id := range []uuid.UUID{pair.ListingID, pair.DuplicateID} {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				ids = append(ids, id)
+			}
+		}
+	}
+	for start := 0; start < len(ids); start += listingservice.MaxPageSize {
+		batch := ids[start:min(start+listingservice.MaxPageSize, len(ids))]
+		found, _, err := svc.List(ctx, listingservice.ListFilter{
+			IDs:    batch,
+			Viewer: listingservice.Viewer{Staff: true},
+			Limit:  len(batch),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range found {
+			listings[l.ID] = l
+		}
+	}
+	return listings, nil
+}
+
+func respondDuplicateError(w http.ResponseWriter, logger zerolog.Logger, err error) {
+	if errors.Is(err, dedupeservice.ErrNotFound) {
+		respondError(w, http.StatusNotFound, "not_found")
+		return
+	}
+	logger.Warn().Err(err).Msg("resolve_duplicate")
+	respondError(w, http.StatusBadRequest, err.Error())
+}
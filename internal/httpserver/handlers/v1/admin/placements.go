@@ -0,0 +1,145 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/auth/session"
+	listingservice "shanraq.com/internal/services/listing"
+	placementservice "shanraq.com/internal/services/placement"
+)
+
+type placementRequest struct {
+	ListingID uuid.UUID  `json:"listing_id"`
+	Position  int        `json:"position"`
+	Country   string     `json:"country"`
+	Locale    string     `json:"locale"`
+	StartsAt  *time.Time `json:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at"`
+	Note      string     `json:"note"`
+}
+
+func (p placementRequest) input(createdBy string) placementservice.Input {
+	input := placementservice.Input{
+		ListingID: p.ListingID,
+		Position:  p.Position,
+		Country:   p.Country,
+		Locale:    p.Locale,
+		EndsAt:    p.EndsAt,
+		Note:      p.Note,
+		CreatedBy: createdBy,
+	}
+	if p.StartsAt != nil {
+		input.StartsAt = *p.StartsAt
+	}
+	return input
+}
+
+// mountPlacements registers CRUD endpoints for featured-listing placements.
+func mountPlacements(r chi.Router, logger zerolog.Logger, svc placementservice.Service) {
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		filter := placementservice.Filter{
+			Country: strings.ToUpper(strings.TrimSpace(query.Get("country"))),
+			Locale:  strings.ToLower(strings.TrimSpace(query.Get("locale"))),
+		}
+		if raw := query.Get("active"); raw != "" {
+			active, err := strconv.ParseBool(raw)
+			if err != nil {
+				respondError(w, http.StatusBadRequest, "active must be a boolean")
+				return
+			}
+			if active {
+				filter.ActiveAt = time.Now().UTC()
+			}
+		}
+		placements, err := svc.List(r.Context(), filter)
+		if err != nil {
+			logger.Error().Err(err).Msg("list_placements_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"data":         placements,
+			"max_position": placementservice.MaxPosition,
+		})
+	})
+
+	r.Post("/", func(w http.ResponseWriter, r *http.Request) {
+		var payload placementRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		defer r.Body.Close()
+
+		identity, _ := session.IdentityFromContext(r.Context())
+		placement, err := svc.Create(r.Context(), payload.input(identity.Email))
+		if err != nil {
+			respondPlacementError(w, logger, err)
+			return
+		}
+		respondJSON(w, http.StatusCreated, placement)
+	})
+
+	r.Put("/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_id")
+			return
+		}
+		var payload placementRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		defer r.Body.Close()
+
+		placement, err := svc.Update(r.Context(), id, payload.input(""))
+		if err != nil {
+			respondPlacementError(w, logger, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, placement)
+	})
+
+	r.Delete("/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_id")
+			return
+		}
+		if err := svc.Delete(r.Context(), id); err != nil {
+			if errors.Is(err, placementservice.ErrNotFound) {
+				respondError(w, http.StatusNotFound, "not_found")
+				return
+			}
+			logger.Error().Err(err).Str("id", id.String()).Msg("delete_placement_failed")
+			respondError(w, http.StatusInternalServerError, "delete_failed")
+			return
+		}
+		respondJSON(w, http.StatusNoContent, nil)
+	})
+}
+
+func respondPlacementError(w http.ResponseWriter, logger zerolog.Logger, err error) {
+	switch {
+	case errors.Is(err, placementservice.ErrNotFound):
+		respondError(w, http.StatusNotFound, "not_found")
+	case errors.Is(err, listingservice.ErrNotFound):
+		respondError(w, http.StatusBadRequest, "unknown_listing")
+	case errors.Is(err, placementservice.ErrUnsupportedLocale):
+		respondError(w, http.StatusBadRequest, "unsupported_locale")
+	default:
+		logger.Warn().Err(err).Msg("save_placement")
+		respondError(w, http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,65 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"shanraq.com/internal/auth/session"
+	"shanraq.com/internal/httpserver/deps"
+)
+
+// Router exposes back-office endpoints restricted to the configured admin e-mails.
+func Router(d deps.Deps) chi.Router {
+	r := chi.NewRouter()
+	r.Use(requireAdmin(d.Config.Auth.AdminEmails))
+
+	r.Route("/placements", func(r chi.Router) {
+		mountPlacements(r, d.Logger, d.Placements)
+	})
+	r.Route("/duplicates", func(r chi.Router) {
+		mountDuplicates(r, d.Logger, d.Duplicates, d.ListingService)
+	})
+
+	return r
+}
+
+// requireAdmin answers 401 for anonymous requests and 403 for signed-in users whose
+// e-mail is not on the admin list.
+func requireAdmin(emails []string) func(http.Handler) http.Handler {
+	allowed := make(map[string]struct{}, len(emails))
+	for _, email := range emails {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			allowed[email] = struct{}{}
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity, ok := session.IdentityFromContext(r.Context())
+			if !ok {
+				respondError(w, http.StatusUnauthorized, "unauthenticated")
+				return
+			}
+			if _, ok := allowed[strings.ToLower(identity.Email)]; !ok {
+				respondError(w, http.StatusForbidden, "forbidden")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func respondJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if payload == nil {
+		return
+	}
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func respondError(w http.ResponseWriter, status int, code string) {
+	respondJSON(w, status, map[string]string{"error": code})
+}
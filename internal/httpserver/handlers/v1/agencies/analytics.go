@@ -0,0 +1,59 @@
+package agencies
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/auth"
+	"shanraq.com/internal/auth/session"
+	agencyservice "shanraq.com/internal/services/agency"
+	favoriteservice "shanraq.com/internal/services/favorite"
+)
+
+// mountAnalytics registers GET /{id}/analytics, visible to realtors of the agency.
+func mountAnalytics(r chi.Router, logger zerolog.Logger, svc agencyservice.Service, favorites favoriteservice.Service) {
+	r.Get("/{id}/analytics", func(w http.ResponseWriter, r *http.Request) {
+		id, _, ok := memberAgency(w, r, logger, svc)
+		if !ok {
+			return
+		}
+		stats, err := favorites.AgencyStats(r.Context(), []uuid.UUID{id}, 10)
+		if err != nil {
+			logger.Error().Err(err).Str("id", id.String()).Msg("agency_watch_stats_failed")
+			respondError(w, http.StatusInternalServerError, "get_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, stats)
+	})
+}
+
+// memberAgency parses the {id} agency and checks that the signed-in user works for
+// it, answering 400, 401 or 403 otherwise.
+func memberAgency(w http.ResponseWriter, r *http.Request, logger zerolog.Logger, svc agencyservice.Service) (uuid.UUID, auth.Identity, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id")
+		return uuid.Nil, auth.Identity{}, false
+	}
+	identity, ok := session.IdentityFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthenticated")
+		return uuid.Nil, auth.Identity{}, false
+	}
+	memberOf, err := svc.MemberAgencyIDs(r.Context(), identity.Email)
+	if err != nil {
+		logger.Error().Err(err).Msg("resolve_agency_membership_failed")
+		respondError(w, http.StatusInternalServerError, "get_failed")
+		return uuid.Nil, auth.Identity{}, false
+	}
+	for _, agencyID := range memberOf {
+		if agencyID == id {
+			return id, identity, true
+		}
+	}
+	respondError(w, http.StatusForbidden, "forbidden")
+	return uuid.Nil, auth.Identity{}, false
+}
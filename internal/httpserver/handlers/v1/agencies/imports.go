@@ -0,0 +1,81 @@
+package agencies
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/importer"
+	agencyservice "shanraq.com/internal/services/agency"
+)
+
+// maxImportBytes caps the size of an uploaded listing feed.
+const maxImportBytes = 20 << 20
+
+// mountImports registers POST /{id}/imports for realtors of the agency. The feed is
+// either a multipart "file" field or the raw request body; ?format=csv|reso overrides
+// detection and ?publish=true publishes newly created listings.
+func mountImports(r chi.Router, logger zerolog.Logger, svc agencyservice.Service, imports *importer.Importer) {
+	r.Post("/{id}/imports", func(w http.ResponseWriter, r *http.Request) {
+		id, identity, ok := memberAgency(w, r, logger, svc)
+		if !ok {
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+
+		var (
+			body        io.Reader = r.Body
+			name        string
+			contentType = r.Header.Get("Content-Type")
+		)
+		if file, header, err := r.FormFile("file"); err == nil {
+			defer file.Close()
+			body, name, contentType = file, header.Filename, header.Header.Get("Content-Type")
+		} else if !errors.Is(err, http.ErrNotMultipart) {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+
+		query := r.URL.Query()
+		var (
+			format importer.Format
+			err    error
+		)
+		if raw := query.Get("format"); raw != "" {
+			format, err = importer.ParseFormat(raw)
+		} else {
+			format, err = importer.DetectFormat(name, contentType)
+		}
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		publish, _ := strconv.ParseBool(query.Get("publish"))
+
+		report, err := imports.Import(r.Context(), id, body, format, importer.Options{
+			Publish:    publish,
+			ActorEmail: identity.Email,
+		})
+		if err != nil {
+			var feedErr *importer.FeedError
+			if errors.As(err, &feedErr) {
+				respondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			logger.Error().Err(err).Str("agency", id.String()).Msg("import_listings_failed")
+			respondError(w, http.StatusInternalServerError, "import_failed")
+			return
+		}
+		logger.Info().
+			Str("agency", id.String()).
+			Int("created", report.Created).
+			Int("updated", report.Updated).
+			Int("failed", report.Failed).
+			Msg("listings_imported")
+		respondJSON(w, http.StatusOK, report)
+	})
+}
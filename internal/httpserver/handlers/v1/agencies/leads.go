@@ -0,0 +1,135 @@
+package agencies
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	agencyservice "shanraq.com/internal/services/agency"
+	leadservice "shanraq.com/internal/services/lead"
+)
+
+type leadStatusRequest struct {
+	Status string `json:"status"`
+	Note   string `json:"note"`
+}
+
+// mountLeads registers the lead inbox of an agency, visible to its realtors.
+func mountLeads(r chi.Router, logger zerolog.Logger, svc agencyservice.Service, leads leadservice.Service) {
+	r.Get("/{id}/leads", func(w http.ResponseWriter, r *http.Request) {
+		agencyID, _, ok := memberAgency(w, r, logger, svc)
+		if !ok {
+			return
+		}
+		query := r.URL.Query()
+		filter := leadservice.Filter{AgencyID: agencyID}
+		if raw := strings.ToLower(strings.TrimSpace(query.Get("status"))); raw != "" {
+			filter.Status = leadservice.Status(raw)
+			if !filter.Status.Valid() {
+				respondError(w, http.StatusBadRequest, "unknown_status")
+				return
+			}
+		}
+		if raw := query.Get("listing_id"); raw != "" {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				respondError(w, http.StatusBadRequest, "invalid_listing_id")
+				return
+			}
+			filter.ListingID = id
+		}
+		filter.Limit, _ = strconv.Atoi(query.Get("limit"))
+		filter.Offset, _ = strconv.Atoi(query.Get("offset"))
+
+		items, total, err := leads.List(r.Context(), filter)
+		if err != nil {
+			logger.Error().Err(err).Str("agency", agencyID.String()).Msg("list_leads_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		counts, err := leads.Counts(r.Context(), agencyID)
+		if err != nil {
+			logger.Error().Err(err).Str("agency", agencyID.String()).Msg("count_leads_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"data": items,
+			"meta": map[string]any{"total": total, "count": len(items), "statuses": counts},
+		})
+	})
+
+	r.Get("/{id}/leads/{leadID}", func(w http.ResponseWriter, r *http.Request) {
+		agencyID, _, ok := memberAgency(w, r, logger, svc)
+		if !ok {
+			return
+		}
+		lead, ok := agencyLead(w, r, logger, leads, agencyID)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, lead)
+	})
+
+	r.Put("/{id}/leads/{leadID}/status", func(w http.ResponseWriter, r *http.Request) {
+		agencyID, identity, ok := memberAgency(w, r, logger, svc)
+		if !ok {
+			return
+		}
+		lead, ok := agencyLead(w, r, logger, leads, agencyID)
+		if !ok {
+			return
+		}
+		var payload leadStatusRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		defer r.Body.Close()
+		updated, err := leads.UpdateStatus(r.Context(), lead.ID, leadservice.Change{
+			Status:     leadservice.Status(payload.Status),
+			Note:       payload.Note,
+			ActorEmail: identity.Email,
+		})
+		if errors.Is(err, leadservice.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "not_found")
+			return
+		}
+		if errors.Is(err, leadservice.ErrInvalid) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err != nil {
+			logger.Error().Err(err).Str("lead", lead.ID.String()).Msg("update_lead_failed")
+			respondError(w, http.StatusInternalServerError, "update_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, updated)
+	})
+}
+
+// agencyLead loads the {leadID} lead, answering 404 unless it belongs to the agency.
+func agencyLead(w http.ResponseWriter, r *http.Request, logger zerolog.Logger, leads leadservice.Service, agencyID uuid.UUID) (leadservice.Lead, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "leadID"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id")
+		return leadservice.Lead{}, false
+	}
+	lead, err := leads.Get(r.Context(), id)
+	if err != nil || lead.AgencyID != agencyID {
+		if err == nil || errors.Is(err, leadservice.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "not_found")
+			return leadservice.Lead{}, false
+		}
+		logger.Error().Err(err).Str("lead", id.String()).Msg("get_lead_failed")
+		respondError(w, http.StatusInternalServerError, "get_failed")
+		return leadservice.Lead{}, false
+	}
+	return lead, true
+}
@@ -5,14 +5,23 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/rs/zerolog"
 
-	"shanraq.com/internal/config"
-	agencyservice "shanraq.com/internal/services/agency"
+	"shanraq.com/internal/httpserver/deps"
+	"shanraq.com/internal/importer"
 )
 
 // Router exposes agency and realtor read endpoints.
-func Router(cfg config.Config, logger zerolog.Logger, svc agencyservice.Service) chi.Router {
+func Router(d deps.Deps) chi.Router {
+	var (
+		cfg       = d.Config
+		logger    = d.Logger
+		svc       = d.AgencyService
+		favorites = d.Favorites
+		imports   = importer.New(d.ListingService, d.Config.Seed.ChunkSize)
+		listings  = d.ListingService
+		viewings  = d.Viewings
+		leads     = d.Leads
+	)
 	r := chi.NewRouter()
 
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
@@ -58,6 +67,11 @@ func Router(cfg config.Config, logger zerolog.Logger, svc agencyservice.Service)
 		})
 	})
 
+	mountAnalytics(r, logger, svc, favorites)
+	mountImports(r, logger, svc, imports)
+	mountViewings(r, cfg, logger, svc, listings, viewings)
+	mountLeads(r, logger, svc, leads)
+
 	return r
 }
 
@@ -0,0 +1,474 @@
+package agencies
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/auth"
+	"shanraq.com/internal/auth/session"
+	"shanraq.com/internal/config"
+	"shanraq.com/internal/ical"
+	agencyservice "shanraq.com/internal/services/agency"
+	listingservice "shanraq.com/internal/services/listing"
+	viewingservice "shanraq.com/internal/services/viewing"
+	"shanraq.com/internal/web"
+)
+
+// feedLookback keeps recent past viewings in the calendar feed.
+const feedLookback = 30 * 24 * time.Hour
+
+type slotRequest struct {
+	Start       string `json:"start"`
+	End         string `json:"end"`
+	SlotMinutes int    `json:"slot_minutes"`
+	TimeZone    string `json:"time_zone"`
+}
+
+type viewingRequest struct {
+	ListingID *uuid.UUID `json:"listing_id"`
+	Name      string     `json:"name"`
+	Phone     string     `json:"phone"`
+	Note      string     `json:"note"`
+	TimeZone  string     `json:"time_zone"`
+}
+
+type cancelRequest struct {
+	Reason string `json:"reason"`
+}
+
+// mountViewings registers realtor availability, viewing bookings and the
+// per-realtor iCalendar feed under /realtors/{id}.
+func mountViewings(r chi.Router, cfg config.Config, logger zerolog.Logger, svc agencyservice.Service, listings listingservice.Service, viewings viewingservice.Service) {
+	r.Get("/realtors/{id}/slots", func(w http.ResponseWriter, r *http.Request) {
+		realtor, ok := loadRealtor(w, r, logger, svc)
+		if !ok {
+			return
+		}
+		query := r.URL.Query()
+		zone := realtor.TimeZone
+		if raw := strings.TrimSpace(query.Get("tz")); raw != "" {
+			zone = raw
+		}
+		loc, err := viewingservice.LoadLocation(zone)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "unknown_time_zone")
+			return
+		}
+		filter := viewingservice.SlotFilter{RealtorID: realtor.ID, From: time.Now().UTC(), AvailableOnly: true}
+		if raw := query.Get("from"); raw != "" {
+			if filter.From, err = viewingservice.ParseTime(raw, loc); err != nil {
+				respondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		if raw := query.Get("to"); raw != "" {
+			if filter.To, err = viewingservice.ParseTime(raw, loc); err != nil {
+				respondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		// The realtor also sees the slots already taken.
+		if identity, ok := session.IdentityFromContext(r.Context()); ok && isRealtor(identity, realtor) {
+			filter.AvailableOnly = query.Get("available") == "true"
+		}
+		slots, err := viewings.Slots(r.Context(), filter)
+		if err != nil {
+			logger.Error().Err(err).Str("realtor", realtor.ID.String()).Msg("list_viewing_slots_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		views := make([]viewingservice.LocalSlot, 0, len(slots))
+		for _, slot := range slots {
+			views = append(views, slot.Local(loc.String()))
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"data": views,
+			"meta": map[string]any{"count": len(views), "time_zone": loc.String(), "realtor_time_zone": realtor.TimeZone},
+		})
+	})
+
+	r.Post("/realtors/{id}/slots", func(w http.ResponseWriter, r *http.Request) {
+		realtor, identity, ok := ownRealtor(w, r, logger, svc)
+		if !ok {
+			return
+		}
+		var payload slotRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		defer r.Body.Close()
+		zone := strings.TrimSpace(payload.TimeZone)
+		if zone == "" {
+			zone = realtor.TimeZone
+		}
+		loc, err := viewingservice.LoadLocation(zone)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "unknown_time_zone")
+			return
+		}
+		start, err := viewingservice.ParseTime(payload.Start, loc)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		end, err := viewingservice.ParseTime(payload.End, loc)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		slots, err := viewings.AddSlots(r.Context(), viewingservice.SlotInput{
+			RealtorID: realtor.ID,
+			Start:     start,
+			End:       end,
+			Length:    time.Duration(payload.SlotMinutes) * time.Minute,
+			TimeZone:  loc.String(),
+		})
+		if err != nil {
+			respondViewingError(w, logger, err)
+			return
+		}
+		logger.Info().Str("realtor", realtor.ID.String()).Str("by", identity.Email).Int("slots", len(slots)).Msg("viewing_slots_added")
+		respondJSON(w, http.StatusCreated, map[string]any{"data": slots})
+	})
+
+	r.Delete("/realtors/{id}/slots/{slotID}", func(w http.ResponseWriter, r *http.Request) {
+		realtor, _, ok := ownRealtor(w, r, logger, svc)
+		if !ok {
+			return
+		}
+		slotID, err := uuid.Parse(chi.URLParam(r, "slotID"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_id")
+			return
+		}
+		if err := viewings.RemoveSlot(r.Context(), realtor.ID, slotID); err != nil {
+			respondViewingError(w, logger, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	r.Post("/realtors/{id}/slots/{slotID}/book", func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := session.IdentityFromContext(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+		realtor, ok := loadRealtor(w, r, logger, svc)
+		if !ok {
+			return
+		}
+		slotID, err := uuid.Parse(chi.URLParam(r, "slotID"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_id")
+			return
+		}
+		var payload viewingRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		defer r.Body.Close()
+		if payload.ListingID != nil {
+			listing, err := listings.Get(r.Context(), *payload.ListingID)
+			if err != nil || listing.Status != listingservice.StatusPublished {
+				respondError(w, http.StatusBadRequest, "unknown_listing")
+				return
+			}
+		}
+		name := strings.TrimSpace(payload.Name)
+		if name == "" {
+			name = identity.FullName
+		}
+		appointment, err := viewings.Book(r.Context(), viewingservice.BookingInput{
+			SlotID:         slotID,
+			RealtorID:      realtor.ID,
+			ListingID:      payload.ListingID,
+			RequesterID:    identity.Key(),
+			RequesterEmail: identity.Email,
+			RequesterName:  name,
+			RequesterPhone: payload.Phone,
+			Note:           payload.Note,
+			TimeZone:       payload.TimeZone,
+		})
+		if err != nil {
+			respondViewingError(w, logger, err)
+			return
+		}
+		respondJSON(w, http.StatusCreated, appointment.Local(appointment.TimeZone))
+	})
+
+	r.Get("/realtors/{id}/appointments", func(w http.ResponseWriter, r *http.Request) {
+		realtor, _, ok := ownRealtor(w, r, logger, svc)
+		if !ok {
+			return
+		}
+		filter := viewingservice.AppointmentFilter{RealtorID: realtor.ID}
+		if raw := strings.TrimSpace(r.URL.Query().Get("status")); raw != "" {
+			filter.Status = viewingservice.Status(raw)
+			if !filter.Status.Valid() {
+				respondError(w, http.StatusBadRequest, "unknown_status")
+				return
+			}
+		}
+		if r.URL.Query().Get("past") != "true" {
+			filter.From = time.Now().UTC()
+		}
+		appointments, err := viewings.Appointments(r.Context(), filter)
+		if err != nil {
+			logger.Error().Err(err).Str("realtor", realtor.ID.String()).Msg("list_appointments_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		views := make([]viewingservice.LocalAppointment, 0, len(appointments))
+		for _, a := range appointments {
+			views = append(views, a.Local(realtor.TimeZone))
+		}
+		generation, err := viewings.FeedGeneration(r.Context(), realtor.ID)
+		if err != nil {
+			logger.Error().Err(err).Str("realtor", realtor.ID.String()).Msg("load_feed_generation_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"data": views,
+			"meta": map[string]any{
+				"count":        len(views),
+				"calendar_url": feedURL(cfg, realtor.ID, generation),
+			},
+		})
+	})
+
+	r.Post("/realtors/{id}/calendar/rotate", func(w http.ResponseWriter, r *http.Request) {
+		realtor, _, ok := ownRealtor(w, r, logger, svc)
+		if !ok {
+			return
+		}
+		generation, err := viewings.RotateFeed(r.Context(), realtor.ID)
+		if err != nil {
+			logger.Error().Err(err).Str("realtor", realtor.ID.String()).Msg("rotate_feed_failed")
+			respondError(w, http.StatusInternalServerError, "rotate_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{"calendar_url": feedURL(cfg, realtor.ID, generation)})
+	})
+
+	r.Post("/realtors/{id}/appointments/{appointmentID}/cancel", func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := session.IdentityFromContext(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+		realtor, ok := loadRealtor(w, r, logger, svc)
+		if !ok {
+			return
+		}
+		appointmentID, err := uuid.Parse(chi.URLParam(r, "appointmentID"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_id")
+			return
+		}
+		appointment, err := viewings.GetAppointment(r.Context(), appointmentID)
+		if err != nil || appointment.RealtorID != realtor.ID {
+			if err == nil || errors.Is(err, viewingservice.ErrNotFound) {
+				respondError(w, http.StatusNotFound, "not_found")
+				return
+			}
+			logger.Error().Err(err).Str("appointment", appointmentID.String()).Msg("get_appointment_failed")
+			respondError(w, http.StatusInternalServerError, "cancel_failed")
+			return
+		}
+		// Either side of the viewing may call it off.
+		byRealtor := isRealtor(identity, realtor)
+		if !byRealtor && appointment.RequesterID != identity.Key() {
+			respondError(w, http.StatusNotFound, "not_found")
+			return
+		}
+		var payload cancelRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				respondError(w, http.StatusBadRequest, "invalid_payload")
+				return
+			}
+			defer r.Body.Close()
+		}
+		cancelled, err := viewings.Cancel(r.Context(), appointment.ID, viewingservice.Cancellation{ActorEmail: identity.Email, Reason: payload.Reason})
+		if err != nil {
+			respondViewingError(w, logger, err)
+			return
+		}
+		zone := cancelled.TimeZone
+		if byRealtor {
+			zone = realtor.TimeZone
+		}
+		respondJSON(w, http.StatusOK, cancelled.Local(zone))
+	})
+
+	r.Get("/realtors/{id}/calendar.ics", func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			respondError(w, http.StatusNotFound, "not_found")
+			return
+		}
+		generation, err := viewings.FeedGeneration(r.Context(), id)
+		if err != nil {
+			logger.Error().Err(err).Str("realtor", id.String()).Msg("load_feed_generation_failed")
+			respondError(w, http.StatusInternalServerError, "get_failed")
+			return
+		}
+		if !viewingservice.ValidFeedToken(cfg.Viewing.FeedSecret, id, generation, r.URL.Query().Get("token")) {
+			respondError(w, http.StatusNotFound, "not_found")
+			return
+		}
+		realtor, ok := loadRealtor(w, r, logger, svc)
+		if !ok {
+			return
+		}
+		now := time.Now().UTC()
+		appointments, err := viewings.Appointments(r.Context(), viewingservice.AppointmentFilter{RealtorID: realtor.ID, From: now.Add(-feedLookback)})
+		if err != nil {
+			logger.Error().Err(err).Str("realtor", realtor.ID.String()).Msg("list_appointments_failed")
+			respondError(w, http.StatusInternalServerError, "feed_failed")
+			return
+		}
+		cal := ical.Calendar{
+			ProductID: "-//Shanraq//Viewings//EN",
+			Name:      "Viewings · " + realtor.FullName,
+			TimeZone:  realtor.TimeZone,
+			Events:    make([]ical.Event, 0, len(appointments)),
+		}
+		titles := make(map[uuid.UUID]listingservice.Listing)
+		for _, a := range appointments {
+			cal.Events = append(cal.Events, viewingEvent(r, cfg, listings, titles, realtor, a))
+		}
+		w.Header().Set("Content-Type", ical.ContentType)
+		w.Header().Set("Content-Disposition", `inline; filename="viewings.ics"`)
+		w.Header().Set("Cache-Control", "private, max-age=300")
+		if err := ical.Encode(w, cal, now); err != nil {
+			logger.Warn().Err(err).Str("realtor", realtor.ID.String()).Msg("write_calendar_failed")
+		}
+	})
+}
+
+// viewingEvent describes an appointment for the calendar feed, looking listings up
+// once per feed.
+func viewingEvent(r *http.Request, cfg config.Config, listings listingservice.Service, cache map[uuid.UUID]listingservice.Listing, realtor agencyservice.Realtor, a viewingservice.Appointment) ical.Event {
+	who := a.RequesterName
+	if who == "" {
+		who = a.RequesterEmail
+	}
+	event := ical.Event{
+		UID:       a.ID.String() + "@shanraq.com",
+		Start:     a.Start,
+		End:       a.End,
+		Summary:   "Viewing with " + who,
+		Organizer: realtor.Email,
+		Attendee:  a.RequesterEmail,
+		Cancelled: a.Status == viewingservice.StatusCancelled,
+		Created:   a.CreatedAt,
+		Updated:   a.UpdatedAt,
+	}
+	if event.Cancelled {
+		event.Sequence = 1
+	}
+	lines := []string{"Contact: " + strings.TrimSpace(who+" <"+a.RequesterEmail+">")}
+	if a.RequesterPhone != "" {
+		lines = append(lines, "Phone: "+a.RequesterPhone)
+	}
+	if a.Note != "" {
+		lines = append(lines, "Note: "+a.Note)
+	}
+	if a.CancelReason != "" {
+		lines = append(lines, "Cancelled: "+a.CancelReason)
+	}
+	if a.ListingID != nil {
+		listing, ok := cache[*a.ListingID]
+		if !ok {
+			if found, err := listings.Get(r.Context(), *a.ListingID); err == nil {
+				listing, ok = found, true
+				cache[*a.ListingID] = found
+			}
+		}
+		if ok {
+			event.Summary = "Viewing: " + listing.Title
+			event.Location = listing.LocationString()
+			event.URL = web.AbsoluteURL(cfg.HTTP.PublicBaseURL, "/listings/"+listing.Slug)
+		}
+	}
+	event.Description = strings.Join(lines, "\n")
+	return event
+}
+
+func feedURL(cfg config.Config, realtorID uuid.UUID, generation int) string {
+	return fmt.Sprintf("%s/api/v1/agencies/realtors/%s/calendar.ics?token=%s",
+		strings.TrimRight(cfg.HTTP.PublicBaseURL, "/"), realtorID, url.QueryEscape(viewingservice.FeedToken(cfg.Viewing.FeedSecret, realtorID, generation)))
+}
+
+func loadRealtor(w http.ResponseWriter, r *http.Request, logger zerolog.Logger, svc agencyservice.Service) (agencyservice.Realtor, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id")
+		return agencyservice.Realtor{}, false
+	}
+	realtor, err := svc.GetRealtor(r.Context(), id)
+	if errors.Is(err, agencyservice.ErrRealtorNotFound) {
+		respondError(w, http.StatusNotFound, "not_found")
+		return agencyservice.Realtor{}, false
+	}
+	if err != nil {
+		logger.Error().Err(err).Str("realtor", id.String()).Msg("get_realtor_failed")
+		respondError(w, http.StatusInternalServerError, "get_failed")
+		return agencyservice.Realtor{}, false
+	}
+	return realtor, true
+}
+
+// ownRealtor loads the {id} realtor for the signed-in realtor themselves.
+func ownRealtor(w http.ResponseWriter, r *http.Request, logger zerolog.Logger, svc agencyservice.Service) (agencyservice.Realtor, auth.Identity, bool) {
+	identity, ok := session.IdentityFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthenticated")
+		return agencyservice.Realtor{}, auth.Identity{}, false
+	}
+	realtor, ok := loadRealtor(w, r, logger, svc)
+	if !ok {
+		return agencyservice.Realtor{}, auth.Identity{}, false
+	}
+	if !isRealtor(identity, realtor) {
+		respondError(w, http.StatusForbidden, "forbidden")
+		return agencyservice.Realtor{}, auth.Identity{}, false
+	}
+	return realtor, identity, true
+}
+
+func isRealtor(identity auth.Identity, realtor agencyservice.Realtor) bool {
+	return identity.Email != "" && strings.EqualFold(identity.Email, realtor.Email)
+}
+
+func respondViewingError(w http.ResponseWriter, logger zerolog.Logger, err error) {
+	switch {
+	case errors.Is(err, viewingservice.ErrNotFound):
+		respondError(w, http.StatusNotFound, "not_found")
+	case errors.Is(err, viewingservice.ErrSlotOverlap):
+		respondError(w, http.StatusConflict, "slot_overlap")
+	case errors.Is(err, viewingservice.ErrSlotTaken):
+		respondError(w, http.StatusConflict, "slot_taken")
+	case errors.Is(err, viewingservice.ErrRequesterBusy):
+		respondError(w, http.StatusConflict, "requester_busy")
+	case errors.Is(err, viewingservice.ErrAlreadyCancelled):
+		respondError(w, http.StatusConflict, "already_cancelled")
+	default:
+		logger.Warn().Err(err).Msg("viewing_request")
+		respondError(w, http.StatusBadRequest, err.Error())
+	}
+}
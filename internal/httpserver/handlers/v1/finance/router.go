@@ -0,0 +1,148 @@
+package finance
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/config"
+	financeservice "shanraq.com/internal/services/finance"
+)
+
+// Router exposes the mortgage and affordability calculators. Terms left out of a
+// request take the per-country defaults.
+func Router(cfg config.Config, logger zerolog.Logger, defaults financeservice.Table) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/defaults", func(w http.ResponseWriter, r *http.Request) {
+		rows := defaults.All()
+		respondJSON(w, http.StatusOK, map[string]any{
+			"data": rows,
+			"meta": map[string]any{"count": len(rows)},
+		})
+	})
+
+	r.Get("/defaults/{country}", func(w http.ResponseWriter, r *http.Request) {
+		row, known := defaults.Lookup(chi.URLParam(r, "country"))
+		respondJSON(w, http.StatusOK, map[string]any{
+			"data": row,
+			"meta": map[string]any{"fallback": !known},
+		})
+	})
+
+	r.Get("/mortgage", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		p := params{query: query}
+		input := financeservice.MortgageInput{
+			Country:     query.Get("country"),
+			Currency:    query.Get("currency"),
+			Price:       p.number("price"),
+			DownPayment: p.optionalNumber("down_payment"),
+			LTV:         p.optionalNumber("ltv"),
+			Rate:        p.optionalNumber("rate"),
+			TermYears:   p.optionalInt("term_years"),
+			Method:      financeservice.Method(query.Get("method")),
+		}
+		if p.err != nil {
+			respondError(w, http.StatusBadRequest, p.err.Error())
+			return
+		}
+		mortgage, err := financeservice.Calculate(defaults, input)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if query.Get("schedule") == "false" {
+			mortgage.Schedule = nil
+		}
+		logger.Debug().Str("country", mortgage.Country).Float64("principal", mortgage.Principal).Msg("mortgage_calculated")
+		respondJSON(w, http.StatusOK, map[string]any{"data": mortgage})
+	})
+
+	r.Get("/affordability", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		p := params{query: query}
+		input := financeservice.AffordabilityInput{
+			Country:       query.Get("country"),
+			Currency:      query.Get("currency"),
+			MonthlyIncome: p.number("monthly_income"),
+			MonthlyDebts:  p.number("monthly_debts"),
+			DownPayment:   p.number("down_payment"),
+			Rate:          p.optionalNumber("rate"),
+			TermYears:     p.optionalInt("term_years"),
+			MaxDTI:        p.optionalNumber("max_dti"),
+			Method:        financeservice.Method(query.Get("method")),
+		}
+		if p.err != nil {
+			respondError(w, http.StatusBadRequest, p.err.Error())
+			return
+		}
+		result, err := financeservice.Afford(defaults, input)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{"data": result})
+	})
+
+	return r
+}
+
+// params parses numeric query parameters, keeping the first error.
+type params struct {
+	query url.Values
+	err   error
+}
+
+func (p *params) number(name string) float64 {
+	if v := p.optionalNumber(name); v != nil {
+		return *v
+	}
+	return 0
+}
+
+func (p *params) optionalNumber(name string) *float64 {
+	raw := strings.TrimSpace(p.query.Get(name))
+	if raw == "" {
+		return nil
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		if p.err == nil {
+			p.err = fmt.Errorf("%s must be a number", name)
+		}
+		return nil
+	}
+	return &v
+}
+
+func (p *params) optionalInt(name string) *int {
+	raw := strings.TrimSpace(p.query.Get(name))
+	if raw == "" {
+		return nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		if p.err == nil {
+			p.err = fmt.Errorf("%s must be a whole number", name)
+		}
+		return nil
+	}
+	return &v
+}
+
+func respondJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func respondError(w http.ResponseWriter, status int, code string) {
+	respondJSON(w, status, map[string]string{"error": code})
+}
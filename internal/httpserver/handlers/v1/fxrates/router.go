@@ -0,0 +1,45 @@
+package fxrates
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/config"
+	"shanraq.com/internal/services/fx"
+)
+
+// Router exposes the FX reference rates used for price normalisation.
+func Router(cfg config.Config, logger zerolog.Logger, svc fx.Service) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		rates, err := svc.Rates(r.Context())
+		if err != nil {
+			logger.Error().Err(err).Msg("list_fx_rates_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"data": rates,
+			"meta": map[string]any{
+				"count": len(rates),
+				"base":  fx.BaseCurrency,
+			},
+		})
+	})
+
+	return r
+}
+
+func respondJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func respondError(w http.ResponseWriter, status int, code string) {
+	respondJSON(w, status, map[string]string{"error": code})
+}
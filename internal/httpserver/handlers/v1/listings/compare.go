@@ -0,0 +1,74 @@
+package listings
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/format"
+	agencyservice "shanraq.com/internal/services/agency"
+	"shanraq.com/internal/services/fx"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+// mountCompare registers GET /compare?ids=a,b,c&currency=EUR.
+func mountCompare(r chi.Router, logger zerolog.Logger, svc listingservice.Service, rates fx.Service, agencies agencyservice.Service) {
+	r.Get("/compare", func(w http.ResponseWriter, r *http.Request) {
+		ids, err := listingservice.ParseCompareIDs(r.URL.Query()["ids"])
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		currency, err := requestedCurrency(r)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		viewer, err := viewerFor(r, agencies)
+		if err != nil {
+			logger.Error().Err(err).Msg("resolve_viewer_failed")
+			respondError(w, http.StatusInternalServerError, "compare_failed")
+			return
+		}
+
+		found := make([]listingservice.Listing, 0, len(ids))
+		for _, id := range ids {
+			listing, err := svc.Get(r.Context(), id)
+			if err != nil || !viewer.CanSee(listing) {
+				if err == nil || errors.Is(err, listingservice.ErrNotFound) {
+					respondError(w, http.StatusNotFound, "not_found")
+					return
+				}
+				logger.Error().Err(err).Str("id", id.String()).Msg("get_listing_failed")
+				respondError(w, http.StatusInternalServerError, "compare_failed")
+				return
+			}
+			found = append(found, listing)
+		}
+		locale, err := localize(w, r, svc, found)
+		if err != nil {
+			logger.Error().Err(err).Msg("localize_listings_failed")
+			respondError(w, http.StatusInternalServerError, "compare_failed")
+			return
+		}
+		applyDisplay(r, found)
+
+		table, err := rates.Table(r.Context())
+		if err != nil {
+			logger.Error().Err(err).Msg("load_fx_table_failed")
+			respondError(w, http.StatusInternalServerError, "compare_failed")
+			return
+		}
+		comparison, err := listingservice.Compare(found, table, currency, format.FromRequest(r))
+		if err != nil {
+			respondConversionError(w, logger, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"data":   comparison,
+			"locale": locale,
+		})
+	})
+}
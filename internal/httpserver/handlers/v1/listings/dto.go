@@ -0,0 +1,138 @@
+package listings
+
+import (
+	"github.com/google/uuid"
+
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+type listResponse struct {
+	Data   []listingservice.Listing `json:"data"`
+	Meta   listMeta                 `json:"meta"`
+	Facets listingservice.Facets    `json:"facets"`
+}
+
+type listMeta struct {
+	Total    int    `json:"total"`
+	Count    int    `json:"count"`
+	Limit    int    `json:"limit"`
+	Offset   int    `json:"offset"`
+	Sort     string `json:"sort,omitempty"`
+	Currency string `json:"currency,omitempty"`
+	Locale   string `json:"locale"`
+}
+
+type searchResponse struct {
+	Data []listingservice.SearchResult `json:"data"`
+	Meta searchMeta                    `json:"meta"`
+}
+
+type searchMeta struct {
+	Total    int    `json:"total"`
+	Count    int    `json:"count"`
+	Limit    int    `json:"limit"`
+	Offset   int    `json:"offset"`
+	Query    string `json:"query"`
+	Language string `json:"language"`
+	Currency string `json:"currency,omitempty"`
+	Locale   string `json:"locale"`
+}
+
+type createRequest struct {
+	Title        string                      `json:"title"`
+	Type         string                      `json:"type"`
+	Tenure       string                      `json:"tenure"`
+	Rental       *listingservice.RentalTerms `json:"rental"`
+	Country      string                      `json:"country"`
+	City         string                      `json:"city"`
+	Region       string                      `json:"region"`
+	Neighborhood string                      `json:"neighborhood"`
+	Summary      string                      `json:"summary"`
+	Price        float64                     `json:"price"`
+	Currency     string                      `json:"currency"`
+	Bedrooms     int                         `json:"bedrooms"`
+	Bathrooms    float64                     `json:"bathrooms"`
+	AreaSqM      float64                     `json:"area_sqm"`
+	ImageURL     string                      `json:"image_url"`
+	AgencyID     uuid.UUID                   `json:"agency_id"`
+	ExternalRef  string                      `json:"external_ref"`
+	Tags         []string                    `json:"tags"`
+	Location     *listingservice.GeoPoint    `json:"location"`
+}
+
+type updateRequest struct {
+	Title        *string                     `json:"title"`
+	Type         *string                     `json:"type"`
+	Tenure       *string                     `json:"tenure"`
+	Rental       *listingservice.RentalTerms `json:"rental"`
+	Country      *string                     `json:"country"`
+	City         *string                     `json:"city"`
+	Region       *string                     `json:"region"`
+	Neighborhood *string                     `json:"neighborhood"`
+	Summary      *string                     `json:"summary"`
+	Price        *float64                    `json:"price"`
+	Currency     *string                     `json:"currency"`
+	Bedrooms     *int                        `json:"bedrooms"`
+	Bathrooms    *float64                    `json:"bathrooms"`
+	AreaSqM      *float64                    `json:"area_sqm"`
+	ImageURL     *string                     `json:"image_url"`
+	AgencyID     *uuid.UUID                  `json:"agency_id"`
+	Tags         []string                    `json:"tags"`
+	Location     *listingservice.GeoPoint    `json:"location"`
+}
+
+func (p createRequest) toInput() listingservice.CreateInput {
+	return listingservice.CreateInput{
+		Title:        p.Title,
+		Type:         listingservice.ListingType(p.Type),
+		Tenure:       listingservice.Tenure(p.Tenure),
+		Rental:       p.Rental,
+		Country:      p.Country,
+		City:         p.City,
+		Region:       p.Region,
+		Neighborhood: p.Neighborhood,
+		Summary:      p.Summary,
+		Price:        p.Price,
+		Currency:     p.Currency,
+		Bedrooms:     p.Bedrooms,
+		Bathrooms:    p.Bathrooms,
+		AreaSqM:      p.AreaSqM,
+		ImageURL:     p.ImageURL,
+		AgencyID:     p.AgencyID,
+		ExternalRef:  p.ExternalRef,
+		Tags:         p.Tags,
+		Location:     p.Location,
+	}
+}
+
+func (p updateRequest) toInput() listingservice.UpdateInput {
+	input := listingservice.UpdateInput{
+		Title:        p.Title,
+		Country:      p.Country,
+		City:         p.City,
+		Region:       p.Region,
+		Neighborhood: p.Neighborhood,
+		Summary:      p.Summary,
+		Price:        p.Price,
+		Currency:     p.Currency,
+		Bedrooms:     p.Bedrooms,
+		Bathrooms:    p.Bathrooms,
+		AreaSqM:      p.AreaSqM,
+		ImageURL:     p.ImageURL,
+		AgencyID:     p.AgencyID,
+		Rental:       p.Rental,
+		Location:     p.Location,
+	}
+	if p.Type != nil {
+		listingType := listingservice.ListingType(*p.Type)
+		input.Type = &listingType
+	}
+	if p.Tenure != nil {
+		tenure := listingservice.Tenure(*p.Tenure)
+		input.Tenure = &tenure
+	}
+	if p.Tags != nil {
+		input.Tags = &p.Tags
+	}
+	return input
+}
@@ -0,0 +1,91 @@
+package listings
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/auth/session"
+	agencyservice "shanraq.com/internal/services/agency"
+	favoriteservice "shanraq.com/internal/services/favorite"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+// mountFavorites registers the watchlist endpoints under /{id}/favorite.
+func mountFavorites(r chi.Router, logger zerolog.Logger, svc listingservice.Service, agencies agencyservice.Service, favorites favoriteservice.Service) {
+	r.Get("/{id}/favorite", func(w http.ResponseWriter, r *http.Request) {
+		listing, ok := visibleListing(w, r, logger, svc, agencies)
+		if !ok {
+			return
+		}
+		counts, err := favorites.WatcherCounts(r.Context(), []uuid.UUID{listing.ID})
+		if err != nil {
+			logger.Error().Err(err).Str("id", listing.ID.String()).Msg("watcher_count_failed")
+			respondError(w, http.StatusInternalServerError, "get_failed")
+			return
+		}
+		favorited := false
+		if identity, ok := session.IdentityFromContext(r.Context()); ok {
+			if favorited, err = favorites.Has(r.Context(), identity, listing.ID); err != nil {
+				logger.Error().Err(err).Str("id", listing.ID.String()).Msg("favorite_lookup_failed")
+				respondError(w, http.StatusInternalServerError, "get_failed")
+				return
+			}
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"listing_id": listing.ID,
+			"favorited":  favorited,
+			"watchers":   counts[listing.ID],
+		})
+	})
+
+	r.Post("/{id}/favorite", func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := session.IdentityFromContext(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+		listing, ok := visibleListing(w, r, logger, svc, agencies)
+		if !ok {
+			return
+		}
+		favorite, created, err := favorites.Add(r.Context(), identity, listing)
+		if err != nil {
+			logger.Error().Err(err).Str("id", listing.ID.String()).Msg("add_favorite_failed")
+			respondError(w, http.StatusInternalServerError, "favorite_failed")
+			return
+		}
+		status := http.StatusOK
+		if created {
+			status = http.StatusCreated
+		}
+		respondJSON(w, status, favorite)
+	})
+
+	r.Delete("/{id}/favorite", func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := session.IdentityFromContext(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_id")
+			return
+		}
+		// Unfavoriting works even after the listing was withdrawn or deleted.
+		if err := favorites.Remove(r.Context(), identity, id); err != nil {
+			if errors.Is(err, favoriteservice.ErrNotFound) {
+				respondError(w, http.StatusNotFound, "not_found")
+				return
+			}
+			logger.Error().Err(err).Str("id", id.String()).Msg("remove_favorite_failed")
+			respondError(w, http.StatusInternalServerError, "delete_failed")
+			return
+		}
+		respondJSON(w, http.StatusNoContent, nil)
+	})
+}
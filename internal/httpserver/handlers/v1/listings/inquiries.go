@@ -0,0 +1,120 @@
+package listings
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/auth/session"
+	"shanraq.com/internal/config"
+	"shanraq.com/internal/httpserver/middlewares"
+	agencyservice "shanraq.com/internal/services/agency"
+	leadservice "shanraq.com/internal/services/lead"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+type inquiryRequest struct {
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Phone   string `json:"phone"`
+	Message string `json:"message"`
+	// Website is a honeypot: the form hides it, so only bots fill it in.
+	Website string `json:"website"`
+}
+
+// mountInquiries registers POST /{id}/inquiries, which stores a buyer's message as
+// a lead in the inbox of the listing's agency. Submissions are throttled per client
+// IP and, for signed-in users, per session.
+func mountInquiries(r chi.Router, cfg config.Config, logger zerolog.Logger, svc listingservice.Service, agencies agencyservice.Service, leads leadservice.Service) {
+	perIP := middlewares.NewThrottle(cfg.Leads.MaxPerIP, cfg.Leads.Window)
+	perSession := middlewares.NewThrottle(cfg.Leads.MaxPerSession, cfg.Leads.Window)
+
+	r.Post("/{id}/inquiries", func(w http.ResponseWriter, r *http.Request) {
+		ip := middlewares.ClientIP(r, cfg.HTTP.TrustedProxies)
+		identity, signedIn := session.IdentityFromContext(r.Context())
+		if ok, retry := perIP.Allow(middlewares.IPKey(ip)); !ok {
+			respondThrottled(w, retry)
+			return
+		}
+		if signedIn {
+			if ok, retry := perSession.Allow("session:" + identity.Key()); !ok {
+				respondThrottled(w, retry)
+				return
+			}
+		}
+
+		listing, ok := visibleListing(w, r, logger, svc, agencies)
+		if !ok {
+			return
+		}
+		if !listing.Status.Public() {
+			respondError(w, http.StatusConflict, "listing_not_available")
+			return
+		}
+		if listing.AgencyID == uuid.Nil {
+			respondError(w, http.StatusConflict, "listing_has_no_agency")
+			return
+		}
+		var payload inquiryRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		defer r.Body.Close()
+		if strings.TrimSpace(payload.Website) != "" {
+			// Answer like a success so bots learn nothing.
+			logger.Info().Str("ip", ip).Str("listing", listing.ID.String()).Msg("inquiry_honeypot")
+			respondJSON(w, http.StatusAccepted, map[string]any{"status": "received"})
+			return
+		}
+		input := leadservice.Input{
+			ListingID:    listing.ID,
+			AgencyID:     listing.AgencyID,
+			ListingTitle: listing.Title,
+			Name:         payload.Name,
+			Email:        payload.Email,
+			Phone:        payload.Phone,
+			Message:      payload.Message,
+			SourceIP:     ip,
+		}
+		if signedIn {
+			input.RequesterID = identity.Key()
+			if strings.TrimSpace(input.Name) == "" {
+				input.Name = identity.FullName
+			}
+			if strings.TrimSpace(input.Email) == "" {
+				input.Email = identity.Email
+			}
+		}
+		lead, err := leads.Create(r.Context(), input)
+		if err != nil {
+			if errors.Is(err, leadservice.ErrInvalid) {
+				respondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			logger.Error().Err(err).Str("listing", listing.ID.String()).Msg("create_inquiry_failed")
+			respondError(w, http.StatusInternalServerError, "inquiry_failed")
+			return
+		}
+		logger.Info().Str("lead", lead.ID.String()).Str("agency", lead.AgencyID.String()).Msg("inquiry_received")
+		respondJSON(w, http.StatusCreated, map[string]any{
+			"id":         lead.ID,
+			"listing_id": lead.ListingID,
+			"status":     "received",
+			"created_at": lead.CreatedAt,
+		})
+	})
+}
+
+func respondThrottled(w http.ResponseWriter, retry time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+	respondError(w, http.StatusTooManyRequests, "too_many_requests")
+}
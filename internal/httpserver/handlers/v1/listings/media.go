@@ -0,0 +1,208 @@
+package listings
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/config"
+	agencyservice "shanraq.com/internal/services/agency"
+	listingservice "shanraq.com/internal/services/listing"
+	mediaservice "shanraq.com/internal/services/media"
+)
+
+type mediaUpdateRequest struct {
+	Kind    *mediaservice.Kind `json:"kind"`
+	Caption *string            `json:"caption"`
+}
+
+type mediaOrderRequest struct {
+	IDs []uuid.UUID `json:"ids"`
+}
+
+// mountMedia registers the gallery endpoints under /{id}/media.
+func mountMedia(r chi.Router, cfg config.Config, logger zerolog.Logger, svc listingservice.Service, media mediaservice.Service, agencies agencyservice.Service) {
+	maxUpload := cfg.Storage.MaxUploadMB << 20
+
+	// listingID resolves the {id} listing visible to the caller.
+	listingID := func(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+		listing, ok := visibleListing(w, r, logger, svc, agencies)
+		return listing.ID, ok
+	}
+
+	// managedID resolves the {id} listing for a gallery write by its agency.
+	managedID := func(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+		listing, _, ok := managedListing(w, r, logger, svc, agencies)
+		return listing.ID, ok
+	}
+
+	// mediaItem loads {mediaID} and checks that it belongs to the listing.
+	mediaItem := func(w http.ResponseWriter, r *http.Request, listing uuid.UUID) (mediaservice.Media, bool) {
+		id, err := uuid.Parse(chi.URLParam(r, "mediaID"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_id")
+			return mediaservice.Media{}, false
+		}
+		item, err := media.Get(r.Context(), id)
+		if err != nil || item.ListingID != listing {
+			if err == nil || errors.Is(err, mediaservice.ErrNotFound) {
+				respondError(w, http.StatusNotFound, "not_found")
+				return mediaservice.Media{}, false
+			}
+			logger.Error().Err(err).Str("media_id", id.String()).Msg("get_media_failed")
+			respondError(w, http.StatusInternalServerError, "get_failed")
+			return mediaservice.Media{}, false
+		}
+		return item, true
+	}
+
+	r.Get("/{id}/media", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := listingID(w, r)
+		if !ok {
+			return
+		}
+		items, err := media.List(r.Context(), id)
+		if err != nil {
+			logger.Error().Err(err).Str("id", id.String()).Msg("list_media_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{"data": items})
+	})
+
+	r.Post("/{id}/media", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := managedID(w, r)
+		if !ok {
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxUpload)
+		if err := r.ParseMultipartForm(maxUpload); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				respondError(w, http.StatusRequestEntityTooLarge, "file_too_large")
+				return
+			}
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		defer func() { _ = r.MultipartForm.RemoveAll() }()
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "missing_file")
+			return
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(file)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+
+		item, err := media.Upload(r.Context(), mediaservice.UploadInput{
+			ListingID: id,
+			Kind:      mediaservice.Kind(r.FormValue("kind")),
+			Caption:   r.FormValue("caption"),
+			Filename:  header.Filename,
+			Data:      data,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, mediaservice.ErrUnsupportedType):
+				respondError(w, http.StatusUnsupportedMediaType, "unsupported_media_type")
+			case errors.Is(err, mediaservice.ErrImageTooLarge):
+				respondError(w, http.StatusRequestEntityTooLarge, "image_too_large")
+			default:
+				logger.Warn().Err(err).Str("id", id.String()).Msg("upload_media")
+				respondError(w, http.StatusBadRequest, err.Error())
+			}
+			return
+		}
+		respondJSON(w, http.StatusCreated, item)
+	})
+
+	r.Put("/{id}/media/order", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := managedID(w, r)
+		if !ok {
+			return
+		}
+		var payload mediaOrderRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		defer r.Body.Close()
+
+		items, err := media.Reorder(r.Context(), id, payload.IDs)
+		if err != nil {
+			if errors.Is(err, mediaservice.ErrInvalidOrder) {
+				respondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			logger.Error().Err(err).Str("id", id.String()).Msg("reorder_media_failed")
+			respondError(w, http.StatusInternalServerError, "reorder_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{"data": items})
+	})
+
+	r.Put("/{id}/media/{mediaID}", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := managedID(w, r)
+		if !ok {
+			return
+		}
+		item, ok := mediaItem(w, r, id)
+		if !ok {
+			return
+		}
+		var payload mediaUpdateRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		defer r.Body.Close()
+
+		updated, err := media.Update(r.Context(), item.ID, mediaservice.UpdateInput{
+			Kind:    payload.Kind,
+			Caption: payload.Caption,
+		})
+		if err != nil {
+			if errors.Is(err, mediaservice.ErrNotFound) {
+				respondError(w, http.StatusNotFound, "not_found")
+				return
+			}
+			logger.Warn().Err(err).Str("media_id", item.ID.String()).Msg("update_media")
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondJSON(w, http.StatusOK, updated)
+	})
+
+	r.Delete("/{id}/media/{mediaID}", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := managedID(w, r)
+		if !ok {
+			return
+		}
+		item, ok := mediaItem(w, r, id)
+		if !ok {
+			return
+		}
+		if err := media.Delete(r.Context(), item.ID); err != nil {
+			if errors.Is(err, mediaservice.ErrNotFound) {
+				respondError(w, http.StatusNotFound, "not_found")
+				return
+			}
+			logger.Error().Err(err).Str("media_id", item.ID.String()).Msg("delete_media")
+			respondError(w, http.StatusInternalServerError, "delete_failed")
+			return
+		}
+		respondJSON(w, http.StatusNoContent, nil)
+	})
+}
@@ -0,0 +1,324 @@
+package listings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/auth"
+	"shanraq.com/internal/auth/session"
+	"shanraq.com/internal/config"
+	"shanraq.com/internal/notify"
+	agencyservice "shanraq.com/internal/services/agency"
+	listingservice "shanraq.com/internal/services/listing"
+	offerservice "shanraq.com/internal/services/offer"
+	"shanraq.com/internal/web"
+)
+
+const outbidKind = "offer_outbid"
+
+type offerRequest struct {
+	Amount     float64  `json:"amount"`
+	Currency   string   `json:"currency"`
+	Conditions []string `json:"conditions"`
+	ExpiresAt  string   `json:"expires_at"`
+	Note       string   `json:"note"`
+}
+
+// terms converts the payload; an empty expires_at is left zero for the service
+// to reject or default.
+func (p offerRequest) terms() (offerservice.Terms, error) {
+	terms := offerservice.Terms{Amount: p.Amount, Currency: p.Currency, Conditions: p.Conditions}
+	if raw := strings.TrimSpace(p.ExpiresAt); raw != "" {
+		at, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return offerservice.Terms{}, errors.New("expires_at must be an RFC 3339 timestamp")
+		}
+		terms.ExpiresAt = at
+	}
+	return terms, nil
+}
+
+// mountOffers registers the offer negotiation endpoints of sale listings. Buyers
+// submit offers and the listing's agency counters, accepts or rejects them; every
+// step is kept in the offer's history. Accepting an offer puts the listing under
+// offer and tells the other bidders.
+func mountOffers(r chi.Router, cfg config.Config, logger zerolog.Logger, svc listingservice.Service, agencies agencyservice.Service, offers offerservice.Service, outbox notify.Outbox) {
+	r.Post("/{id}/offers", func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := session.IdentityFromContext(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+		listing, ok := biddableListing(w, r, logger, svc, agencies)
+		if !ok {
+			return
+		}
+		party, err := offerParty(r, agencies, listing, identity)
+		if err != nil {
+			logger.Error().Err(err).Msg("resolve_viewer_failed")
+			respondError(w, http.StatusInternalServerError, "create_failed")
+			return
+		}
+		if party == offerservice.PartyAgency {
+			respondError(w, http.StatusForbidden, "agency_cannot_bid")
+			return
+		}
+		var payload offerRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		defer r.Body.Close()
+		terms, err := payload.terms()
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if strings.TrimSpace(terms.Currency) == "" {
+			terms.Currency = listing.Currency
+		}
+		created, err := offers.Submit(r.Context(), offerservice.SubmitInput{
+			ListingID:  listing.ID,
+			AgencyID:   listing.AgencyID,
+			BuyerID:    identity.Key(),
+			BuyerEmail: identity.Email,
+			BuyerName:  identity.FullName,
+			Terms:      terms,
+			Note:       payload.Note,
+		})
+		if err != nil {
+			respondOfferError(w, logger, err)
+			return
+		}
+		logger.Info().Str("offer", created.ID.String()).Str("listing", listing.ID.String()).Msg("offer_submitted")
+		respondJSON(w, http.StatusCreated, created)
+	})
+
+	r.Get("/{id}/offers", func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := session.IdentityFromContext(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+		listing, ok := visibleListing(w, r, logger, svc, agencies)
+		if !ok {
+			return
+		}
+		filter := offerservice.Filter{ListingID: listing.ID}
+		if raw := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("status"))); raw != "" {
+			filter.Status = offerservice.Status(raw)
+			if !filter.Status.Valid() {
+				respondError(w, http.StatusBadRequest, "unknown_status")
+				return
+			}
+		}
+		party, err := offerParty(r, agencies, listing, identity)
+		if err != nil {
+			logger.Error().Err(err).Msg("resolve_viewer_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		// The agency sees every bid; buyers only their own.
+		if party != offerservice.PartyAgency {
+			filter.BuyerID = identity.Key()
+		}
+		found, err := offers.List(r.Context(), filter)
+		if err != nil {
+			logger.Error().Err(err).Str("id", listing.ID.String()).Msg("list_offers_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{"data": found})
+	})
+
+	r.Get("/{id}/offers/{offerID}", func(w http.ResponseWriter, r *http.Request) {
+		_, offer, _, ok := partyOffer(w, r, logger, svc, agencies, offers)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, offer)
+	})
+
+	respond := func(action offerservice.Action) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			listing, offer, party, ok := partyOffer(w, r, logger, svc, agencies, offers)
+			if !ok {
+				return
+			}
+			identity, _ := session.IdentityFromContext(r.Context())
+			response := offerservice.Response{Action: action, Party: party, ActorEmail: identity.Email}
+			if r.ContentLength != 0 {
+				var payload offerRequest
+				if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+					respondError(w, http.StatusBadRequest, "invalid_payload")
+					return
+				}
+				defer r.Body.Close()
+				terms, err := payload.terms()
+				if err != nil {
+					respondError(w, http.StatusBadRequest, err.Error())
+					return
+				}
+				response.Terms, response.Note = terms, payload.Note
+			}
+			if action == offerservice.ActionAccept && !listing.Status.Public() {
+				respondError(w, http.StatusConflict, "listing_not_available")
+				return
+			}
+			updated, err := offers.Respond(r.Context(), offer.ID, response)
+			if err != nil {
+				respondOfferError(w, logger, err)
+				return
+			}
+			logger.Info().Str("offer", updated.ID.String()).Str("action", string(action)).Str("party", string(party)).Msg("offer_response")
+			if updated.Status == offerservice.StatusAccepted {
+				acceptOffer(r.Context(), cfg, logger, svc, offers, outbox, listing, updated, identity)
+			}
+			respondJSON(w, http.StatusOK, updated)
+		}
+	}
+	r.Post("/{id}/offers/{offerID}/counter", respond(offerservice.ActionCounter))
+	r.Post("/{id}/offers/{offerID}/accept", respond(offerservice.ActionAccept))
+	r.Post("/{id}/offers/{offerID}/reject", respond(offerservice.ActionReject))
+	r.Post("/{id}/offers/{offerID}/withdraw", respond(offerservice.ActionWithdraw))
+}
+
+// acceptOffer moves the listing under offer and tells the buyers of the other open
+// offers. The acceptance already stands, so failures are only logged.
+func acceptOffer(ctx context.Context, cfg config.Config, logger zerolog.Logger, svc listingservice.Service, offers offerservice.Service, outbox notify.Outbox, listing listingservice.Listing, accepted offerservice.Offer, actor auth.Identity) {
+	if listing.Status == listingservice.StatusPublished {
+		_, err := svc.Transition(ctx, listing.ID, listingservice.TransitionInput{
+			Status:     listingservice.StatusUnderOffer,
+			ActorEmail: actor.Email,
+			ActorName:  actor.FullName,
+			Note:       "offer " + accepted.ID.String() + " accepted",
+		})
+		if err != nil {
+			logger.Error().Err(err).Str("id", listing.ID.String()).Msg("listing_under_offer_failed")
+		}
+	}
+
+	competing, err := offers.List(ctx, offerservice.Filter{ListingID: listing.ID, OpenOnly: true})
+	if err != nil {
+		logger.Error().Err(err).Str("id", listing.ID.String()).Msg("list_competing_offers_failed")
+		return
+	}
+	link := web.AbsoluteURL(cfg.HTTP.PublicBaseURL, "/listings/"+listing.Slug)
+	for _, o := range competing {
+		if o.ID == accepted.ID || o.BuyerEmail == "" {
+			continue
+		}
+		var body strings.Builder
+		fmt.Fprintf(&body, "The agency has accepted another offer on \"%s\" (%s).\n\n", listing.Title, listing.LocationString())
+		fmt.Fprintf(&body, "Your offer of %.2f %s stays open until %s in case the accepted sale falls through.\n", o.Amount, o.Currency, o.ExpiresAt.Format(time.RFC1123))
+		fmt.Fprintf(&body, "You can withdraw it at any time from %s/api/v1/workspaces/me/offers\n\n%s\n", strings.TrimRight(cfg.HTTP.PublicBaseURL, "/"), link)
+		_, err := outbox.Enqueue(ctx, notify.Notification{
+			Kind:      outbidKind,
+			Recipient: o.BuyerEmail,
+			Subject:   fmt.Sprintf("Another offer was accepted on \"%s\"", listing.Title),
+			Body:      body.String(),
+		})
+		if err != nil {
+			logger.Error().Err(err).Str("offer", o.ID.String()).Msg("enqueue_outbid_notice_failed")
+		}
+	}
+}
+
+// biddableListing loads the {id} listing and rejects listings that cannot take
+// offers: rentals, listings that are not on the market and listings without an
+// agency to negotiate with.
+func biddableListing(w http.ResponseWriter, r *http.Request, logger zerolog.Logger, svc listingservice.Service, agencies agencyservice.Service) (listingservice.Listing, bool) {
+	listing, ok := visibleListing(w, r, logger, svc, agencies)
+	if !ok {
+		return listingservice.Listing{}, false
+	}
+	switch {
+	case listing.IsRental():
+		respondError(w, http.StatusConflict, "not_for_sale")
+	case !listing.Status.Public():
+		respondError(w, http.StatusConflict, "listing_not_available")
+	case listing.AgencyID == uuid.Nil:
+		respondError(w, http.StatusConflict, "listing_has_no_agency")
+	default:
+		return listing, true
+	}
+	return listingservice.Listing{}, false
+}
+
+// offerParty tells whether the caller negotiates for the listing's agency or as a
+// buyer.
+func offerParty(r *http.Request, agencies agencyservice.Service, listing listingservice.Listing, identity auth.Identity) (offerservice.Party, error) {
+	viewer, err := viewerFor(r, agencies)
+	if err != nil {
+		return "", err
+	}
+	if viewer.IsMember(listing.AgencyID) {
+		return offerservice.PartyAgency, nil
+	}
+	return offerservice.PartyBuyer, nil
+}
+
+// partyOffer loads the {offerID} offer of the {id} listing for a signed-in party to
+// it, answering 404 to everyone else.
+func partyOffer(w http.ResponseWriter, r *http.Request, logger zerolog.Logger, svc listingservice.Service, agencies agencyservice.Service, offers offerservice.Service) (listingservice.Listing, offerservice.Offer, offerservice.Party, bool) {
+	identity, ok := session.IdentityFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthenticated")
+		return listingservice.Listing{}, offerservice.Offer{}, "", false
+	}
+	listing, ok := visibleListing(w, r, logger, svc, agencies)
+	if !ok {
+		return listingservice.Listing{}, offerservice.Offer{}, "", false
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "offerID"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id")
+		return listingservice.Listing{}, offerservice.Offer{}, "", false
+	}
+	offer, err := offers.Get(r.Context(), id)
+	if err != nil && !errors.Is(err, offerservice.ErrNotFound) {
+		logger.Error().Err(err).Str("offer", id.String()).Msg("get_offer_failed")
+		respondError(w, http.StatusInternalServerError, "get_failed")
+		return listingservice.Listing{}, offerservice.Offer{}, "", false
+	}
+	party, partyErr := offerParty(r, agencies, listing, identity)
+	if partyErr != nil {
+		logger.Error().Err(partyErr).Msg("resolve_viewer_failed")
+		respondError(w, http.StatusInternalServerError, "get_failed")
+		return listingservice.Listing{}, offerservice.Offer{}, "", false
+	}
+	if err != nil || offer.ListingID != listing.ID || (party == offerservice.PartyBuyer && offer.BuyerID != identity.Key()) {
+		respondError(w, http.StatusNotFound, "not_found")
+		return listingservice.Listing{}, offerservice.Offer{}, "", false
+	}
+	return listing, offer, party, true
+}
+
+func respondOfferError(w http.ResponseWriter, logger zerolog.Logger, err error) {
+	switch {
+	case errors.Is(err, offerservice.ErrNotFound):
+		respondError(w, http.StatusNotFound, "not_found")
+	case errors.Is(err, offerservice.ErrOpenOffer):
+		respondError(w, http.StatusConflict, "offer_already_open")
+	case errors.Is(err, offerservice.ErrNotYourTurn):
+		respondError(w, http.StatusConflict, "awaiting_other_party")
+	case errors.Is(err, offerservice.ErrClosed):
+		respondError(w, http.StatusConflict, "offer_closed")
+	case errors.Is(err, offerservice.ErrExpired):
+		respondError(w, http.StatusConflict, "offer_expired")
+	case errors.Is(err, offerservice.ErrAlreadyAccepted):
+		respondError(w, http.StatusConflict, "offer_already_accepted")
+	default:
+		logger.Warn().Err(err).Msg("offer_request")
+		respondError(w, http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,110 @@
+package listings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/auth"
+	"shanraq.com/internal/auth/session"
+	"shanraq.com/internal/httpserver/deps"
+	"shanraq.com/internal/notify"
+	agencyservice "shanraq.com/internal/services/agency"
+	"shanraq.com/internal/services/fx"
+	listingservice "shanraq.com/internal/services/listing"
+	offerservice "shanraq.com/internal/services/offer"
+)
+
+func TestAcceptedOfferKeepsListingVisibleAndBiddable(t *testing.T) {
+	ctx := context.Background()
+	listings := listingservice.NewInMemoryService()
+	agencies := agencyservice.NewInMemoryService()
+	offers := offerservice.NewInMemoryService()
+
+	realtors, err := agencies.ListRealtors(ctx)
+	if err != nil || len(realtors) == 0 {
+		t.Fatalf("ListRealtors() = %d realtors, %v", len(realtors), err)
+	}
+	realtor := realtors[0]
+	all, _, err := listings.List(ctx, listingservice.ListFilter{Type: listingservice.ListingTypeResidential, Limit: listingservice.MaxPageSize})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var listing listingservice.Listing
+	for _, l := range all {
+		if !l.IsRental() {
+			listing = l
+			break
+		}
+	}
+	if listing.ID == uuid.Nil {
+		t.Fatal("no sale listing in the demo data")
+	}
+	if _, err := listings.Update(ctx, listing.ID, listingservice.UpdateInput{AgencyID: &realtor.AgencyID}); err != nil {
+		t.Fatalf("Update(agency) error = %v", err)
+	}
+
+	router := Router(deps.Deps{
+		Logger:         zerolog.Nop(),
+		ListingService: listings,
+		AgencyService:  agencies,
+		FXService:      fx.NewInMemoryService(),
+		Offers:         offers,
+		Outbox:         notify.NewInMemoryOutbox(),
+	})
+	do := func(method, path string, identity *auth.Identity, payload any) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			if err := json.NewEncoder(&body).Encode(payload); err != nil {
+				t.Fatalf("encode payload: %v", err)
+			}
+		}
+		req := httptest.NewRequest(method, path, &body)
+		if identity != nil {
+			req = req.WithContext(session.WithIdentity(req.Context(), *identity))
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	bid := func(buyer auth.Identity, amount float64) *httptest.ResponseRecorder {
+		return do(http.MethodPost, "/"+listing.ID.String()+"/offers", &buyer, offerRequest{
+			Amount:    amount,
+			ExpiresAt: time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339),
+		})
+	}
+
+	ana := auth.Identity{Subject: "buyer-ana", Email: "ana@example.com"}
+	rui := auth.Identity{Subject: "buyer-rui", Email: "rui@example.com"}
+	rec := bid(ana, listing.Price)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("submit offer = %d %s, want 201", rec.Code, rec.Body)
+	}
+	var offer offerservice.Offer
+	if err := json.NewDecoder(rec.Body).Decode(&offer); err != nil {
+		t.Fatalf("decode offer: %v", err)
+	}
+
+	agent := auth.Identity{Subject: "realtor", Email: realtor.Email}
+	if rec := do(http.MethodPost, "/"+listing.ID.String()+"/offers/"+offer.ID.String()+"/accept", &agent, nil); rec.Code != http.StatusOK {
+		t.Fatalf("accept offer = %d %s, want 200", rec.Code, rec.Body)
+	}
+	stored, err := listings.Get(ctx, listing.ID)
+	if err != nil || stored.Status != listingservice.StatusUnderOffer {
+		t.Fatalf("listing after accept = %s, %v; want under_offer", stored.Status, err)
+	}
+
+	if rec := do(http.MethodGet, "/"+listing.ID.String(), nil, nil); rec.Code != http.StatusOK {
+		t.Errorf("anonymous GET after accept = %d, want 200", rec.Code)
+	}
+	if rec := bid(rui, listing.Price*1.05); rec.Code != http.StatusCreated {
+		t.Errorf("competing offer after accept = %d %s, want 201", rec.Code, rec.Body)
+	}
+}
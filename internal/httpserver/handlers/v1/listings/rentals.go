@@ -0,0 +1,339 @@
+package listings
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/auth"
+	"shanraq.com/internal/auth/session"
+	agencyservice "shanraq.com/internal/services/agency"
+	listingservice "shanraq.com/internal/services/listing"
+	rentalservice "shanraq.com/internal/services/rental"
+)
+
+type blockRequest struct {
+	Start  string `json:"start"`
+	End    string `json:"end"`
+	Reason string `json:"reason"`
+}
+
+type bookingRequest struct {
+	CheckIn  string `json:"check_in"`
+	CheckOut string `json:"check_out"`
+	Guests   int    `json:"guests"`
+	Message  string `json:"message"`
+}
+
+// mountRentals registers the availability calendar and booking request endpoints of
+// rental listings.
+func mountRentals(r chi.Router, logger zerolog.Logger, svc listingservice.Service, agencies agencyservice.Service, rentals rentalservice.Service) {
+	r.Get("/{id}/availability", func(w http.ResponseWriter, r *http.Request) {
+		listing, ok := visibleRental(w, r, logger, svc, agencies)
+		if !ok {
+			return
+		}
+		query := r.URL.Query()
+		from, err := optionalDate(query.Get("from"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "from must be a date (YYYY-MM-DD)")
+			return
+		}
+		to, err := optionalDate(query.Get("to"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "to must be a date (YYYY-MM-DD)")
+			return
+		}
+		if from, to, err = rentalservice.CalendarRange(from, to, time.Now().UTC()); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		blocks, err := rentals.Blocks(r.Context(), listing.ID, from, to)
+		if err != nil {
+			logger.Error().Err(err).Str("id", listing.ID.String()).Msg("list_rental_blocks_failed")
+			respondError(w, http.StatusInternalServerError, "get_failed")
+			return
+		}
+		booked, err := rentals.Bookings(r.Context(), rentalservice.BookingFilter{
+			ListingID: listing.ID,
+			Status:    rentalservice.BookingAccepted,
+			From:      from,
+			To:        to,
+		})
+		if err != nil {
+			logger.Error().Err(err).Str("id", listing.ID.String()).Msg("list_rental_bookings_failed")
+			respondError(w, http.StatusInternalServerError, "get_failed")
+			return
+		}
+		payload := map[string]any{
+			"listing_id": listing.ID,
+			"rental":     listing.Rental,
+			"from":       from.Format(rentalservice.DateLayout),
+			"to":         to.Format(rentalservice.DateLayout),
+			"days":       rentalservice.Calendar(from, to, blocks, booked),
+		}
+		if viewer, err := viewerFor(r, agencies); err == nil && viewer.IsMember(listing.AgencyID) {
+			payload["blocks"] = blocks
+		}
+		respondJSON(w, http.StatusOK, payload)
+	})
+
+	r.Post("/{id}/availability/blocks", func(w http.ResponseWriter, r *http.Request) {
+		listing, identity, ok := managedRental(w, r, logger, svc, agencies)
+		if !ok {
+			return
+		}
+		var payload blockRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		defer r.Body.Close()
+		start, errStart := rentalservice.ParseDate(payload.Start)
+		end, errEnd := rentalservice.ParseDate(payload.End)
+		if errStart != nil || errEnd != nil {
+			respondError(w, http.StatusBadRequest, "start and end must be dates (YYYY-MM-DD)")
+			return
+		}
+		block, err := rentals.AddBlock(r.Context(), rentalservice.BlockInput{
+			ListingID: listing.ID,
+			Start:     start,
+			End:       end,
+			Reason:    payload.Reason,
+			CreatedBy: identity.Email,
+		})
+		if err != nil {
+			respondRentalError(w, logger, err)
+			return
+		}
+		respondJSON(w, http.StatusCreated, block)
+	})
+
+	r.Delete("/{id}/availability/blocks/{blockID}", func(w http.ResponseWriter, r *http.Request) {
+		listing, _, ok := managedRental(w, r, logger, svc, agencies)
+		if !ok {
+			return
+		}
+		blockID, err := uuid.Parse(chi.URLParam(r, "blockID"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_id")
+			return
+		}
+		if err := rentals.RemoveBlock(r.Context(), listing.ID, blockID); err != nil {
+			respondRentalError(w, logger, err)
+			return
+		}
+		respondJSON(w, http.StatusNoContent, nil)
+	})
+
+	r.Get("/{id}/bookings", func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := session.IdentityFromContext(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+		listing, ok := visibleRental(w, r, logger, svc, agencies)
+		if !ok {
+			return
+		}
+		filter := rentalservice.BookingFilter{ListingID: listing.ID}
+		if raw := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("status"))); raw != "" {
+			filter.Status = rentalservice.BookingStatus(raw)
+			if !filter.Status.Valid() {
+				respondError(w, http.StatusBadRequest, "unknown_status")
+				return
+			}
+		}
+		viewer, err := viewerFor(r, agencies)
+		if err != nil {
+			logger.Error().Err(err).Msg("resolve_viewer_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		// Landlords see every request; everyone else only their own.
+		if !viewer.IsMember(listing.AgencyID) {
+			filter.RequesterID = identity.Key()
+		}
+		bookings, err := rentals.Bookings(r.Context(), filter)
+		if err != nil {
+			logger.Error().Err(err).Str("id", listing.ID.String()).Msg("list_bookings_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{"data": bookings})
+	})
+
+	r.Post("/{id}/bookings", func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := session.IdentityFromContext(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+		listing, ok := visibleRental(w, r, logger, svc, agencies)
+		if !ok {
+			return
+		}
+		if listing.Status != listingservice.StatusPublished {
+			respondError(w, http.StatusConflict, "listing_not_published")
+			return
+		}
+		var payload bookingRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		defer r.Body.Close()
+		checkIn, errIn := rentalservice.ParseDate(payload.CheckIn)
+		checkOut, errOut := rentalservice.ParseDate(payload.CheckOut)
+		if errIn != nil || errOut != nil {
+			respondError(w, http.StatusBadRequest, "check_in and check_out must be dates (YYYY-MM-DD)")
+			return
+		}
+		booking, err := rentals.RequestBooking(r.Context(), rentalservice.BookingInput{
+			ListingID:      listing.ID,
+			RequesterID:    identity.Key(),
+			RequesterEmail: identity.Email,
+			RequesterName:  identity.FullName,
+			CheckIn:        checkIn,
+			CheckOut:       checkOut,
+			Guests:         payload.Guests,
+			Message:        payload.Message,
+			MinNights:      listing.Rental.MinNights(),
+		})
+		if err != nil {
+			respondRentalError(w, logger, err)
+			return
+		}
+		respondJSON(w, http.StatusCreated, booking)
+	})
+
+	decide := func(status rentalservice.BookingStatus) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			listing, identity, ok := managedRental(w, r, logger, svc, agencies)
+			if !ok {
+				return
+			}
+			booking, ok := listingBooking(w, r, logger, rentals, listing.ID)
+			if !ok {
+				return
+			}
+			decided, err := rentals.DecideBooking(r.Context(), booking.ID, rentalservice.Decision{Status: status, ActorEmail: identity.Email})
+			if err != nil {
+				respondRentalError(w, logger, err)
+				return
+			}
+			respondJSON(w, http.StatusOK, decided)
+		}
+	}
+	r.Post("/{id}/bookings/{bookingID}/accept", decide(rentalservice.BookingAccepted))
+	r.Post("/{id}/bookings/{bookingID}/decline", decide(rentalservice.BookingDeclined))
+
+	r.Post("/{id}/bookings/{bookingID}/cancel", func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := session.IdentityFromContext(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_id")
+			return
+		}
+		booking, ok := listingBooking(w, r, logger, rentals, id)
+		if !ok {
+			return
+		}
+		cancelled, err := rentals.CancelBooking(r.Context(), booking.ID, identity.Key())
+		if err != nil {
+			respondRentalError(w, logger, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, cancelled)
+	})
+}
+
+// visibleRental loads the {id} listing like visibleListing and rejects listings
+// that are not offered for rent.
+func visibleRental(w http.ResponseWriter, r *http.Request, logger zerolog.Logger, svc listingservice.Service, agencies agencyservice.Service) (listingservice.Listing, bool) {
+	listing, ok := visibleListing(w, r, logger, svc, agencies)
+	if !ok {
+		return listingservice.Listing{}, false
+	}
+	if !listing.IsRental() {
+		respondError(w, http.StatusConflict, "not_a_rental")
+		return listingservice.Listing{}, false
+	}
+	return listing, true
+}
+
+// managedRental loads the {id} rental for a signed-in member of its agency.
+func managedRental(w http.ResponseWriter, r *http.Request, logger zerolog.Logger, svc listingservice.Service, agencies agencyservice.Service) (listingservice.Listing, auth.Identity, bool) {
+	identity, ok := session.IdentityFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthenticated")
+		return listingservice.Listing{}, auth.Identity{}, false
+	}
+	listing, ok := visibleRental(w, r, logger, svc, agencies)
+	if !ok {
+		return listingservice.Listing{}, auth.Identity{}, false
+	}
+	viewer, err := viewerFor(r, agencies)
+	if err != nil {
+		logger.Error().Err(err).Msg("resolve_viewer_failed")
+		respondError(w, http.StatusInternalServerError, "get_failed")
+		return listingservice.Listing{}, auth.Identity{}, false
+	}
+	if !viewer.IsMember(listing.AgencyID) {
+		respondError(w, http.StatusForbidden, "forbidden")
+		return listingservice.Listing{}, auth.Identity{}, false
+	}
+	return listing, identity, true
+}
+
+// listingBooking loads the {bookingID} booking, answering 404 unless it belongs to
+// the listing.
+func listingBooking(w http.ResponseWriter, r *http.Request, logger zerolog.Logger, rentals rentalservice.Service, listingID uuid.UUID) (rentalservice.Booking, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "bookingID"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id")
+		return rentalservice.Booking{}, false
+	}
+	booking, err := rentals.GetBooking(r.Context(), id)
+	if err != nil || booking.ListingID != listingID {
+		if err == nil || errors.Is(err, rentalservice.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "not_found")
+			return rentalservice.Booking{}, false
+		}
+		logger.Error().Err(err).Str("booking", id.String()).Msg("get_booking_failed")
+		respondError(w, http.StatusInternalServerError, "get_failed")
+		return rentalservice.Booking{}, false
+	}
+	return booking, true
+}
+
+func optionalDate(raw string) (time.Time, error) {
+	if strings.TrimSpace(raw) == "" {
+		return time.Time{}, nil
+	}
+	return rentalservice.ParseDate(raw)
+}
+
+func respondRentalError(w http.ResponseWriter, logger zerolog.Logger, err error) {
+	switch {
+	case errors.Is(err, rentalservice.ErrNotFound):
+		respondError(w, http.StatusNotFound, "not_found")
+	case errors.Is(err, rentalservice.ErrUnavailable):
+		respondError(w, http.StatusConflict, "dates_unavailable")
+	case errors.Is(err, rentalservice.ErrNotPending):
+		respondError(w, http.StatusConflict, "booking_not_pending")
+	default:
+		logger.Warn().Err(err).Msg("rental_request")
+		respondError(w, http.StatusBadRequest, err.Error())
+	}
+}
@@ -1,69 +1,383 @@
 package listings
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 
-	"shanraq.com/internal/config"
+	"shanraq.com/internal/auth/session"
+	"shanraq.com/internal/format"
+	"shanraq.com/internal/httpserver/deps"
+	"shanraq.com/internal/i18n"
+	"shanraq.com/internal/services/fx"
 	listingservice "shanraq.com/internal/services/listing"
+	placementservice "shanraq.com/internal/services/placement"
 )
 
-// Router exposes property listing read endpoints.
-func Router(cfg config.Config, logger zerolog.Logger, svc listingservice.Service) chi.Router {
+// Router exposes property listing read and write endpoints.
+func Router(d deps.Deps) chi.Router {
+	var (
+		cfg        = d.Config
+		logger     = d.Logger
+		svc        = d.ListingService
+		rates      = d.FXService
+		media      = d.MediaService
+		agencies   = d.AgencyService
+		favorites  = d.Favorites
+		rentals    = d.Rentals
+		leads      = d.Leads
+		offers     = d.Offers
+		outbox     = d.Outbox
+		placements = d.Placements
+	)
 	r := chi.NewRouter()
 
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
-		listings, err := svc.List(r.Context())
+		filter, err := listingservice.ParseFilter(r.URL.Query())
 		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if filter.Viewer, err = viewerFor(r, agencies); err != nil {
+			logger.Error().Err(err).Msg("resolve_viewer_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+
+		listings, total, err := svc.List(r.Context(), filter)
+		if err != nil {
+			if errors.Is(err, fx.ErrUnknownCurrency) {
+				respondError(w, http.StatusBadRequest, "unknown_currency")
+				return
+			}
 			logger.Error().Err(err).Msg("list_listings_failed")
 			respondError(w, http.StatusInternalServerError, "list_failed")
 			return
 		}
-		respondJSON(w, http.StatusOK, map[string]any{
-			"data": listings,
-			"meta": map[string]any{
-				"count": len(listings),
+		facets, err := svc.Facets(r.Context(), filter)
+		if err != nil {
+			logger.Error().Err(err).Msg("listing_facets_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		locale, err := localize(w, r, svc, listings)
+		if err != nil {
+			logger.Error().Err(err).Msg("localize_listings_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		applyDisplay(r, listings)
+
+		respondJSON(w, http.StatusOK, listResponse{
+			Data: listings,
+			Meta: listMeta{
+				Total:    total,
+				Count:    len(listings),
+				Limit:    filter.Limit,
+				Offset:   filter.Offset,
+				Sort:     string(filter.Sort),
+				Currency: filter.Currency,
+				Locale:   locale,
+			},
+			Facets: facets,
+		})
+	})
+
+	r.Get("/search", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		text := strings.TrimSpace(query.Get("q"))
+		if text == "" {
+			respondError(w, http.StatusBadRequest, "missing_query")
+			return
+		}
+		filter, err := listingservice.ParseFilter(query)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if filter.Viewer, err = viewerFor(r, agencies); err != nil {
+			logger.Error().Err(err).Msg("resolve_viewer_failed")
+			respondError(w, http.StatusInternalServerError, "search_failed")
+			return
+		}
+		language := query.Get("lang")
+		if language == "" {
+			language = r.Header.Get("Accept-Language")
+		}
+		language = listingservice.NormalizeSearchLanguage(language)
+
+		results, total, err := svc.Search(r.Context(), listingservice.SearchQuery{
+			Text:     text,
+			Language: language,
+			Filter:   filter,
+		})
+		if err != nil {
+			if errors.Is(err, listingservice.ErrEmptyQuery) {
+				respondError(w, http.StatusBadRequest, "missing_query")
+				return
+			}
+			if errors.Is(err, fx.ErrUnknownCurrency) {
+				respondError(w, http.StatusBadRequest, "unknown_currency")
+				return
+			}
+			logger.Error().Err(err).Str("q", text).Msg("search_listings_failed")
+			respondError(w, http.StatusInternalServerError, "search_failed")
+			return
+		}
+		found := make([]listingservice.Listing, len(results))
+		for idx := range results {
+			found[idx] = results[idx].Listing
+		}
+		locale, err := localize(w, r, svc, found)
+		if err != nil {
+			logger.Error().Err(err).Msg("localize_listings_failed")
+			respondError(w, http.StatusInternalServerError, "search_failed")
+			return
+		}
+		applyDisplay(r, found)
+		for idx := range results {
+			results[idx].Listing = found[idx]
+		}
+
+		respondJSON(w, http.StatusOK, searchResponse{
+			Data: results,
+			Meta: searchMeta{
+				Total:    total,
+				Count:    len(results),
+				Limit:    filter.Limit,
+				Offset:   filter.Offset,
+				Query:    text,
+				Language: language,
+				Currency: filter.Currency,
+				Locale:   locale,
 			},
 		})
 	})
 
 	r.Get("/featured", func(w http.ResponseWriter, r *http.Request) {
-		listings, err := svc.Featured(r.Context(), 6)
+		currency, err := requestedCurrency(r)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		viewer, err := viewerFor(r, agencies)
+		if err != nil {
+			logger.Error().Err(err).Msg("resolve_viewer_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		listings, err := placements.Featured(r.Context(), placementservice.FeaturedQuery{
+			Limit:   6,
+			Viewer:  viewer,
+			Country: r.URL.Query().Get("country"),
+			Locale:  i18n.Negotiate(r),
+		})
 		if err != nil {
 			logger.Error().Err(err).Msg("featured_listings_failed")
 			respondError(w, http.StatusInternalServerError, "list_failed")
 			return
 		}
+		if err := convertPrices(r.Context(), rates, currency, listings); err != nil {
+			respondConversionError(w, logger, err)
+			return
+		}
+		locale, err := localize(w, r, svc, listings)
+		if err != nil {
+			logger.Error().Err(err).Msg("localize_listings_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		applyDisplay(r, listings)
 		respondJSON(w, http.StatusOK, map[string]any{
-			"data": listings,
+			"data":   listings,
+			"locale": locale,
 		})
 	})
 
 	r.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {
-		idParam := chi.URLParam(r, "id")
-		id, err := uuid.Parse(idParam)
+		currency, err := requestedCurrency(r)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		listing, ok := visibleListing(w, r, logger, svc, agencies)
+		if !ok {
+			return
+		}
+		converted := []listingservice.Listing{listing}
+		if err := convertPrices(r.Context(), rates, currency, converted); err != nil {
+			respondConversionError(w, logger, err)
+			return
+		}
+		if _, err := localize(w, r, svc, converted); err != nil {
+			logger.Error().Err(err).Str("id", listing.ID.String()).Msg("localize_listing_failed")
+			respondError(w, http.StatusInternalServerError, "get_failed")
+			return
+		}
+		applyDisplay(r, converted)
+		respondJSON(w, http.StatusOK, converted[0])
+	})
+
+	r.Post("/", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := session.IdentityFromContext(r.Context()); !ok {
+			respondError(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+		var payload createRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		defer r.Body.Close()
+
+		viewer, err := viewerFor(r, agencies)
 		if err != nil {
-			respondError(w, http.StatusBadRequest, "invalid_id")
+			logger.Error().Err(err).Msg("resolve_viewer_failed")
+			respondError(w, http.StatusInternalServerError, "create_failed")
 			return
 		}
-		listing, err := svc.Get(r.Context(), id)
+		if !viewer.IsMember(payload.AgencyID) {
+			respondError(w, http.StatusForbidden, "forbidden")
+			return
+		}
+
+		listing, err := svc.Create(r.Context(), payload.toInput())
 		if err != nil {
-			respondError(w, http.StatusNotFound, "not_found")
+			logger.Warn().Err(err).Msg("create_listing")
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondJSON(w, http.StatusCreated, listing)
+	})
+
+	r.Put("/{id}", func(w http.ResponseWriter, r *http.Request) {
+		current, _, ok := managedListing(w, r, logger, svc, agencies)
+		if !ok {
+			return
+		}
+		id := current.ID
+
+		var payload updateRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		defer r.Body.Close()
+
+		if payload.AgencyID != nil && *payload.AgencyID != current.AgencyID {
+			viewer, err := viewerFor(r, agencies)
+			if err != nil {
+				logger.Error().Err(err).Msg("resolve_viewer_failed")
+				respondError(w, http.StatusInternalServerError, "update_failed")
+				return
+			}
+			if !viewer.IsMember(*payload.AgencyID) {
+				respondError(w, http.StatusForbidden, "forbidden")
+				return
+			}
+		}
+
+		listing, err := svc.Update(r.Context(), id, payload.toInput())
+		if err != nil {
+			if errors.Is(err, listingservice.ErrNotFound) {
+				respondError(w, http.StatusNotFound, "not_found")
+				return
+			}
+			logger.Warn().Err(err).Str("id", id.String()).Msg("update_listing")
+			respondError(w, http.StatusBadRequest, err.Error())
 			return
 		}
 		respondJSON(w, http.StatusOK, listing)
 	})
 
+	r.Delete("/{id}", func(w http.ResponseWriter, r *http.Request) {
+		listing, _, ok := managedListing(w, r, logger, svc, agencies)
+		if !ok {
+			return
+		}
+		id := listing.ID
+
+		if err := svc.Delete(r.Context(), id); err != nil {
+			if errors.Is(err, listingservice.ErrNotFound) {
+				respondError(w, http.StatusNotFound, "not_found")
+				return
+			}
+			logger.Error().Err(err).Str("id", id.String()).Msg("delete_listing")
+			respondError(w, http.StatusInternalServerError, "delete_failed")
+			return
+		}
+		respondJSON(w, http.StatusNoContent, nil)
+	})
+
+	mountCompare(r, logger, svc, rates, agencies)
+	mountMedia(r, cfg, logger, svc, media, agencies)
+	mountStatus(r, logger, svc, agencies)
+	mountTranslations(r, logger, svc, agencies)
+	mountFavorites(r, logger, svc, agencies, favorites)
+	mountRentals(r, logger, svc, agencies, rentals)
+	mountInquiries(r, cfg, logger, svc, agencies, leads)
+	mountOffers(r, cfg, logger, svc, agencies, offers, outbox)
+
 	return r
 }
 
+// requestedCurrency validates the optional ?currency= parameter.
+func requestedCurrency(r *http.Request) (string, error) {
+	filter, err := listingservice.ParseFilter(url.Values{"currency": r.URL.Query()["currency"]})
+	if err != nil {
+		return "", err
+	}
+	return filter.Currency, nil
+}
+
+// convertPrices applies a target currency to listings fetched without a ListFilter.
+func convertPrices(ctx context.Context, rates fx.Service, currency string, listings []listingservice.Listing) error {
+	if currency == "" {
+		return nil
+	}
+	table, err := rates.Table(ctx)
+	if err != nil {
+		return err
+	}
+	if !table.Has(currency) {
+		return fx.ErrUnknownCurrency
+	}
+	for idx := range listings {
+		listingservice.ApplyCurrency(&listings[idx], table, currency)
+	}
+	return nil
+}
+
+// applyDisplay fills the display_* fields with price and area formatted for the
+// request's locale and region when ?display=true.
+func applyDisplay(r *http.Request, listings []listingservice.Listing) {
+	if display, _ := strconv.ParseBool(r.URL.Query().Get("display")); display {
+		listingservice.ApplyDisplay(listings, format.FromRequest(r))
+	}
+}
+
+func respondConversionError(w http.ResponseWriter, logger zerolog.Logger, err error) {
+	if errors.Is(err, fx.ErrUnknownCurrency) {
+		respondError(w, http.StatusBadRequest, "unknown_currency")
+		return
+	}
+	logger.Error().Err(err).Msg("convert_listing_prices_failed")
+	respondError(w, http.StatusInternalServerError, "conversion_failed")
+}
+
 func respondJSON(w http.ResponseWriter, status int, payload any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
+	if payload == nil {
+		return
+	}
 	_ = json.NewEncoder(w).Encode(payload)
 }
 
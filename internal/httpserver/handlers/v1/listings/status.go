@@ -0,0 +1,177 @@
+package listings
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/auth"
+	"shanraq.com/internal/auth/session"
+	agencyservice "shanraq.com/internal/services/agency"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+type transitionRequest struct {
+	Status string `json:"status"`
+	Note   string `json:"note"`
+}
+
+// viewerFor resolves the agencies the signed-in user works for. Anonymous requests
+// get the zero viewer, which only sees published listings.
+func viewerFor(r *http.Request, agencies agencyservice.Service) (listingservice.Viewer, error) {
+	identity, ok := session.IdentityFromContext(r.Context())
+	if !ok || agencies == nil {
+		return listingservice.Viewer{}, nil
+	}
+	ids, err := agencies.MemberAgencyIDs(r.Context(), identity.Email)
+	if err != nil {
+		return listingservice.Viewer{}, err
+	}
+	return listingservice.Viewer{AgencyIDs: ids}, nil
+}
+
+// visibleListing loads the {id} listing, answering 404 when it does not exist or is
+// hidden from the caller.
+func visibleListing(w http.ResponseWriter, r *http.Request, logger zerolog.Logger, svc listingservice.Service, agencies agencyservice.Service) (listingservice.Listing, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id")
+		return listingservice.Listing{}, false
+	}
+	viewer, err := viewerFor(r, agencies)
+	if err != nil {
+		logger.Error().Err(err).Msg("resolve_viewer_failed")
+		respondError(w, http.StatusInternalServerError, "get_failed")
+		return listingservice.Listing{}, false
+	}
+	listing, err := svc.Get(r.Context(), id)
+	if err != nil || !viewer.CanSee(listing) {
+		if err == nil || errors.Is(err, listingservice.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "not_found")
+			return listingservice.Listing{}, false
+		}
+		logger.Error().Err(err).Str("id", id.String()).Msg("get_listing_failed")
+		respondError(w, http.StatusInternalServerError, "get_failed")
+		return listingservice.Listing{}, false
+	}
+	return listing, true
+}
+
+// managedListing loads the {id} listing for a write. Anonymous callers get 401,
+// members of other agencies 403, and listings the caller cannot see 404.
+func managedListing(w http.ResponseWriter, r *http.Request, logger zerolog.Logger, svc listingservice.Service, agencies agencyservice.Service) (listingservice.Listing, auth.Identity, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id")
+		return listingservice.Listing{}, auth.Identity{}, false
+	}
+	identity, ok := session.IdentityFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthenticated")
+		return listingservice.Listing{}, auth.Identity{}, false
+	}
+	viewer, err := viewerFor(r, agencies)
+	if err != nil {
+		logger.Error().Err(err).Msg("resolve_viewer_failed")
+		respondError(w, http.StatusInternalServerError, "get_failed")
+		return listingservice.Listing{}, auth.Identity{}, false
+	}
+	listing, err := svc.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, listingservice.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "not_found")
+			return listingservice.Listing{}, auth.Identity{}, false
+		}
+		logger.Error().Err(err).Str("id", id.String()).Msg("get_listing_failed")
+		respondError(w, http.StatusInternalServerError, "get_failed")
+		return listingservice.Listing{}, auth.Identity{}, false
+	}
+	if !viewer.IsMember(listing.AgencyID) {
+		if !viewer.CanSee(listing) {
+			respondError(w, http.StatusNotFound, "not_found")
+			return listingservice.Listing{}, auth.Identity{}, false
+		}
+		respondError(w, http.StatusForbidden, "forbidden")
+		return listingservice.Listing{}, auth.Identity{}, false
+	}
+	return listing, identity, true
+}
+
+// mountStatus registers the lifecycle and price-history endpoints.
+func mountStatus(r chi.Router, logger zerolog.Logger, svc listingservice.Service, agencies agencyservice.Service) {
+	r.Get("/{id}/transitions", func(w http.ResponseWriter, r *http.Request) {
+		listing, ok := visibleListing(w, r, logger, svc, agencies)
+		if !ok {
+			return
+		}
+		id := listing.ID
+		events, err := svc.History(r.Context(), id)
+		if err != nil {
+			logger.Error().Err(err).Str("id", id.String()).Msg("listing_history_failed")
+			respondError(w, http.StatusInternalServerError, "get_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"data":   events,
+			"status": listing.Status,
+			"next":   listing.Status.Next(),
+		})
+	})
+
+	r.Get("/{id}/price-history", func(w http.ResponseWriter, r *http.Request) {
+		listing, ok := visibleListing(w, r, logger, svc, agencies)
+		if !ok {
+			return
+		}
+		id := listing.ID
+		changes, err := svc.PriceHistory(r.Context(), id)
+		if err != nil {
+			logger.Error().Err(err).Str("id", id.String()).Msg("listing_price_history_failed")
+			respondError(w, http.StatusInternalServerError, "get_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"data":     changes,
+			"price":    listing.Price,
+			"currency": listing.Currency,
+		})
+	})
+
+	r.Post("/{id}/transitions", func(w http.ResponseWriter, r *http.Request) {
+		listing, identity, ok := managedListing(w, r, logger, svc, agencies)
+		if !ok {
+			return
+		}
+		id := listing.ID
+		var payload transitionRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		defer r.Body.Close()
+
+		updated, err := svc.Transition(r.Context(), id, listingservice.TransitionInput{
+			Status:     listingservice.Status(payload.Status),
+			ActorEmail: identity.Email,
+			ActorName:  identity.FullName,
+			Note:       payload.Note,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, listingservice.ErrNotFound):
+				respondError(w, http.StatusNotFound, "not_found")
+			case errors.Is(err, listingservice.ErrInvalidTransition):
+				respondError(w, http.StatusConflict, err.Error())
+			default:
+				logger.Warn().Err(err).Str("id", id.String()).Msg("transition_listing")
+				respondError(w, http.StatusBadRequest, err.Error())
+			}
+			return
+		}
+		respondJSON(w, http.StatusOK, updated)
+	})
+}
@@ -0,0 +1,118 @@
+package listings
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/i18n"
+	agencyservice "shanraq.com/internal/services/agency"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+type translationRequest struct {
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+}
+
+// localize applies the negotiated locale to the listings and returns it. Listings
+// without a translation keep the default copy, so Content-Language names the locale
+// actually served and is left out when the listings mix languages.
+func localize(w http.ResponseWriter, r *http.Request, svc listingservice.Service, listings []listingservice.Listing) (string, error) {
+	locale := i18n.Negotiate(r)
+	w.Header().Add("Vary", "Accept-Language")
+	if err := svc.Localize(r.Context(), listings, locale); err != nil {
+		return "", err
+	}
+	if served, ok := servedLocale(listings, locale); ok {
+		w.Header().Set("Content-Language", served)
+	}
+	return locale, nil
+}
+
+// servedLocale returns the locale shared by every listing, or the requested one
+// when there are none. It reports false when the listings mix locales.
+func servedLocale(listings []listingservice.Listing, requested string) (string, bool) {
+	if len(listings) == 0 {
+		return requested, true
+	}
+	served := listings[0].Locale
+	for _, l := range listings[1:] {
+		if l.Locale != served {
+			return "", false
+		}
+	}
+	return served, served != ""
+}
+
+// mountTranslations registers the localized copy endpoints under /{id}/translations.
+func mountTranslations(r chi.Router, logger zerolog.Logger, svc listingservice.Service, agencies agencyservice.Service) {
+	r.Get("/{id}/translations", func(w http.ResponseWriter, r *http.Request) {
+		listing, ok := visibleListing(w, r, logger, svc, agencies)
+		if !ok {
+			return
+		}
+		translations, err := svc.Translations(r.Context(), listing.ID)
+		if err != nil {
+			logger.Error().Err(err).Str("id", listing.ID.String()).Msg("list_translations_failed")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"data":           translations,
+			"default_locale": i18n.DefaultLocale,
+			"locales":        i18n.SupportedLocales,
+		})
+	})
+
+	r.Put("/{id}/translations/{locale}", func(w http.ResponseWriter, r *http.Request) {
+		listing, _, ok := managedListing(w, r, logger, svc, agencies)
+		if !ok {
+			return
+		}
+		var payload translationRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		defer r.Body.Close()
+
+		translation, err := svc.SetTranslation(r.Context(), listing.ID, chi.URLParam(r, "locale"), listingservice.TranslationInput{
+			Title:   payload.Title,
+			Summary: payload.Summary,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, listingservice.ErrNotFound):
+				respondError(w, http.StatusNotFound, "not_found")
+			case errors.Is(err, listingservice.ErrUnsupportedLocale):
+				respondError(w, http.StatusBadRequest, "unsupported_locale")
+			default:
+				logger.Warn().Err(err).Str("id", listing.ID.String()).Msg("set_translation")
+				respondError(w, http.StatusBadRequest, err.Error())
+			}
+			return
+		}
+		respondJSON(w, http.StatusOK, translation)
+	})
+
+	r.Delete("/{id}/translations/{locale}", func(w http.ResponseWriter, r *http.Request) {
+		listing, _, ok := managedListing(w, r, logger, svc, agencies)
+		if !ok {
+			return
+		}
+		if err := svc.DeleteTranslation(r.Context(), listing.ID, chi.URLParam(r, "locale")); err != nil {
+			if errors.Is(err, listingservice.ErrNotFound) {
+				respondError(w, http.StatusNotFound, "not_found")
+				return
+			}
+			logger.Error().Err(err).Str("id", listing.ID.String()).Msg("delete_translation")
+			respondError(w, http.StatusInternalServerError, "delete_failed")
+			return
+		}
+		respondJSON(w, http.StatusNoContent, nil)
+	})
+}
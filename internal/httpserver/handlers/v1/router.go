@@ -2,27 +2,28 @@ package v1
 
 import (
 	"github.com/go-chi/chi/v5"
-	"github.com/rs/zerolog"
 
-	"shanraq.com/internal/config"
+	"shanraq.com/internal/httpserver/deps"
+	"shanraq.com/internal/httpserver/handlers/v1/admin"
 	"shanraq.com/internal/httpserver/handlers/v1/agencies"
+	financehandler "shanraq.com/internal/httpserver/handlers/v1/finance"
+	"shanraq.com/internal/httpserver/handlers/v1/fxrates"
 	"shanraq.com/internal/httpserver/handlers/v1/listings"
 	"shanraq.com/internal/httpserver/handlers/v1/transport"
 	"shanraq.com/internal/httpserver/handlers/v1/workspaces"
-	agencyservice "shanraq.com/internal/services/agency"
-	listingservice "shanraq.com/internal/services/listing"
-	transportservice "shanraq.com/internal/services/transport"
-	workspaceservice "shanraq.com/internal/services/workspace"
 )
 
 // Router wires REST API routes under /api/v1.
-func Router(cfg config.Config, logger zerolog.Logger, transportSvc transportservice.Service, agencySvc agencyservice.Service, listingSvc listingservice.Service, workspaceSvc workspaceservice.Service) chi.Router {
+func Router(d deps.Deps) chi.Router {
 	r := chi.NewRouter()
 
-	r.Mount("/transport-companies", transport.Router(cfg, logger, transportSvc))
-	r.Mount("/agencies", agencies.Router(cfg, logger, agencySvc))
-	r.Mount("/listings", listings.Router(cfg, logger, listingSvc))
-	r.Mount("/workspaces", workspaces.Router(cfg, logger, workspaceSvc))
+	r.Mount("/transport-companies", transport.Router(d.Config, d.Logger, d.TransportService))
+	r.Mount("/agencies", agencies.Router(d))
+	r.Mount("/listings", listings.Router(d))
+	r.Mount("/workspaces", workspaces.Router(d))
+	r.Mount("/fx-rates", fxrates.Router(d.Config, d.Logger, d.FXService))
+	r.Mount("/finance", financehandler.Router(d.Config, d.Logger, d.MortgageDefaults))
+	r.Mount("/admin", admin.Router(d))
 
 	return r
 }
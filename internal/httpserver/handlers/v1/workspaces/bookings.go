@@ -0,0 +1,41 @@
+package workspaces
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/auth/session"
+	rentalservice "shanraq.com/internal/services/rental"
+)
+
+// mountBookings registers the rental booking requests of the current user under
+// /me/bookings.
+func mountBookings(r chi.Router, logger zerolog.Logger, rentals rentalservice.Service) {
+	r.Get("/me/bookings", func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := session.IdentityFromContext(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+		filter := rentalservice.BookingFilter{RequesterID: identity.Key()}
+		if raw := r.URL.Query().Get("status"); raw != "" {
+			filter.Status = rentalservice.BookingStatus(raw)
+			if !filter.Status.Valid() {
+				respondError(w, http.StatusBadRequest, "unknown_status")
+				return
+			}
+		}
+		items, err := rentals.Bookings(r.Context(), filter)
+		if err != nil {
+			logger.Error().Err(err).Str("user", identity.Subject).Msg("list_bookings")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"data": items,
+			"meta": map[string]any{"count": len(items)},
+		})
+	})
+}
@@ -0,0 +1,61 @@
+package workspaces
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/auth"
+	"shanraq.com/internal/auth/session"
+	agencyservice "shanraq.com/internal/services/agency"
+	favoriteservice "shanraq.com/internal/services/favorite"
+	workspaceservice "shanraq.com/internal/services/workspace"
+)
+
+// mountFavorites registers the watchlist endpoint under /me/favorites.
+func mountFavorites(r chi.Router, logger zerolog.Logger, favorites favoriteservice.Service) {
+	r.Get("/me/favorites", func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := session.IdentityFromContext(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+		items, err := favorites.List(r.Context(), identity)
+		if err != nil {
+			logger.Error().Err(err).Str("user", identity.Subject).Msg("list_favorites")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"data": items,
+			"meta": map[string]any{"count": len(items)},
+		})
+	})
+}
+
+// applyWatchMetrics fills the workspace metrics with live counts: the listings the
+// user saved, and the watchers across listings of the agencies they work for.
+func applyWatchMetrics(r *http.Request, favorites favoriteservice.Service, agencies agencyservice.Service, identity auth.Identity, workspace *workspaceservice.Workspace) error {
+	if favorites == nil {
+		return nil
+	}
+	saved, err := favorites.List(r.Context(), identity)
+	if err != nil {
+		return err
+	}
+	workspace.Metrics.Favorites = len(saved)
+	if agencies == nil || identity.Email == "" {
+		return nil
+	}
+	agencyIDs, err := agencies.MemberAgencyIDs(r.Context(), identity.Email)
+	if err != nil {
+		return err
+	}
+	stats, err := favorites.AgencyStats(r.Context(), agencyIDs, 0)
+	if err != nil {
+		return err
+	}
+	workspace.Metrics.Watchers = stats.Watchers
+	return nil
+}
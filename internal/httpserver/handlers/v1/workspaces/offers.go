@@ -0,0 +1,41 @@
+package workspaces
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/auth/session"
+	offerservice "shanraq.com/internal/services/offer"
+)
+
+// mountOffers registers the offers the current user made on sale listings under
+// /me/offers.
+func mountOffers(r chi.Router, logger zerolog.Logger, offers offerservice.Service) {
+	r.Get("/me/offers", func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := session.IdentityFromContext(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+		filter := offerservice.Filter{BuyerID: identity.Key()}
+		if raw := r.URL.Query().Get("status"); raw != "" {
+			filter.Status = offerservice.Status(raw)
+			if !filter.Status.Valid() {
+				respondError(w, http.StatusBadRequest, "unknown_status")
+				return
+			}
+		}
+		items, err := offers.List(r.Context(), filter)
+		if err != nil {
+			logger.Error().Err(err).Str("user", identity.Subject).Msg("list_offers")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"data": items,
+			"meta": map[string]any{"count": len(items)},
+		})
+	})
+}
@@ -5,16 +5,24 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/rs/zerolog"
 
 	"shanraq.com/internal/auth/session"
-	"shanraq.com/internal/config"
+	"shanraq.com/internal/httpserver/deps"
 	workspaceservice "shanraq.com/internal/services/workspace"
 )
 
 // Router exposes workspace APIs for authenticated users.
-func Router(cfg config.Config, logger zerolog.Logger, svc workspaceservice.Service) chi.Router {
-	_ = cfg
+func Router(d deps.Deps) chi.Router {
+	var (
+		logger    = d.Logger
+		svc       = d.WorkspaceService
+		searches  = d.SavedSearches
+		favorites = d.Favorites
+		agencies  = d.AgencyService
+		rentals   = d.Rentals
+		viewings  = d.Viewings
+		offers    = d.Offers
+	)
 	r := chi.NewRouter()
 
 	r.Get("/me", func(w http.ResponseWriter, r *http.Request) {
@@ -29,6 +37,9 @@ func Router(cfg config.Config, logger zerolog.Logger, svc workspaceservice.Servi
 			respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "workspace_error"})
 			return
 		}
+		if err := applyWatchMetrics(r, favorites, agencies, identity, &workspace); err != nil {
+			logger.Warn().Err(err).Str("user", identity.Subject).Msg("workspace_watch_metrics")
+		}
 		respondJSON(w, http.StatusOK, workspace)
 	})
 
@@ -49,14 +60,30 @@ func Router(cfg config.Config, logger zerolog.Logger, svc workspaceservice.Servi
 			respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "workspace_error"})
 			return
 		}
+		if err := applyWatchMetrics(r, favorites, agencies, identity, &workspace); err != nil {
+			logger.Warn().Err(err).Str("user", identity.Subject).Msg("workspace_watch_metrics")
+		}
 		respondJSON(w, http.StatusCreated, workspace)
 	})
 
+	mountSearches(r, logger, searches)
+	mountFavorites(r, logger, favorites)
+	mountBookings(r, logger, rentals)
+	mountViewings(r, logger, viewings)
+	mountOffers(r, logger, offers)
+
 	return r
 }
 
 func respondJSON(w http.ResponseWriter, status int, payload any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
+	if payload == nil {
+		return
+	}
 	_ = json.NewEncoder(w).Encode(payload)
 }
+
+func respondError(w http.ResponseWriter, status int, code string) {
+	respondJSON(w, status, map[string]string{"error": code})
+}
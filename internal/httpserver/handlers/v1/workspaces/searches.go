@@ -0,0 +1,145 @@
+package workspaces
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/auth/session"
+	"shanraq.com/internal/format"
+	savedsearchservice "shanraq.com/internal/services/savedsearch"
+)
+
+type searchRequest struct {
+	Name   string `json:"name"`
+	Query  string `json:"query"`
+	Alerts *bool  `json:"alerts"`
+}
+
+// input carries the request locale and region so alerts are formatted for the
+// reader who saved the search.
+func (p searchRequest) input(r *http.Request) savedsearchservice.Input {
+	f := format.FromRequest(r)
+	return savedsearchservice.Input{Name: p.Name, Query: p.Query, Alerts: p.Alerts, Locale: f.Locale, Region: f.Region}
+}
+
+// mountSearches registers the saved-search endpoints under /me/searches.
+func mountSearches(r chi.Router, logger zerolog.Logger, svc savedsearchservice.Service) {
+	r.Get("/me/searches", func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := session.IdentityFromContext(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+		searches, err := svc.List(r.Context(), identity)
+		if err != nil {
+			logger.Error().Err(err).Str("user", identity.Subject).Msg("list_saved_searches")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{"data": searches})
+	})
+
+	r.Post("/me/searches", func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := session.IdentityFromContext(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+		var payload searchRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		defer r.Body.Close()
+
+		search, err := svc.Create(r.Context(), identity, payload.input(r))
+		if err != nil {
+			logger.Warn().Err(err).Str("user", identity.Subject).Msg("create_saved_search")
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondJSON(w, http.StatusCreated, search)
+	})
+
+	r.Get("/me/searches/{id}", func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := session.IdentityFromContext(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_id")
+			return
+		}
+		search, err := svc.Get(r.Context(), identity, id)
+		if err != nil {
+			respondSearchError(w, logger, err, "get_failed")
+			return
+		}
+		respondJSON(w, http.StatusOK, search)
+	})
+
+	r.Put("/me/searches/{id}", func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := session.IdentityFromContext(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_id")
+			return
+		}
+		var payload searchRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_payload")
+			return
+		}
+		defer r.Body.Close()
+
+		search, err := svc.Update(r.Context(), identity, id, payload.input(r))
+		if err != nil {
+			if errors.Is(err, savedsearchservice.ErrNotFound) {
+				respondError(w, http.StatusNotFound, "not_found")
+				return
+			}
+			logger.Warn().Err(err).Str("id", id.String()).Msg("update_saved_search")
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondJSON(w, http.StatusOK, search)
+	})
+
+	r.Delete("/me/searches/{id}", func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := session.IdentityFromContext(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_id")
+			return
+		}
+		if err := svc.Delete(r.Context(), identity, id); err != nil {
+			respondSearchError(w, logger, err, "delete_failed")
+			return
+		}
+		respondJSON(w, http.StatusNoContent, nil)
+	})
+}
+
+func respondSearchError(w http.ResponseWriter, logger zerolog.Logger, err error, code string) {
+	if errors.Is(err, savedsearchservice.ErrNotFound) {
+		respondError(w, http.StatusNotFound, "not_found")
+		return
+	}
+	logger.Error().Err(err).Msg("saved_search_failed")
+	respondError(w, http.StatusInternalServerError, code)
+}
@@ -0,0 +1,52 @@
+package workspaces
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+
+	"shanraq.com/internal/auth/session"
+	viewingservice "shanraq.com/internal/services/viewing"
+)
+
+// mountViewings registers the viewing appointments the current user booked under
+// /me/viewings. Times are shown in the zone given at booking unless ?tz= overrides it.
+func mountViewings(r chi.Router, logger zerolog.Logger, viewings viewingservice.Service) {
+	r.Get("/me/viewings", func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := session.IdentityFromContext(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+		query := r.URL.Query()
+		zone := query.Get("tz")
+		if _, err := viewingservice.LoadLocation(zone); err != nil {
+			respondError(w, http.StatusBadRequest, "unknown_time_zone")
+			return
+		}
+		filter := viewingservice.AppointmentFilter{RequesterID: identity.Key()}
+		if query.Get("past") != "true" {
+			filter.From = time.Now().UTC()
+		}
+		items, err := viewings.Appointments(r.Context(), filter)
+		if err != nil {
+			logger.Error().Err(err).Str("user", identity.Subject).Msg("list_viewings")
+			respondError(w, http.StatusInternalServerError, "list_failed")
+			return
+		}
+		views := make([]viewingservice.LocalAppointment, 0, len(items))
+		for _, item := range items {
+			display := zone
+			if display == "" {
+				display = item.TimeZone
+			}
+			views = append(views, item.Local(display))
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"data": views,
+			"meta": map[string]any{"count": len(views)},
+		})
+	})
+}
@@ -0,0 +1,133 @@
+package middlewares
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// maxTrackedKeys forces an early sweep when a flood of distinct keys arrives
+// within one window.
+const maxTrackedKeys = 50000
+
+// Throttle is a sliding-window limiter allowing at most limit hits per key within
+// window. It lives in process memory, so each instance counts on its own.
+type Throttle struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	hits      map[string][]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewThrottle returns a limiter; a non-positive limit disables it.
+func NewThrottle(limit int, window time.Duration) *Throttle {
+	return &Throttle{
+		limit:  limit,
+		window: window,
+		hits:   make(map[string][]time.Time),
+		now:    time.Now,
+	}
+}
+
+// Allow records a hit for key and reports whether it is within the limit. When it
+// is not, the returned duration says when the oldest hit leaves the window.
+func (t *Throttle) Allow(key string) (bool, time.Duration) {
+	if t == nil || t.limit <= 0 || key == "" {
+		return true, 0
+	}
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.sweep(now)
+	recent := t.recent(key, now)
+	if len(recent) >= t.limit {
+		t.hits[key] = recent
+		return false, recent[0].Add(t.window).Sub(now)
+	}
+	t.hits[key] = append(recent, now)
+	return true, 0
+}
+
+func (t *Throttle) recent(key string, now time.Time) []time.Time {
+	hits := t.hits[key]
+	cutoff := now.Add(-t.window)
+	i := 0
+	for i < len(hits) && !hits[i].After(cutoff) {
+		i++
+	}
+	return hits[i:]
+}
+
+// sweep drops idle keys once per window, or sooner when too many keys are
+// tracked, so the map does not grow without bound.
+func (t *Throttle) sweep(now time.Time) {
+	if now.Sub(t.lastSweep) < t.window && len(t.hits) < maxTrackedKeys {
+		return
+	}
+	t.lastSweep = now
+	for key := range t.hits {
+		if len(t.recent(key, now)) == 0 {
+			delete(t.hits, key)
+		}
+	}
+}
+
+type peerContextKey struct{}
+
+// SocketPeer records the connection's remote address before RealIP rewrites it
+// from forwarding headers, so ClientIP can tell whether those headers are trusted.
+func SocketPeer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), peerContextKey{}, r.RemoteAddr)))
+	})
+}
+
+// ClientIP returns the request's client address without the port. The address
+// RealIP took from X-Forwarded-For or X-Real-IP is only used when the socket peer
+// is one of the trusted proxies; otherwise the peer itself is the client, so a
+// caller cannot pick its own throttle key by sending a header.
+func ClientIP(r *http.Request, trusted []netip.Prefix) string {
+	peer, ok := r.Context().Value(peerContextKey{}).(string)
+	if !ok {
+		return hostOnly(r.RemoteAddr)
+	}
+	host := hostOnly(peer)
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addr = addr.Unmap()
+		for _, proxy := range trusted {
+			if proxy.Contains(addr) {
+				return hostOnly(r.RemoteAddr)
+			}
+		}
+	}
+	return host
+}
+
+// IPKey returns the throttle key for a client address. IPv6 clients usually hold
+// a whole /64, so they are counted per prefix rather than per address.
+func IPKey(ip string) string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return "ip:" + ip
+	}
+	addr = addr.Unmap()
+	if addr.Is6() {
+		prefix, _ := addr.Prefix(64)
+		return "ip:" + prefix.String()
+	}
+	return "ip:" + addr.String()
+}
+
+func hostOnly(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
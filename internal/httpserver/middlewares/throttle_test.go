@@ -0,0 +1,85 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+func TestThrottle(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	throttle := NewThrottle(2, time.Hour)
+	throttle.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := throttle.Allow("ip:203.0.113.7"); !ok {
+			t.Fatalf("Allow() hit %d = false, want true", i+1)
+		}
+	}
+	ok, retry := throttle.Allow("ip:203.0.113.7")
+	if ok || retry != time.Hour {
+		t.Fatalf("Allow() over limit = %v, %v; want false, 1h", ok, retry)
+	}
+	if ok, _ := throttle.Allow("ip:198.51.100.1"); !ok {
+		t.Error("Allow() for another key = false, want true")
+	}
+
+	now = now.Add(time.Hour + time.Second)
+	if ok, _ := throttle.Allow("ip:203.0.113.7"); !ok {
+		t.Error("Allow() after the window = false, want true")
+	}
+	if _, tracked := throttle.hits["ip:198.51.100.1"]; tracked {
+		t.Error("idle key was not swept")
+	}
+
+	if ok, _ := NewThrottle(0, time.Hour).Allow("any"); !ok {
+		t.Error("disabled throttle rejected a hit")
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	r := httptest.NewRequest("POST", "/", nil)
+	r.RemoteAddr = "203.0.113.7:53124"
+	if got := ClientIP(r, nil); got != "203.0.113.7" {
+		t.Errorf("ClientIP() = %q, want 203.0.113.7", got)
+	}
+	r.RemoteAddr = "2001:db8::1"
+	if got := ClientIP(r, nil); got != "2001:db8::1" {
+		t.Errorf("ClientIP() = %q, want bare address", got)
+	}
+}
+
+func TestClientIPTrustedProxies(t *testing.T) {
+	proxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+	resolve := func(peer, forwarded string) string {
+		var got string
+		handler := SocketPeer(chimiddleware.RealIP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = ClientIP(r, proxies)
+		})))
+		r := httptest.NewRequest("POST", "/", nil)
+		r.RemoteAddr = peer
+		r.Header.Set("X-Forwarded-For", forwarded)
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+		return got
+	}
+
+	if got := resolve("10.1.2.3:443", "198.51.100.9"); got != "198.51.100.9" {
+		t.Errorf("ClientIP() behind a trusted proxy = %q, want the forwarded address", got)
+	}
+	if got := resolve("203.0.113.7:53124", "198.51.100.9"); got != "203.0.113.7" {
+		t.Errorf("ClientIP() with a spoofed header = %q, want the socket peer", got)
+	}
+}
+
+func TestIPKey(t *testing.T) {
+	if got := IPKey("203.0.113.7"); got != "ip:203.0.113.7" {
+		t.Errorf("IPKey(v4) = %q", got)
+	}
+	if a, b := IPKey("2001:db8:1:2::1"), IPKey("2001:db8:1:2:ffff::9"); a != b || a != "ip:2001:db8:1:2::/64" {
+		t.Errorf("IPKey(v6) = %q and %q, want one /64 key", a, b)
+	}
+}
@@ -17,6 +17,7 @@ import (
 func NewRouter(deps Deps) http.Handler {
 	r := chi.NewRouter()
 
+	r.Use(middlewares.SocketPeer)
 	r.Use(chimiddleware.RealIP)
 	r.Use(chimiddleware.RequestID)
 	r.Use(middlewares.RequestLogger(deps.Logger))
@@ -33,7 +34,7 @@ func NewRouter(deps Deps) http.Handler {
 		MaxAge:           300,
 	}))
 
-	handlers.RegisterRoutes(r, deps.Config, deps.Logger, deps.Renderer, deps.TransportService, deps.AgencyService, deps.ListingService, deps.AuthRegistry, deps.SessionManager, deps.WorkspaceService)
+	handlers.RegisterRoutes(r, deps)
 
 	return r
 }
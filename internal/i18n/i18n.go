@@ -0,0 +1,87 @@
+// Package i18n negotiates the content locale for a request.
+package i18n
+
+import (
+	"net/http"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale is the language listings are authored in.
+const DefaultLocale = "en"
+
+// SupportedLocales lists the locales content can be served in, default first.
+var SupportedLocales = []string{DefaultLocale, "ar", "sv", "ja", "pt"}
+
+var (
+	supportedTags = func() []language.Tag {
+		tags := make([]language.Tag, 0, len(SupportedLocales))
+		for _, locale := range SupportedLocales {
+			tags = append(tags, language.MustParse(locale))
+		}
+		return tags
+	}()
+	matcher = language.NewMatcher(supportedTags)
+)
+
+// Supported reports whether the locale is one content can be served in.
+func Supported(locale string) bool {
+	for _, candidate := range SupportedLocales {
+		if candidate == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalize reduces a language tag such as "sv-SE" to a supported locale, or returns
+// an empty string when there is no reasonable match.
+func Normalize(tag string) string {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return ""
+	}
+	parsed, err := language.Parse(tag)
+	if err != nil {
+		return ""
+	}
+	_, idx, confidence := matcher.Match(parsed)
+	if confidence == language.No {
+		return ""
+	}
+	return SupportedLocales[idx]
+}
+
+// Negotiate picks the locale for a request: an explicit ?lang= wins, then the
+// Accept-Language header, then DefaultLocale.
+func Negotiate(r *http.Request) string {
+	if locale := Normalize(r.URL.Query().Get("lang")); locale != "" {
+		return locale
+	}
+	return FromAcceptLanguage(r.Header.Get("Accept-Language"))
+}
+
+// FromAcceptLanguage matches an Accept-Language header against SupportedLocales.
+func FromAcceptLanguage(header string) string {
+	if strings.TrimSpace(header) == "" {
+		return DefaultLocale
+	}
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(tags) == 0 {
+		return DefaultLocale
+	}
+	_, idx, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return DefaultLocale
+	}
+	return SupportedLocales[idx]
+}
+
+// Direction returns the text direction of the locale for the HTML dir attribute.
+func Direction(locale string) string {
+	if locale == "ar" {
+		return "rtl"
+	}
+	return "ltr"
+}
@@ -0,0 +1,37 @@
+package i18n
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFromAcceptLanguage(t *testing.T) {
+	cases := map[string]string{
+		"":                           DefaultLocale,
+		"sv-SE,sv;q=0.9,en;q=0.8":    "sv",
+		"de-DE,de;q=0.9":             DefaultLocale,
+		"de-DE,pt-BR;q=0.8,en;q=0.5": "pt",
+		"ar-AE":                      "ar",
+		"ja;q=0.4, en-GB;q=0.9":      "en",
+		"not a header;;q=abc":        DefaultLocale,
+	}
+	for header, want := range cases {
+		if got := FromAcceptLanguage(header); got != want {
+			t.Errorf("FromAcceptLanguage(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestNegotiatePrefersQueryParameter(t *testing.T) {
+	r := httptest.NewRequest("GET", "/api/v1/listings?lang=ja-JP", nil)
+	r.Header.Set("Accept-Language", "sv")
+	if got := Negotiate(r); got != "ja" {
+		t.Errorf("Negotiate() = %q, want ja", got)
+	}
+
+	r = httptest.NewRequest("GET", "/api/v1/listings?lang=xx", nil)
+	r.Header.Set("Accept-Language", "sv")
+	if got := Negotiate(r); got != "sv" {
+		t.Errorf("Negotiate() with unsupported lang = %q, want sv", got)
+	}
+}
@@ -0,0 +1,130 @@
+// Package ical renders iCalendar (RFC 5545) feeds that calendar apps can subscribe
+// to.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type calendars are served with.
+const ContentType = "text/calendar; charset=utf-8"
+
+const stampLayout = "20060102T150405Z"
+
+// maxLineOctets is the longest content line RFC 5545 allows before folding.
+const maxLineOctets = 75
+
+// Calendar is a published feed of events.
+type Calendar struct {
+	ProductID string
+	Name      string
+	TimeZone  string
+	Events    []Event
+}
+
+// Event is one VEVENT. UID must stay stable across updates so subscribers replace
+// the event instead of duplicating it; Sequence grows with every change.
+type Event struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Organizer   string
+	Attendee    string
+	Cancelled   bool
+	Sequence    int
+	Created     time.Time
+	Updated     time.Time
+}
+
+// Encode writes the calendar with CRLF line endings and folded lines. Times are
+// written in UTC, so subscribers show them in their own zone.
+func Encode(w io.Writer, cal Calendar, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", text(cal.ProductID))
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		line("X-WR-CALNAME", text(cal.Name))
+	}
+	if cal.TimeZone != "" {
+		line("X-WR-TIMEZONE", text(cal.TimeZone))
+	}
+	for _, event := range cal.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", text(event.UID))
+		line("DTSTAMP", stamp(now))
+		line("DTSTART", stamp(event.Start))
+		line("DTEND", stamp(event.End))
+		line("SUMMARY", text(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION", text(event.Description))
+		}
+		if event.Location != "" {
+			line("LOCATION", text(event.Location))
+		}
+		if event.URL != "" {
+			line("URL", event.URL)
+		}
+		if event.Organizer != "" {
+			line("ORGANIZER", "mailto:"+event.Organizer)
+		}
+		if event.Attendee != "" {
+			line("ATTENDEE;ROLE=REQ-PARTICIPANT", "mailto:"+event.Attendee)
+		}
+		status := "CONFIRMED"
+		if event.Cancelled {
+			status = "CANCELLED"
+		}
+		line("STATUS", status)
+		line("SEQUENCE", strconv.Itoa(event.Sequence))
+		if !event.Created.IsZero() {
+			line("CREATED", stamp(event.Created))
+		}
+		if !event.Updated.IsZero() {
+			line("LAST-MODIFIED", stamp(event.Updated))
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+func stamp(t time.Time) string {
+	return t.UTC().Format(stampLayout)
+}
+
+// text escapes a TEXT value.
+func text(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(value)
+}
+
+// writeFolded splits content lines longer than 75 octets without breaking UTF-8
+// sequences; continuation lines start with a space.
+func writeFolded(w *bufio.Writer, content string) {
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		_, _ = w.WriteString(content[:cut] + "\r\n ")
+		content = content[cut:]
+		limit = maxLineOctets - 1
+	}
+	_, _ = w.WriteString(content + "\r\n")
+}
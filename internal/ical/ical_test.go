@@ -0,0 +1,58 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncode(t *testing.T) {
+	start := time.Date(2026, 10, 20, 10, 0, 0, 0, time.FixedZone("ALMT", 5*3600))
+	cal := Calendar{
+		ProductID: "-//Shanraq//Viewings//EN",
+		Name:      "Viewings, Layla",
+		Events: []Event{{
+			UID:         "abc@shanraq.com",
+			Start:       start,
+			End:         start.Add(30 * time.Minute),
+			Summary:     "Viewing; Palm Jumeirah Sky Villa",
+			Description: strings.Repeat("Ключ у консьержа. ", 8),
+			Cancelled:   true,
+			Sequence:    2,
+		}},
+	}
+	var out strings.Builder
+	if err := Encode(&out, cal, start); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	body := out.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Viewings\\, Layla\r\n",
+		"DTSTART:20261020T050000Z\r\n",
+		"DTEND:20261020T053000Z\r\n",
+		"SUMMARY:Viewing\\; Palm Jumeirah Sky Villa\r\n",
+		"STATUS:CANCELLED\r\n",
+		"SEQUENCE:2\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Encode() missing %q in\n%s", want, body)
+		}
+	}
+
+	var unfolded strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(body, "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("line of %d octets exceeds the limit: %q", len(line), line)
+		}
+		if strings.HasPrefix(line, " ") {
+			unfolded.WriteString(line[1:])
+			continue
+		}
+		unfolded.WriteString("\n" + line)
+	}
+	if !strings.Contains(unfolded.String(), "DESCRIPTION:"+strings.Repeat("Ключ у консьержа. ", 8)) {
+		t.Error("folded DESCRIPTION does not unfold to the original text")
+	}
+}
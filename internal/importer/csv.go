@@ -0,0 +1,218 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"shanraq.com/internal/format"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+// csvColumns maps accepted header names to their canonical field.
+var csvColumns = map[string]string{
+	"reference":     "ref",
+	"ref":           "ref",
+	"external_ref":  "ref",
+	"listing_ref":   "ref",
+	"title":         "title",
+	"type":          "type",
+	"listing_type":  "type",
+	"property_type": "type",
+	"country":       "country",
+	"country_code":  "country",
+	"city":          "city",
+	"region":        "region",
+	"state":         "region",
+	"neighborhood":  "neighborhood",
+	"neighbourhood": "neighborhood",
+	"district":      "neighborhood",
+	"summary":       "summary",
+	"description":   "summary",
+	"price":         "price",
+	"currency":      "currency",
+	"bedrooms":      "bedrooms",
+	"bathrooms":     "bathrooms",
+	"area_sqm":      "area_sqm",
+	"area":          "area_sqm",
+	"area_sqft":     "area_sqft",
+	"image_url":     "image_url",
+	"hero_image":    "image_url",
+	"tags":          "tags",
+	"latitude":      "latitude",
+	"lat":           "latitude",
+	"longitude":     "longitude",
+	"lng":           "longitude",
+	"lon":           "longitude",
+}
+
+// ParseCSV reads a header row followed by one listing per line. Headers are matched
+// case-insensitively against property_listings column names and common aliases;
+// tags are separated by "|" or ";". Lines that fail to parse are reported as failed
+// rows.
+func ParseCSV(r io.Reader) ([]Row, []RowResult, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errors.New("csv feed is empty")
+		}
+		return nil, nil, fmt.Errorf("read csv header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for idx, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := csvColumns[name]; ok {
+			if _, dup := columns[field]; !dup {
+				columns[field] = idx
+			}
+		}
+	}
+	if _, ok := columns["ref"]; !ok {
+		return nil, nil, errors.New("csv header must include a reference column")
+	}
+
+	var (
+		rows     []Row
+		rejected []RowResult
+	)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rejected = append(rejected, RowResult{Line: parseErr.StartLine, Action: ActionFailed, Error: parseErr.Err.Error()})
+				continue
+			}
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		get := func(field string) string {
+			if idx, ok := columns[field]; ok && idx < len(record) {
+				return strings.TrimSpace(record[idx])
+			}
+			return ""
+		}
+		if isBlank(record) {
+			continue
+		}
+		row, err := csvRow(line, get)
+		if err != nil {
+			rejected = append(rejected, RowResult{Line: line, Ref: get("ref"), Action: ActionFailed, Error: err.Error()})
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, rejected, nil
+}
+
+func csvRow(line int, get func(string) string) (Row, error) {
+	row := Row{Line: line, Ref: get("ref")}
+	if row.Ref == "" {
+		return row, errors.New("reference is required")
+	}
+	in := listingservice.CreateInput{
+		Title:        get("title"),
+		Type:         listingservice.ListingType(strings.ToLower(get("type"))),
+		Country:      get("country"),
+		City:         get("city"),
+		Region:       get("region"),
+		Neighborhood: get("neighborhood"),
+		Summary:      get("summary"),
+		Currency:     get("currency"),
+		ImageURL:     get("image_url"),
+		Tags:         splitTags(get("tags")),
+	}
+	var err error
+	if in.Price, err = parseFloat("price", get("price")); err != nil {
+		return row, err
+	}
+	if in.Bathrooms, err = parseFloat("bathrooms", get("bathrooms")); err != nil {
+		return row, err
+	}
+	bedrooms, err := parseFloat("bedrooms", get("bedrooms"))
+	if err != nil {
+		return row, err
+	}
+	if bedrooms != float64(int(bedrooms)) {
+		return row, fmt.Errorf("bedrooms must be a whole number, got %q", get("bedrooms"))
+	}
+	in.Bedrooms = int(bedrooms)
+	if in.AreaSqM, err = parseFloat("area_sqm", get("area_sqm")); err != nil {
+		return row, err
+	}
+	if in.AreaSqM == 0 {
+		sqft, err := parseFloat("area_sqft", get("area_sqft"))
+		if err != nil {
+			return row, err
+		}
+		in.AreaSqM = sqftToSqm(sqft)
+	}
+	if in.Location, err = parseLocation(get("latitude"), get("longitude")); err != nil {
+		return row, err
+	}
+	row.Input = in
+	return row, nil
+}
+
+func parseFloat(field, raw string) (float64, error) {
+	raw = strings.ReplaceAll(strings.TrimSpace(raw), ",", "")
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number, got %q", field, raw)
+	}
+	return value, nil
+}
+
+func parseLocation(lat, lng string) (*listingservice.GeoPoint, error) {
+	if lat == "" && lng == "" {
+		return nil, nil
+	}
+	if lat == "" || lng == "" {
+		return nil, errors.New("latitude and longitude must be given together")
+	}
+	la, err := parseFloat("latitude", lat)
+	if err != nil {
+		return nil, err
+	}
+	lo, err := parseFloat("longitude", lng)
+	if err != nil {
+		return nil, err
+	}
+	return &listingservice.GeoPoint{Lat: la, Lng: lo}, nil
+}
+
+func splitTags(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	return strings.FieldsFunc(raw, func(r rune) bool { return r == '|' || r == ';' })
+}
+
+func sqftToSqm(sqft float64) float64 {
+	if sqft == 0 {
+		return 0
+	}
+	return float64(int(sqft/format.SqFtPerSqM*100+0.5)) / 100
+}
+
+func isBlank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,293 @@
+// Package importer bulk-loads agency listings from CSV files and RESO Web API
+// shaped JSON feeds, upserting them by the agency's own reference ID.
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+// Format names a supported feed layout.
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatRESO Format = "reso"
+)
+
+// DefaultChunkSize is used when no positive chunk size is configured.
+const DefaultChunkSize = 500
+
+// Row is one parsed feed entry. Line is the 1-based CSV line or JSON array
+// position it came from.
+type Row struct {
+	Line  int
+	Ref   string
+	Input listingservice.CreateInput
+}
+
+// Action describes what happened to a row.
+type Action string
+
+const (
+	ActionCreated Action = "created"
+	ActionUpdated Action = "updated"
+	ActionFailed  Action = "failed"
+)
+
+// RowResult reports the outcome of a single row.
+type RowResult struct {
+	Line      int        `json:"line"`
+	Ref       string     `json:"ref,omitempty"`
+	Action    Action     `json:"action"`
+	ListingID *uuid.UUID `json:"listing_id,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// Report summarises an import run.
+type Report struct {
+	AgencyID uuid.UUID   `json:"agency_id"`
+	Format   Format      `json:"format"`
+	Total    int         `json:"total"`
+	Created  int         `json:"created"`
+	Updated  int         `json:"updated"`
+	Failed   int         `json:"failed"`
+	Rows     []RowResult `json:"rows"`
+}
+
+// Errors returns only the failed rows.
+func (r Report) Errors() []RowResult {
+	out := make([]RowResult, 0, r.Failed)
+	for _, row := range r.Rows {
+		if row.Action == ActionFailed {
+			out = append(out, row)
+		}
+	}
+	return out
+}
+
+func (r *Report) add(result RowResult) {
+	switch result.Action {
+	case ActionCreated:
+		r.Created++
+	case ActionUpdated:
+		r.Updated++
+	case ActionFailed:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, result)
+}
+
+// Options tune an import run.
+type Options struct {
+	// Publish moves newly created listings through review to published instead
+	// of leaving them as drafts.
+	Publish bool
+	// ActorEmail is recorded on the status history of published listings and is
+	// required with Publish.
+	ActorEmail string
+}
+
+// Importer upserts parsed rows into the listing service one row at a time, looking
+// up existing references a chunk at a time.
+type Importer struct {
+	listings  listingservice.Service
+	chunkSize int
+}
+
+// New creates an importer that resolves references chunkSize rows at a time.
+func New(listings listingservice.Service, chunkSize int) *Importer {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	return &Importer{listings: listings, chunkSize: chunkSize}
+}
+
+// ParseFormat validates a format name.
+func ParseFormat(value string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(value))) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatRESO, "json":
+		return FormatRESO, nil
+	default:
+		return "", fmt.Errorf("unsupported import format %q: expected csv or reso", value)
+	}
+}
+
+// DetectFormat guesses the format from a file name or content type.
+func DetectFormat(name, contentType string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".json":
+		return FormatRESO, nil
+	}
+	switch {
+	case strings.Contains(contentType, "csv"):
+		return FormatCSV, nil
+	case strings.Contains(contentType, "json"):
+		return FormatRESO, nil
+	}
+	return "", errors.New("cannot detect import format: name the file .csv or .json or pass a format")
+}
+
+// Parse reads a feed in the given format. Rows that cannot be parsed are returned as
+// failed results rather than aborting the whole feed.
+func Parse(r io.Reader, format Format) ([]Row, []RowResult, error) {
+	switch format {
+	case FormatCSV:
+		return ParseCSV(r)
+	case FormatRESO:
+		return ParseRESO(r)
+	default:
+		return nil, nil, fmt.Errorf("unsupported import format %q", format)
+	}
+}
+
+// FeedError reports a feed that could not be read at all, as opposed to individual
+// rows failing.
+type FeedError struct {
+	Err error
+}
+
+func (e *FeedError) Error() string { return e.Err.Error() }
+
+// Unwrap exposes the underlying parse error.
+func (e *FeedError) Unwrap() error { return e.Err }
+
+// Import parses the feed and upserts it for the agency. A feed that cannot be parsed
+// returns a *FeedError.
+func (im *Importer) Import(ctx context.Context, agencyID uuid.UUID, r io.Reader, format Format, opts Options) (Report, error) {
+	rows, rejected, err := Parse(r, format)
+	if err != nil {
+		return Report{}, &FeedError{Err: err}
+	}
+	report, err := im.Run(ctx, agencyID, rows, opts)
+	report.Format = format
+	for _, result := range rejected {
+		report.add(result)
+	}
+	report.Total += len(rejected)
+	sortResults(report.Rows)
+	return report, err
+}
+
+// Run upserts the rows for the agency. Each chunk resolves its references with a
+// single lookup; rows whose reference already exists are updated in place, the rest
+// are created. Every row is then written by its own Create or Update call, with no
+// transaction spanning the chunk, so a run that stops early keeps the rows written
+// before it; since rows are upserted by reference, running the feed again finishes
+// the job. Row failures are recorded in the report and do not stop the run.
+func (im *Importer) Run(ctx context.Context, agencyID uuid.UUID, rows []Row, opts Options) (Report, error) {
+	report := Report{AgencyID: agencyID, Total: len(rows), Rows: make([]RowResult, 0, len(rows))}
+	if agencyID == uuid.Nil {
+		return report, errors.New("agency is required")
+	}
+	if opts.Publish && strings.TrimSpace(opts.ActorEmail) == "" {
+		return report, errors.New("an actor email is required to publish imported listings")
+	}
+
+	seen := make(map[string]int, len(rows))
+	for start := 0; start < len(rows); start += im.chunkSize {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		end := start + im.chunkSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		chunk := rows[start:end]
+
+		refs := make([]string, 0, len(chunk))
+		for _, row := range chunk {
+			refs = append(refs, row.Ref)
+		}
+		existing, err := im.listings.ReferenceIDs(ctx, agencyID, refs)
+		if err != nil {
+			return report, fmt.Errorf("resolve references: %w", err)
+		}
+
+		for _, row := range chunk {
+			if first, dup := seen[row.Ref]; dup {
+				report.add(failed(row, fmt.Sprintf("duplicate reference, first seen on line %d", first)))
+				continue
+			}
+			seen[row.Ref] = row.Line
+			report.add(im.upsert(ctx, agencyID, row, existing, opts))
+		}
+	}
+	return report, nil
+}
+
+func (im *Importer) upsert(ctx context.Context, agencyID uuid.UUID, row Row, existing map[string]uuid.UUID, opts Options) RowResult {
+	input := row.Input
+	input.AgencyID = agencyID
+	input.ExternalRef = row.Ref
+
+	if id, ok := existing[row.Ref]; ok {
+		updated, err := im.listings.Update(ctx, id, updateInput(input))
+		if err != nil {
+			return failed(row, err.Error())
+		}
+		return RowResult{Line: row.Line, Ref: row.Ref, Action: ActionUpdated, ListingID: &updated.ID}
+	}
+
+	created, err := im.listings.Create(ctx, input)
+	if err != nil {
+		return failed(row, err.Error())
+	}
+	result := RowResult{Line: row.Line, Ref: row.Ref, Action: ActionCreated, ListingID: &created.ID}
+	if opts.Publish {
+		for _, status := range []listingservice.Status{listingservice.StatusReview, listingservice.StatusPublished} {
+			if _, err := im.listings.Transition(ctx, created.ID, listingservice.TransitionInput{
+				Status:     status,
+				ActorEmail: opts.ActorEmail,
+				Note:       "bulk import",
+			}); err != nil {
+				result.Error = "created as draft: " + err.Error()
+				break
+			}
+		}
+	}
+	return result
+}
+
+// updateInput overwrites every imported field of an existing listing.
+func updateInput(in listingservice.CreateInput) listingservice.UpdateInput {
+	tags := in.Tags
+	return listingservice.UpdateInput{
+		Title:        &in.Title,
+		Type:         &in.Type,
+		Country:      &in.Country,
+		City:         &in.City,
+		Region:       &in.Region,
+		Neighborhood: &in.Neighborhood,
+		Summary:      &in.Summary,
+		Price:        &in.Price,
+		Currency:     &in.Currency,
+		Bedrooms:     &in.Bedrooms,
+		Bathrooms:    &in.Bathrooms,
+		AreaSqM:      &in.AreaSqM,
+		ImageURL:     &in.ImageURL,
+		Tags:         &tags,
+		Location:     in.Location,
+	}
+}
+
+func failed(row Row, message string) RowResult {
+	return RowResult{Line: row.Line, Ref: row.Ref, Action: ActionFailed, Error: message}
+}
+
+func sortResults(results []RowResult) {
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Line < results[j].Line })
+}
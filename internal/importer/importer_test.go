@@ -0,0 +1,124 @@
+package importer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+const sampleCSV = `Reference,Title,Type,Country,City,Price,Currency,Bedrooms,Bathrooms,Area_SqFt,Tags,Lat,Lng
+LIS-1,Alfama loft,residential,PT,Lisbon,"450,000",EUR,2,1,861,river view|terrace,38.711,-9.13
+LIS-2,Chiado office,commercial,PT,Lisbon,cheap,EUR,0,1,,,,
+,Nameless,residential,PT,Porto,100000,EUR,1,1,,,,
+LIS-1,Alfama loft again,residential,PT,Lisbon,440000,EUR,2,1,861,,,
+LIS-3,Bad country,residential,Portugal,Porto,100000,EUR,1,1,,,,
+`
+
+func TestImportCSVUpsertsByReference(t *testing.T) {
+	ctx := context.Background()
+	listings := listingservice.NewInMemoryService()
+	agencyID := uuid.New()
+	im := New(listings, 2)
+
+	report, err := im.Import(ctx, agencyID, strings.NewReader(sampleCSV), FormatCSV, Options{Publish: true, ActorEmail: "ops@example.com"})
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if report.Total != 5 || report.Created != 1 || report.Failed != 4 {
+		t.Fatalf("report = %+v, want 1 created and 4 failed of 5", report)
+	}
+	wantErrors := map[int]string{3: "price must be a number", 4: "reference is required", 5: "duplicate reference", 6: "country"}
+	for _, row := range report.Errors() {
+		if want, ok := wantErrors[row.Line]; !ok || !strings.Contains(row.Error, want) {
+			t.Errorf("line %d error = %q, want it to mention %q", row.Line, row.Error, want)
+		}
+	}
+
+	created, err := listings.Get(ctx, *report.Rows[0].ListingID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if created.ExternalRef != "LIS-1" || created.AgencyID != agencyID || created.Status != listingservice.StatusPublished {
+		t.Errorf("created listing = ref %q agency %v status %s, want published LIS-1 of the agency", created.ExternalRef, created.AgencyID, created.Status)
+	}
+	if created.AreaSqM != 79.99 || len(created.Tags) != 2 || created.Location == nil {
+		t.Errorf("created listing area %v tags %v location %v, want 79.99 m², two tags and coordinates", created.AreaSqM, created.Tags, created.Location)
+	}
+
+	update := "Reference,Title,Type,Country,City,Price,Currency\nLIS-1,Alfama loft,residential,PT,Lisbon,425000,EUR\n"
+	report, err = im.Import(ctx, agencyID, strings.NewReader(update), FormatCSV, Options{})
+	if err != nil {
+		t.Fatalf("second Import() error = %v", err)
+	}
+	if report.Updated != 1 || *report.Rows[0].ListingID != created.ID {
+		t.Fatalf("second import = %+v, want the existing listing updated", report)
+	}
+	updated, _ := listings.Get(ctx, created.ID)
+	if updated.Price != 425000 || !updated.PriceDropped() {
+		t.Errorf("updated price = %v (dropped %v), want 425000 tracked as a reduction", updated.Price, updated.PriceDropped())
+	}
+
+	other := New(listings, 0)
+	report, _ = other.Import(ctx, uuid.New(), strings.NewReader(update), FormatCSV, Options{})
+	if report.Created != 1 {
+		t.Errorf("same reference for another agency = %+v, want a new listing", report)
+	}
+}
+
+func TestParseRESO(t *testing.T) {
+	feed := `{
+	  "@odata.context": "https://api.example.com/Property",
+	  "value": [
+	    {
+	      "ListingKey": "SG-100",
+	      "PropertyType": "Residential",
+	      "PropertySubType": "Duplex",
+	      "Country": "SG",
+	      "City": "Singapore",
+	      "ListPrice": 2400000,
+	      "Currency": "SGD",
+	      "BedroomsTotal": 3,
+	      "BathroomsTotalInteger": 2,
+	      "LivingArea": 1500,
+	      "LivingAreaUnits": "Square Feet",
+	      "Latitude": 1.29,
+	      "Longitude": 103.85,
+	      "Media": [
+	        {"MediaURL": "https://cdn.example.com/2.jpg", "Order": 2},
+	        {"MediaURL": "https://cdn.example.com/1.jpg", "Order": 1}
+	      ]
+	    },
+	    {"PropertyType": "Land", "ListPrice": 10}
+	  ]
+	}`
+	rows, rejected, err := ParseRESO(strings.NewReader(feed))
+	if err != nil {
+		t.Fatalf("ParseRESO() error = %v", err)
+	}
+	if len(rows) != 1 || len(rejected) != 1 || rejected[0].Line != 2 {
+		t.Fatalf("ParseRESO() = %d rows, rejected %+v, want one of each", len(rows), rejected)
+	}
+	in := rows[0].Input
+	if rows[0].Ref != "SG-100" || in.Title != "3-bedroom Duplex in Singapore" || in.Type != listingservice.ListingTypeResidential {
+		t.Errorf("row = %q %q %q, want SG-100 residential duplex headline", rows[0].Ref, in.Title, in.Type)
+	}
+	if in.AreaSqM != 139.35 || in.ImageURL != "https://cdn.example.com/1.jpg" || in.Bathrooms != 2 {
+		t.Errorf("input area %v image %q baths %v, want 139.35 m², the first media item and 2 baths", in.AreaSqM, in.ImageURL, in.Bathrooms)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	if f, err := DetectFormat("feed.JSON", ""); err != nil || f != FormatRESO {
+		t.Errorf("DetectFormat(feed.JSON) = %q, %v", f, err)
+	}
+	if f, err := DetectFormat("", "text/csv; charset=utf-8"); err != nil || f != FormatCSV {
+		t.Errorf("DetectFormat(text/csv) = %q, %v", f, err)
+	}
+	if _, err := DetectFormat("feed.xlsx", "application/octet-stream"); err == nil {
+		t.Error("DetectFormat() accepted an unknown file")
+	}
+}
@@ -0,0 +1,205 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+// resoMedia is the subset of a RESO Media resource the importer reads.
+type resoMedia struct {
+	MediaURL string `json:"MediaURL"`
+	Order    *int   `json:"Order"`
+}
+
+// ParseRESO reads RESO Web API shaped JSON: either an OData envelope with a "value"
+// array of Property resources or a bare array of them. ListingKey (or ListingId)
+// becomes the reference; LivingArea honours LivingAreaUnits; the first Media item by
+// Order becomes the hero image. Currency is not part of the RESO dictionary, so a
+// "Currency" or "CurrencyCode" field is expected on each record.
+func ParseRESO(r io.Reader) ([]Row, []RowResult, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, nil, errors.New("json feed is empty")
+	}
+
+	var records []json.RawMessage
+	if body[0] == '[' {
+		err = json.Unmarshal(body, &records)
+	} else {
+		var envelope struct {
+			Value []json.RawMessage `json:"value"`
+		}
+		err = json.Unmarshal(body, &envelope)
+		records = envelope.Value
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode json feed: %w", err)
+	}
+
+	var (
+		rows     []Row
+		rejected []RowResult
+	)
+	for idx, raw := range records {
+		line := idx + 1
+		row, err := resoRow(line, raw)
+		if err != nil {
+			rejected = append(rejected, RowResult{Line: line, Ref: row.Ref, Action: ActionFailed, Error: err.Error()})
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, rejected, nil
+}
+
+func resoRow(line int, raw json.RawMessage) (Row, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var fields map[string]any
+	if err := decoder.Decode(&fields); err != nil {
+		return Row{Line: line}, errors.New("record must be a JSON object")
+	}
+	str := func(keys ...string) string {
+		for _, key := range keys {
+			switch v := fields[key].(type) {
+			case string:
+				if s := strings.TrimSpace(v); s != "" {
+					return s
+				}
+			case json.Number:
+				return v.String()
+			}
+		}
+		return ""
+	}
+	num := func(key string) (float64, error) {
+		switch v := fields[key].(type) {
+		case nil:
+			return 0, nil
+		case json.Number:
+			return v.Float64()
+		case string:
+			return parseFloat(key, v)
+		default:
+			return 0, fmt.Errorf("%s must be a number", key)
+		}
+	}
+
+	row := Row{Line: line, Ref: str("ListingKey", "ListingId")}
+	if row.Ref == "" {
+		return row, errors.New("ListingKey is required")
+	}
+
+	in := listingservice.CreateInput{
+		Type:         resoType(str("PropertyType")),
+		Country:      str("Country"),
+		City:         str("City"),
+		Region:       str("StateOrProvince"),
+		Neighborhood: str("SubdivisionName", "MLSAreaMajor"),
+		Summary:      str("PublicRemarks"),
+		Currency:     str("Currency", "CurrencyCode"),
+	}
+	var err error
+	if in.Price, err = num("ListPrice"); err != nil {
+		return row, err
+	}
+	bedrooms, err := num("BedroomsTotal")
+	if err != nil {
+		return row, err
+	}
+	in.Bedrooms = int(bedrooms)
+	if in.Bathrooms, err = num("BathroomsTotalDecimal"); err != nil {
+		return row, err
+	}
+	if in.Bathrooms == 0 {
+		if in.Bathrooms, err = num("BathroomsTotalInteger"); err != nil {
+			return row, err
+		}
+	}
+	area, err := num("LivingArea")
+	if err != nil {
+		return row, err
+	}
+	if area == 0 {
+		if area, err = num("LotSizeSquareFeet"); err != nil {
+			return row, err
+		}
+		area = sqftToSqm(area)
+	} else if units := strings.ToLower(str("LivingAreaUnits")); units == "" || strings.Contains(units, "feet") || strings.Contains(units, "sqft") {
+		area = sqftToSqm(area)
+	}
+	in.AreaSqM = area
+	if in.Location, err = parseLocation(str("Latitude"), str("Longitude")); err != nil {
+		return row, err
+	}
+	in.ImageURL = resoHeroImage(fields["Media"])
+	in.Title = str("ListingTitle")
+	if in.Title == "" {
+		in.Title = resoTitle(in, str("PropertySubType"))
+	}
+	row.Input = in
+	return row, nil
+}
+
+// resoType maps the RESO PropertyType lookup onto listing types.
+func resoType(value string) listingservice.ListingType {
+	switch v := strings.ToLower(value); {
+	case v == "land" || v == "farm":
+		return listingservice.ListingTypeLand
+	case strings.HasPrefix(v, "commercial") || v == "businessopportunity":
+		return listingservice.ListingTypeCommercial
+	case v == "":
+		return ""
+	default:
+		return listingservice.ListingTypeResidential
+	}
+}
+
+// resoTitle builds a headline for feeds that carry no title, e.g.
+// "3-bedroom Townhouse in Lisbon".
+func resoTitle(in listingservice.CreateInput, subtype string) string {
+	kind := subtype
+	if kind == "" {
+		kind = strings.Title(string(in.Type))
+	}
+	if kind == "" {
+		kind = "Property"
+	}
+	if in.Bedrooms > 0 {
+		kind = strconv.Itoa(in.Bedrooms) + "-bedroom " + kind
+	}
+	if in.City != "" {
+		return kind + " in " + in.City
+	}
+	return kind
+}
+
+func resoHeroImage(value any) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	var media []resoMedia
+	if err := json.Unmarshal(raw, &media); err != nil || len(media) == 0 {
+		return ""
+	}
+	sort.SliceStable(media, func(i, j int) bool {
+		if media[i].Order == nil || media[j].Order == nil {
+			return media[j].Order == nil && media[i].Order != nil
+		}
+		return *media[i].Order < *media[j].Order
+	})
+	return strings.TrimSpace(media[0].MediaURL)
+}
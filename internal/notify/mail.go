@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// FileSender writes each notification as an RFC 5322 message (.eml) into a
+// directory, which is handy for local development and for shipping through an
+// external mail relay.
+type FileSender struct {
+	dir  string
+	from string
+}
+
+// NewFileSender creates a sender that drops messages into dir.
+func NewFileSender(dir, from string) *FileSender {
+	return &FileSender{dir: dir, from: from}
+}
+
+func (s *FileSender) Send(_ context.Context, n Notification) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", n.CreatedAt.UTC().Format("20060102T150405Z"), n.ID)
+	target := filepath.Join(s.dir, name)
+	tmp := target + ".tmp"
+	if err := os.WriteFile(tmp, composeMessage(s.from, n), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, target)
+}
+
+// SMTPSender delivers notifications through an SMTP relay using PLAIN auth when
+// credentials are configured.
+type SMTPSender struct {
+	addr string
+	host string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPSender creates a sender for the relay at host:port.
+func NewSMTPSender(host string, port int, username, password, from string) *SMTPSender {
+	sender := &SMTPSender{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		host: host,
+		from: from,
+	}
+	if username != "" {
+		sender.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return sender
+}
+
+func (s *SMTPSender) Send(_ context.Context, n Notification) error {
+	from, err := mail.ParseAddress(s.from)
+	if err != nil {
+		return fmt.Errorf("parse sender address: %w", err)
+	}
+	return smtp.SendMail(s.addr, s.auth, from.Address, []string{n.Recipient}, composeMessage(s.from, n))
+}
+
+// composeMessage renders the notification as a plain-text UTF-8 e-mail.
+func composeMessage(from string, n Notification) []byte {
+	var buf bytes.Buffer
+	date := n.CreatedAt
+	if date.IsZero() {
+		date = time.Now()
+	}
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", n.Recipient)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", n.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@shanraq.com>\r\n", n.ID)
+	fmt.Fprintf(&buf, "X-Shanraq-Kind: %s\r\n", n.Kind)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	buf.WriteString(n.Body)
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,180 @@
+// Package notify queues outbound messages in an outbox and delivers them through a
+// configurable transport.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"shanraq.com/internal/config"
+)
+
+// Notification is a message waiting in, or delivered from, the outbox.
+type Notification struct {
+	ID        uuid.UUID  `json:"id"`
+	Kind      string     `json:"kind"`
+	Recipient string     `json:"recipient"`
+	Subject   string     `json:"subject"`
+	Body      string     `json:"body"`
+	Attempts  int        `json:"attempts"`
+	LastError string     `json:"last_error,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+}
+
+// Outbox stores notifications until a sender has delivered them.
+type Outbox interface {
+	Enqueue(ctx context.Context, n Notification) (Notification, error)
+	Pending(ctx context.Context, limit, maxAttempts int) ([]Notification, error)
+	MarkSent(ctx context.Context, id uuid.UUID, at time.Time) error
+	MarkFailed(ctx context.Context, id uuid.UUID, reason string) error
+}
+
+// Sender delivers a single notification.
+type Sender interface {
+	Send(ctx context.Context, n Notification) error
+}
+
+// ErrNotFound is returned when a notification cannot be located.
+var ErrNotFound = errors.New("notification not found")
+
+// NewSender builds the delivery adapter selected by configuration.
+func NewSender(cfg config.Notify) (Sender, error) {
+	switch strings.ToLower(cfg.Driver) {
+	case "", "file":
+		return NewFileSender(cfg.FileDir, cfg.From), nil
+	case "smtp":
+		if cfg.SMTPHost == "" {
+			return nil, errors.New("smtp notify driver requires NOTIFY_SMTP_HOST")
+		}
+		return NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From), nil
+	default:
+		return nil, fmt.Errorf("unsupported notify driver %q", cfg.Driver)
+	}
+}
+
+// Dispatch delivers up to limit pending notifications and reports how many were
+// sent. Failed deliveries are recorded and retried on a later run until they reach
+// maxAttempts.
+func Dispatch(ctx context.Context, outbox Outbox, sender Sender, limit, maxAttempts int) (int, error) {
+	pending, err := outbox.Pending(ctx, limit, maxAttempts)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, n := range pending {
+		if err := sender.Send(ctx, n); err != nil {
+			if markErr := outbox.MarkFailed(ctx, n.ID, err.Error()); markErr != nil {
+				return sent, markErr
+			}
+			continue
+		}
+		if err := outbox.MarkSent(ctx, n.ID, time.Now().UTC()); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func normalizeNotification(n Notification) (Notification, error) {
+	n.Recipient = strings.TrimSpace(n.Recipient)
+	n.Subject = strings.TrimSpace(n.Subject)
+	if n.Recipient == "" {
+		return Notification{}, errors.New("recipient is required")
+	}
+	if n.Subject == "" {
+		return Notification{}, errors.New("subject is required")
+	}
+	if n.Kind == "" {
+		n.Kind = "generic"
+	}
+	return n, nil
+}
+
+// InMemoryOutbox is a process-local outbox.
+type InMemoryOutbox struct {
+	mu            sync.RWMutex
+	notifications []Notification
+}
+
+// NewInMemoryOutbox creates an empty outbox.
+func NewInMemoryOutbox() *InMemoryOutbox {
+	return &InMemoryOutbox{}
+}
+
+// Enqueue stores a notification for delivery.
+func (o *InMemoryOutbox) Enqueue(_ context.Context, n Notification) (Notification, error) {
+	n, err := normalizeNotification(n)
+	if err != nil {
+		return Notification{}, err
+	}
+	n.ID = uuid.New()
+	n.CreatedAt = time.Now().UTC()
+	n.Attempts = 0
+	n.SentAt = nil
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.notifications = append(o.notifications, n)
+	return n, nil
+}
+
+// Pending returns the oldest undelivered notifications that still have attempts left.
+func (o *InMemoryOutbox) Pending(_ context.Context, limit, maxAttempts int) ([]Notification, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	out := make([]Notification, 0)
+	for _, n := range o.notifications {
+		if n.SentAt == nil && (maxAttempts <= 0 || n.Attempts < maxAttempts) {
+			out = append(out, n)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// MarkSent records a successful delivery.
+func (o *InMemoryOutbox) MarkSent(_ context.Context, id uuid.UUID, at time.Time) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for idx := range o.notifications {
+		if o.notifications[idx].ID == id {
+			sent := at
+			o.notifications[idx].SentAt = &sent
+			o.notifications[idx].Attempts++
+			o.notifications[idx].LastError = ""
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// MarkFailed records a failed delivery attempt.
+func (o *InMemoryOutbox) MarkFailed(_ context.Context, id uuid.UUID, reason string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for idx := range o.notifications {
+		if o.notifications[idx].ID == id {
+			o.notifications[idx].Attempts++
+			o.notifications[idx].LastError = reason
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+var _ Outbox = (*InMemoryOutbox)(nil)
@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type failingSender struct{}
+
+func (failingSender) Send(context.Context, Notification) error {
+	return errors.New("relay unavailable")
+}
+
+func TestDispatchWritesMessagesAndRetriesFailures(t *testing.T) {
+	ctx := context.Background()
+	outbox := NewInMemoryOutbox()
+	if _, err := outbox.Enqueue(ctx, Notification{Kind: "test", Recipient: "buyer@example.com", Subject: "Nya bostäder", Body: "Hello"}); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	if _, err := outbox.Enqueue(ctx, Notification{Recipient: "", Subject: "missing"}); err == nil {
+		t.Error("Enqueue() accepted a notification without recipient")
+	}
+
+	sent, err := Dispatch(ctx, outbox, failingSender{}, 10, 2)
+	if err != nil || sent != 0 {
+		t.Fatalf("Dispatch(failing) = %d, %v", sent, err)
+	}
+	pending, _ := outbox.Pending(ctx, 10, 2)
+	if len(pending) != 1 || pending[0].Attempts != 1 || pending[0].LastError == "" {
+		t.Fatalf("pending after failure = %+v", pending)
+	}
+
+	dir := t.TempDir()
+	sent, err = Dispatch(ctx, outbox, NewFileSender(dir, "Shanraq <alerts@shanraq.com>"), 10, 2)
+	if err != nil || sent != 1 {
+		t.Fatalf("Dispatch(file) = %d, %v", sent, err)
+	}
+	if pending, _ := outbox.Pending(ctx, 10, 2); len(pending) != 0 {
+		t.Errorf("pending after delivery = %d, want 0", len(pending))
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("found %d .eml files, want 1", len(files))
+	}
+	raw, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	message := string(raw)
+	for _, token := range []string{"To: buyer@example.com", "Subject: =?utf-8?q?", "X-Shanraq-Kind: test", "\r\n\r\nHello"} {
+		if !strings.Contains(message, token) {
+			t.Errorf("message missing %q:\n%s", token, message)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const notificationColumns = `id, kind, recipient, subject, body, attempts, last_error, created_at, sent_at`
+
+type sqlOutbox struct {
+	db *sql.DB
+}
+
+// NewSQLOutbox builds an outbox backed by the notification_outbox table.
+func NewSQLOutbox(db *sql.DB) (Outbox, error) {
+	return &sqlOutbox{db: db}, nil
+}
+
+func (o *sqlOutbox) Enqueue(ctx context.Context, n Notification) (Notification, error) {
+	n, err := normalizeNotification(n)
+	if err != nil {
+		return Notification{}, err
+	}
+	row := o.db.QueryRowContext(ctx, `
+        INSERT INTO notification_outbox (kind, recipient, subject, body)
+        VALUES ($1, $2, $3, $4)
+        RETURNING `+notificationColumns, n.Kind, n.Recipient, n.Subject, n.Body)
+	return scanNotification(row)
+}
+
+func (o *sqlOutbox) Pending(ctx context.Context, limit, maxAttempts int) ([]Notification, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 1 << 30
+	}
+	rows, err := o.db.QueryContext(ctx, `
+        SELECT `+notificationColumns+`
+        FROM notification_outbox
+        WHERE sent_at IS NULL AND attempts < $1
+        ORDER BY created_at, id
+        LIMIT $2`, maxAttempts, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]Notification, 0)
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (o *sqlOutbox) MarkSent(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return o.update(ctx, `
+        UPDATE notification_outbox
+        SET sent_at = $2, attempts = attempts + 1, last_error = ''
+        WHERE id = $1`, id, at)
+}
+
+func (o *sqlOutbox) MarkFailed(ctx context.Context, id uuid.UUID, reason string) error {
+	return o.update(ctx, `
+        UPDATE notification_outbox
+        SET attempts = attempts + 1, last_error = $2
+        WHERE id = $1`, id, reason)
+}
+
+func (o *sqlOutbox) update(ctx context.Context, query string, args ...any) error {
+	result, err := o.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanNotification(row interface {
+	Scan(dest ...any) error
+}) (Notification, error) {
+	var (
+		n      Notification
+		sentAt sql.NullTime
+	)
+	if err := row.Scan(&n.ID, &n.Kind, &n.Recipient, &n.Subject, &n.Body, &n.Attempts, &n.LastError, &n.CreatedAt, &sentAt); err != nil {
+		return Notification{}, err
+	}
+	if sentAt.Valid {
+		sent := sentAt.Time
+		n.SentAt = &sent
+	}
+	return n, nil
+}
@@ -0,0 +1,702 @@
+package reso
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// DefaultTop is the page size used when $top is not given.
+	DefaultTop = 100
+	// MaxTop caps $top.
+	MaxTop = 200
+)
+
+// Record is a resource translated to RESO field names.
+type Record map[string]any
+
+// Expr is a compiled $filter expression.
+type Expr interface {
+	Match(rec Record) bool
+}
+
+// Order is one $orderby term.
+type Order struct {
+	Field string
+	Desc  bool
+}
+
+// Query is the parsed subset of OData system query options the API supports:
+// $filter, $select, $orderby, $top, $skip and $count.
+type Query struct {
+	Filter  Expr
+	Select  []string
+	OrderBy []Order
+	Top     int
+	Skip    int
+	Count   bool
+}
+
+// Comparison is a field-versus-literal term of a top-level "and" chain, which
+// callers may push down to the backing service before the full filter runs.
+type Comparison struct {
+	Field string
+	Op    string
+	Value any
+}
+
+// ParseQuery validates the OData options against the resource's fields.
+func ParseQuery(values url.Values, fields []string) (Query, error) {
+	known := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		known[f] = true
+	}
+	q := Query{Top: DefaultTop}
+	for key := range values {
+		switch key {
+		case "$filter", "$select", "$orderby", "$top", "$skip", "$count":
+		default:
+			if strings.HasPrefix(key, "$") {
+				return Query{}, fmt.Errorf("unsupported query option %s", key)
+			}
+		}
+	}
+
+	if raw := strings.TrimSpace(values.Get("$filter")); raw != "" {
+		expr, err := ParseFilter(raw, known)
+		if err != nil {
+			return Query{}, err
+		}
+		q.Filter = expr
+	}
+	if raw := strings.TrimSpace(values.Get("$select")); raw != "" {
+		for _, field := range strings.Split(raw, ",") {
+			field = strings.TrimSpace(field)
+			if !known[field] {
+				return Query{}, fmt.Errorf("unknown field %q in $select", field)
+			}
+			q.Select = append(q.Select, field)
+		}
+	}
+	if raw := strings.TrimSpace(values.Get("$orderby")); raw != "" {
+		for _, term := range strings.Split(raw, ",") {
+			parts := strings.Fields(term)
+			if len(parts) == 0 || len(parts) > 2 || !known[parts[0]] {
+				return Query{}, fmt.Errorf("invalid $orderby term %q", strings.TrimSpace(term))
+			}
+			order := Order{Field: parts[0]}
+			if len(parts) == 2 {
+				switch strings.ToLower(parts[1]) {
+				case "asc":
+				case "desc":
+					order.Desc = true
+				default:
+					return Query{}, fmt.Errorf("invalid $orderby direction %q", parts[1])
+				}
+			}
+			q.OrderBy = append(q.OrderBy, order)
+		}
+	}
+	if raw := values.Get("$top"); raw != "" {
+		top, err := strconv.Atoi(raw)
+		if err != nil || top < 0 || top > MaxTop {
+			return Query{}, fmt.Errorf("$top must be between 0 and %d", MaxTop)
+		}
+		q.Top = top
+	}
+	if raw := values.Get("$skip"); raw != "" {
+		skip, err := strconv.Atoi(raw)
+		if err != nil || skip < 0 {
+			return Query{}, errors.New("$skip must be a non-negative integer")
+		}
+		q.Skip = skip
+	}
+	if raw := values.Get("$count"); raw != "" {
+		count, err := strconv.ParseBool(raw)
+		if err != nil {
+			return Query{}, errors.New("$count must be true or false")
+		}
+		q.Count = count
+	}
+	return q, nil
+}
+
+// Apply filters, orders and pages the records, returning the page and the number of
+// records that matched before paging.
+func (q Query) Apply(records []Record) ([]Record, int) {
+	matched := make([]Record, 0, len(records))
+	for _, rec := range records {
+		if q.Filter == nil || q.Filter.Match(rec) {
+			matched = append(matched, rec)
+		}
+	}
+	if len(q.OrderBy) > 0 {
+		sort.SliceStable(matched, func(i, j int) bool {
+			for _, o := range q.OrderBy {
+				c, ok := compareValues(matched[i][o.Field], matched[j][o.Field])
+				if !ok || c == 0 {
+					continue
+				}
+				if o.Desc {
+					return c > 0
+				}
+				return c < 0
+			}
+			return false
+		})
+	}
+	total := len(matched)
+	if q.Skip >= len(matched) {
+		return []Record{}, total
+	}
+	matched = matched[q.Skip:]
+	if q.Top < len(matched) {
+		matched = matched[:q.Top]
+	}
+	if len(q.Select) > 0 {
+		for idx, rec := range matched {
+			projected := make(Record, len(q.Select))
+			for _, field := range q.Select {
+				projected[field] = rec[field]
+			}
+			matched[idx] = projected
+		}
+	}
+	return matched, total
+}
+
+// Conjuncts returns the field comparisons every match must satisfy.
+func (q Query) Conjuncts() []Comparison {
+	var out []Comparison
+	var walk func(Expr)
+	walk = func(e Expr) {
+		switch n := e.(type) {
+		case andExpr:
+			walk(n.left)
+			walk(n.right)
+		case compareExpr:
+			if n.left.field != "" && n.left.fold == "" && n.right.field == "" {
+				out = append(out, Comparison{Field: n.left.field, Op: n.op, Value: n.right.value})
+			}
+		}
+	}
+	if q.Filter != nil {
+		walk(q.Filter)
+	}
+	return out
+}
+
+type andExpr struct{ left, right Expr }
+
+func (e andExpr) Match(rec Record) bool { return e.left.Match(rec) && e.right.Match(rec) }
+
+type orExpr struct{ left, right Expr }
+
+func (e orExpr) Match(rec Record) bool { return e.left.Match(rec) || e.right.Match(rec) }
+
+type notExpr struct{ inner Expr }
+
+func (e notExpr) Match(rec Record) bool { return !e.inner.Match(rec) }
+
+// operand is a field reference, optionally wrapped in tolower/toupper, or a literal.
+type operand struct {
+	field string
+	fold  string
+	value any
+}
+
+func (o operand) resolve(rec Record) any {
+	if o.field == "" {
+		return o.value
+	}
+	v := rec[o.field]
+	if s, ok := v.(string); ok {
+		switch o.fold {
+		case "tolower":
+			return strings.ToLower(s)
+		case "toupper":
+			return strings.ToUpper(s)
+		}
+	}
+	return v
+}
+
+type compareExpr struct {
+	left, right operand
+	op          string
+}
+
+func (e compareExpr) Match(rec Record) bool {
+	a, b := e.left.resolve(rec), e.right.resolve(rec)
+	if a == nil || b == nil {
+		switch e.op {
+		case "eq":
+			return a == nil && b == nil
+		case "ne":
+			return (a == nil) != (b == nil)
+		default:
+			return false
+		}
+	}
+	if list, ok := a.([]string); ok {
+		// Collection fields match when any element compares true, e.g. MemberLanguages eq 'English'.
+		for _, item := range list {
+			if (compareExpr{left: operand{value: item}, right: e.right, op: e.op}).Match(rec) {
+				return true
+			}
+		}
+		return false
+	}
+	c, ok := compareValues(a, b)
+	if !ok {
+		return e.op == "ne"
+	}
+	switch e.op {
+	case "eq":
+		return c == 0
+	case "ne":
+		return c != 0
+	case "gt":
+		return c > 0
+	case "ge":
+		return c >= 0
+	case "lt":
+		return c < 0
+	case "le":
+		return c <= 0
+	}
+	return false
+}
+
+type inExpr struct {
+	arg    operand
+	values []any
+}
+
+func (e inExpr) Match(rec Record) bool {
+	for _, v := range e.values {
+		if (compareExpr{left: e.arg, right: operand{value: v}, op: "eq"}).Match(rec) {
+			return true
+		}
+	}
+	return false
+}
+
+type stringFuncExpr struct {
+	name string
+	arg  operand
+	text string
+}
+
+func (e stringFuncExpr) Match(rec Record) bool {
+	s, ok := e.arg.resolve(rec).(string)
+	if !ok {
+		return false
+	}
+	switch e.name {
+	case "contains":
+		return strings.Contains(s, e.text)
+	case "startswith":
+		return strings.HasPrefix(s, e.text)
+	case "endswith":
+		return strings.HasSuffix(s, e.text)
+	}
+	return false
+}
+
+// compareValues orders two scalar values of the same kind.
+func compareValues(a, b any) (int, bool) {
+	switch x := a.(type) {
+	case float64:
+		y, ok := b.(float64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	case string:
+		y, ok := b.(string)
+		if !ok {
+			if t, ok := b.(time.Time); ok {
+				if parsed, err := parseTime(x); err == nil {
+					return compareValues(parsed, t)
+				}
+			}
+			return 0, false
+		}
+		return strings.Compare(x, y), true
+	case time.Time:
+		switch y := b.(type) {
+		case time.Time:
+			return x.Compare(y), true
+		case string:
+			parsed, err := parseTime(y)
+			if err != nil {
+				return 0, false
+			}
+			return x.Compare(parsed), true
+		}
+	case bool:
+		y, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		if x == y {
+			return 0, true
+		}
+		if !x {
+			return -1, true
+		}
+		return 1, true
+	}
+	return 0, false
+}
+
+func parseTime(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", raw)
+}
+
+// ParseFilter compiles a $filter expression. Supported: eq ne gt ge lt le, and, or,
+// not, parentheses, "in (...)", contains/startswith/endswith, tolower/toupper,
+// string, number, boolean, null and date/timestamp literals, and enum literals in
+// the qualified Namespace.Type'Value' form.
+func ParseFilter(raw string, fields map[string]bool) (Expr, error) {
+	tokens, err := tokenize(raw)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens, fields: fields}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in $filter", p.tokens[p.pos].text)
+	}
+	return expr, nil
+}
+
+type tokenKind int
+
+const (
+	tokIdent tokenKind = iota
+	tokString
+	tokNumber
+	tokTime
+	tokPunct
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value any
+}
+
+func tokenize(raw string) ([]token, error) {
+	var tokens []token
+	runes := []rune(raw)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == ',':
+			tokens = append(tokens, token{kind: tokPunct, text: string(r)})
+			i++
+		case r == '\'':
+			text, next, err := readString(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokString, text: text, value: text})
+			i = next
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || strings.ContainsRune(".:-+TZ", runes[i])) {
+				i++
+			}
+			text := string(runes[start:i])
+			if n, err := strconv.ParseFloat(text, 64); err == nil {
+				tokens = append(tokens, token{kind: tokNumber, text: text, value: n})
+			} else if t, err := parseTime(text); err == nil {
+				tokens = append(tokens, token{kind: tokTime, text: text, value: t})
+			} else {
+				return nil, fmt.Errorf("invalid literal %q in $filter", text)
+			}
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			text := string(runes[start:i])
+			if i < len(runes) && runes[i] == '\'' {
+				// Qualified enum literal, e.g. Odata.Models.StandardStatus'Active'.
+				value, next, err := readString(runes, i)
+				if err != nil {
+					return nil, err
+				}
+				tokens = append(tokens, token{kind: tokString, text: text + "'" + value + "'", value: value})
+				i = next
+				continue
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: text})
+		default:
+			return nil, fmt.Errorf("unexpected character %q in $filter", r)
+		}
+	}
+	return tokens, nil
+}
+
+func readString(runes []rune, start int) (string, int, error) {
+	var b strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] == '\'' {
+			if i+1 < len(runes) && runes[i+1] == '\'' {
+				b.WriteRune('\'')
+				i++
+				continue
+			}
+			return b.String(), i + 1, nil
+		}
+		b.WriteRune(runes[i])
+	}
+	return "", 0, errors.New("unterminated string in $filter")
+}
+
+type filterParser struct {
+	tokens []token
+	pos    int
+	fields map[string]bool
+}
+
+func (p *filterParser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *filterParser) keyword(word string) bool {
+	t, ok := p.peek()
+	if ok && t.kind == tokIdent && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) punct(text string) bool {
+	t, ok := p.peek()
+	if ok && t.kind == tokPunct && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) expect(text string) error {
+	if !p.punct(text) {
+		return fmt.Errorf("expected %q in $filter", text)
+	}
+	return nil
+}
+
+func (p *filterParser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (Expr, error) {
+	if p.keyword("not") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{inner: inner}, nil
+	}
+	if p.punct("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	}
+	if t, ok := p.peek(); ok && t.kind == tokIdent {
+		name := strings.ToLower(t.text)
+		if name == "contains" || name == "startswith" || name == "endswith" {
+			p.pos++
+			return p.parseStringFunc(name)
+		}
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseStringFunc(name string) (Expr, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	arg, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if arg.field == "" {
+		return nil, fmt.Errorf("%s expects a field as its first argument", name)
+	}
+	if err := p.expect(","); err != nil {
+		return nil, err
+	}
+	lit, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	text, ok := lit.value.(string)
+	if lit.field != "" || !ok {
+		return nil, fmt.Errorf("%s expects a string as its second argument", name)
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return stringFuncExpr{name: name, arg: arg, text: text}, nil
+}
+
+func (p *filterParser) parseComparison() (Expr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if p.keyword("in") {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		var values []any
+		for {
+			lit, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			if lit.field != "" {
+				return nil, errors.New("in expects a list of literals")
+			}
+			values = append(values, lit.value)
+			if p.punct(")") {
+				break
+			}
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		return inExpr{arg: left, values: values}, nil
+	}
+
+	t, ok := p.peek()
+	if !ok || t.kind != tokIdent {
+		return nil, errors.New("expected a comparison operator in $filter")
+	}
+	op := strings.ToLower(t.text)
+	switch op {
+	case "eq", "ne", "gt", "ge", "lt", "le":
+	default:
+		return nil, fmt.Errorf("unsupported operator %q in $filter", t.text)
+	}
+	p.pos++
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if left.field == "" && right.field != "" {
+		left, right = right, left
+		op = mirror(op)
+	}
+	return compareExpr{left: left, right: right, op: op}, nil
+}
+
+func (p *filterParser) parseOperand() (operand, error) {
+	t, ok := p.peek()
+	if !ok {
+		return operand{}, errors.New("unexpected end of $filter")
+	}
+	p.pos++
+	switch t.kind {
+	case tokString, tokNumber, tokTime:
+		return operand{value: t.value}, nil
+	case tokIdent:
+		switch lower := strings.ToLower(t.text); lower {
+		case "true", "false":
+			return operand{value: lower == "true"}, nil
+		case "null":
+			return operand{}, nil
+		case "tolower", "toupper":
+			if err := p.expect("("); err != nil {
+				return operand{}, err
+			}
+			inner, err := p.parseOperand()
+			if err != nil {
+				return operand{}, err
+			}
+			if inner.field == "" {
+				return operand{}, fmt.Errorf("%s expects a field", lower)
+			}
+			inner.fold = lower
+			return inner, p.expect(")")
+		}
+		if !p.fields[t.text] {
+			return operand{}, fmt.Errorf("unknown field %q in $filter", t.text)
+		}
+		return operand{field: t.text}, nil
+	}
+	return operand{}, fmt.Errorf("unexpected %q in $filter", t.text)
+}
+
+func mirror(op string) string {
+	switch op {
+	case "gt":
+		return "lt"
+	case "ge":
+		return "le"
+	case "lt":
+		return "gt"
+	case "le":
+		return "ge"
+	}
+	return op
+}
@@ -0,0 +1,178 @@
+package reso
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+
+	agencyservice "shanraq.com/internal/services/agency"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+func sampleProperties() []Record {
+	return []Record{
+		{"ListingKey": "a", "City": "Almaty", "ListPrice": float64(90000), "BedroomsTotal": float64(2), "PropertyType": "Residential"},
+		{"ListingKey": "b", "City": "Astana", "ListPrice": float64(150000), "BedroomsTotal": float64(3), "PropertyType": "Residential"},
+		{"ListingKey": "c", "City": "Almaty", "ListPrice": float64(400000), "BedroomsTotal": float64(0), "PropertyType": "CommercialSale"},
+	}
+}
+
+func keys(records []Record) []string {
+	out := make([]string, 0, len(records))
+	for _, rec := range records {
+		out = append(out, rec["ListingKey"].(string))
+	}
+	return out
+}
+
+func TestFilterEvaluation(t *testing.T) {
+	cases := []struct {
+		filter string
+		want   []string
+	}{
+		{"City eq 'Almaty'", []string{"a", "c"}},
+		{"City eq 'Almaty' and ListPrice lt 100000", []string{"a"}},
+		{"ListPrice ge 150000 or BedroomsTotal eq 2", []string{"a", "b", "c"}},
+		{"not (PropertyType eq 'Residential')", []string{"c"}},
+		{"PropertyType in ('CommercialSale', 'Land')", []string{"c"}},
+		{"startswith(City, 'Ast')", []string{"b"}},
+		{"tolower(City) eq 'almaty' and BedroomsTotal gt 1", []string{"a"}},
+		{"PropertyType eq PropertyEnums.PropertyType'Residential'", []string{"a", "b"}},
+	}
+	for _, tc := range cases {
+		q, err := ParseQuery(url.Values{"$filter": {tc.filter}}, PropertyFields)
+		if err != nil {
+			t.Fatalf("ParseQuery(%q) error = %v", tc.filter, err)
+		}
+		page, total := q.Apply(sampleProperties())
+		if got := keys(page); total != len(tc.want) || len(got) != len(tc.want) {
+			t.Errorf("%q matched %v (total %d), want %v", tc.filter, got, total, tc.want)
+			continue
+		}
+		for idx, key := range keys(page) {
+			if key != tc.want[idx] {
+				t.Errorf("%q matched %v, want %v", tc.filter, keys(page), tc.want)
+				break
+			}
+		}
+	}
+}
+
+func TestParseQueryRejectsUnsupportedInput(t *testing.T) {
+	for _, values := range []url.Values{
+		{"$filter": {"Unknown eq 1"}},
+		{"$filter": {"City eq"}},
+		{"$select": {"ListingKey,Secret"}},
+		{"$orderby": {"ListPrice sideways"}},
+		{"$top": {"1000"}},
+		{"$expand": {"Media"}},
+	} {
+		if _, err := ParseQuery(values, PropertyFields); err == nil {
+			t.Errorf("ParseQuery(%v) succeeded, want error", values)
+		}
+	}
+}
+
+func TestApplyOrdersPagesAndSelects(t *testing.T) {
+	q, err := ParseQuery(url.Values{
+		"$orderby": {"ListPrice desc"},
+		"$top":     {"1"},
+		"$skip":    {"1"},
+		"$select":  {"ListingKey,ListPrice"},
+		"$count":   {"true"},
+	}, PropertyFields)
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+	page, total := q.Apply(sampleProperties())
+	if total != 3 || len(page) != 1 || page[0]["ListingKey"] != "b" {
+		t.Fatalf("Apply() = %v (total %d), want listing b of 3", page, total)
+	}
+	if _, ok := page[0]["City"]; ok || len(page[0]) != 2 {
+		t.Fatalf("Apply() did not project $select: %v", page[0])
+	}
+	if !q.Count {
+		t.Fatal("$count=true was not parsed")
+	}
+}
+
+func TestConjunctsPushDownToListingFilter(t *testing.T) {
+	agencyID := uuid.New()
+	q, err := ParseQuery(url.Values{"$filter": {
+		"City eq 'Almaty' and ListPrice ge 50000 and ListPrice le 200000 and BedroomsTotal gt 1.5 and " +
+			"PropertyType eq 'CommercialSale' and ListOfficeKey eq '" + agencyID.String() + "' and (Country eq 'KZ' or Country eq 'PT')",
+	}}, PropertyFields)
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+	f := ListingFilter(q.Conjuncts())
+	if f.City != "Almaty" || f.MinPrice != 50000 || f.MaxPrice != 200000 || f.MinBedrooms != 2 {
+		t.Fatalf("ListingFilter() = %+v", f)
+	}
+	if f.Type != listingservice.ListingTypeCommercial || f.AgencyID != agencyID || f.Country != "" {
+		t.Fatalf("ListingFilter() = %+v, want commercial type, agency and no country", f)
+	}
+}
+
+func TestExactListingFilter(t *testing.T) {
+	cases := []struct {
+		values url.Values
+		exact  bool
+	}{
+		{url.Values{}, true},
+		{url.Values{"$filter": {"Country eq 'KZ' and ListPrice ge 50000 and BedroomsTotal ge 2"}}, true},
+		{url.Values{"$filter": {"PropertyType eq 'Land' and LivingArea le 500"}}, true},
+		{url.Values{"$filter": {"City eq 'Almaty'"}}, false},
+		{url.Values{"$filter": {"Country eq 'kz'"}}, false},
+		{url.Values{"$filter": {"BedroomsTotal gt 1.5"}}, false},
+		{url.Values{"$filter": {"Country eq 'KZ' or Country eq 'PT'"}}, false},
+		{url.Values{"$filter": {"Country eq 'KZ' and Country eq 'PT'"}}, false},
+		{url.Values{"$filter": {"StandardStatus eq 'Active'"}}, false},
+		{url.Values{"$orderby": {"ListPrice desc"}}, false},
+	}
+	for _, tc := range cases {
+		q, err := ParseQuery(tc.values, PropertyFields)
+		if err != nil {
+			t.Fatalf("ParseQuery(%v) error = %v", tc.values, err)
+		}
+		if _, exact := ExactListingFilter(q); exact != tc.exact {
+			t.Errorf("ExactListingFilter(%v) exact = %v, want %v", tc.values, exact, tc.exact)
+		}
+	}
+}
+
+func TestResourceMapping(t *testing.T) {
+	price := 120000.0
+	l := listingservice.Listing{
+		ID:            uuid.New(),
+		Slug:          "alfama-loft",
+		Status:        listingservice.StatusUnderOffer,
+		Type:          listingservice.ListingTypeLand,
+		Price:         100000,
+		PreviousPrice: &price,
+		Currency:      "EUR",
+		City:          "Lisbon",
+		DetailsURL:    "/listings/alfama-loft",
+		ImageURL:      "https://cdn.example.com/loft.jpg",
+	}
+	rec := Property(l, "https://shanraq.example/")
+	if rec["ListingId"] != "alfama-loft" || rec["StandardStatus"] != "Active Under Contract" || rec["PropertyType"] != "Land" {
+		t.Fatalf("Property() = %v", rec)
+	}
+	if rec["ListingURL"] != "https://shanraq.example/listings/alfama-loft" || rec["PreviousListPrice"] != price || rec["PhotosCount"] != float64(1) {
+		t.Fatalf("Property() = %v", rec)
+	}
+
+	member := Member(agencyservice.Realtor{ID: uuid.New(), FullName: "Aigerim Nurlanovna Sadykova", Languages: []string{"kk", "ru"}})
+	if member["MemberFirstName"] != "Aigerim Nurlanovna" || member["MemberLastName"] != "Sadykova" {
+		t.Fatalf("Member() = %v", member)
+	}
+	q, err := ParseQuery(url.Values{"$filter": {"MemberLanguages eq 'ru'"}}, MemberFields)
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+	if _, total := q.Apply([]Record{member}); total != 1 {
+		t.Fatal("collection field did not match any element")
+	}
+}
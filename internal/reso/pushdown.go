@@ -0,0 +1,148 @@
+package reso
+
+import (
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+// ListingFilter narrows the listing query with the comparisons that map onto
+// ListFilter fields. The result may match more listings than the OData filter;
+// callers still apply the full expression to the translated records.
+func ListingFilter(terms []Comparison) listingservice.ListFilter {
+	var f listingservice.ListFilter
+	for _, t := range terms {
+		switch value := t.Value.(type) {
+		case string:
+			if t.Op != "eq" {
+				continue
+			}
+			switch t.Field {
+			case "Country":
+				f.Country = value
+			case "City":
+				f.City = value
+			case "PropertyType":
+				f.Type = listingType(value)
+			case "ListOfficeKey":
+				if id, err := uuid.Parse(value); err == nil {
+					f.AgencyID = id
+				}
+			}
+		case float64:
+			lower := t.Op == "ge" || t.Op == "gt" || t.Op == "eq"
+			upper := t.Op == "le" || t.Op == "lt" || t.Op == "eq"
+			switch t.Field {
+			case "ListPrice":
+				if lower && value > f.MinPrice {
+					f.MinPrice = value
+				}
+				if upper && value > 0 && (f.MaxPrice == 0 || value < f.MaxPrice) {
+					f.MaxPrice = value
+				}
+			case "BedroomsTotal":
+				if lower && int(math.Ceil(value)) > f.MinBedrooms {
+					f.MinBedrooms = int(math.Ceil(value))
+				}
+			case "BathroomsTotalDecimal":
+				if lower && value > f.MinBathrooms {
+					f.MinBathrooms = value
+				}
+			case "LivingArea":
+				if lower && value > f.MinArea {
+					f.MinArea = value
+				}
+				if upper && value > 0 && (f.MaxArea == 0 || value < f.MaxArea) {
+					f.MaxArea = value
+				}
+			}
+		}
+	}
+	return f
+}
+
+// ExactListingFilter returns ListingFilter for the query and reports whether it is
+// exact: the OData filter selects precisely the listings the ListFilter does and
+// no $orderby overrides the listing order. Exact queries can hand $top, $skip and
+// the count to the listing service instead of filtering records in memory.
+func ExactListingFilter(q Query) (listingservice.ListFilter, bool) {
+	f := ListingFilter(q.Conjuncts())
+	if len(q.OrderBy) > 0 {
+		return f, false
+	}
+	if q.Filter == nil {
+		return f, true
+	}
+	seen := make(map[string]bool)
+	var exact func(Expr) bool
+	exact = func(e Expr) bool {
+		switch n := e.(type) {
+		case andExpr:
+			return exact(n.left) && exact(n.right)
+		case compareExpr:
+			if n.left.field == "" || n.left.fold != "" || n.right.field != "" {
+				return false
+			}
+			return exactComparison(Comparison{Field: n.left.field, Op: n.op, Value: n.right.value}, seen)
+		default:
+			return false
+		}
+	}
+	return f, exact(q.Filter)
+}
+
+// exactComparison reports whether ListingFilter maps the term without widening
+// it. String terms may appear once per field, since a second one would overwrite
+// the first instead of narrowing it.
+func exactComparison(t Comparison, seen map[string]bool) bool {
+	switch value := t.Value.(type) {
+	case string:
+		if t.Op != "eq" || seen[t.Field] {
+			return false
+		}
+		seen[t.Field] = true
+		switch t.Field {
+		case "Country":
+			// Country codes are stored upper-case; ListFilter upper-cases the value.
+			return value == strings.ToUpper(value)
+		case "PropertyType":
+			return true
+		case "ListOfficeKey":
+			id, err := uuid.Parse(value)
+			return err == nil && id.String() == value
+		}
+		// City matches case-insensitively in ListFilter, unlike OData eq.
+		return false
+	case float64:
+		if value <= 0 {
+			return false
+		}
+		switch t.Field {
+		case "ListPrice", "LivingArea":
+			return t.Op == "ge" || t.Op == "le" || t.Op == "eq"
+		case "BathroomsTotalDecimal":
+			return t.Op == "ge"
+		case "BedroomsTotal":
+			return t.Op == "ge" && value == math.Trunc(value)
+		}
+	}
+	return false
+}
+
+// listingType inverts PropertyType. Unknown lookups map to an impossible type so
+// the pushed-down query matches nothing, as the OData filter would.
+func listingType(value string) listingservice.ListingType {
+	for _, t := range []listingservice.ListingType{
+		listingservice.ListingTypeResidential,
+		listingservice.ListingTypeCommercial,
+		listingservice.ListingTypeLand,
+	} {
+		if PropertyType(t) == value {
+			return t
+		}
+	}
+	return listingservice.ListingType(strings.ToLower(value))
+}
@@ -0,0 +1,149 @@
+// Package reso translates listings and realtors into RESO Data Dictionary
+// resources and evaluates the OData query subset used by syndication partners.
+package reso
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	agencyservice "shanraq.com/internal/services/agency"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+// PropertyFields lists the fields of the Property resource.
+var PropertyFields = []string{
+	"ListingKey", "ListingId", "StandardStatus", "PropertyType", "ListPrice", "CurrencyCode",
+	"PreviousListPrice", "PriceChangeTimestamp", "Country", "StateOrProvince", "City",
+	"SubdivisionName", "UnparsedAddress", "PublicRemarks", "BedroomsTotal", "BathroomsTotalDecimal",
+	"LivingArea", "LivingAreaUnits", "Latitude", "Longitude", "ListOfficeKey", "ListOfficeName",
+	"ListingURL", "PhotosCount", "Media", "OnMarketTimestamp", "OriginalEntryTimestamp",
+	"ModificationTimestamp",
+}
+
+// MemberFields lists the fields of the Member resource.
+var MemberFields = []string{
+	"MemberKey", "MemberFullName", "MemberFirstName", "MemberLastName", "MemberEmail",
+	"MemberPreferredPhone", "MemberLanguages", "MemberStatus", "OfficeKey", "OfficeName",
+}
+
+// StandardStatus maps a listing status onto the RESO StandardStatus lookup.
+func StandardStatus(s listingservice.Status) string {
+	switch s {
+	case listingservice.StatusPublished:
+		return "Active"
+	case listingservice.StatusUnderOffer:
+		return "Active Under Contract"
+	case listingservice.StatusSold:
+		return "Closed"
+	case listingservice.StatusArchived:
+		return "Withdrawn"
+	default:
+		return "Coming Soon"
+	}
+}
+
+// PropertyType maps a listing type onto the RESO PropertyType lookup.
+func PropertyType(t listingservice.ListingType) string {
+	switch t {
+	case listingservice.ListingTypeCommercial:
+		return "CommercialSale"
+	case listingservice.ListingTypeLand:
+		return "Land"
+	default:
+		return "Residential"
+	}
+}
+
+// Property translates a listing into a RESO Property resource. baseURL prefixes the
+// listing page link.
+func Property(l listingservice.Listing, baseURL string) Record {
+	rec := Record{
+		"ListingKey":             l.ID.String(),
+		"ListingId":              l.ExternalRef,
+		"StandardStatus":         StandardStatus(l.Status),
+		"PropertyType":           PropertyType(l.Type),
+		"ListPrice":              l.Price,
+		"CurrencyCode":           l.Currency,
+		"PreviousListPrice":      nil,
+		"PriceChangeTimestamp":   nil,
+		"Country":                l.Country,
+		"StateOrProvince":        l.Region,
+		"City":                   l.City,
+		"SubdivisionName":        l.Neighborhood,
+		"UnparsedAddress":        l.LocationString(),
+		"PublicRemarks":          l.Summary,
+		"BedroomsTotal":          float64(l.Bedrooms),
+		"BathroomsTotalDecimal":  l.Bathrooms,
+		"LivingArea":             l.AreaSqM,
+		"LivingAreaUnits":        "Square Meters",
+		"Latitude":               nil,
+		"Longitude":              nil,
+		"ListOfficeKey":          nil,
+		"ListOfficeName":         l.AgencyName,
+		"ListingURL":             strings.TrimRight(baseURL, "/") + l.DetailsURL,
+		"PhotosCount":            float64(0),
+		"Media":                  []Record{},
+		"OnMarketTimestamp":      timestamp(l.PublishedAt),
+		"OriginalEntryTimestamp": l.CreatedAt,
+		"ModificationTimestamp":  l.UpdatedAt,
+	}
+	if l.ExternalRef == "" {
+		rec["ListingId"] = l.Slug
+	}
+	if l.PreviousPrice != nil {
+		rec["PreviousListPrice"] = *l.PreviousPrice
+		rec["PriceChangeTimestamp"] = timestamp(l.PriceChangedAt)
+	}
+	if l.Location != nil {
+		rec["Latitude"] = l.Location.Lat
+		rec["Longitude"] = l.Location.Lng
+	}
+	if l.AgencyID != uuid.Nil {
+		rec["ListOfficeKey"] = l.AgencyID.String()
+	}
+	if l.ImageURL != "" {
+		rec["PhotosCount"] = float64(1)
+		rec["Media"] = []Record{{"MediaURL": l.ImageURL, "Order": 1, "MediaCategory": "Photo"}}
+	}
+	return rec
+}
+
+// Member translates a realtor into a RESO Member resource.
+func Member(r agencyservice.Realtor) Record {
+	first, last := splitName(r.FullName)
+	languages := r.Languages
+	if languages == nil {
+		languages = []string{}
+	}
+	return Record{
+		"MemberKey":            r.ID.String(),
+		"MemberFullName":       r.FullName,
+		"MemberFirstName":      first,
+		"MemberLastName":       last,
+		"MemberEmail":          r.Email,
+		"MemberPreferredPhone": r.Phone,
+		"MemberLanguages":      languages,
+		"MemberStatus":         "Active",
+		"OfficeKey":            r.AgencyID.String(),
+		"OfficeName":           r.AgencyName,
+	}
+}
+
+// splitName takes the last word as the family name.
+func splitName(full string) (string, string) {
+	full = strings.TrimSpace(full)
+	idx := strings.LastIndex(full, " ")
+	if idx < 0 {
+		return full, ""
+	}
+	return strings.TrimSpace(full[:idx]), full[idx+1:]
+}
+
+func timestamp(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
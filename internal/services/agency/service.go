@@ -2,6 +2,7 @@ package agency
 
 import (
 	"context"
+	"errors"
 	"sort"
 	"strings"
 	"sync"
@@ -31,14 +32,20 @@ type Realtor struct {
 	Languages  []string  `json:"languages"`
 	Region     string    `json:"region"`
 	PhotoURL   string    `json:"photo_url"`
+	TimeZone   string    `json:"time_zone"`
 }
 
+// ErrRealtorNotFound is returned when a realtor does not exist.
+var ErrRealtorNotFound = errors.New("realtor not found")
+
 // Service exposes agency and realtor data.
 type Service interface {
 	ListAgencies(ctx context.Context) ([]Agency, error)
 	Featured(ctx context.Context, limit int) ([]Agency, error)
 	ListRealtors(ctx context.Context) ([]Realtor, error)
 	FeaturedRealtors(ctx context.Context, limit int) ([]Realtor, error)
+	GetRealtor(ctx context.Context, id uuid.UUID) (Realtor, error)
+	MemberAgencyIDs(ctx context.Context, email string) ([]uuid.UUID, error)
 }
 
 // InMemoryService provides seeded demo data.
@@ -101,6 +108,42 @@ func (s *InMemoryService) FeaturedRealtors(ctx context.Context, limit int) ([]Re
 	return realtors[:limit], nil
 }
 
+func (s *InMemoryService) GetRealtor(_ context.Context, id uuid.UUID) (Realtor, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, r := range s.realtors {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return Realtor{}, ErrRealtorNotFound
+}
+
+// MemberAgencyIDs returns the agencies employing a realtor with the given email.
+func (s *InMemoryService) MemberAgencyIDs(_ context.Context, email string) ([]uuid.UUID, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var ids []uuid.UUID
+	seen := make(map[uuid.UUID]struct{})
+	for _, r := range s.realtors {
+		if !strings.EqualFold(r.Email, email) {
+			continue
+		}
+		if _, ok := seen[r.AgencyID]; !ok {
+			seen[r.AgencyID] = struct{}{}
+			ids = append(ids, r.AgencyID)
+		}
+	}
+	return ids, nil
+}
+
 func (s *InMemoryService) seed() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -159,6 +202,7 @@ func (s *InMemoryService) seed() {
 			Languages:  []string{"Arabic", "English", "Hindi"},
 			Region:     "Middle East & North Africa",
 			PhotoURL:   "",
+			TimeZone:   "Asia/Dubai",
 		},
 		{
 			ID:         uuid.New(),
@@ -170,6 +214,7 @@ func (s *InMemoryService) seed() {
 			Languages:  []string{"Swedish", "Norwegian", "English"},
 			Region:     "Nordics & Northern Europe",
 			PhotoURL:   "",
+			TimeZone:   "Europe/Stockholm",
 		},
 		{
 			ID:         uuid.New(),
@@ -181,6 +226,7 @@ func (s *InMemoryService) seed() {
 			Languages:  []string{"English", "Mandarin"},
 			Region:     "Pacific Rim & Silicon Valley",
 			PhotoURL:   "",
+			TimeZone:   "America/Los_Angeles",
 		},
 		{
 			ID:         uuid.New(),
@@ -192,6 +238,7 @@ func (s *InMemoryService) seed() {
 			Languages:  []string{"Italian", "English", "French"},
 			Region:     "Southern Europe & Mediterranean",
 			PhotoURL:   "",
+			TimeZone:   "Europe/Rome",
 		},
 		{
 			ID:         uuid.New(),
@@ -203,6 +250,7 @@ func (s *InMemoryService) seed() {
 			Languages:  []string{"Spanish", "English"},
 			Region:     "Latin America & US Sunbelt",
 			PhotoURL:   "",
+			TimeZone:   "America/New_York",
 		},
 	}
 
@@ -4,8 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"sort"
 	"strings"
+
+	"github.com/google/uuid"
 )
 
 type sqlRepository struct {
@@ -41,6 +44,39 @@ func (s *sqlService) FeaturedRealtors(ctx context.Context, limit int) ([]Realtor
 	return s.repo.featuredRealtors(ctx, limit)
 }
 
+func (s *sqlService) GetRealtor(ctx context.Context, id uuid.UUID) (Realtor, error) {
+	return s.repo.getRealtor(ctx, id)
+}
+
+func (s *sqlService) MemberAgencyIDs(ctx context.Context, email string) ([]uuid.UUID, error) {
+	return s.repo.memberAgencyIDs(ctx, email)
+}
+
+func (r *sqlRepository) memberAgencyIDs(ctx context.Context, email string) ([]uuid.UUID, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil, nil
+	}
+	rows, err := r.db.QueryContext(ctx, `SELECT DISTINCT agency_id FROM realtors WHERE LOWER(email) = LOWER($1)`, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
 func (r *sqlRepository) listAgencies(ctx context.Context) ([]Agency, error) {
 	rows, err := r.db.QueryContext(ctx, `SELECT id, name, tagline, country_code, website, logo_url, head_office FROM real_estate_agencies ORDER BY name`)
 	if err != nil {
@@ -94,10 +130,12 @@ func (r *sqlRepository) featuredAgencies(ctx context.Context, limit int) ([]Agen
 	return agencies, nil
 }
 
+const realtorColumns = `r.id, r.agency_id, COALESCE(a.name, ''), r.full_name, r.email, r.phone,
+               COALESCE(array_to_json(r.languages)::text, '[]'), r.region, r.photo_url, r.time_zone`
+
 func (r *sqlRepository) listRealtors(ctx context.Context) ([]Realtor, error) {
 	rows, err := r.db.QueryContext(ctx, `
-        SELECT r.id, r.agency_id, COALESCE(a.name, ''), r.full_name, r.email, r.phone,
-               COALESCE(array_to_json(r.languages)::text, '[]'), r.region, r.photo_url
+        SELECT `+realtorColumns+`
         FROM realtors r
         LEFT JOIN real_estate_agencies a ON a.id = r.agency_id
         ORDER BY r.full_name`)
@@ -108,21 +146,10 @@ func (r *sqlRepository) listRealtors(ctx context.Context) ([]Realtor, error) {
 
 	realtors := make([]Realtor, 0)
 	for rows.Next() {
-		var realtor Realtor
-		var agencyName string
-		var email, phone, region, photo sql.NullString
-		var langsJSON string
-		if err := rows.Scan(&realtor.ID, &realtor.AgencyID, &agencyName, &realtor.FullName, &email, &phone, &langsJSON, &region, &photo); err != nil {
+		realtor, err := scanRealtor(rows)
+		if err != nil {
 			return nil, err
 		}
-		realtor.AgencyName = agencyName
-		realtor.Email = strings.TrimSpace(email.String)
-		realtor.Phone = strings.TrimSpace(phone.String)
-		realtor.Region = region.String
-		realtor.PhotoURL = photo.String
-		if err := json.Unmarshal([]byte(langsJSON), &realtor.Languages); err != nil {
-			realtor.Languages = nil
-		}
 		realtors = append(realtors, realtor)
 	}
 	if err := rows.Err(); err != nil {
@@ -139,8 +166,7 @@ func (r *sqlRepository) featuredRealtors(ctx context.Context, limit int) ([]Real
 		limit = 4
 	}
 	rows, err := r.db.QueryContext(ctx, `
-        SELECT r.id, r.agency_id, COALESCE(a.name, ''), r.full_name, r.email, r.phone,
-               COALESCE(array_to_json(r.languages)::text, '[]'), r.region, r.photo_url
+        SELECT `+realtorColumns+`
         FROM realtors r
         LEFT JOIN real_estate_agencies a ON a.id = r.agency_id
         ORDER BY r.full_name
@@ -152,21 +178,10 @@ func (r *sqlRepository) featuredRealtors(ctx context.Context, limit int) ([]Real
 
 	list := make([]Realtor, 0)
 	for rows.Next() {
-		var realtor Realtor
-		var agencyName string
-		var email, phone, region, photo sql.NullString
-		var langsJSON string
-		if err := rows.Scan(&realtor.ID, &realtor.AgencyID, &agencyName, &realtor.FullName, &email, &phone, &langsJSON, &region, &photo); err != nil {
+		realtor, err := scanRealtor(rows)
+		if err != nil {
 			return nil, err
 		}
-		realtor.AgencyName = agencyName
-		realtor.Email = strings.TrimSpace(email.String)
-		realtor.Phone = strings.TrimSpace(phone.String)
-		realtor.Region = region.String
-		realtor.PhotoURL = photo.String
-		if err := json.Unmarshal([]byte(langsJSON), &realtor.Languages); err != nil {
-			realtor.Languages = nil
-		}
 		list = append(list, realtor)
 	}
 	if err := rows.Err(); err != nil {
@@ -175,4 +190,35 @@ func (r *sqlRepository) featuredRealtors(ctx context.Context, limit int) ([]Real
 	return list, nil
 }
 
+func (r *sqlRepository) getRealtor(ctx context.Context, id uuid.UUID) (Realtor, error) {
+	realtor, err := scanRealtor(r.db.QueryRowContext(ctx, `
+        SELECT `+realtorColumns+`
+        FROM realtors r
+        LEFT JOIN real_estate_agencies a ON a.id = r.agency_id
+        WHERE r.id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Realtor{}, ErrRealtorNotFound
+	}
+	return realtor, err
+}
+
+func scanRealtor(row interface {
+	Scan(dest ...any) error
+}) (Realtor, error) {
+	var realtor Realtor
+	var email, phone, region, photo sql.NullString
+	var langsJSON string
+	if err := row.Scan(&realtor.ID, &realtor.AgencyID, &realtor.AgencyName, &realtor.FullName, &email, &phone, &langsJSON, &region, &photo, &realtor.TimeZone); err != nil {
+		return Realtor{}, err
+	}
+	realtor.Email = strings.TrimSpace(email.String)
+	realtor.Phone = strings.TrimSpace(phone.String)
+	realtor.Region = region.String
+	realtor.PhotoURL = photo.String
+	if err := json.Unmarshal([]byte(langsJSON), &realtor.Languages); err != nil {
+		realtor.Languages = nil
+	}
+	return realtor, nil
+}
+
 var _ Service = (*sqlService)(nil)
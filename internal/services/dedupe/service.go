@@ -0,0 +1,341 @@
+package dedupe
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+const (
+	// DefaultPageSize and MaxPageSize bound review queue pages.
+	DefaultPageSize = 50
+	MaxPageSize     = 100
+)
+
+// Status tracks the review outcome of a probable duplicate pair.
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusMerged   Status = "merged"
+	StatusDistinct Status = "distinct"
+)
+
+// Valid reports whether the status is one of the supported review outcomes.
+func (s Status) Valid() bool {
+	switch s {
+	case StatusPending, StatusMerged, StatusDistinct:
+		return true
+	default:
+		return false
+	}
+}
+
+// Duplicate pairs two listings, usually from different agencies, that appear to
+// advertise the same property. ListingID is the canonical listing; once the pair is
+// merged, DuplicateID is collapsed out of search results in its favour.
+type Duplicate struct {
+	ID          uuid.UUID  `json:"id"`
+	ListingID   uuid.UUID  `json:"listing_id"`
+	DuplicateID uuid.UUID  `json:"duplicate_id"`
+	Score       float64    `json:"score"`
+	Reasons     []string   `json:"reasons"`
+	Status      Status     `json:"status"`
+	ResolvedBy  string     `json:"resolved_by,omitempty"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Input describes a pair flagged by the detector.
+type Input struct {
+	ListingID   uuid.UUID
+	DuplicateID uuid.UUID
+	Score       float64
+	Reasons     []string
+}
+
+// Filter narrows the pairs returned to reviewers. ListingID matches either side of
+// a pair.
+type Filter struct {
+	Status    Status
+	ListingID uuid.UUID
+	Limit     int
+	Offset    int
+}
+
+// Resolution records a reviewer's decision. KeepID picks the listing that stays
+// visible when merging and defaults to the pair's canonical listing.
+type Resolution struct {
+	Status     Status
+	KeepID     uuid.UUID
+	ActorEmail string
+}
+
+// Service stores probable duplicate listings and the reviewers' decisions on them.
+type Service interface {
+	List(ctx context.Context, filter Filter) ([]Duplicate, int, error)
+	Flag(ctx context.Context, input Input) (Duplicate, error)
+	Resolve(ctx context.Context, id uuid.UUID, resolution Resolution) (Duplicate, error)
+}
+
+// ErrNotFound is returned when a duplicate pair cannot be located.
+var ErrNotFound = errors.New("duplicate not found")
+
+// Involves reports whether the listing is either side of the pair.
+func (d Duplicate) Involves(id uuid.UUID) bool {
+	return d.ListingID == id || d.DuplicateID == id
+}
+
+// Matches reports whether the pair passes the filter, ignoring paging.
+func (f Filter) Matches(d Duplicate) bool {
+	if f.Status != "" && d.Status != f.Status {
+		return false
+	}
+	return f.ListingID == uuid.Nil || d.Involves(f.ListingID)
+}
+
+func normalizePage(filter Filter) Filter {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageSize
+	}
+	if filter.Limit > MaxPageSize {
+		filter.Limit = MaxPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return filter
+}
+
+func normalizeInput(input Input) (Input, error) {
+	if input.ListingID == uuid.Nil || input.DuplicateID == uuid.Nil {
+		return Input{}, errors.New("listing_id and duplicate_id are required")
+	}
+	if input.ListingID == input.DuplicateID {
+		return Input{}, errors.New("a listing cannot duplicate itself")
+	}
+	if input.Score < 0 || input.Score > 1 {
+		return Input{}, errors.New("score must be between 0 and 1")
+	}
+	reasons := make([]string, 0, len(input.Reasons))
+	for _, reason := range input.Reasons {
+		if reason = strings.TrimSpace(reason); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+	input.Reasons = reasons
+	return input, nil
+}
+
+func normalizeResolution(d Duplicate, res Resolution) (Resolution, error) {
+	switch res.Status {
+	case StatusMerged, StatusDistinct:
+	default:
+		return Resolution{}, errors.New("status must be merged or distinct")
+	}
+	res.ActorEmail = strings.TrimSpace(res.ActorEmail)
+	if res.ActorEmail == "" {
+		return Resolution{}, errors.New("actor email is required")
+	}
+	if res.KeepID == uuid.Nil {
+		res.KeepID = d.ListingID
+	}
+	if !d.Involves(res.KeepID) {
+		return Resolution{}, errors.New("keep_id must be one of the pair")
+	}
+	return res, nil
+}
+
+// applyResolution records the decision, swapping the pair when the reviewer keeps
+// the listing the detector had flagged as the duplicate.
+func applyResolution(d *Duplicate, res Resolution, now time.Time) {
+	if res.Status == StatusMerged && res.KeepID == d.DuplicateID {
+		d.ListingID, d.DuplicateID = d.DuplicateID, d.ListingID
+	}
+	d.Status = res.Status
+	d.ResolvedBy = res.ActorEmail
+	d.ResolvedAt = &now
+	d.UpdatedAt = now
+}
+
+// sortDuplicates orders the review queue by confidence, then by age.
+func sortDuplicates(pairs []Duplicate) {
+	sort.SliceStable(pairs, func(i, j int) bool {
+		a, b := pairs[i], pairs[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+}
+
+// InMemoryService keeps duplicate pairs in process memory.
+type InMemoryService struct {
+	mu         sync.RWMutex
+	duplicates []Duplicate
+	listings   listingservice.Service
+}
+
+// NewInMemoryService creates an empty review queue over the listing service.
+func NewInMemoryService(listings listingservice.Service) *InMemoryService {
+	return &InMemoryService{listings: listings}
+}
+
+// List returns a page of the pairs passing the filter, most confident first, along
+// with the total match count. Pairs involving deleted listings are dropped, as the
+// foreign keys do in PostgreSQL.
+func (s *InMemoryService) List(ctx context.Context, filter Filter) ([]Duplicate, int, error) {
+	filter = normalizePage(filter)
+	if err := s.prune(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Duplicate, 0, len(s.duplicates))
+	for _, d := range s.duplicates {
+		if filter.Matches(d) {
+			d.Reasons = append([]string(nil), d.Reasons...)
+			out = append(out, d)
+		}
+	}
+	sortDuplicates(out)
+	total := len(out)
+	if filter.Offset >= total {
+		return []Duplicate{}, total, nil
+	}
+	end := total
+	if filter.Offset+filter.Limit < end {
+		end = filter.Offset + filter.Limit
+	}
+	return out[filter.Offset:end], total, nil
+}
+
+// Flag records a probable duplicate pair. A pending pair already flagged in either
+// direction is refreshed; pairs a reviewer has resolved are returned as they are so
+// the detector never reopens them.
+func (s *InMemoryService) Flag(ctx context.Context, input Input) (Duplicate, error) {
+	input, err := normalizeInput(input)
+	if err != nil {
+		return Duplicate{}, err
+	}
+	for _, id := range []uuid.UUID{input.ListingID, input.DuplicateID} {
+		if _, err := s.listings.Get(ctx, id); err != nil {
+			return Duplicate{}, err
+		}
+	}
+	now := time.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for idx := range s.duplicates {
+		d := &s.duplicates[idx]
+		if !d.Involves(input.ListingID) || !d.Involves(input.DuplicateID) {
+			continue
+		}
+		if d.Status == StatusPending {
+			d.ListingID, d.DuplicateID = input.ListingID, input.DuplicateID
+			d.Score = input.Score
+			d.Reasons = input.Reasons
+			d.UpdatedAt = now
+		}
+		return *d, nil
+	}
+	d := Duplicate{
+		ID:          uuid.New(),
+		ListingID:   input.ListingID,
+		DuplicateID: input.DuplicateID,
+		Score:       input.Score,
+		Reasons:     input.Reasons,
+		Status:      StatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	s.duplicates = append(s.duplicates, d)
+	return d, nil
+}
+
+// Resolve merges a pair or marks it distinct.
+func (s *InMemoryService) Resolve(_ context.Context, id uuid.UUID, resolution Resolution) (Duplicate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for idx := range s.duplicates {
+		if s.duplicates[idx].ID != id {
+			continue
+		}
+		res, err := normalizeResolution(s.duplicates[idx], resolution)
+		if err != nil {
+			return Duplicate{}, err
+		}
+		applyResolution(&s.duplicates[idx], res, time.Now().UTC())
+		return s.duplicates[idx], nil
+	}
+	return Duplicate{}, ErrNotFound
+}
+
+// Merged maps every listing a reviewer merged away to the listing kept in its
+// place, so the in-memory listing service can collapse them from results.
+func (s *InMemoryService) Merged(_ context.Context) (map[uuid.UUID]uuid.UUID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make(map[uuid.UUID]uuid.UUID)
+	for _, d := range s.duplicates {
+		if d.Status == StatusMerged {
+			out[d.DuplicateID] = d.ListingID
+		}
+	}
+	return out, nil
+}
+
+// prune drops pairs whose listings no longer exist.
+func (s *InMemoryService) prune(ctx context.Context) error {
+	s.mu.RLock()
+	ids := make(map[uuid.UUID]struct{}, 2*len(s.duplicates))
+	for _, d := range s.duplicates {
+		ids[d.ListingID] = struct{}{}
+		ids[d.DuplicateID] = struct{}{}
+	}
+	s.mu.RUnlock()
+
+	gone := make(map[uuid.UUID]struct{})
+	for id := range ids {
+		if _, err := s.listings.Get(ctx, id); errors.Is(err, listingservice.ErrNotFound) {
+			gone[id] = struct{}{}
+		} else if err != nil {
+			return err
+		}
+	}
+	if len(gone) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	kept := s.duplicates[:0]
+	for _, d := range s.duplicates {
+		_, listingGone := gone[d.ListingID]
+		_, duplicateGone := gone[d.DuplicateID]
+		if !listingGone && !duplicateGone {
+			kept = append(kept, d)
+		}
+	}
+	s.duplicates = kept
+	return nil
+}
+
+var (
+	_ Service                    = (*InMemoryService)(nil)
+	_ listingservice.MergeSource = (*InMemoryService)(nil)
+)
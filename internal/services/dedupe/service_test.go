@@ -0,0 +1,99 @@
+package dedupe
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+func TestInMemoryServiceReview(t *testing.T) {
+	listings := listingservice.NewInMemoryService()
+	service := NewInMemoryService(listings)
+	listings.CollapseMerged(service)
+	ctx := context.Background()
+
+	kyoto, err := listings.GetBySlug(ctx, "kyoto-machiya-boutique-hotel")
+	if err != nil {
+		t.Fatalf("GetBySlug() error = %v", err)
+	}
+	stockholm, err := listings.GetBySlug(ctx, "ostermalm-art-nouveau")
+	if err != nil {
+		t.Fatalf("GetBySlug() error = %v", err)
+	}
+	kyotoAgency := uuid.New()
+	if _, err := listings.Update(ctx, kyoto.ID, listingservice.UpdateInput{AgencyID: &kyotoAgency}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	pair, err := service.Flag(ctx, Input{ListingID: kyoto.ID, DuplicateID: stockholm.ID, Score: 0.8, Reasons: []string{"area", " "}})
+	if err != nil {
+		t.Fatalf("Flag() error = %v", err)
+	}
+	if pair.Status != StatusPending || len(pair.Reasons) != 1 {
+		t.Fatalf("flagged pair = %+v, want pending with one reason", pair)
+	}
+	again, err := service.Flag(ctx, Input{ListingID: stockholm.ID, DuplicateID: kyoto.ID, Score: 0.9})
+	if err != nil {
+		t.Fatalf("Flag(reversed) error = %v", err)
+	}
+	if again.ID != pair.ID || again.Score != 0.9 {
+		t.Errorf("reflagged pair = %+v, want the pending pair refreshed", again)
+	}
+	if _, err := service.Flag(ctx, Input{ListingID: kyoto.ID, DuplicateID: kyoto.ID}); err == nil {
+		t.Error("Flag(self) succeeded, want validation error")
+	}
+
+	visible := func(viewer listingservice.Viewer) bool {
+		listings, _, err := listings.List(ctx, listingservice.ListFilter{CollapseDuplicates: true, Viewer: viewer, Limit: listingservice.MaxPageSize})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		for _, l := range listings {
+			if l.ID == kyoto.ID {
+				return true
+			}
+		}
+		return false
+	}
+
+	merged, err := service.Resolve(ctx, pair.ID, Resolution{Status: StatusMerged, KeepID: stockholm.ID, ActorEmail: "editor@example.com"})
+	if err != nil {
+		t.Fatalf("Resolve(merged) error = %v", err)
+	}
+	if merged.ListingID != stockholm.ID || merged.DuplicateID != kyoto.ID || merged.ResolvedAt == nil {
+		t.Fatalf("merged pair = %+v, want stockholm kept", merged)
+	}
+	if visible(listingservice.Viewer{}) {
+		t.Error("merged duplicate still listed for anonymous viewers")
+	}
+	if !visible(listingservice.Viewer{AgencyIDs: []uuid.UUID{kyotoAgency}}) {
+		t.Error("merged duplicate hidden from its own agency")
+	}
+	if reflagged, _ := service.Flag(ctx, Input{ListingID: kyoto.ID, DuplicateID: stockholm.ID, Score: 0.7}); reflagged.Status != StatusMerged {
+		t.Errorf("reflagging a merged pair reopened it: %+v", reflagged)
+	}
+	if page, total, err := service.List(ctx, Filter{Limit: 1, Offset: 1}); err != nil || total != 1 || len(page) != 0 {
+		t.Errorf("List(offset past the end) = %d pairs of %d, %v; want an empty page of 1", len(page), total, err)
+	}
+
+	if _, err := service.Resolve(ctx, pair.ID, Resolution{Status: StatusDistinct, ActorEmail: "editor@example.com"}); err != nil {
+		t.Fatalf("Resolve(distinct) error = %v", err)
+	}
+	if !visible(listingservice.Viewer{}) {
+		t.Error("listing marked distinct is still collapsed")
+	}
+	if _, err := service.Resolve(ctx, uuid.New(), Resolution{Status: StatusDistinct, ActorEmail: "editor@example.com"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Resolve(unknown) error = %v, want ErrNotFound", err)
+	}
+
+	if err := listings.Delete(ctx, kyoto.ID); err != nil {
+		t.Fatalf("listing Delete() error = %v", err)
+	}
+	if pairs, total, _ := service.List(ctx, Filter{}); total != 0 || len(pairs) != 0 {
+		t.Errorf("pairs after listing delete = %d, want 0", total)
+	}
+}
@@ -0,0 +1,159 @@
+package dedupe
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+const duplicateColumns = `id, listing_id, duplicate_id, score, COALESCE(array_to_json(reasons)::text, '[]'), status, resolved_by, resolved_at, created_at, updated_at`
+
+type sqlService struct {
+	db       *sql.DB
+	listings listingservice.Service
+}
+
+// NewSQLService builds a duplicate review service backed by PostgreSQL.
+func NewSQLService(db *sql.DB, listings listingservice.Service) (Service, error) {
+	return &sqlService{db: db, listings: listings}, nil
+}
+
+func (s *sqlService) List(ctx context.Context, filter Filter) ([]Duplicate, int, error) {
+	filter = normalizePage(filter)
+	var (
+		clauses []string
+		args    []any
+	)
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		clauses = append(clauses, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.ListingID != uuid.Nil {
+		args = append(args, filter.ListingID)
+		clauses = append(clauses, fmt.Sprintf("(listing_id = $%[1]d OR duplicate_id = $%[1]d)", len(args)))
+	}
+	where := ""
+	if len(clauses) > 0 {
+		where = "WHERE " + strings.Join(clauses, " AND ")
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM listing_duplicates `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+        SELECT `+duplicateColumns+`
+        FROM listing_duplicates
+        %s
+        ORDER BY score DESC, created_at, id
+        LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	pairs := make([]Duplicate, 0)
+	for rows.Next() {
+		d, err := scanDuplicate(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		pairs = append(pairs, d)
+	}
+	return pairs, total, rows.Err()
+}
+
+func (s *sqlService) Flag(ctx context.Context, input Input) (Duplicate, error) {
+	input, err := normalizeInput(input)
+	if err != nil {
+		return Duplicate{}, err
+	}
+	for _, id := range []uuid.UUID{input.ListingID, input.DuplicateID} {
+		if _, err := s.listings.Get(ctx, id); err != nil {
+			return Duplicate{}, err
+		}
+	}
+	row := s.db.QueryRowContext(ctx, `
+        INSERT INTO listing_duplicates (listing_id, duplicate_id, score, reasons)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT ((LEAST(listing_id, duplicate_id)), (GREATEST(listing_id, duplicate_id))) DO UPDATE
+        SET listing_id = EXCLUDED.listing_id, duplicate_id = EXCLUDED.duplicate_id,
+            score = EXCLUDED.score, reasons = EXCLUDED.reasons, updated_at = NOW()
+        WHERE listing_duplicates.status = 'pending'
+        RETURNING `+duplicateColumns,
+		input.ListingID, input.DuplicateID, input.Score, input.Reasons)
+	d, err := scanDuplicate(row)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return d, err
+	}
+	// The pair was already resolved; leave the reviewer's decision alone.
+	row = s.db.QueryRowContext(ctx, `
+        SELECT `+duplicateColumns+`
+        FROM listing_duplicates
+        WHERE LEAST(listing_id, duplicate_id) = LEAST($1::uuid, $2::uuid)
+          AND GREATEST(listing_id, duplicate_id) = GREATEST($1::uuid, $2::uuid)`,
+		input.ListingID, input.DuplicateID)
+	return scanDuplicate(row)
+}
+
+func (s *sqlService) Resolve(ctx context.Context, id uuid.UUID, resolution Resolution) (Duplicate, error) {
+	d, err := scanDuplicate(s.db.QueryRowContext(ctx, `
+        SELECT `+duplicateColumns+`
+        FROM listing_duplicates
+        WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Duplicate{}, ErrNotFound
+	}
+	if err != nil {
+		return Duplicate{}, err
+	}
+	res, err := normalizeResolution(d, resolution)
+	if err != nil {
+		return Duplicate{}, err
+	}
+	applyResolution(&d, res, time.Now().UTC())
+
+	row := s.db.QueryRowContext(ctx, `
+        UPDATE listing_duplicates
+        SET listing_id = $2, duplicate_id = $3, status = $4, resolved_by = $5, resolved_at = $6, updated_at = $6
+        WHERE id = $1
+        RETURNING `+duplicateColumns,
+		id, d.ListingID, d.DuplicateID, string(d.Status), d.ResolvedBy, *d.ResolvedAt)
+	updated, err := scanDuplicate(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Duplicate{}, ErrNotFound
+	}
+	return updated, err
+}
+
+func scanDuplicate(row interface {
+	Scan(dest ...any) error
+}) (Duplicate, error) {
+	var (
+		d           Duplicate
+		reasonsJSON string
+		status      string
+		resolvedAt  sql.NullTime
+	)
+	if err := row.Scan(&d.ID, &d.ListingID, &d.DuplicateID, &d.Score, &reasonsJSON, &status, &d.ResolvedBy, &resolvedAt, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return Duplicate{}, err
+	}
+	if err := json.Unmarshal([]byte(reasonsJSON), &d.Reasons); err != nil {
+		return Duplicate{}, err
+	}
+	d.Status = Status(status)
+	if resolvedAt.Valid {
+		at := resolvedAt.Time
+		d.ResolvedAt = &at
+	}
+	return d, nil
+}
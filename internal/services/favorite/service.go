@@ -0,0 +1,224 @@
+package favorite
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"shanraq.com/internal/auth"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+// Snapshot preserves how a listing looked when it was saved, so the watchlist
+// still renders after the listing is edited, withdrawn or deleted.
+type Snapshot struct {
+	Title      string                `json:"title"`
+	Slug       string                `json:"slug"`
+	Status     listingservice.Status `json:"status"`
+	Country    string                `json:"country"`
+	City       string                `json:"city"`
+	Price      float64               `json:"price"`
+	Currency   string                `json:"currency"`
+	ImageURL   string                `json:"image_url"`
+	DetailsURL string                `json:"details_url"`
+	AgencyID   uuid.UUID             `json:"agency_id"`
+}
+
+// Favorite is a listing on a user's watchlist.
+type Favorite struct {
+	ListingID uuid.UUID `json:"listing_id"`
+	OwnerID   string    `json:"owner_id"`
+	Snapshot  Snapshot  `json:"snapshot"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListingWatchers pairs a listing with the number of users watching it.
+type ListingWatchers struct {
+	ListingID uuid.UUID `json:"listing_id"`
+	Title     string    `json:"title"`
+	Watchers  int       `json:"watchers"`
+}
+
+// AgencyStats summarises watchlist activity on an agency's listings.
+type AgencyStats struct {
+	AgencyID        uuid.UUID         `json:"agency_id"`
+	Watchers        int               `json:"watchers"`
+	WatchedListings int               `json:"watched_listings"`
+	TopListings     []ListingWatchers `json:"top_listings"`
+}
+
+// Service manages per-user listing watchlists.
+type Service interface {
+	List(ctx context.Context, identity auth.Identity) ([]Favorite, error)
+	Add(ctx context.Context, identity auth.Identity, listing listingservice.Listing) (Favorite, bool, error)
+	Remove(ctx context.Context, identity auth.Identity, listingID uuid.UUID) error
+	Has(ctx context.Context, identity auth.Identity, listingID uuid.UUID) (bool, error)
+	WatcherCounts(ctx context.Context, listingIDs []uuid.UUID) (map[uuid.UUID]int, error)
+	AgencyStats(ctx context.Context, agencyIDs []uuid.UUID, top int) (AgencyStats, error)
+}
+
+// ErrNotFound is returned when the listing is not on the user's watchlist.
+var ErrNotFound = errors.New("favorite not found")
+
+// SnapshotOf captures the watchlist snapshot of a listing.
+func SnapshotOf(l listingservice.Listing) Snapshot {
+	return Snapshot{
+		Title:      l.Title,
+		Slug:       l.Slug,
+		Status:     l.Status,
+		Country:    l.Country,
+		City:       l.City,
+		Price:      l.Price,
+		Currency:   l.Currency,
+		ImageURL:   l.ImageURL,
+		DetailsURL: l.DetailsURL,
+		AgencyID:   l.AgencyID,
+	}
+}
+
+// InMemoryService keeps watchlists in process memory.
+type InMemoryService struct {
+	mu        sync.RWMutex
+	favorites []Favorite
+}
+
+// NewInMemoryService creates an empty watchlist store.
+func NewInMemoryService() *InMemoryService {
+	return &InMemoryService{}
+}
+
+func (s *InMemoryService) List(_ context.Context, identity auth.Identity) ([]Favorite, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Favorite, 0)
+	for _, f := range s.favorites {
+		if f.OwnerID == identity.Key() {
+			out = append(out, f)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out, nil
+}
+
+// Add saves the listing to the watchlist. Saving it again refreshes the snapshot and
+// reports created as false.
+func (s *InMemoryService) Add(_ context.Context, identity auth.Identity, listing listingservice.Listing) (Favorite, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if idx := s.indexOf(identity, listing.ID); idx >= 0 {
+		s.favorites[idx].Snapshot = SnapshotOf(listing)
+		return s.favorites[idx], false, nil
+	}
+	favorite := Favorite{
+		ListingID: listing.ID,
+		OwnerID:   identity.Key(),
+		Snapshot:  SnapshotOf(listing),
+		CreatedAt: time.Now().UTC(),
+	}
+	s.favorites = append(s.favorites, favorite)
+	return favorite, true, nil
+}
+
+func (s *InMemoryService) Remove(_ context.Context, identity auth.Identity, listingID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := s.indexOf(identity, listingID)
+	if idx < 0 {
+		return ErrNotFound
+	}
+	s.favorites = append(s.favorites[:idx], s.favorites[idx+1:]...)
+	return nil
+}
+
+func (s *InMemoryService) Has(_ context.Context, identity auth.Identity, listingID uuid.UUID) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.indexOf(identity, listingID) >= 0, nil
+}
+
+// WatcherCounts returns how many users watch each of the listings. Listings nobody
+// watches are omitted.
+func (s *InMemoryService) WatcherCounts(_ context.Context, listingIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	wanted := make(map[uuid.UUID]struct{}, len(listingIDs))
+	for _, id := range listingIDs {
+		wanted[id] = struct{}{}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[uuid.UUID]int)
+	for _, f := range s.favorites {
+		if _, ok := wanted[f.ListingID]; ok {
+			counts[f.ListingID]++
+		}
+	}
+	return counts, nil
+}
+
+// AgencyStats aggregates watchers across the agencies' listings and returns the top
+// most-watched ones.
+func (s *InMemoryService) AgencyStats(_ context.Context, agencyIDs []uuid.UUID, top int) (AgencyStats, error) {
+	wanted := make(map[uuid.UUID]struct{}, len(agencyIDs))
+	for _, id := range agencyIDs {
+		wanted[id] = struct{}{}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	byListing := make(map[uuid.UUID]*ListingWatchers)
+	stats := AgencyStats{}
+	if len(agencyIDs) == 1 {
+		stats.AgencyID = agencyIDs[0]
+	}
+	for _, f := range s.favorites {
+		if _, ok := wanted[f.Snapshot.AgencyID]; !ok || f.Snapshot.AgencyID == uuid.Nil {
+			continue
+		}
+		stats.Watchers++
+		entry, ok := byListing[f.ListingID]
+		if !ok {
+			entry = &ListingWatchers{ListingID: f.ListingID, Title: f.Snapshot.Title}
+			byListing[f.ListingID] = entry
+		}
+		entry.Watchers++
+	}
+	stats.WatchedListings = len(byListing)
+	stats.TopListings = make([]ListingWatchers, 0, len(byListing))
+	for _, entry := range byListing {
+		stats.TopListings = append(stats.TopListings, *entry)
+	}
+	sortTopListings(stats.TopListings)
+	if top > 0 && len(stats.TopListings) > top {
+		stats.TopListings = stats.TopListings[:top]
+	}
+	return stats, nil
+}
+
+func (s *InMemoryService) indexOf(identity auth.Identity, listingID uuid.UUID) int {
+	for idx, f := range s.favorites {
+		if f.ListingID == listingID && f.OwnerID == identity.Key() {
+			return idx
+		}
+	}
+	return -1
+}
+
+func sortTopListings(entries []ListingWatchers) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Watchers != entries[j].Watchers {
+			return entries[i].Watchers > entries[j].Watchers
+		}
+		return entries[i].Title < entries[j].Title
+	})
+}
+
+var _ Service = (*InMemoryService)(nil)
@@ -0,0 +1,82 @@
+package favorite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"shanraq.com/internal/auth"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+func TestInMemoryServiceWatchlist(t *testing.T) {
+	service := NewInMemoryService()
+	ctx := context.Background()
+	buyer := auth.Identity{Subject: "buyer-1", Email: "buyer@example.com"}
+	other := auth.Identity{Subject: "buyer-2", Email: "other@example.com"}
+	listing := listingservice.Listing{ID: uuid.New(), Title: "Marina loft", Price: 900000, Currency: "AED"}
+
+	if _, created, err := service.Add(ctx, buyer, listing); err != nil || !created {
+		t.Fatalf("Add() created = %v, err = %v, want new favorite", created, err)
+	}
+	listing.Price = 850000
+	favorite, created, err := service.Add(ctx, buyer, listing)
+	if err != nil || created {
+		t.Fatalf("second Add() created = %v, err = %v, want idempotent save", created, err)
+	}
+	if favorite.Snapshot.Price != 850000 {
+		t.Errorf("snapshot price = %v, want refreshed 850000", favorite.Snapshot.Price)
+	}
+	if _, _, err := service.Add(ctx, other, listing); err != nil {
+		t.Fatalf("Add() by other user error = %v", err)
+	}
+
+	list, _ := service.List(ctx, buyer)
+	if len(list) != 1 || list[0].ListingID != listing.ID {
+		t.Fatalf("List() = %+v, want the single saved listing", list)
+	}
+	counts, _ := service.WatcherCounts(ctx, []uuid.UUID{listing.ID, uuid.New()})
+	if counts[listing.ID] != 2 || len(counts) != 1 {
+		t.Errorf("WatcherCounts() = %v, want 2 watchers on one listing", counts)
+	}
+
+	if err := service.Remove(ctx, buyer, listing.ID); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := service.Remove(ctx, buyer, listing.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Remove() error = %v, want ErrNotFound", err)
+	}
+	if has, _ := service.Has(ctx, buyer, listing.ID); has {
+		t.Error("Has() = true after removal")
+	}
+}
+
+func TestInMemoryServiceAgencyStats(t *testing.T) {
+	service := NewInMemoryService()
+	ctx := context.Background()
+	agencyID := uuid.New()
+	popular := listingservice.Listing{ID: uuid.New(), Title: "Popular villa", AgencyID: agencyID}
+	quiet := listingservice.Listing{ID: uuid.New(), Title: "Quiet flat", AgencyID: agencyID}
+	foreign := listingservice.Listing{ID: uuid.New(), Title: "Elsewhere", AgencyID: uuid.New()}
+
+	for i, identity := range []auth.Identity{{Subject: "a"}, {Subject: "b"}, {Subject: "c"}} {
+		service.Add(ctx, identity, popular)
+		service.Add(ctx, identity, foreign)
+		if i == 0 {
+			service.Add(ctx, identity, quiet)
+		}
+	}
+
+	stats, err := service.AgencyStats(ctx, []uuid.UUID{agencyID}, 1)
+	if err != nil {
+		t.Fatalf("AgencyStats() error = %v", err)
+	}
+	if stats.AgencyID != agencyID || stats.Watchers != 4 || stats.WatchedListings != 2 {
+		t.Errorf("AgencyStats() = %+v, want 4 watchers across 2 listings", stats)
+	}
+	if len(stats.TopListings) != 1 || stats.TopListings[0].ListingID != popular.ID || stats.TopListings[0].Watchers != 3 {
+		t.Errorf("TopListings = %+v, want the popular villa with 3 watchers", stats.TopListings)
+	}
+}
@@ -0,0 +1,189 @@
+package favorite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"shanraq.com/internal/auth"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+type sqlService struct {
+	db *sql.DB
+}
+
+// NewSQLService builds a watchlist service backed by PostgreSQL.
+func NewSQLService(db *sql.DB) (Service, error) {
+	return &sqlService{db: db}, nil
+}
+
+func (s *sqlService) List(ctx context.Context, identity auth.Identity) ([]Favorite, error) {
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT listing_id, owner_id, snapshot, created_at
+        FROM listing_favorites
+        WHERE owner_id = $1
+        ORDER BY created_at DESC`, identity.Key())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]Favorite, 0)
+	for rows.Next() {
+		f, err := scanFavorite(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *sqlService) Add(ctx context.Context, identity auth.Identity, listing listingservice.Listing) (Favorite, bool, error) {
+	snapshot, err := json.Marshal(SnapshotOf(listing))
+	if err != nil {
+		return Favorite{}, false, err
+	}
+	var created bool
+	row := s.db.QueryRowContext(ctx, `
+        INSERT INTO listing_favorites (owner_id, listing_id, snapshot)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (owner_id, listing_id) DO UPDATE SET snapshot = EXCLUDED.snapshot
+        RETURNING listing_id, owner_id, snapshot, created_at, (xmax = 0)`, identity.Key(), listing.ID, snapshot)
+	f, err := scanFavorite(rowWithCreated{row, &created})
+	if err != nil {
+		return Favorite{}, false, err
+	}
+	return f, created, nil
+}
+
+func (s *sqlService) Remove(ctx context.Context, identity auth.Identity, listingID uuid.UUID) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM listing_favorites WHERE owner_id = $1 AND listing_id = $2`, identity.Key(), listingID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *sqlService) Has(ctx context.Context, identity auth.Identity, listingID uuid.UUID) (bool, error) {
+	var exists bool
+	err := s.db.QueryRowContext(ctx, `
+        SELECT EXISTS (SELECT 1 FROM listing_favorites WHERE owner_id = $1 AND listing_id = $2)`,
+		identity.Key(), listingID).Scan(&exists)
+	return exists, err
+}
+
+func (s *sqlService) WatcherCounts(ctx context.Context, listingIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int)
+	if len(listingIDs) == 0 {
+		return counts, nil
+	}
+	ids := make([]string, 0, len(listingIDs))
+	for _, id := range listingIDs {
+		ids = append(ids, id.String())
+	}
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT listing_id, COUNT(*)
+        FROM listing_favorites
+        WHERE listing_id = ANY($1::uuid[])
+        GROUP BY listing_id`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id    uuid.UUID
+			count int
+		)
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, err
+		}
+		counts[id] = count
+	}
+	return counts, rows.Err()
+}
+
+func (s *sqlService) AgencyStats(ctx context.Context, agencyIDs []uuid.UUID, top int) (AgencyStats, error) {
+	stats := AgencyStats{TopListings: make([]ListingWatchers, 0)}
+	if len(agencyIDs) == 1 {
+		stats.AgencyID = agencyIDs[0]
+	}
+	if len(agencyIDs) == 0 {
+		return stats, nil
+	}
+	ids := make([]string, 0, len(agencyIDs))
+	for _, id := range agencyIDs {
+		ids = append(ids, id.String())
+	}
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT l.id, l.title, COUNT(*)
+        FROM listing_favorites f
+        JOIN property_listings l ON l.id = f.listing_id
+        WHERE l.agency_id = ANY($1::uuid[])
+        GROUP BY l.id, l.title`, ids)
+	if err != nil {
+		return AgencyStats{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry ListingWatchers
+		if err := rows.Scan(&entry.ListingID, &entry.Title, &entry.Watchers); err != nil {
+			return AgencyStats{}, err
+		}
+		stats.Watchers += entry.Watchers
+		stats.TopListings = append(stats.TopListings, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return AgencyStats{}, err
+	}
+	stats.WatchedListings = len(stats.TopListings)
+	sortTopListings(stats.TopListings)
+	if top > 0 && len(stats.TopListings) > top {
+		stats.TopListings = stats.TopListings[:top]
+	}
+	return stats, nil
+}
+
+// rowWithCreated scans the trailing "inserted" flag returned by Add.
+type rowWithCreated struct {
+	row interface {
+		Scan(dest ...any) error
+	}
+	created *bool
+}
+
+func (r rowWithCreated) Scan(dest ...any) error {
+	return r.row.Scan(append(dest, r.created)...)
+}
+
+func scanFavorite(row interface {
+	Scan(dest ...any) error
+}) (Favorite, error) {
+	var (
+		f        Favorite
+		snapshot []byte
+	)
+	if err := row.Scan(&f.ListingID, &f.OwnerID, &snapshot, &f.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Favorite{}, ErrNotFound
+		}
+		return Favorite{}, err
+	}
+	if err := json.Unmarshal(snapshot, &f.Snapshot); err != nil {
+		return Favorite{}, err
+	}
+	return f, nil
+}
@@ -0,0 +1,168 @@
+package finance
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Wildcard is the country code of the row that applies to unlisted markets.
+const Wildcard = "*"
+
+// Defaults are the typical mortgage terms of one market.
+type Defaults struct {
+	Country   string  `json:"country"`
+	MaxLTV    float64 `json:"max_ltv"`
+	Rate      float64 `json:"rate"`
+	TermYears int     `json:"term_years"`
+	MaxDTI    float64 `json:"max_dti"`
+	Method    Method  `json:"method"`
+}
+
+// Fallback is used for unlisted markets when the defaults file has no wildcard row.
+var Fallback = Defaults{Country: Wildcard, MaxLTV: 0.8, Rate: 5, TermYears: 25, MaxDTI: 0.35, Method: MethodAnnuity}
+
+// Table is an immutable snapshot of per-country defaults.
+type Table struct {
+	rows map[string]Defaults
+}
+
+// NewTable builds a table from rows; later rows for the same country win.
+func NewTable(rows []Defaults) Table {
+	table := Table{rows: make(map[string]Defaults, len(rows))}
+	for _, row := range rows {
+		row.Country = strings.ToUpper(strings.TrimSpace(row.Country))
+		table.rows[row.Country] = row
+	}
+	return table
+}
+
+// Lookup returns the defaults of the country, falling back to the wildcard row.
+// The second result reports whether the country has a row of its own.
+func (t Table) Lookup(country string) (Defaults, bool) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if row, ok := t.rows[country]; ok && country != Wildcard {
+		return row, true
+	}
+	row, ok := t.rows[Wildcard]
+	if !ok {
+		row = Fallback
+	}
+	if country != "" {
+		row.Country = country
+	}
+	return row, false
+}
+
+// All returns the rows ordered by country, wildcard first.
+func (t Table) All() []Defaults {
+	rows := make([]Defaults, 0, len(t.rows))
+	for _, row := range t.rows {
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Country == Wildcard || rows[j].Country == Wildcard {
+			return rows[i].Country == Wildcard
+		}
+		return rows[i].Country < rows[j].Country
+	})
+	return rows
+}
+
+// LoadFile reads defaults from a CSV file.
+func LoadFile(path string) (Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Table{}, err
+	}
+	defer f.Close()
+
+	rows, err := ParseCSV(f)
+	if err != nil {
+		return Table{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return NewTable(rows), nil
+}
+
+// ParseCSV reads a "country,max_ltv,rate,term_years,max_dti,method" table. Columns
+// may come in any order; lines starting with # are comments.
+func ParseCSV(r io.Reader) ([]Defaults, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read csv: %w", err)
+	}
+	if len(records) < 2 {
+		return nil, errors.New("defaults file has no data rows")
+	}
+
+	columns := map[string]int{}
+	for i, h := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, name := range []string{"country", "max_ltv", "rate", "term_years", "max_dti", "method"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("defaults file is missing the %s column", name)
+		}
+	}
+
+	rows := make([]Defaults, 0, len(records)-1)
+	for n, record := range records[1:] {
+		line := n + 2
+		field := func(name string) string { return strings.TrimSpace(record[columns[name]]) }
+		number := func(name string) (float64, error) {
+			v, err := strconv.ParseFloat(field(name), 64)
+			if err != nil {
+				return 0, fmt.Errorf("line %d: invalid %s %q", line, name, field(name))
+			}
+			return v, nil
+		}
+		row := Defaults{Country: strings.ToUpper(field("country")), Method: Method(strings.ToLower(field("method")))}
+		if row.MaxLTV, err = number("max_ltv"); err != nil {
+			return nil, err
+		}
+		if row.Rate, err = number("rate"); err != nil {
+			return nil, err
+		}
+		if row.MaxDTI, err = number("max_dti"); err != nil {
+			return nil, err
+		}
+		if row.TermYears, err = strconv.Atoi(field("term_years")); err != nil {
+			return nil, fmt.Errorf("line %d: invalid term_years %q", line, field("term_years"))
+		}
+		if err := row.validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (d Defaults) validate() error {
+	if d.Country != Wildcard && (len(d.Country) != 2 || strings.Trim(d.Country, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "") {
+		return fmt.Errorf("country %q must be an ISO 3166 alpha-2 code or %q", d.Country, Wildcard)
+	}
+	if d.MaxLTV <= 0 || d.MaxLTV > 1 {
+		return errors.New("max_ltv must be in (0, 1]")
+	}
+	if d.MaxDTI <= 0 || d.MaxDTI > 1 {
+		return errors.New("max_dti must be in (0, 1]")
+	}
+	if d.Rate < 0 || d.Rate > MaxRate {
+		return fmt.Errorf("rate must be between 0 and %d", MaxRate)
+	}
+	if d.TermYears < 1 || d.TermYears > MaxTermYears {
+		return fmt.Errorf("term_years must be between 1 and %d", MaxTermYears)
+	}
+	if !d.Method.Valid() {
+		return errors.New("method must be annuity or linear")
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+package finance
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// MaxRate caps the nominal annual rate in percent.
+	MaxRate = 30
+	// MaxTermYears caps the loan term.
+	MaxTermYears = 50
+)
+
+// Method is how a loan is repaid.
+type Method string
+
+const (
+	// MethodAnnuity repays with equal monthly payments.
+	MethodAnnuity Method = "annuity"
+	// MethodLinear repays equal principal each month, so payments fall over time.
+	MethodLinear Method = "linear"
+)
+
+// Valid reports whether the method is supported.
+func (m Method) Valid() bool {
+	return m == MethodAnnuity || m == MethodLinear
+}
+
+// MortgageInput describes a loan. Nil fields and an empty method take the
+// country's defaults; DownPayment and LTV are mutually exclusive.
+type MortgageInput struct {
+	Country     string
+	Currency    string
+	Price       float64
+	DownPayment *float64
+	LTV         *float64
+	Rate        *float64
+	TermYears   *int
+	Method      Method
+}
+
+// Installment is one monthly payment of the schedule.
+type Installment struct {
+	Month     int     `json:"month"`
+	Payment   float64 `json:"payment"`
+	Principal float64 `json:"principal"`
+	Interest  float64 `json:"interest"`
+	Balance   float64 `json:"balance"`
+}
+
+// Mortgage is a calculated loan with its amortization schedule. MonthlyPayment is
+// the first installment, which for linear loans is also the largest.
+type Mortgage struct {
+	Country        string        `json:"country"`
+	Currency       string        `json:"currency,omitempty"`
+	Method         Method        `json:"method"`
+	Price          float64       `json:"price"`
+	DownPayment    float64       `json:"down_payment"`
+	Principal      float64       `json:"principal"`
+	LTV            float64       `json:"ltv"`
+	MaxLTV         float64       `json:"max_ltv"`
+	Rate           float64       `json:"rate"`
+	TermYears      int           `json:"term_years"`
+	Payments       int           `json:"payments"`
+	MonthlyPayment float64       `json:"monthly_payment"`
+	FinalPayment   float64       `json:"final_payment"`
+	TotalInterest  float64       `json:"total_interest"`
+	TotalPaid      float64       `json:"total_paid"`
+	Schedule       []Installment `json:"schedule,omitempty"`
+}
+
+// AffordabilityInput describes a buyer's finances. Nil fields and an empty method
+// take the country's defaults.
+type AffordabilityInput struct {
+	Country       string
+	Currency      string
+	MonthlyIncome float64
+	MonthlyDebts  float64
+	DownPayment   float64
+	Rate          *float64
+	TermYears     *int
+	MaxDTI        *float64
+	Method        Method
+}
+
+// Affordability is the most a buyer can borrow and pay. LimitedBy tells whether
+// the income ("income") or the down payment against the maximum loan-to-value
+// ("down_payment") sets the ceiling.
+type Affordability struct {
+	Country        string  `json:"country"`
+	Currency       string  `json:"currency,omitempty"`
+	Method         Method  `json:"method"`
+	Rate           float64 `json:"rate"`
+	TermYears      int     `json:"term_years"`
+	MaxDTI         float64 `json:"max_dti"`
+	MaxLTV         float64 `json:"max_ltv"`
+	MonthlyIncome  float64 `json:"monthly_income"`
+	MonthlyDebts   float64 `json:"monthly_debts"`
+	DownPayment    float64 `json:"down_payment"`
+	PaymentBudget  float64 `json:"payment_budget"`
+	MonthlyPayment float64 `json:"monthly_payment"`
+	MaxLoan        float64 `json:"max_loan"`
+	MaxPrice       float64 `json:"max_price"`
+	LimitedBy      string  `json:"limited_by"`
+}
+
+type loanTerms struct {
+	rate   float64
+	years  int
+	method Method
+}
+
+func resolveTerms(d Defaults, rate *float64, years *int, method Method) (loanTerms, error) {
+	terms := loanTerms{rate: d.Rate, years: d.TermYears, method: d.Method}
+	if rate != nil {
+		terms.rate = *rate
+	}
+	if years != nil {
+		terms.years = *years
+	}
+	if method != "" {
+		terms.method = Method(strings.ToLower(string(method)))
+	}
+	if terms.rate < 0 || terms.rate > MaxRate {
+		return loanTerms{}, fmt.Errorf("rate must be between 0 and %d percent", MaxRate)
+	}
+	if terms.years < 1 || terms.years > MaxTermYears {
+		return loanTerms{}, fmt.Errorf("term_years must be between 1 and %d", MaxTermYears)
+	}
+	if !terms.method.Valid() {
+		return loanTerms{}, errors.New("method must be annuity or linear")
+	}
+	return terms, nil
+}
+
+// Calculate prices a loan and builds its monthly amortization schedule.
+func Calculate(table Table, input MortgageInput) (Mortgage, error) {
+	d, _ := table.Lookup(input.Country)
+	if input.Price <= 0 {
+		return Mortgage{}, errors.New("price must be positive")
+	}
+	terms, err := resolveTerms(d, input.Rate, input.TermYears, input.Method)
+	if err != nil {
+		return Mortgage{}, err
+	}
+
+	var down, ltv float64
+	switch {
+	case input.DownPayment != nil && input.LTV != nil:
+		return Mortgage{}, errors.New("give either down_payment or ltv, not both")
+	case input.DownPayment != nil:
+		down = *input.DownPayment
+		if down < 0 || down >= input.Price {
+			return Mortgage{}, errors.New("down_payment must be at least 0 and below the price")
+		}
+		ltv = (input.Price - down) / input.Price
+	case input.LTV != nil:
+		ltv = *input.LTV
+		if ltv <= 0 || ltv > 1 {
+			return Mortgage{}, errors.New("ltv must be in (0, 1]")
+		}
+		down = input.Price * (1 - ltv)
+	default:
+		ltv = d.MaxLTV
+		down = input.Price * (1 - ltv)
+	}
+	if ltv > d.MaxLTV+1e-9 {
+		return Mortgage{}, fmt.Errorf("loan-to-value %.1f%% exceeds the %.1f%% maximum for %s", ltv*100, d.MaxLTV*100, d.Country)
+	}
+
+	down = cents(down)
+	principal := cents(input.Price - down)
+	schedule := amortize(principal, terms.rate, terms.years*12, terms.method)
+	m := Mortgage{
+		Country:     d.Country,
+		Currency:    strings.ToUpper(strings.TrimSpace(input.Currency)),
+		Method:      terms.method,
+		Price:       cents(input.Price),
+		DownPayment: down,
+		Principal:   principal,
+		LTV:         math.Round(ltv*10000) / 10000,
+		MaxLTV:      d.MaxLTV,
+		Rate:        terms.rate,
+		TermYears:   terms.years,
+		Payments:    len(schedule),
+		Schedule:    schedule,
+	}
+	if len(schedule) > 0 {
+		m.MonthlyPayment = schedule[0].Payment
+		m.FinalPayment = schedule[len(schedule)-1].Payment
+	}
+	for _, installment := range schedule {
+		m.TotalInterest += installment.Interest
+		m.TotalPaid += installment.Payment
+	}
+	m.TotalInterest, m.TotalPaid = cents(m.TotalInterest), cents(m.TotalPaid)
+	return m, nil
+}
+
+// Afford computes the highest price a buyer can finance: the loan whose first
+// payment fits within MaxDTI of income after existing debts, capped by the
+// country's maximum loan-to-value for the given down payment.
+func Afford(table Table, input AffordabilityInput) (Affordability, error) {
+	d, _ := table.Lookup(input.Country)
+	if input.MonthlyIncome <= 0 {
+		return Affordability{}, errors.New("monthly_income must be positive")
+	}
+	if input.MonthlyDebts < 0 {
+		return Affordability{}, errors.New("monthly_debts cannot be negative")
+	}
+	if input.DownPayment < 0 {
+		return Affordability{}, errors.New("down_payment cannot be negative")
+	}
+	terms, err := resolveTerms(d, input.Rate, input.TermYears, input.Method)
+	if err != nil {
+		return Affordability{}, err
+	}
+	dti := d.MaxDTI
+	if input.MaxDTI != nil {
+		dti = *input.MaxDTI
+	}
+	if dti <= 0 || dti > 1 {
+		return Affordability{}, errors.New("max_dti must be in (0, 1]")
+	}
+
+	a := Affordability{
+		Country:       d.Country,
+		Currency:      strings.ToUpper(strings.TrimSpace(input.Currency)),
+		Method:        terms.method,
+		Rate:          terms.rate,
+		TermYears:     terms.years,
+		MaxDTI:        dti,
+		MaxLTV:        d.MaxLTV,
+		MonthlyIncome: input.MonthlyIncome,
+		MonthlyDebts:  input.MonthlyDebts,
+		DownPayment:   input.DownPayment,
+		LimitedBy:     "income",
+	}
+	budget := input.MonthlyIncome*dti - input.MonthlyDebts
+	a.PaymentBudget = floorCents(math.Max(budget, 0))
+	loan := floorCents(maxPrincipal(a.PaymentBudget, terms.rate, terms.years*12, terms.method))
+	if d.MaxLTV < 1 {
+		byDeposit := floorCents(input.DownPayment * d.MaxLTV / (1 - d.MaxLTV))
+		if byDeposit < loan {
+			loan, a.LimitedBy = byDeposit, "down_payment"
+		}
+	}
+	a.MaxLoan = loan
+	a.MaxPrice = floorCents(loan + input.DownPayment)
+	if schedule := amortize(loan, terms.rate, terms.years*12, terms.method); len(schedule) > 0 {
+		a.MonthlyPayment = schedule[0].Payment
+	}
+	return a, nil
+}
+
+// amortize splits the principal into monthly installments rounded to cents; the
+// last installment absorbs the rounding so the balance ends at zero.
+func amortize(principal, rate float64, months int, method Method) []Installment {
+	if principal <= 0 || months <= 0 {
+		return nil
+	}
+	monthly := rate / 100 / 12
+	payment := cents(annuityPayment(principal, monthly, months))
+	linear := cents(principal / float64(months))
+
+	schedule := make([]Installment, 0, months)
+	balance := principal
+	for month := 1; month <= months; month++ {
+		interest := cents(balance * monthly)
+		repaid := linear
+		if method == MethodAnnuity {
+			repaid = cents(payment - interest)
+		}
+		if month == months || repaid > balance {
+			repaid = balance
+		}
+		balance = cents(balance - repaid)
+		schedule = append(schedule, Installment{
+			Month:     month,
+			Payment:   cents(repaid + interest),
+			Principal: repaid,
+			Interest:  interest,
+			Balance:   balance,
+		})
+	}
+	return schedule
+}
+
+func annuityPayment(principal, monthly float64, months int) float64 {
+	if monthly == 0 {
+		return principal / float64(months)
+	}
+	return principal * monthly / (1 - math.Pow(1+monthly, -float64(months)))
+}
+
+// maxPrincipal inverts the first payment of a loan.
+func maxPrincipal(payment, rate float64, months int, method Method) float64 {
+	monthly := rate / 100 / 12
+	if method == MethodLinear {
+		return payment / (1/float64(months) + monthly)
+	}
+	if monthly == 0 {
+		return payment * float64(months)
+	}
+	return payment * (1 - math.Pow(1+monthly, -float64(months))) / monthly
+}
+
+func cents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func floorCents(v float64) float64 {
+	return math.Floor(v*100+1e-6) / 100
+}
@@ -0,0 +1,133 @@
+package finance
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestShippedDefaultsParse(t *testing.T) {
+	table, err := LoadFile("../../../data/finance/mortgage-defaults.csv")
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	se, ok := table.Lookup("se")
+	if !ok || se.Method != MethodLinear {
+		t.Errorf("Lookup(se) = %+v, %v, want the Swedish linear defaults", se, ok)
+	}
+	other, ok := table.Lookup("NZ")
+	if ok || other.Country != "NZ" || other.MaxLTV != 0.8 {
+		t.Errorf("Lookup(NZ) = %+v, %v, want the wildcard row for NZ", other, ok)
+	}
+	if all := table.All(); all[0].Country != Wildcard {
+		t.Errorf("All()[0] = %q, want the wildcard row first", all[0].Country)
+	}
+
+	if _, err := ParseCSV(strings.NewReader("country,max_ltv,rate,term_years,max_dti,method\nSE,1.5,3,30,0.3,linear\n")); err == nil {
+		t.Error("ParseCSV(max_ltv 1.5) error = nil, want error")
+	}
+	if _, err := ParseCSV(strings.NewReader("country,rate\nSE,3\n")); err == nil {
+		t.Error("ParseCSV(missing columns) error = nil, want error")
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	table := NewTable([]Defaults{
+		{Country: "US", MaxLTV: 0.8, Rate: 6, TermYears: 30, MaxDTI: 0.43, Method: MethodAnnuity},
+		{Country: "SE", MaxLTV: 0.85, Rate: 12, TermYears: 1, MaxDTI: 0.3, Method: MethodLinear},
+	})
+
+	annuity, err := Calculate(table, MortgageInput{Country: "US", Price: 125000, Currency: "usd"})
+	if err != nil {
+		t.Fatalf("Calculate(US) error = %v", err)
+	}
+	if annuity.Principal != 100000 || annuity.DownPayment != 25000 || annuity.Payments != 360 || annuity.Currency != "USD" {
+		t.Fatalf("Calculate(US) = %+v, want 100000 over 360 payments", annuity)
+	}
+	if annuity.MonthlyPayment != 599.55 {
+		t.Errorf("MonthlyPayment = %v, want 599.55", annuity.MonthlyPayment)
+	}
+	assertRepaid(t, annuity)
+
+	linear, err := Calculate(table, MortgageInput{Country: "SE", Price: 20000, DownPayment: ptr(8000.0)})
+	if err != nil {
+		t.Fatalf("Calculate(SE) error = %v", err)
+	}
+	if linear.Method != MethodLinear || linear.Payments != 12 || linear.MonthlyPayment != 1120 || linear.FinalPayment != 1010 {
+		t.Fatalf("Calculate(SE) = %+v, want linear payments from 1120 down to 1010", linear)
+	}
+	if linear.TotalInterest != 780 {
+		t.Errorf("TotalInterest = %v, want 780", linear.TotalInterest)
+	}
+	assertRepaid(t, linear)
+
+	free, err := Calculate(table, MortgageInput{Country: "US", Price: 1200, LTV: ptr(0.5), Rate: ptr(0.0), TermYears: ptr(1), Method: MethodAnnuity})
+	if err != nil {
+		t.Fatalf("Calculate(0%%) error = %v", err)
+	}
+	if free.MonthlyPayment != 50 || free.TotalInterest != 0 {
+		t.Errorf("Calculate(0%%) = %v monthly, %v interest, want 50 and 0", free.MonthlyPayment, free.TotalInterest)
+	}
+
+	cases := map[string]MortgageInput{
+		"no price":       {Country: "US"},
+		"ltv over max":   {Country: "US", Price: 1000, LTV: ptr(0.9)},
+		"down too small": {Country: "US", Price: 1000, DownPayment: ptr(100.0)},
+		"both":           {Country: "US", Price: 1000, LTV: ptr(0.5), DownPayment: ptr(500.0)},
+		"bad method":     {Country: "US", Price: 1000, Method: "balloon"},
+		"long term":      {Country: "US", Price: 1000, TermYears: ptr(60)},
+	}
+	for name, input := range cases {
+		if _, err := Calculate(table, input); err == nil {
+			t.Errorf("%s: Calculate() error = nil, want error", name)
+		}
+	}
+}
+
+func TestAfford(t *testing.T) {
+	table := NewTable([]Defaults{{Country: Wildcard, MaxLTV: 0.8, Rate: 0, TermYears: 25, MaxDTI: 0.35, Method: MethodAnnuity}})
+
+	byIncome, err := Afford(table, AffordabilityInput{Country: "PT", MonthlyIncome: 10000, MonthlyDebts: 500, DownPayment: 500000})
+	if err != nil {
+		t.Fatalf("Afford() error = %v", err)
+	}
+	if byIncome.PaymentBudget != 3000 || byIncome.MaxLoan != 900000 || byIncome.MaxPrice != 1400000 || byIncome.LimitedBy != "income" {
+		t.Fatalf("Afford() = %+v, want a 900000 loan limited by income", byIncome)
+	}
+	if byIncome.MonthlyPayment != 3000 {
+		t.Errorf("MonthlyPayment = %v, want 3000", byIncome.MonthlyPayment)
+	}
+
+	byDeposit, err := Afford(table, AffordabilityInput{Country: "PT", MonthlyIncome: 10000, MonthlyDebts: 500, DownPayment: 100000})
+	if err != nil {
+		t.Fatalf("Afford() error = %v", err)
+	}
+	if byDeposit.MaxLoan != 400000 || byDeposit.MaxPrice != 500000 || byDeposit.LimitedBy != "down_payment" {
+		t.Fatalf("Afford() = %+v, want a 400000 loan limited by the down payment", byDeposit)
+	}
+
+	indebted, err := Afford(table, AffordabilityInput{MonthlyIncome: 1000, MonthlyDebts: 800, DownPayment: 20000})
+	if err != nil {
+		t.Fatalf("Afford() error = %v", err)
+	}
+	if indebted.MaxLoan != 0 || indebted.MaxPrice != 20000 {
+		t.Errorf("Afford() = %+v, want no loan and the down payment as the price", indebted)
+	}
+
+	if _, err := Afford(table, AffordabilityInput{MonthlyIncome: 0}); err == nil {
+		t.Error("Afford(no income) error = nil, want error")
+	}
+}
+
+func assertRepaid(t *testing.T, m Mortgage) {
+	t.Helper()
+	var principal float64
+	for _, installment := range m.Schedule {
+		principal += installment.Principal
+	}
+	if last := m.Schedule[len(m.Schedule)-1]; last.Balance != 0 || math.Abs(principal-m.Principal) > 0.005 {
+		t.Errorf("schedule repays %v ending at %v, want %v ending at 0", principal, last.Balance, m.Principal)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,166 @@
+package fx
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoadFile reads rates from a CSV or ECB XML file, choosing the parser by extension.
+func LoadFile(path string) ([]Rate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	source := filepath.Base(path)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xml":
+		return ParseECBXML(f, source)
+	case ".csv":
+		return ParseCSV(f, source)
+	default:
+		return nil, fmt.Errorf("unsupported rate file %q: expected .csv or .xml", source)
+	}
+}
+
+// ParseCSV accepts either a long table with a "currency,rate[,date]" header or the
+// ECB wide layout ("Date, USD, JPY, ...") with one row per day. For the wide layout
+// the first data row is treated as the latest fixing.
+func ParseCSV(r io.Reader, source string) ([]Rate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read csv: %w", err)
+	}
+	if len(records) < 2 {
+		return nil, errors.New("rate file has no data rows")
+	}
+
+	header := make([]string, len(records[0]))
+	for i, h := range records[0] {
+		header[i] = strings.ToLower(strings.TrimSpace(h))
+	}
+	if len(header) > 0 && header[0] == "date" {
+		return parseWideCSV(header, records[1], source)
+	}
+
+	currencyCol, rateCol, dateCol := -1, -1, -1
+	for i, h := range header {
+		switch h {
+		case "currency", "code":
+			currencyCol = i
+		case "rate", "per_eur":
+			rateCol = i
+		case "date", "as_of":
+			dateCol = i
+		}
+	}
+	if currencyCol < 0 || rateCol < 0 {
+		return nil, errors.New("csv header must include currency and rate columns")
+	}
+
+	rates := make([]Rate, 0, len(records)-1)
+	for line, record := range records[1:] {
+		if len(record) <= currencyCol || len(record) <= rateCol {
+			return nil, fmt.Errorf("line %d: missing columns", line+2)
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[rateCol]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid rate %q", line+2, record[rateCol])
+		}
+		rate := Rate{Currency: strings.TrimSpace(record[currencyCol]), PerBase: value, Source: source}
+		if dateCol >= 0 && dateCol < len(record) {
+			if rate.AsOf, err = parseDate(record[dateCol]); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line+2, err)
+			}
+		}
+		rates = append(rates, rate)
+	}
+	return normalizeRates(rates)
+}
+
+func parseWideCSV(header, row []string, source string) ([]Rate, error) {
+	asOf, err := parseDate(row[0])
+	if err != nil {
+		return nil, err
+	}
+	rates := make([]Rate, 0, len(header)-1)
+	for i := 1; i < len(header) && i < len(row); i++ {
+		currency := strings.ToUpper(header[i])
+		raw := strings.TrimSpace(row[i])
+		if currency == "" || raw == "" || strings.EqualFold(raw, "N/A") {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid rate %q", currency, raw)
+		}
+		rates = append(rates, Rate{Currency: currency, PerBase: value, AsOf: asOf, Source: source})
+	}
+	return normalizeRates(rates)
+}
+
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// ParseECBXML reads the ECB eurofxref daily or historical XML feed. When the feed
+// holds several days only the most recent one is returned.
+func ParseECBXML(r io.Reader, source string) ([]Rate, error) {
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("decode ecb xml: %w", err)
+	}
+	if len(envelope.Days) == 0 {
+		return nil, errors.New("ecb xml contains no rates")
+	}
+
+	latest := 0
+	for i, day := range envelope.Days {
+		if day.Time > envelope.Days[latest].Time {
+			latest = i
+		}
+	}
+	day := envelope.Days[latest]
+	asOf, err := parseDate(day.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make([]Rate, 0, len(day.Rates))
+	for _, entry := range day.Rates {
+		value, err := strconv.ParseFloat(strings.TrimSpace(entry.Rate), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid rate %q", entry.Currency, entry.Rate)
+		}
+		rates = append(rates, Rate{Currency: entry.Currency, PerBase: value, AsOf: asOf, Source: source})
+	}
+	return normalizeRates(rates)
+}
+
+func parseDate(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	for _, layout := range []string{"2006-01-02", "02 January 2006", "2 January 2006"} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", raw)
+}
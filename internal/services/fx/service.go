@@ -0,0 +1,177 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BaseCurrency is the currency every stored rate is quoted against, matching the ECB reference feed.
+const BaseCurrency = "EUR"
+
+// ErrUnknownCurrency is returned when no rate is available for a requested currency.
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// Rate is the number of units of Currency that buy one unit of BaseCurrency.
+type Rate struct {
+	Currency  string    `json:"currency"`
+	PerBase   float64   `json:"per_eur"`
+	AsOf      time.Time `json:"as_of"`
+	Source    string    `json:"source"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Money is an amount tagged with its ISO 4217 currency.
+type Money struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// Service exposes FX reference rates.
+type Service interface {
+	Rates(ctx context.Context) ([]Rate, error)
+	Table(ctx context.Context) (Table, error)
+	Upsert(ctx context.Context, rates []Rate) (int, error)
+}
+
+// Table is an immutable snapshot of rates keyed by currency code.
+type Table struct {
+	rates map[string]float64
+}
+
+// NewTable builds a snapshot from rates; the base currency is always present.
+func NewTable(rates []Rate) Table {
+	table := Table{rates: map[string]float64{BaseCurrency: 1}}
+	for _, r := range rates {
+		if r.PerBase > 0 {
+			table.rates[strings.ToUpper(r.Currency)] = r.PerBase
+		}
+	}
+	return table
+}
+
+// Has reports whether the table can convert to and from the currency.
+func (t Table) Has(currency string) bool {
+	_, ok := t.rates[strings.ToUpper(currency)]
+	return ok
+}
+
+// Convert translates an amount between currencies, rounded to cents.
+func (t Table) Convert(amount float64, from, to string) (float64, error) {
+	fromRate, ok := t.rates[strings.ToUpper(from)]
+	if !ok {
+		return 0, ErrUnknownCurrency
+	}
+	toRate, ok := t.rates[strings.ToUpper(to)]
+	if !ok {
+		return 0, ErrUnknownCurrency
+	}
+	return math.Round(amount/fromRate*toRate*100) / 100, nil
+}
+
+// InMemoryService keeps rates in memory, seeded with indicative reference values.
+type InMemoryService struct {
+	mu    sync.RWMutex
+	rates map[string]Rate
+}
+
+// NewInMemoryService seeds demo rates covering every currency used by the listing demo data.
+func NewInMemoryService() *InMemoryService {
+	service := &InMemoryService{rates: make(map[string]Rate)}
+	service.seed()
+	return service
+}
+
+func (s *InMemoryService) Rates(_ context.Context) ([]Rate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rates := make([]Rate, 0, len(s.rates))
+	for _, r := range s.rates {
+		rates = append(rates, r)
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Currency < rates[j].Currency })
+	return rates, nil
+}
+
+func (s *InMemoryService) Table(ctx context.Context) (Table, error) {
+	rates, err := s.Rates(ctx)
+	if err != nil {
+		return Table{}, err
+	}
+	return NewTable(rates), nil
+}
+
+func (s *InMemoryService) Upsert(_ context.Context, rates []Rate) (int, error) {
+	rates, err := normalizeRates(rates)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	for _, r := range rates {
+		r.UpdatedAt = now
+		s.rates[r.Currency] = r
+	}
+	return len(rates), nil
+}
+
+func (s *InMemoryService) seed() {
+	asOf := time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC)
+	for currency, perBase := range map[string]float64{
+		"EUR": 1,
+		"USD": 1.0321,
+		"GBP": 0.8285,
+		"SEK": 11.4865,
+		"JPY": 162.74,
+		"SGD": 1.4121,
+		"ISK": 144.1,
+		"ZAR": 19.4586,
+		"BRL": 6.4093,
+		"CAD": 1.4872,
+		"AED": 3.7904,
+		"KZT": 541.2,
+	} {
+		s.rates[currency] = Rate{Currency: currency, PerBase: perBase, AsOf: asOf, Source: "seed", UpdatedAt: asOf}
+	}
+}
+
+// normalizeRates validates codes and values before they are stored.
+func normalizeRates(rates []Rate) ([]Rate, error) {
+	out := make([]Rate, 0, len(rates))
+	for _, r := range rates {
+		r.Currency = strings.ToUpper(strings.TrimSpace(r.Currency))
+		if !validCode(r.Currency) {
+			return nil, errors.New("currency must be an ISO 4217 code")
+		}
+		if r.PerBase <= 0 || math.IsInf(r.PerBase, 0) || math.IsNaN(r.PerBase) {
+			return nil, errors.New("rate must be a positive number")
+		}
+		if r.Currency == BaseCurrency {
+			r.PerBase = 1
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func validCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+var _ Service = (*InMemoryService)(nil)
@@ -0,0 +1,79 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTableConvert(t *testing.T) {
+	table := NewTable([]Rate{{Currency: "USD", PerBase: 1.25}, {Currency: "SEK", PerBase: 11.5}})
+
+	got, err := table.Convert(125, "USD", "EUR")
+	if err != nil || got != 100 {
+		t.Fatalf("Convert(125 USD -> EUR) = %v, %v; want 100", got, err)
+	}
+	got, err = table.Convert(1150, "sek", "usd")
+	if err != nil || got != 125 {
+		t.Fatalf("Convert(1150 SEK -> USD) = %v, %v; want 125", got, err)
+	}
+	if _, err := table.Convert(1, "XYZ", "EUR"); !errors.Is(err, ErrUnknownCurrency) {
+		t.Errorf("Convert(unknown) error = %v, want ErrUnknownCurrency", err)
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	long := "currency,rate,date\nusd,1.04,2025-01-03\nJPY,163.1,2025-01-03\n"
+	rates, err := ParseCSV(strings.NewReader(long), "manual.csv")
+	if err != nil {
+		t.Fatalf("ParseCSV(long) error = %v", err)
+	}
+	if len(rates) != 2 || rates[0].Currency != "USD" || rates[0].AsOf.Day() != 3 || rates[0].Source != "manual.csv" {
+		t.Errorf("ParseCSV(long) = %+v", rates)
+	}
+
+	wide := "Date, USD, JPY, ISK, \n03 January 2025, 1.0299, 163.55, N/A, \n02 January 2025, 1.0321, 162.74, N/A, \n"
+	rates, err = ParseCSV(strings.NewReader(wide), "eurofxref.csv")
+	if err != nil {
+		t.Fatalf("ParseCSV(wide) error = %v", err)
+	}
+	if len(rates) != 2 || rates[0].PerBase != 1.0299 || rates[1].Currency != "JPY" {
+		t.Errorf("ParseCSV(wide) = %+v", rates)
+	}
+
+	if _, err := ParseCSV(strings.NewReader("currency,rate\nUSD,-1\n"), "bad.csv"); err == nil {
+		t.Error("ParseCSV(negative rate) error = nil, want error")
+	}
+}
+
+func TestParseECBXML(t *testing.T) {
+	feed := `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<Cube>
+		<Cube time="2025-01-02"><Cube currency="USD" rate="1.0321"/></Cube>
+		<Cube time="2025-01-03"><Cube currency="USD" rate="1.0299"/><Cube currency="SEK" rate="11.51"/></Cube>
+	</Cube>
+</gesmes:Envelope>`
+	rates, err := ParseECBXML(strings.NewReader(feed), "ecb")
+	if err != nil {
+		t.Fatalf("ParseECBXML() error = %v", err)
+	}
+	if len(rates) != 2 || rates[0].PerBase != 1.0299 || rates[0].AsOf.Day() != 3 {
+		t.Errorf("ParseECBXML() = %+v, want the 2025-01-03 fixing", rates)
+	}
+}
+
+func TestInMemoryServiceUpsert(t *testing.T) {
+	service := NewInMemoryService()
+	if _, err := service.Upsert(context.Background(), []Rate{{Currency: "usd", PerBase: 2}}); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	table, err := service.Table(context.Background())
+	if err != nil {
+		t.Fatalf("Table() error = %v", err)
+	}
+	if got, _ := table.Convert(10, "EUR", "USD"); got != 20 {
+		t.Errorf("Convert after upsert = %v, want 20", got)
+	}
+}
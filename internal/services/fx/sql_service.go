@@ -0,0 +1,91 @@
+package fx
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type sqlService struct {
+	db *sql.DB
+}
+
+// NewSQLService builds an FX rate service backed by the fx_rates table.
+func NewSQLService(db *sql.DB) (Service, error) {
+	return &sqlService{db: db}, nil
+}
+
+func (s *sqlService) Rates(ctx context.Context) ([]Rate, error) {
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT currency, rate, as_of, source, updated_at
+        FROM fx_rates
+        ORDER BY currency`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := make([]Rate, 0)
+	for rows.Next() {
+		var r Rate
+		if err := rows.Scan(&r.Currency, &r.PerBase, &r.AsOf, &r.Source, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		rates = append(rates, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (s *sqlService) Table(ctx context.Context) (Table, error) {
+	rates, err := s.Rates(ctx)
+	if err != nil {
+		return Table{}, err
+	}
+	return NewTable(rates), nil
+}
+
+func (s *sqlService) Upsert(ctx context.Context, rates []Rate) (int, error) {
+	rates, err := normalizeRates(rates)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.PrepareContext(ctx, `
+        INSERT INTO fx_rates (currency, rate, as_of, source, updated_at)
+        VALUES ($1, $2, $3, $4, NOW())
+        ON CONFLICT (currency) DO UPDATE
+        SET rate = EXCLUDED.rate,
+            as_of = EXCLUDED.as_of,
+            source = EXCLUDED.source,
+            updated_at = NOW()
+        WHERE fx_rates.as_of <= EXCLUDED.as_of`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	for _, r := range rates {
+		asOf := r.AsOf
+		if asOf.IsZero() {
+			asOf = time.Now().UTC()
+		}
+		if _, err := stmt.ExecContext(ctx, r.Currency, r.PerBase, asOf, r.Source); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(rates), nil
+}
+
+var _ Service = (*sqlService)(nil)
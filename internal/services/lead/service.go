@@ -0,0 +1,322 @@
+package lead
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxMessageLength caps the inquiry text in characters.
+	MaxMessageLength = 5000
+	// DefaultPageSize and MaxPageSize bound inbox pages.
+	DefaultPageSize = 25
+	MaxPageSize     = 100
+)
+
+// Status tracks a lead through the agency's sales pipeline.
+type Status string
+
+const (
+	StatusNew       Status = "new"
+	StatusContacted Status = "contacted"
+	StatusQualified Status = "qualified"
+	StatusLost      Status = "lost"
+	StatusWon       Status = "won"
+)
+
+// Statuses lists the pipeline stages in order.
+var Statuses = []Status{StatusNew, StatusContacted, StatusQualified, StatusLost, StatusWon}
+
+// Valid reports whether the status is supported.
+func (s Status) Valid() bool {
+	for _, known := range Statuses {
+		if s == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Lead is a buyer's inquiry about a listing, routed to the listing's agency. The
+// listing title is a snapshot so the inbox still reads well after the listing is
+// edited or removed.
+type Lead struct {
+	ID           uuid.UUID `json:"id"`
+	ListingID    uuid.UUID `json:"listing_id"`
+	AgencyID     uuid.UUID `json:"agency_id"`
+	ListingTitle string    `json:"listing_title"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	Phone        string    `json:"phone,omitempty"`
+	Message      string    `json:"message"`
+	Status       Status    `json:"status"`
+	Note         string    `json:"note,omitempty"`
+	UpdatedBy    string    `json:"updated_by,omitempty"`
+	RequesterID  string    `json:"-"`
+	SourceIP     string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Input captures a new inquiry.
+type Input struct {
+	ListingID    uuid.UUID
+	AgencyID     uuid.UUID
+	ListingTitle string
+	Name         string
+	Email        string
+	Phone        string
+	Message      string
+	RequesterID  string
+	SourceIP     string
+}
+
+// Filter narrows an agency inbox.
+type Filter struct {
+	AgencyID  uuid.UUID
+	ListingID uuid.UUID
+	Status    Status
+	Limit     int
+	Offset    int
+}
+
+// Change moves a lead to another status with an optional note.
+type Change struct {
+	Status     Status
+	Note       string
+	ActorEmail string
+}
+
+// Service stores leads and the agency inbox.
+type Service interface {
+	Create(ctx context.Context, input Input) (Lead, error)
+	List(ctx context.Context, filter Filter) ([]Lead, int, error)
+	Get(ctx context.Context, id uuid.UUID) (Lead, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, change Change) (Lead, error)
+	Counts(ctx context.Context, agencyID uuid.UUID) (map[Status]int, error)
+}
+
+// ErrNotFound is returned when a lead does not exist.
+var ErrNotFound = errors.New("lead not found")
+
+// ErrInvalid matches every ValidationError.
+var ErrInvalid = errors.New("invalid lead")
+
+// ValidationError describes input the caller must correct.
+type ValidationError struct {
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Reason
+}
+
+// Unwrap lets callers match the error with errors.Is(err, ErrInvalid).
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalid
+}
+
+func invalid(reason string) error {
+	return &ValidationError{Reason: reason}
+}
+
+func normalizeInput(input Input) (Input, error) {
+	if input.ListingID == uuid.Nil {
+		return Input{}, invalid("listing is required")
+	}
+	if input.AgencyID == uuid.Nil {
+		return Input{}, invalid("listing has no agency to route the inquiry to")
+	}
+	input.Name = strings.TrimSpace(input.Name)
+	input.Email = strings.TrimSpace(input.Email)
+	input.Phone = strings.TrimSpace(input.Phone)
+	input.Message = strings.TrimSpace(input.Message)
+	input.ListingTitle = strings.TrimSpace(input.ListingTitle)
+	if input.Name == "" {
+		return Input{}, invalid("name is required")
+	}
+	if utf8.RuneCountInString(input.Name) > 200 {
+		return Input{}, invalid("name is too long")
+	}
+	if addr, err := mail.ParseAddress(input.Email); err != nil || addr.Address != input.Email {
+		return Input{}, invalid("a valid email is required")
+	}
+	if utf8.RuneCountInString(input.Phone) > 40 {
+		return Input{}, invalid("phone is too long")
+	}
+	if input.Message == "" {
+		return Input{}, invalid("message is required")
+	}
+	if utf8.RuneCountInString(input.Message) > MaxMessageLength {
+		return Input{}, invalid("message cannot exceed 5000 characters")
+	}
+	return input, nil
+}
+
+// normalizeChange validates a status change. Leads never return to new; any
+// other move is allowed so closed leads can be reopened.
+func normalizeChange(change Change) (Change, error) {
+	change.Status = Status(strings.ToLower(strings.TrimSpace(string(change.Status))))
+	if !change.Status.Valid() {
+		return Change{}, invalid("status must be one of new, contacted, qualified, lost, won")
+	}
+	if change.Status == StatusNew {
+		return Change{}, invalid("leads cannot be moved back to new")
+	}
+	change.Note = strings.TrimSpace(change.Note)
+	change.ActorEmail = strings.TrimSpace(change.ActorEmail)
+	return change, nil
+}
+
+func normalizePage(filter Filter) Filter {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageSize
+	}
+	if filter.Limit > MaxPageSize {
+		filter.Limit = MaxPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return filter
+}
+
+// Matches reports whether the lead passes the filter, ignoring paging.
+func (f Filter) Matches(l Lead) bool {
+	if f.AgencyID != uuid.Nil && l.AgencyID != f.AgencyID {
+		return false
+	}
+	if f.ListingID != uuid.Nil && l.ListingID != f.ListingID {
+		return false
+	}
+	if f.Status != "" && l.Status != f.Status {
+		return false
+	}
+	return true
+}
+
+// InMemoryService keeps leads in process memory.
+type InMemoryService struct {
+	mu    sync.RWMutex
+	leads []Lead
+}
+
+// NewInMemoryService returns an empty lead store.
+func NewInMemoryService() *InMemoryService {
+	return &InMemoryService{}
+}
+
+func (s *InMemoryService) Create(_ context.Context, input Input) (Lead, error) {
+	input, err := normalizeInput(input)
+	if err != nil {
+		return Lead{}, err
+	}
+	now := time.Now().UTC()
+	lead := Lead{
+		ID:           uuid.New(),
+		ListingID:    input.ListingID,
+		AgencyID:     input.AgencyID,
+		ListingTitle: input.ListingTitle,
+		Name:         input.Name,
+		Email:        input.Email,
+		Phone:        input.Phone,
+		Message:      input.Message,
+		Status:       StatusNew,
+		RequesterID:  input.RequesterID,
+		SourceIP:     input.SourceIP,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.leads = append(s.leads, lead)
+	return lead, nil
+}
+
+// List returns the newest leads first.
+func (s *InMemoryService) List(_ context.Context, filter Filter) ([]Lead, int, error) {
+	filter = normalizePage(filter)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matched := make([]Lead, 0)
+	for _, l := range s.leads {
+		if filter.Matches(l) {
+			matched = append(matched, l)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].CreatedAt.After(matched[j].CreatedAt) })
+	total := len(matched)
+	if filter.Offset >= total {
+		return []Lead{}, total, nil
+	}
+	end := filter.Offset + filter.Limit
+	if end > total {
+		end = total
+	}
+	return matched[filter.Offset:end], total, nil
+}
+
+func (s *InMemoryService) Get(_ context.Context, id uuid.UUID) (Lead, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, l := range s.leads {
+		if l.ID == id {
+			return l, nil
+		}
+	}
+	return Lead{}, ErrNotFound
+}
+
+func (s *InMemoryService) UpdateStatus(_ context.Context, id uuid.UUID, change Change) (Lead, error) {
+	change, err := normalizeChange(change)
+	if err != nil {
+		return Lead{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.leads {
+		if s.leads[i].ID != id {
+			continue
+		}
+		s.leads[i].Status = change.Status
+		s.leads[i].Note = change.Note
+		s.leads[i].UpdatedBy = change.ActorEmail
+		s.leads[i].UpdatedAt = time.Now().UTC()
+		return s.leads[i], nil
+	}
+	return Lead{}, ErrNotFound
+}
+
+// Counts returns the number of the agency's leads in each status.
+func (s *InMemoryService) Counts(_ context.Context, agencyID uuid.UUID) (map[Status]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[Status]int, len(Statuses))
+	for _, status := range Statuses {
+		counts[status] = 0
+	}
+	for _, l := range s.leads {
+		if l.AgencyID == agencyID {
+			counts[l.Status]++
+		}
+	}
+	return counts, nil
+}
+
+var _ Service = (*InMemoryService)(nil)
@@ -0,0 +1,78 @@
+package lead
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestInMemoryServiceInbox(t *testing.T) {
+	ctx := context.Background()
+	service := NewInMemoryService()
+	agencyID, otherAgency, listingID := uuid.New(), uuid.New(), uuid.New()
+	valid := Input{ListingID: listingID, AgencyID: agencyID, ListingTitle: "Sky Villa", Name: "Ana", Email: "ana@example.com", Message: "Is it still available?"}
+
+	cases := map[string]func(*Input){
+		"no agency":     func(in *Input) { in.AgencyID = uuid.Nil },
+		"no name":       func(in *Input) { in.Name = " " },
+		"bad email":     func(in *Input) { in.Email = "Ana <ana@example.com>" },
+		"empty message": func(in *Input) { in.Message = "" },
+	}
+	for name, mutate := range cases {
+		input := valid
+		mutate(&input)
+		if _, err := service.Create(ctx, input); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: Create() error = %v, want validation error", name, err)
+		}
+	}
+
+	first, err := service.Create(ctx, valid)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if first.Status != StatusNew {
+		t.Errorf("Status = %q, want new", first.Status)
+	}
+	second := valid
+	second.Email = "rui@example.com"
+	if _, err := service.Create(ctx, second); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	elsewhere := valid
+	elsewhere.AgencyID = otherAgency
+	if _, err := service.Create(ctx, elsewhere); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	inbox, total, err := service.List(ctx, Filter{AgencyID: agencyID, Limit: 1})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if total != 2 || len(inbox) != 1 || inbox[0].Email != "rui@example.com" {
+		t.Fatalf("List() = %d of %d, want the newest of 2 agency leads", len(inbox), total)
+	}
+
+	if _, err := service.UpdateStatus(ctx, first.ID, Change{Status: StatusNew}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("UpdateStatus(new) error = %v, want validation error", err)
+	}
+	updated, err := service.UpdateStatus(ctx, first.ID, Change{Status: "Qualified", Note: "budget confirmed", ActorEmail: "layla@shanraq.com"})
+	if err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+	if updated.Status != StatusQualified || updated.UpdatedBy != "layla@shanraq.com" {
+		t.Errorf("UpdateStatus() = %+v, want qualified by layla", updated)
+	}
+	if _, err := service.UpdateStatus(ctx, uuid.New(), Change{Status: StatusWon}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateStatus() unknown error = %v, want ErrNotFound", err)
+	}
+
+	counts, err := service.Counts(ctx, agencyID)
+	if err != nil {
+		t.Fatalf("Counts() error = %v", err)
+	}
+	if counts[StatusNew] != 1 || counts[StatusQualified] != 1 || counts[StatusWon] != 0 {
+		t.Errorf("Counts() = %v, want one new and one qualified", counts)
+	}
+}
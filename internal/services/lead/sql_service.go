@@ -0,0 +1,155 @@
+package lead
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const leadColumns = `id, listing_id, agency_id, listing_title, name, email, phone, message, status, note,
+        updated_by, requester_id, source_ip, created_at, updated_at`
+
+type sqlService struct {
+	db *sql.DB
+}
+
+// NewSQLService builds a lead service backed by PostgreSQL.
+func NewSQLService(db *sql.DB) (Service, error) {
+	return &sqlService{db: db}, nil
+}
+
+func (s *sqlService) Create(ctx context.Context, input Input) (Lead, error) {
+	input, err := normalizeInput(input)
+	if err != nil {
+		return Lead{}, err
+	}
+	return scanLead(s.db.QueryRowContext(ctx, `
+        INSERT INTO listing_leads
+            (listing_id, agency_id, listing_title, name, email, phone, message, requester_id, source_ip)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        RETURNING `+leadColumns,
+		input.ListingID, input.AgencyID, input.ListingTitle, input.Name, input.Email, input.Phone, input.Message,
+		input.RequesterID, input.SourceIP))
+}
+
+func (s *sqlService) List(ctx context.Context, filter Filter) ([]Lead, int, error) {
+	filter = normalizePage(filter)
+	var (
+		clauses []string
+		args    []any
+	)
+	add := func(clause string, value any) {
+		args = append(args, value)
+		clauses = append(clauses, fmt.Sprintf(clause, len(args)))
+	}
+	if filter.AgencyID != uuid.Nil {
+		add("agency_id = $%d", filter.AgencyID)
+	}
+	if filter.ListingID != uuid.Nil {
+		add("listing_id = $%d", filter.ListingID)
+	}
+	if filter.Status != "" {
+		add("status = $%d", string(filter.Status))
+	}
+	where := ""
+	if len(clauses) > 0 {
+		where = "WHERE " + strings.Join(clauses, " AND ")
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM listing_leads `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+        SELECT `+leadColumns+`
+        FROM listing_leads
+        %s
+        ORDER BY created_at DESC, id
+        LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	leads := make([]Lead, 0)
+	for rows.Next() {
+		l, err := scanLead(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		leads = append(leads, l)
+	}
+	return leads, total, rows.Err()
+}
+
+func (s *sqlService) Get(ctx context.Context, id uuid.UUID) (Lead, error) {
+	l, err := scanLead(s.db.QueryRowContext(ctx, `SELECT `+leadColumns+` FROM listing_leads WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Lead{}, ErrNotFound
+	}
+	return l, err
+}
+
+func (s *sqlService) UpdateStatus(ctx context.Context, id uuid.UUID, change Change) (Lead, error) {
+	change, err := normalizeChange(change)
+	if err != nil {
+		return Lead{}, err
+	}
+	l, err := scanLead(s.db.QueryRowContext(ctx, `
+        UPDATE listing_leads
+        SET status = $2, note = $3, updated_by = $4, updated_at = NOW()
+        WHERE id = $1
+        RETURNING `+leadColumns, id, string(change.Status), change.Note, change.ActorEmail))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Lead{}, ErrNotFound
+	}
+	return l, err
+}
+
+func (s *sqlService) Counts(ctx context.Context, agencyID uuid.UUID) (map[Status]int, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT status, COUNT(*) FROM listing_leads WHERE agency_id = $1 GROUP BY status`, agencyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[Status]int, len(Statuses))
+	for _, status := range Statuses {
+		counts[status] = 0
+	}
+	for rows.Next() {
+		var (
+			status string
+			count  int
+		)
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[Status(status)] = count
+	}
+	return counts, rows.Err()
+}
+
+func scanLead(row interface {
+	Scan(dest ...any) error
+}) (Lead, error) {
+	var (
+		l         Lead
+		listingID uuid.NullUUID
+		status    string
+	)
+	if err := row.Scan(&l.ID, &listingID, &l.AgencyID, &l.ListingTitle, &l.Name, &l.Email, &l.Phone, &l.Message, &status, &l.Note,
+		&l.UpdatedBy, &l.RequesterID, &l.SourceIP, &l.CreatedAt, &l.UpdatedAt); err != nil {
+		return Lead{}, err
+	}
+	l.ListingID = listingID.UUID
+	l.Status = Status(status)
+	return l, nil
+}
+
+var _ Service = (*sqlService)(nil)
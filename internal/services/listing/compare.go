@@ -0,0 +1,224 @@
+package listing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"shanraq.com/internal/format"
+	"shanraq.com/internal/services/fx"
+)
+
+// MaxCompare caps how many listings a single comparison may include.
+const MaxCompare = 5
+
+// Comparison lines up several listings in one currency and unit system.
+type Comparison struct {
+	Currency string            `json:"currency"`
+	Listings []ComparedListing `json:"listings"`
+	Diff     []AttributeDiff   `json:"diff"`
+}
+
+// ComparedListing is a listing with its normalised comparison figures. Price and the
+// per-area prices are nil when the listing's currency has no FX rate or the price
+// or area is unknown.
+type ComparedListing struct {
+	Listing      Listing   `json:"listing"`
+	AreaSqM      float64   `json:"area_sqm"`
+	AreaSqFt     float64   `json:"area_sqft"`
+	Price        *fx.Money `json:"price,omitempty"`
+	PricePerSqM  *fx.Money `json:"price_per_sqm,omitempty"`
+	PricePerSqFt *fx.Money `json:"price_per_sqft,omitempty"`
+}
+
+// AttributeDiff compares one attribute across the listings, in listing order. Best
+// is the index of the most favourable value for numeric attributes (lowest price,
+// largest area) and is omitted when the values are all equal or not comparable.
+type AttributeDiff struct {
+	Attribute string   `json:"attribute"`
+	Values    []any    `json:"values"`
+	Display   []string `json:"display"`
+	Same      bool     `json:"same"`
+	Best      *int     `json:"best,omitempty"`
+}
+
+// ParseCompareIDs reads listing ids from one or more ?ids= values, each of which may
+// hold a comma-separated list. Duplicates are dropped and order is preserved.
+func ParseCompareIDs(raw []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, MaxCompare)
+	seen := make(map[uuid.UUID]struct{})
+	for _, value := range raw {
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			id, err := uuid.Parse(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid listing id %q", part)
+			}
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) < 2 {
+		return nil, errors.New("ids must name at least two listings")
+	}
+	if len(ids) > MaxCompare {
+		return nil, fmt.Errorf("at most %d listings can be compared", MaxCompare)
+	}
+	return ids, nil
+}
+
+// Compare normalises the listings to one currency and builds the attribute diff,
+// whose display values are rendered with f. An empty currency compares in the first
+// listing's currency.
+func Compare(listings []Listing, table fx.Table, currency string, f format.Formatter) (Comparison, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" && len(listings) > 0 {
+		currency = strings.ToUpper(listings[0].Currency)
+	}
+	if currency != "" && !table.Has(currency) {
+		return Comparison{}, fx.ErrUnknownCurrency
+	}
+
+	comparison := Comparison{
+		Currency: currency,
+		Listings: make([]ComparedListing, 0, len(listings)),
+	}
+	for _, l := range listings {
+		ApplyCurrency(&l, table, currency)
+		entry := ComparedListing{
+			Listing:  l,
+			AreaSqM:  l.AreaSqM,
+			AreaSqFt: roundTo(l.AreaSqM*format.SqFtPerSqM, 1),
+		}
+		if l.Price > 0 && l.ConvertedPrice != nil {
+			price := *l.ConvertedPrice
+			entry.Price = &price
+			if l.AreaSqM > 0 {
+				entry.PricePerSqM = &fx.Money{Amount: roundTo(price.Amount/l.AreaSqM, 2), Currency: currency}
+				entry.PricePerSqFt = &fx.Money{Amount: roundTo(price.Amount/(l.AreaSqM*format.SqFtPerSqM), 2), Currency: currency}
+			}
+		}
+		comparison.Listings = append(comparison.Listings, entry)
+	}
+	comparison.Diff = diffAttributes(comparison.Listings, f)
+	return comparison, nil
+}
+
+// preference says whether lower or higher numbers win for an attribute.
+type preference int
+
+const (
+	preferNone preference = iota
+	preferLower
+	preferHigher
+)
+
+func diffAttributes(entries []ComparedListing, f format.Formatter) []AttributeDiff {
+	text := func(name string, value func(ComparedListing) string) AttributeDiff {
+		values := make([]any, len(entries))
+		display := make([]string, len(entries))
+		for idx, e := range entries {
+			v := value(e)
+			values[idx] = v
+			display[idx] = v
+		}
+		return finishDiff(name, values, display, nil, preferNone)
+	}
+	number := func(name string, pref preference, value func(ComparedListing) (float64, bool), render func(float64) string) AttributeDiff {
+		values := make([]any, len(entries))
+		display := make([]string, len(entries))
+		numbers := make([]*float64, len(entries))
+		for idx, e := range entries {
+			v, ok := value(e)
+			if !ok {
+				display[idx] = "—"
+				continue
+			}
+			values[idx] = v
+			display[idx] = render(v)
+			numbers[idx] = &v
+		}
+		return finishDiff(name, values, display, numbers, pref)
+	}
+	money := func(pick func(ComparedListing) *fx.Money) func(ComparedListing) (float64, bool) {
+		return func(e ComparedListing) (float64, bool) {
+			if m := pick(e); m != nil {
+				return m.Amount, true
+			}
+			return 0, false
+		}
+	}
+	currency := ""
+	for _, e := range entries {
+		if e.Price != nil {
+			currency = e.Price.Currency
+			break
+		}
+	}
+	formatMoney := func(v float64) string { return f.Money(math.Round(v), currency) }
+	formatArea := func(unit string) func(float64) string {
+		return func(v float64) string { return f.Number(math.Round(v), 0) + "\u00a0" + unit }
+	}
+	formatCount := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+	return []AttributeDiff{
+		text("type", func(e ComparedListing) string { return string(e.Listing.Type) }),
+		text("status", func(e ComparedListing) string { return string(e.Listing.Status) }),
+		text("country", func(e ComparedListing) string { return e.Listing.Country }),
+		text("city", func(e ComparedListing) string { return e.Listing.City }),
+		number("price", preferLower, money(func(e ComparedListing) *fx.Money { return e.Price }), formatMoney),
+		number("area_sqm", preferHigher, func(e ComparedListing) (float64, bool) { return e.AreaSqM, e.AreaSqM > 0 }, formatArea("m²")),
+		number("area_sqft", preferHigher, func(e ComparedListing) (float64, bool) { return e.AreaSqFt, e.AreaSqFt > 0 }, formatArea("sq\u00a0ft")),
+		number("price_per_sqm", preferLower, money(func(e ComparedListing) *fx.Money { return e.PricePerSqM }), formatMoney),
+		number("price_per_sqft", preferLower, money(func(e ComparedListing) *fx.Money { return e.PricePerSqFt }), formatMoney),
+		number("bedrooms", preferHigher, func(e ComparedListing) (float64, bool) {
+			return float64(e.Listing.Bedrooms), e.Listing.Type != ListingTypeLand
+		}, formatCount),
+		number("bathrooms", preferHigher, func(e ComparedListing) (float64, bool) {
+			return e.Listing.Bathrooms, e.Listing.Type != ListingTypeLand
+		}, formatCount),
+	}
+}
+
+func finishDiff(name string, values []any, display []string, numbers []*float64, pref preference) AttributeDiff {
+	diff := AttributeDiff{Attribute: name, Values: values, Display: display, Same: true}
+	for idx := 1; idx < len(display); idx++ {
+		if display[idx] != display[0] {
+			diff.Same = false
+			break
+		}
+	}
+	if diff.Same || pref == preferNone {
+		return diff
+	}
+	best := -1
+	for idx, n := range numbers {
+		if n == nil {
+			continue
+		}
+		if best < 0 ||
+			(pref == preferLower && *n < *numbers[best]) ||
+			(pref == preferHigher && *n > *numbers[best]) {
+			best = idx
+		}
+	}
+	if best >= 0 {
+		diff.Best = &best
+	}
+	return diff
+}
+
+func roundTo(value float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(value*scale) / scale
+}
@@ -0,0 +1,31 @@
+package listing
+
+import (
+	"strings"
+
+	"shanraq.com/internal/services/fx"
+)
+
+// ApplyCurrency sets the listing's converted price in the target currency. It clears
+// the conversion when no currency is requested or the listing's currency has no rate.
+func ApplyCurrency(l *Listing, table fx.Table, currency string) {
+	l.ConvertedPrice = nil
+	if currency == "" {
+		return
+	}
+	amount, err := table.Convert(l.Price, l.Currency, currency)
+	if err != nil {
+		return
+	}
+	l.ConvertedPrice = &fx.Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// baseAmount normalises the price to the FX base currency so listings priced in
+// different currencies can be ordered against each other.
+func baseAmount(l Listing, table fx.Table) (float64, bool) {
+	amount, err := table.Convert(l.Price, l.Currency, fx.BaseCurrency)
+	if err != nil {
+		return 0, false
+	}
+	return amount, true
+}
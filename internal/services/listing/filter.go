@@ -0,0 +1,457 @@
+package listing
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"shanraq.com/internal/services/fx"
+)
+
+// SortOrder enumerates supported listing orderings.
+type SortOrder string
+
+const (
+	SortNewest    SortOrder = "newest"
+	SortPriceAsc  SortOrder = "price_asc"
+	SortPriceDesc SortOrder = "price_desc"
+	SortAreaDesc  SortOrder = "area_desc"
+	SortTitle     SortOrder = "title"
+	SortDistance  SortOrder = "distance"
+)
+
+// Valid reports whether the sort order is supported.
+func (o SortOrder) Valid() bool {
+	switch o {
+	case SortNewest, SortPriceAsc, SortPriceDesc, SortAreaDesc, SortTitle, SortDistance:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	// DefaultPageSize is the page size of List and Search calls that ask for none.
+	DefaultPageSize = 50
+	// MaxPageSize caps the number of listings returned by a single List call.
+	MaxPageSize = 100
+)
+
+// ListFilter captures query parameters for listing searches.
+type ListFilter struct {
+	Country           string
+	City              string
+	Type              ListingType
+	Tenure            Tenure
+	MinPrice          float64
+	MaxPrice          float64
+	MinBedrooms       int
+	MinBathrooms      float64
+	MinArea           float64
+	MaxArea           float64
+	Tags              []string
+	AgencyID          uuid.UUID
+	IDs               []uuid.UUID
+	Status            Status
+	Viewer            Viewer
+	PriceDroppedSince time.Time
+	PublishedSince    time.Time
+	Near              *GeoPoint
+	RadiusKm          float64
+	BBox              *BoundingBox
+	Polygon           []GeoPoint
+	Currency          string
+	// CollapseDuplicates hides listings a reviewer merged into another published
+	// listing, except from members of the hidden listing's agency.
+	CollapseDuplicates bool
+	Sort               SortOrder
+	Limit              int
+	Offset             int
+}
+
+// FacetCount is the number of matching listings sharing a value.
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Facets aggregates counts for the filter dimensions shown in the search UI.
+type Facets struct {
+	Countries []FacetCount `json:"countries"`
+	Types     []FacetCount `json:"types"`
+	Tags      []FacetCount `json:"tags"`
+}
+
+// ParseFilter builds a ListFilter from URL query parameters.
+func ParseFilter(values url.Values) (ListFilter, error) {
+	var filter ListFilter
+	var err error
+
+	filter.Country = strings.ToUpper(strings.TrimSpace(values.Get("country")))
+	filter.City = strings.TrimSpace(values.Get("city"))
+	if v := strings.TrimSpace(values.Get("type")); v != "" {
+		filter.Type = ListingType(strings.ToLower(v))
+		if !filter.Type.Valid() {
+			return ListFilter{}, fmt.Errorf("unknown listing type %q", v)
+		}
+	}
+	if v := strings.TrimSpace(values.Get("tenure")); v != "" {
+		filter.Tenure = Tenure(strings.ToLower(v))
+		if !filter.Tenure.Valid() {
+			return ListFilter{}, fmt.Errorf("unknown tenure %q", v)
+		}
+	}
+	if filter.MinPrice, err = parseFloatParam(values, "min_price"); err != nil {
+		return ListFilter{}, err
+	}
+	if filter.MaxPrice, err = parseFloatParam(values, "max_price"); err != nil {
+		return ListFilter{}, err
+	}
+	if filter.MinBedrooms, err = parseIntParam(values, "min_bedrooms"); err != nil {
+		return ListFilter{}, err
+	}
+	if filter.MinBathrooms, err = parseFloatParam(values, "min_bathrooms"); err != nil {
+		return ListFilter{}, err
+	}
+	if filter.MinArea, err = parseFloatParam(values, "min_area"); err != nil {
+		return ListFilter{}, err
+	}
+	if filter.MaxArea, err = parseFloatParam(values, "max_area"); err != nil {
+		return ListFilter{}, err
+	}
+	for _, raw := range values["tags"] {
+		filter.Tags = append(filter.Tags, strings.Split(raw, ",")...)
+	}
+	filter.Tags = normalizeTags(filter.Tags)
+	if v := strings.TrimSpace(values.Get("agency_id")); v != "" {
+		if filter.AgencyID, err = uuid.Parse(v); err != nil {
+			return ListFilter{}, fmt.Errorf("agency_id must be a UUID")
+		}
+	}
+	if v := strings.TrimSpace(values.Get("status")); v != "" {
+		filter.Status = Status(strings.ToLower(v))
+		if !filter.Status.Valid() {
+			return ListFilter{}, fmt.Errorf("unknown status %q", v)
+		}
+	}
+	if v := strings.TrimSpace(values.Get("price_dropped_since")); v != "" {
+		if filter.PriceDroppedSince, err = parseTimeParam(v); err != nil {
+			return ListFilter{}, fmt.Errorf("price_dropped_since must be a date (YYYY-MM-DD) or RFC 3339 timestamp")
+		}
+	}
+	if v := strings.TrimSpace(values.Get("published_since")); v != "" {
+		if filter.PublishedSince, err = parseTimeParam(v); err != nil {
+			return ListFilter{}, fmt.Errorf("published_since must be a date (YYYY-MM-DD) or RFC 3339 timestamp")
+		}
+	}
+	if v := strings.ToUpper(strings.TrimSpace(values.Get("currency"))); v != "" {
+		if !isAlphaCode(v, 3) {
+			return ListFilter{}, fmt.Errorf("currency must be an ISO 4217 code")
+		}
+		filter.Currency = v
+	}
+	if v := strings.TrimSpace(values.Get("near")); v != "" {
+		point, err := parsePoint(v)
+		if err != nil {
+			return ListFilter{}, err
+		}
+		filter.Near = &point
+	}
+	if filter.RadiusKm, err = parseFloatParam(values, "radius_km"); err != nil {
+		return ListFilter{}, err
+	}
+	if filter.RadiusKm > 0 && filter.Near == nil {
+		return ListFilter{}, fmt.Errorf("radius_km requires near")
+	}
+	if v := strings.TrimSpace(values.Get("bbox")); v != "" {
+		box, err := parseBoundingBox(v)
+		if err != nil {
+			return ListFilter{}, err
+		}
+		filter.BBox = &box
+	}
+	if v := strings.TrimSpace(values.Get("polygon")); v != "" {
+		if filter.Polygon, err = parsePolygon(v); err != nil {
+			return ListFilter{}, err
+		}
+	}
+	if v := strings.TrimSpace(values.Get("sort")); v != "" {
+		filter.Sort = SortOrder(strings.ToLower(v))
+		if !filter.Sort.Valid() {
+			return ListFilter{}, fmt.Errorf("unknown sort order %q", v)
+		}
+		if filter.Sort == SortDistance && filter.Near == nil {
+			return ListFilter{}, fmt.Errorf("sort=distance requires near")
+		}
+	}
+	if filter.Limit, err = parseIntParam(values, "limit"); err != nil {
+		return ListFilter{}, err
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultPageSize
+	}
+	if filter.Limit > MaxPageSize {
+		filter.Limit = MaxPageSize
+	}
+	if filter.Offset, err = parseIntParam(values, "offset"); err != nil {
+		return ListFilter{}, err
+	}
+	filter.CollapseDuplicates = true
+	if v := strings.TrimSpace(values.Get("include_duplicates")); v != "" {
+		include, err := strconv.ParseBool(v)
+		if err != nil {
+			return ListFilter{}, fmt.Errorf("include_duplicates must be a boolean")
+		}
+		filter.CollapseDuplicates = !include
+	}
+	if filter.MaxPrice > 0 && filter.MinPrice > filter.MaxPrice {
+		return ListFilter{}, fmt.Errorf("min_price cannot exceed max_price")
+	}
+	if filter.MaxArea > 0 && filter.MinArea > filter.MaxArea {
+		return ListFilter{}, fmt.Errorf("min_area cannot exceed max_area")
+	}
+	return filter, nil
+}
+
+// Matches reports whether the listing satisfies every criterion of the filter.
+// Listings hidden from the viewer never match. Pagination and sorting are ignored.
+// When the filter names a currency, price bounds apply to the listing's converted
+// price, so ApplyCurrency must run first.
+func (f ListFilter) Matches(l Listing) bool {
+	if !f.Viewer.CanSee(l) {
+		return false
+	}
+	if f.Status != "" && f.Status != l.Status {
+		return false
+	}
+	if f.Country != "" && !strings.EqualFold(f.Country, l.Country) {
+		return false
+	}
+	if f.City != "" && !strings.EqualFold(f.City, l.City) {
+		return false
+	}
+	if f.Type != "" && f.Type != l.Type {
+		return false
+	}
+	if f.Tenure != "" && f.Tenure != l.Tenure {
+		return false
+	}
+	if f.MinPrice > 0 || f.MaxPrice > 0 {
+		price, ok := f.price(l)
+		if !ok || (f.MinPrice > 0 && price < f.MinPrice) || (f.MaxPrice > 0 && price > f.MaxPrice) {
+			return false
+		}
+	}
+	if !f.PriceDroppedSince.IsZero() {
+		if !l.PriceDropped() || l.PriceChangedAt == nil || l.PriceChangedAt.Before(f.PriceDroppedSince) {
+			return false
+		}
+	}
+	if !f.PublishedSince.IsZero() && (l.PublishedAt == nil || l.PublishedAt.Before(f.PublishedSince)) {
+		return false
+	}
+	if f.MinBedrooms > 0 && l.Bedrooms < f.MinBedrooms {
+		return false
+	}
+	if f.MinBathrooms > 0 && l.Bathrooms < f.MinBathrooms {
+		return false
+	}
+	if f.MinArea > 0 && l.AreaSqM < f.MinArea {
+		return false
+	}
+	if f.MaxArea > 0 && l.AreaSqM > f.MaxArea {
+		return false
+	}
+	if f.AgencyID != uuid.Nil && f.AgencyID != l.AgencyID {
+		return false
+	}
+	if len(f.IDs) > 0 && !containsID(f.IDs, l.ID) {
+		return false
+	}
+	for _, tag := range f.Tags {
+		if !containsFold(l.Tags, tag) {
+			return false
+		}
+	}
+	if f.hasGeoConstraint() {
+		if l.Location == nil {
+			return false
+		}
+		if f.Near != nil && f.RadiusKm > 0 && HaversineKm(*f.Near, *l.Location) > f.RadiusKm {
+			return false
+		}
+		if f.BBox != nil && !f.BBox.Contains(*l.Location) {
+			return false
+		}
+		if len(f.Polygon) > 0 && !polygonContains(f.Polygon, *l.Location) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// price returns the amount price bounds are compared against.
+func (f ListFilter) price(l Listing) (float64, bool) {
+	if f.Currency == "" {
+		return l.Price, true
+	}
+	if l.ConvertedPrice == nil {
+		return 0, false
+	}
+	return l.ConvertedPrice.Amount, true
+}
+
+func (f ListFilter) hasGeoConstraint() bool {
+	return (f.Near != nil && f.RadiusKm > 0) || f.BBox != nil || len(f.Polygon) > 0
+}
+
+// withDistance annotates the listing with its distance from the origin when coordinates are known.
+func withDistance(l *Listing, origin GeoPoint) {
+	if l.Location == nil {
+		l.DistanceKm = nil
+		return
+	}
+	distance := roundKm(HaversineKm(origin, *l.Location))
+	l.DistanceKm = &distance
+}
+
+// sortListings orders listings in place; price orderings compare amounts normalised
+// through the rate table and place unconvertible prices last.
+func sortListings(listings []Listing, order SortOrder, table fx.Table) {
+	sort.SliceStable(listings, func(i, j int) bool {
+		a, b := listings[i], listings[j]
+		switch order {
+		case SortPriceAsc, SortPriceDesc:
+			priceA, okA := baseAmount(a, table)
+			priceB, okB := baseAmount(b, table)
+			if !okA || !okB {
+				return okA
+			}
+			if order == SortPriceAsc {
+				return priceA < priceB
+			}
+			return priceA > priceB
+		case SortAreaDesc:
+			return a.AreaSqM > b.AreaSqM
+		case SortTitle:
+			return a.Title < b.Title
+		case SortDistance:
+			if a.DistanceKm == nil || b.DistanceKm == nil {
+				return a.DistanceKm != nil
+			}
+			return *a.DistanceKm < *b.DistanceKm
+		default:
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+	})
+}
+
+func paginate(listings []Listing, limit, offset int) []Listing {
+	if offset > len(listings) {
+		return []Listing{}
+	}
+	end := len(listings)
+	if limit = pageLimit(limit); offset+limit < end {
+		end = offset + limit
+	}
+	return listings[offset:end]
+}
+
+// pageLimit applies DefaultPageSize to callers that did not choose a page size,
+// so both implementations return the same pages.
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultPageSize
+	}
+	return limit
+}
+
+func computeFacets(listings []Listing) Facets {
+	countries := make(map[string]int)
+	types := make(map[string]int)
+	tags := make(map[string]int)
+	for _, l := range listings {
+		countries[l.Country]++
+		types[string(l.Type)]++
+		for _, tag := range l.Tags {
+			tags[tag]++
+		}
+	}
+	return Facets{
+		Countries: facetCounts(countries),
+		Types:     facetCounts(types),
+		Tags:      facetCounts(tags),
+	}
+}
+
+func facetCounts(counts map[string]int) []FacetCount {
+	result := make([]FacetCount, 0, len(counts))
+	for value, count := range counts {
+		if value == "" {
+			continue
+		}
+		result = append(result, FacetCount{Value: value, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Value < result[j].Value
+	})
+	return result
+}
+
+func containsFold(values []string, target string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseFloatParam(values url.Values, key string) (float64, error) {
+	raw := strings.TrimSpace(values.Get(key))
+	if raw == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative number", key)
+	}
+	return v, nil
+}
+
+// parseTimeParam accepts a calendar date (UTC midnight) or an RFC 3339 timestamp.
+func parseTimeParam(raw string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", raw); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
+
+func parseIntParam(values url.Values, key string) (int, error) {
+	raw := strings.TrimSpace(values.Get(key))
+	if raw == "" {
+		return 0, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", key)
+	}
+	return v, nil
+}
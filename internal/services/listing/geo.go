@@ -0,0 +1,147 @@
+package listing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// earthRadiusKm is the mean Earth radius used for haversine distances.
+const earthRadiusKm = 6371.0088
+
+// GeoPoint is a WGS84 coordinate.
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// Valid reports whether the point lies within WGS84 bounds.
+func (p GeoPoint) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180
+}
+
+// BoundingBox follows the GeoJSON bbox order: west, south, east, north.
+// A box whose MinLng exceeds MaxLng crosses the antimeridian.
+type BoundingBox struct {
+	MinLng float64
+	MinLat float64
+	MaxLng float64
+	MaxLat float64
+}
+
+// Contains reports whether the point falls inside the box.
+func (b BoundingBox) Contains(p GeoPoint) bool {
+	if p.Lat < b.MinLat || p.Lat > b.MaxLat {
+		return false
+	}
+	if b.MinLng <= b.MaxLng {
+		return p.Lng >= b.MinLng && p.Lng <= b.MaxLng
+	}
+	return p.Lng >= b.MinLng || p.Lng <= b.MaxLng
+}
+
+// HaversineKm returns the great-circle distance between two points in kilometres.
+func HaversineKm(a, b GeoPoint) float64 {
+	lat1 := a.Lat * math.Pi / 180
+	lat2 := b.Lat * math.Pi / 180
+	dLat := (b.Lat - a.Lat) * math.Pi / 180
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// roundKm rounds a distance to ten metres for presentation.
+func roundKm(km float64) float64 {
+	return math.Round(km*100) / 100
+}
+
+// polygonContains applies the even-odd ray casting rule to a closed or open ring.
+func polygonContains(ring []GeoPoint, p GeoPoint) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) {
+			crossLng := (b.Lng-a.Lng)*(p.Lat-a.Lat)/(b.Lat-a.Lat) + a.Lng
+			if p.Lng < crossLng {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// parsePoint parses "lat,lng".
+func parsePoint(raw string) (GeoPoint, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) != 2 {
+		return GeoPoint{}, errors.New("near must be formatted as lat,lng")
+	}
+	lat, errLat := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	lng, errLng := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	point := GeoPoint{Lat: lat, Lng: lng}
+	if errLat != nil || errLng != nil || !point.Valid() {
+		return GeoPoint{}, errors.New("near must contain a valid latitude and longitude")
+	}
+	return point, nil
+}
+
+// parseBoundingBox parses "minLng,minLat,maxLng,maxLat".
+func parseBoundingBox(raw string) (BoundingBox, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) != 4 {
+		return BoundingBox{}, errors.New("bbox must be formatted as minLng,minLat,maxLng,maxLat")
+	}
+	values := make([]float64, 4)
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return BoundingBox{}, errors.New("bbox must contain four numbers")
+		}
+		values[i] = v
+	}
+	box := BoundingBox{MinLng: values[0], MinLat: values[1], MaxLng: values[2], MaxLat: values[3]}
+	if !(GeoPoint{Lat: box.MinLat, Lng: box.MinLng}).Valid() || !(GeoPoint{Lat: box.MaxLat, Lng: box.MaxLng}).Valid() || box.MinLat > box.MaxLat {
+		return BoundingBox{}, errors.New("bbox coordinates are out of range")
+	}
+	return box, nil
+}
+
+// parsePolygon parses a GeoJSON Polygon geometry and returns its exterior ring.
+func parsePolygon(raw string) ([]GeoPoint, error) {
+	var geometry struct {
+		Type        string         `json:"type"`
+		Coordinates [][][2]float64 `json:"coordinates"`
+	}
+	if err := json.Unmarshal([]byte(raw), &geometry); err != nil {
+		return nil, errors.New("polygon must be a GeoJSON Polygon geometry")
+	}
+	if !strings.EqualFold(geometry.Type, "Polygon") || len(geometry.Coordinates) == 0 {
+		return nil, errors.New("polygon must be a GeoJSON Polygon geometry")
+	}
+	exterior := geometry.Coordinates[0]
+	if len(exterior) < 4 {
+		return nil, errors.New("polygon ring needs at least four positions")
+	}
+	ring := make([]GeoPoint, 0, len(exterior))
+	for _, position := range exterior {
+		point := GeoPoint{Lng: position[0], Lat: position[1]}
+		if !point.Valid() {
+			return nil, errors.New("polygon coordinates are out of range")
+		}
+		ring = append(ring, point)
+	}
+	return ring, nil
+}
+
+// polygonLiteral renders a ring in PostgreSQL's native polygon syntax with (x=lng, y=lat) pairs.
+func polygonLiteral(ring []GeoPoint) string {
+	parts := make([]string, 0, len(ring))
+	for _, p := range ring {
+		parts = append(parts, fmt.Sprintf("(%g,%g)", p.Lng, p.Lat))
+	}
+	return "(" + strings.Join(parts, ",") + ")"
+}
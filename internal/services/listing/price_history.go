@@ -0,0 +1,62 @@
+package listing
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PriceChange is a recorded update of a listing's asking price.
+type PriceChange struct {
+	ID          uuid.UUID `json:"id"`
+	ListingID   uuid.UUID `json:"listing_id"`
+	OldPrice    float64   `json:"old_price"`
+	OldCurrency string    `json:"old_currency"`
+	NewPrice    float64   `json:"new_price"`
+	NewCurrency string    `json:"new_currency"`
+	ChangedAt   time.Time `json:"changed_at"`
+}
+
+// Dropped reports whether the change lowered the price within the same currency.
+func (c PriceChange) Dropped() bool {
+	return c.OldCurrency == c.NewCurrency && c.NewPrice < c.OldPrice
+}
+
+// PriceDropped reports whether the most recent price change was a reduction.
+func (l Listing) PriceDropped() bool {
+	return l.PreviousPrice != nil && *l.PreviousPrice > l.Price
+}
+
+// PriceDropPercent returns the last reduction as a whole percentage, or 0.
+func (l Listing) PriceDropPercent() int {
+	if !l.PriceDropped() || *l.PreviousPrice == 0 {
+		return 0
+	}
+	return int(math.Round((*l.PreviousPrice - l.Price) / *l.PreviousPrice * 100))
+}
+
+// trackPriceChange compares the listing before and after an update. When the price
+// or currency moved it stamps the previous price on updated and returns the change.
+func trackPriceChange(before Listing, updated *Listing, now time.Time) (PriceChange, bool) {
+	if before.Price == updated.Price && before.Currency == updated.Currency {
+		return PriceChange{}, false
+	}
+	change := PriceChange{
+		ID:          uuid.New(),
+		ListingID:   before.ID,
+		OldPrice:    before.Price,
+		OldCurrency: before.Currency,
+		NewPrice:    updated.Price,
+		NewCurrency: updated.Currency,
+		ChangedAt:   now,
+	}
+	updated.PreviousPrice = nil
+	if before.Currency == updated.Currency {
+		previous := before.Price
+		updated.PreviousPrice = &previous
+	}
+	changedAt := now
+	updated.PriceChangedAt = &changedAt
+	return change, true
+}
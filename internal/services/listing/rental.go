@@ -0,0 +1,110 @@
+package listing
+
+import (
+	"errors"
+	"strings"
+)
+
+// Tenure distinguishes listings offered for sale from rentals.
+type Tenure string
+
+const (
+	TenureSale Tenure = "sale"
+	TenureRent Tenure = "rent"
+)
+
+// Valid reports whether the tenure is supported.
+func (t Tenure) Valid() bool {
+	return t == TenureSale || t == TenureRent
+}
+
+// RentPeriod is the unit a rent is quoted per.
+type RentPeriod string
+
+const (
+	RentPerNight RentPeriod = "night"
+	RentPerWeek  RentPeriod = "week"
+	RentPerMonth RentPeriod = "month"
+	RentPerYear  RentPeriod = "year"
+)
+
+// Valid reports whether the period is supported.
+func (p RentPeriod) Valid() bool {
+	switch p {
+	case RentPerNight, RentPerWeek, RentPerMonth, RentPerYear:
+		return true
+	default:
+		return false
+	}
+}
+
+// Nights approximates the length of one period for minimum-stay checks.
+func (p RentPeriod) Nights() int {
+	switch p {
+	case RentPerWeek:
+		return 7
+	case RentPerMonth:
+		return 30
+	case RentPerYear:
+		return 365
+	default:
+		return 1
+	}
+}
+
+// RentalTerms describe a rental. The listing's Price is the rent per Period, MinStay
+// counts periods and Deposit is in the listing currency.
+type RentalTerms struct {
+	Period  RentPeriod `json:"period"`
+	MinStay int        `json:"min_stay"`
+	Deposit float64    `json:"deposit"`
+}
+
+// MinNights is the shortest booking the terms allow.
+func (t RentalTerms) MinNights() int {
+	if t.MinStay <= 0 {
+		return 1
+	}
+	return t.MinStay * t.Period.Nights()
+}
+
+// IsRental reports whether the listing is offered for rent.
+func (l Listing) IsRental() bool {
+	return l.Tenure == TenureRent && l.Rental != nil
+}
+
+// normalizeTenure validates the tenure together with its rental terms. Sales drop
+// any rental terms; rentals require them.
+func normalizeTenure(tenure Tenure, terms *RentalTerms) (Tenure, *RentalTerms, error) {
+	tenure = Tenure(strings.ToLower(strings.TrimSpace(string(tenure))))
+	if tenure == "" {
+		tenure = TenureSale
+		if terms != nil {
+			tenure = TenureRent
+		}
+	}
+	if !tenure.Valid() {
+		return "", nil, errors.New("tenure must be sale or rent")
+	}
+	if tenure == TenureSale {
+		return tenure, nil, nil
+	}
+	if terms == nil {
+		return "", nil, errors.New("rental terms are required for rentals")
+	}
+	normalized := *terms
+	normalized.Period = RentPeriod(strings.ToLower(strings.TrimSpace(string(normalized.Period))))
+	if !normalized.Period.Valid() {
+		return "", nil, errors.New("rent period must be night, week, month, or year")
+	}
+	if normalized.MinStay < 0 {
+		return "", nil, errors.New("minimum stay cannot be negative")
+	}
+	if normalized.MinStay == 0 {
+		normalized.MinStay = 1
+	}
+	if normalized.Deposit < 0 {
+		return "", nil, errors.New("deposit cannot be negative")
+	}
+	return tenure, &normalized, nil
+}
@@ -0,0 +1,247 @@
+package listing
+
+import (
+	"context"
+	"errors"
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SearchQuery describes a free-text listing search.
+type SearchQuery struct {
+	Text     string
+	Language string
+	Filter   ListFilter
+}
+
+// SearchResult is a listing ranked against a free-text query.
+type SearchResult struct {
+	Listing
+	Rank       float64    `json:"rank"`
+	Highlights Highlights `json:"highlights"`
+}
+
+// Highlights carries HTML-escaped listing text with query matches wrapped in <mark>
+// tags, safe to render as HTML.
+type Highlights struct {
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"`
+}
+
+// ErrEmptyQuery is returned when a search has no usable terms.
+var ErrEmptyQuery = errors.New("search query is empty")
+
+// SearchLanguages maps supported request languages to PostgreSQL text search configurations.
+var SearchLanguages = map[string]string{
+	"en": "english",
+	"sv": "swedish",
+	"pt": "portuguese",
+	"it": "italian",
+	"ar": "arabic",
+}
+
+// NormalizeSearchLanguage reduces a language tag such as "sv-SE" to a supported primary subtag.
+func NormalizeSearchLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if idx := strings.IndexAny(lang, "-_"); idx >= 0 {
+		lang = lang[:idx]
+	}
+	if _, ok := SearchLanguages[lang]; ok {
+		return lang
+	}
+	return "en"
+}
+
+// Field weights mirror PostgreSQL's default ts_rank weights for labels A, B and C.
+const (
+	weightTitle    = 1.0
+	weightLocation = 0.4
+	weightSummary  = 0.2
+)
+
+// Search ranks listings with a token matcher comparable to the PostgreSQL tsvector search.
+func (s *InMemoryService) Search(ctx context.Context, query SearchQuery) ([]SearchResult, int, error) {
+	lang := NormalizeSearchLanguage(query.Language)
+	terms := tokenize(query.Text, lang)
+	if len(terms) == 0 {
+		return nil, 0, ErrEmptyQuery
+	}
+
+	matches, _, err := s.filter(ctx, query.Filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	results := make([]SearchResult, 0)
+	for _, l := range matches {
+		rank, ok := scoreListing(l, terms, lang)
+		if !ok {
+			continue
+		}
+		results = append(results, SearchResult{
+			Listing: l,
+			Rank:    rank,
+			Highlights: Highlights{
+				Title:   highlight(l.Title, terms, lang),
+				Snippet: highlight(l.Summary, terms, lang),
+			},
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].Title < results[j].Title
+	})
+
+	total := len(results)
+	start := query.Filter.Offset
+	if start > total {
+		return []SearchResult{}, total, nil
+	}
+	end := total
+	if limit := pageLimit(query.Filter.Limit); start+limit < end {
+		end = start + limit
+	}
+	return results[start:end], total, nil
+}
+
+// searchTerm keeps both the folded surface form and its stem, matching the
+// "simple" and language-specific halves of the SQL search vector.
+type searchTerm struct {
+	raw  string
+	stem string
+}
+
+func (t searchTerm) matches(other searchTerm) bool {
+	return t.raw == other.raw || t.stem == other.stem
+}
+
+// scoreListing requires every query term to appear in at least one field.
+func scoreListing(l Listing, terms []searchTerm, lang string) (float64, bool) {
+	fields := []struct {
+		tokens []searchTerm
+		weight float64
+	}{
+		{tokenize(l.Title, lang), weightTitle},
+		{tokenize(strings.Join([]string{l.Neighborhood, l.City, l.Region, strings.Join(l.Tags, " ")}, " "), lang), weightLocation},
+		{tokenize(l.Summary, lang), weightSummary},
+	}
+
+	var rank float64
+	for _, term := range terms {
+		best := 0.0
+		for _, field := range fields {
+			for _, token := range field.tokens {
+				if token.matches(term) && field.weight > best {
+					best = field.weight
+				}
+			}
+		}
+		if best == 0 {
+			return 0, false
+		}
+		rank += best
+	}
+	return rank / float64(len(terms)), true
+}
+
+// highlight wraps words matching any term in <mark> tags, preserving the original
+// text. Everything else is HTML-escaped so listing copy cannot inject markup.
+func highlight(text string, terms []searchTerm, lang string) string {
+	var b strings.Builder
+	word := make([]rune, 0, 16)
+	flush := func() {
+		if len(word) == 0 {
+			return
+		}
+		w := string(word)
+		matched := false
+		for _, token := range tokenize(w, lang) {
+			for _, term := range terms {
+				if token.matches(term) {
+					matched = true
+				}
+			}
+		}
+		if matched {
+			b.WriteString("<mark>" + html.EscapeString(w) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(w))
+		}
+		word = word[:0]
+	}
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			word = append(word, r)
+			continue
+		}
+		flush()
+		b.WriteString(html.EscapeString(string(r)))
+	}
+	flush()
+	return b.String()
+}
+
+func tokenize(text, lang string) []searchTerm {
+	fields := strings.FieldsFunc(strings.ToLower(foldAccents(text)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([]searchTerm, 0, len(fields))
+	for _, f := range fields {
+		if isStopWord(f, lang) {
+			continue
+		}
+		terms = append(terms, searchTerm{raw: f, stem: stem(f, lang)})
+	}
+	return terms
+}
+
+// stemSuffixes lists accent-folded inflection suffixes stripped per language, longest first.
+var stemSuffixes = map[string][]string{
+	"en": {"ational", "ations", "ation", "ness", "ies", "ing", "ers", "ed", "es", "er", "ly", "s"},
+	"sv": {"arna", "erna", "orna", "ande", "ende", "het", "ar", "er", "or", "en", "et", "a", "e"},
+	"pt": {"mente", "coes", "cao", "oes", "ais", "eis", "os", "as", "es", "o", "a", "s"},
+	"it": {"mente", "zioni", "zione", "ita", "i", "e", "o", "a"},
+	"ar": {"ات", "ون", "ين", "ة"},
+}
+
+// stem applies a light suffix-stripping stemmer; it is deliberately conservative
+// so that short place names such as "Gion" survive untouched.
+func stem(word, lang string) string {
+	if len([]rune(word)) <= 4 {
+		return word
+	}
+	for _, suffix := range stemSuffixes[lang] {
+		if strings.HasSuffix(word, suffix) && len([]rune(word))-len([]rune(suffix)) >= 3 {
+			trimmed := strings.TrimSuffix(word, suffix)
+			if lang == "en" && suffix == "ies" {
+				trimmed += "y"
+			}
+			return trimmed
+		}
+	}
+	return word
+}
+
+var stopWords = map[string]map[string]struct{}{
+	"en": wordSet("a", "an", "and", "at", "for", "in", "of", "on", "the", "to", "with"),
+	"sv": wordSet("och", "i", "med", "en", "ett", "pa", "av", "till"),
+	"pt": wordSet("a", "o", "e", "de", "da", "do", "em", "com", "para"),
+	"it": wordSet("il", "la", "e", "di", "da", "in", "con", "per"),
+}
+
+func isStopWord(word, lang string) bool {
+	_, ok := stopWords[lang][word]
+	return ok
+}
+
+func wordSet(words ...string) map[string]struct{} {
+	set := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		set[w] = struct{}{}
+	}
+	return set
+}
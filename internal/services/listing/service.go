@@ -2,12 +2,18 @@ package listing
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
+
+	"shanraq.com/internal/format"
+	"shanraq.com/internal/i18n"
+	"shanraq.com/internal/services/fx"
 )
 
 // ListingType enumerates property categories.
@@ -19,69 +25,217 @@ const (
 	ListingTypeLand        ListingType = "land"
 )
 
+// Valid reports whether the listing type is one of the supported categories.
+func (t ListingType) Valid() bool {
+	switch t {
+	case ListingTypeResidential, ListingTypeCommercial, ListingTypeLand:
+		return true
+	default:
+		return false
+	}
+}
+
 // Listing represents an individual property.
 type Listing struct {
-	ID           uuid.UUID   `json:"id"`
-	Title        string      `json:"title"`
-	Type         ListingType `json:"type"`
-	Country      string      `json:"country"`
-	City         string      `json:"city"`
-	Region       string      `json:"region"`
-	Neighborhood string      `json:"neighborhood"`
-	Summary      string      `json:"summary"`
-	Price        float64     `json:"price"`
-	Currency     string      `json:"currency"`
-	Bedrooms     int         `json:"bedrooms"`
-	Bathrooms    float64     `json:"bathrooms"`
-	AreaSqM      float64     `json:"area_sqm"`
-	ImageURL     string      `json:"image_url"`
-	DetailsURL   string      `json:"details_url"`
-	AgencyID     uuid.UUID   `json:"agency_id"`
-	AgencyName   string      `json:"agency_name"`
-	Tags         []string    `json:"tags"`
-}
-
-// Service exposes read access to listings.
+	ID             uuid.UUID    `json:"id"`
+	Title          string       `json:"title"`
+	Locale         string       `json:"locale,omitempty"`
+	Slug           string       `json:"slug"`
+	Type           ListingType  `json:"type"`
+	Tenure         Tenure       `json:"tenure"`
+	Rental         *RentalTerms `json:"rental,omitempty"`
+	Status         Status       `json:"status"`
+	Country        string       `json:"country"`
+	City           string       `json:"city"`
+	Region         string       `json:"region"`
+	Neighborhood   string       `json:"neighborhood"`
+	Summary        string       `json:"summary"`
+	Price          float64      `json:"price"`
+	Currency       string       `json:"currency"`
+	ConvertedPrice *fx.Money    `json:"converted_price,omitempty"`
+	PreviousPrice  *float64     `json:"previous_price,omitempty"`
+	PriceChangedAt *time.Time   `json:"price_changed_at,omitempty"`
+	Bedrooms       int          `json:"bedrooms"`
+	Bathrooms      float64      `json:"bathrooms"`
+	AreaSqM        float64      `json:"area_sqm"`
+	PriceDisplay   string       `json:"display_price,omitempty"`
+	AreaDisplay    string       `json:"display_area,omitempty"`
+	ImageURL       string       `json:"image_url"`
+	DetailsURL     string       `json:"details_url"`
+	AgencyID       uuid.UUID    `json:"agency_id"`
+	AgencyName     string       `json:"agency_name"`
+	ExternalRef    string       `json:"external_ref,omitempty"`
+	Tags           []string     `json:"tags"`
+	Location       *GeoPoint    `json:"location,omitempty"`
+	DistanceKm     *float64     `json:"distance_km,omitempty"`
+	PublishedAt    *time.Time   `json:"published_at,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// CreateInput defines attributes required to publish a listing.
+type CreateInput struct {
+	Title        string
+	Type         ListingType
+	Tenure       Tenure
+	Rental       *RentalTerms
+	Country      string
+	City         string
+	Region       string
+	Neighborhood string
+	Summary      string
+	Price        float64
+	Currency     string
+	Bedrooms     int
+	Bathrooms    float64
+	AreaSqM      float64
+	ImageURL     string
+	AgencyID     uuid.UUID
+	ExternalRef  string
+	Tags         []string
+	Location     *GeoPoint
+}
+
+// UpdateInput defines mutable fields for a listing.
+type UpdateInput struct {
+	Title        *string
+	Type         *ListingType
+	Tenure       *Tenure
+	Rental       *RentalTerms
+	Country      *string
+	City         *string
+	Region       *string
+	Neighborhood *string
+	Summary      *string
+	Price        *float64
+	Currency     *string
+	Bedrooms     *int
+	Bathrooms    *float64
+	AreaSqM      *float64
+	ImageURL     *string
+	AgencyID     *uuid.UUID
+	Tags         *[]string
+	Location     *GeoPoint
+}
+
+// Service exposes read and write access to listings.
 type Service interface {
-	List(ctx context.Context) ([]Listing, error)
-	Featured(ctx context.Context, limit int) ([]Listing, error)
+	List(ctx context.Context, filter ListFilter) ([]Listing, int, error)
+	Facets(ctx context.Context, filter ListFilter) (Facets, error)
+	Search(ctx context.Context, query SearchQuery) ([]SearchResult, int, error)
 	Get(ctx context.Context, id uuid.UUID) (Listing, error)
+	GetBySlug(ctx context.Context, slug string) (Listing, error)
+	ReferenceIDs(ctx context.Context, agencyID uuid.UUID, refs []string) (map[string]uuid.UUID, error)
+	Create(ctx context.Context, input CreateInput) (Listing, error)
+	Update(ctx context.Context, id uuid.UUID, input UpdateInput) (Listing, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Transition(ctx context.Context, id uuid.UUID, input TransitionInput) (Listing, error)
+	History(ctx context.Context, id uuid.UUID) ([]StatusEvent, error)
+	PriceHistory(ctx context.Context, id uuid.UUID) ([]PriceChange, error)
+	Translations(ctx context.Context, id uuid.UUID) ([]Translation, error)
+	SetTranslation(ctx context.Context, id uuid.UUID, locale string, input TranslationInput) (Translation, error)
+	DeleteTranslation(ctx context.Context, id uuid.UUID, locale string) error
+	Localize(ctx context.Context, listings []Listing, locale string) error
 }
 
+// ErrNotFound is returned when a listing cannot be located.
+var ErrNotFound = errors.New("listing not found")
+
+// ErrDuplicateReference is returned when an agency reuses an external reference.
+var ErrDuplicateReference = errors.New("external reference already used by another listing of the agency")
+
 // InMemoryService is a seeded implementation.
 type InMemoryService struct {
-	mu       sync.RWMutex
-	listings []Listing
+	mu           sync.RWMutex
+	listings     []Listing
+	events       []StatusEvent
+	prices       []PriceChange
+	translations map[uuid.UUID]map[string]Translation
+	rates        fx.Service
+	merges       MergeSource
 }
 
-// NewInMemoryService seeds demo listings.
+// MergeSource maps listings a reviewer merged away to the listing kept in their
+// place.
+type MergeSource interface {
+	Merged(ctx context.Context) (map[uuid.UUID]uuid.UUID, error)
+}
+
+// NewInMemoryService seeds demo listings and converts prices with the demo FX rates.
 func NewInMemoryService() *InMemoryService {
-	svc := &InMemoryService{}
+	svc := &InMemoryService{rates: fx.NewInMemoryService()}
 	svc.seed()
 	return svc
 }
 
-func (s *InMemoryService) List(_ context.Context) ([]Listing, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// CollapseMerged lets List collapse listings merged by the review queue. Without a
+// source, CollapseDuplicates hides nothing.
+func (s *InMemoryService) CollapseMerged(source MergeSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.merges = source
+}
 
-	out := make([]Listing, len(s.listings))
-	copy(out, s.listings)
-	sort.Slice(out, func(i, j int) bool {
-		return out[i].Title < out[j].Title
-	})
-	return out, nil
+// List returns listings matching the filter along with the total match count.
+func (s *InMemoryService) List(ctx context.Context, filter ListFilter) ([]Listing, int, error) {
+	matches, table, err := s.filter(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	sortListings(matches, filter.Sort, table)
+	return paginate(matches, filter.Limit, filter.Offset), len(matches), nil
+}
+
+// Facets counts matching listings per country, type, and tag.
+func (s *InMemoryService) Facets(ctx context.Context, filter ListFilter) (Facets, error) {
+	matches, _, err := s.filter(ctx, filter)
+	if err != nil {
+		return Facets{}, err
+	}
+	return computeFacets(matches), nil
 }
 
-func (s *InMemoryService) Featured(ctx context.Context, limit int) ([]Listing, error) {
-	listings, err := s.List(ctx)
+// filter returns annotated copies of the listings matching the filter together with
+// the rate table used for currency conversion.
+func (s *InMemoryService) filter(ctx context.Context, filter ListFilter) ([]Listing, fx.Table, error) {
+	table, err := s.rates.Table(ctx)
 	if err != nil {
-		return nil, err
+		return nil, fx.Table{}, err
+	}
+	if filter.Currency != "" && !table.Has(filter.Currency) {
+		return nil, fx.Table{}, fx.ErrUnknownCurrency
+	}
+
+	var merged map[uuid.UUID]uuid.UUID
+	if filter.CollapseDuplicates {
+		s.mu.RLock()
+		source := s.merges
+		s.mu.RUnlock()
+		if source != nil {
+			if merged, err = source.Merged(ctx); err != nil {
+				return nil, fx.Table{}, err
+			}
+		}
 	}
-	if limit <= 0 || limit > len(listings) {
-		limit = len(listings)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	collapsed := s.collapsed(merged)
+	out := make([]Listing, 0, len(s.listings))
+	for _, l := range s.listings {
+		if _, hidden := collapsed[l.ID]; hidden && !filter.Viewer.IsMember(l.AgencyID) {
+			continue
+		}
+		ApplyCurrency(&l, table, filter.Currency)
+		if filter.Near != nil {
+			withDistance(&l, *filter.Near)
+		}
+		if filter.Matches(l) {
+			out = append(out, l)
+		}
 	}
-	return listings[:limit], nil
+	return out, table, nil
 }
 
 func (s *InMemoryService) Get(_ context.Context, id uuid.UUID) (Listing, error) {
@@ -93,7 +247,327 @@ func (s *InMemoryService) Get(_ context.Context, id uuid.UUID) (Listing, error)
 			return l, nil
 		}
 	}
-	return Listing{}, fmt.Errorf("listing %s not found", id)
+	return Listing{}, ErrNotFound
+}
+
+// GetBySlug returns the listing published under the slug.
+func (s *InMemoryService) GetBySlug(_ context.Context, slug string) (Listing, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, l := range s.listings {
+		if l.Slug == slug {
+			return l, nil
+		}
+	}
+	return Listing{}, ErrNotFound
+}
+
+// ReferenceIDs resolves the agency's external references to listing IDs. Unknown
+// references are omitted from the result.
+func (s *InMemoryService) ReferenceIDs(_ context.Context, agencyID uuid.UUID, refs []string) (map[string]uuid.UUID, error) {
+	wanted := make(map[string]struct{}, len(refs))
+	for _, ref := range refs {
+		wanted[ref] = struct{}{}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make(map[string]uuid.UUID)
+	for _, l := range s.listings {
+		if l.AgencyID != agencyID || l.ExternalRef == "" {
+			continue
+		}
+		if _, ok := wanted[l.ExternalRef]; ok {
+			out[l.ExternalRef] = l.ID
+		}
+	}
+	return out, nil
+}
+
+// Create validates the input and stores a new listing under a unique slug.
+func (s *InMemoryService) Create(_ context.Context, input CreateInput) (Listing, error) {
+	input, err := normalizeCreateInput(input)
+	if err != nil {
+		return Listing{}, err
+	}
+
+	now := time.Now().UTC()
+	listing := Listing{
+		ID:           uuid.New(),
+		Title:        input.Title,
+		Type:         input.Type,
+		Tenure:       input.Tenure,
+		Rental:       input.Rental,
+		Status:       StatusDraft,
+		Country:      input.Country,
+		City:         input.City,
+		Region:       input.Region,
+		Neighborhood: input.Neighborhood,
+		Summary:      input.Summary,
+		Price:        input.Price,
+		Currency:     input.Currency,
+		Bedrooms:     input.Bedrooms,
+		Bathrooms:    input.Bathrooms,
+		AreaSqM:      input.AreaSqM,
+		ImageURL:     input.ImageURL,
+		AgencyID:     input.AgencyID,
+		ExternalRef:  input.ExternalRef,
+		Tags:         input.Tags,
+		Location:     input.Location,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if listing.ExternalRef != "" {
+		for _, l := range s.listings {
+			if l.AgencyID == listing.AgencyID && l.ExternalRef == listing.ExternalRef {
+				return Listing{}, ErrDuplicateReference
+			}
+		}
+	}
+
+	listing.Slug = s.generateUniqueSlug(listing.Title, uuid.Nil)
+	listing.DetailsURL = detailsURL(listing.Slug)
+	listing.AgencyName = s.agencyName(listing.AgencyID)
+	s.listings = append(s.listings, listing)
+	return listing, nil
+}
+
+// Update mutates fields for an existing listing.
+func (s *InMemoryService) Update(_ context.Context, id uuid.UUID, input UpdateInput) (Listing, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := s.indexOf(id)
+	if idx < 0 {
+		return Listing{}, ErrNotFound
+	}
+
+	listing := s.listings[idx]
+	titleChanged, err := applyUpdate(&listing, input)
+	if err != nil {
+		return Listing{}, err
+	}
+	now := time.Now().UTC()
+	if change, ok := trackPriceChange(s.listings[idx], &listing, now); ok {
+		s.prices = append(s.prices, change)
+	}
+	if titleChanged {
+		listing.Slug = s.generateUniqueSlug(listing.Title, listing.ID)
+		listing.DetailsURL = detailsURL(listing.Slug)
+	}
+	if input.AgencyID != nil {
+		listing.AgencyName = s.agencyName(listing.AgencyID)
+	}
+
+	listing.UpdatedAt = now
+	s.listings[idx] = listing
+	return listing, nil
+}
+
+// Delete removes a listing permanently.
+func (s *InMemoryService) Delete(_ context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := s.indexOf(id)
+	if idx < 0 {
+		return ErrNotFound
+	}
+	s.listings = append(s.listings[:idx], s.listings[idx+1:]...)
+	return nil
+}
+
+// Transition moves a listing to a new status and records who made the change.
+func (s *InMemoryService) Transition(_ context.Context, id uuid.UUID, input TransitionInput) (Listing, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := s.indexOf(id)
+	if idx < 0 {
+		return Listing{}, ErrNotFound
+	}
+	listing := s.listings[idx]
+	input, err := normalizeTransition(listing.Status, input)
+	if err != nil {
+		return Listing{}, err
+	}
+
+	now := time.Now().UTC()
+	s.events = append(s.events, StatusEvent{
+		ID:         uuid.New(),
+		ListingID:  id,
+		FromStatus: listing.Status,
+		ToStatus:   input.Status,
+		ActorEmail: input.ActorEmail,
+		ActorName:  input.ActorName,
+		Note:       input.Note,
+		CreatedAt:  now,
+	})
+	listing.Status = input.Status
+	listing.UpdatedAt = now
+	if input.Status == StatusPublished && listing.PublishedAt == nil {
+		published := now
+		listing.PublishedAt = &published
+	}
+	s.listings[idx] = listing
+	return listing, nil
+}
+
+// Translations returns the localized copy of a listing ordered by locale.
+func (s *InMemoryService) Translations(_ context.Context, id uuid.UUID) ([]Translation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.indexOf(id) < 0 {
+		return nil, ErrNotFound
+	}
+	result := make([]Translation, 0, len(s.translations[id]))
+	for _, t := range s.translations[id] {
+		result = append(result, t)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Locale < result[j].Locale
+	})
+	return result, nil
+}
+
+// SetTranslation creates or replaces the copy of a listing for one locale.
+func (s *InMemoryService) SetTranslation(_ context.Context, id uuid.UUID, locale string, input TranslationInput) (Translation, error) {
+	locale, input, err := normalizeTranslation(locale, input)
+	if err != nil {
+		return Translation{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.indexOf(id) < 0 {
+		return Translation{}, ErrNotFound
+	}
+	if s.translations == nil {
+		s.translations = make(map[uuid.UUID]map[string]Translation)
+	}
+	if s.translations[id] == nil {
+		s.translations[id] = make(map[string]Translation)
+	}
+	translation := Translation{Locale: locale, Title: input.Title, Summary: input.Summary, UpdatedAt: time.Now().UTC()}
+	s.translations[id][locale] = translation
+	return translation, nil
+}
+
+// DeleteTranslation removes the copy of a listing for one locale.
+func (s *InMemoryService) DeleteTranslation(_ context.Context, id uuid.UUID, locale string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.indexOf(id) < 0 {
+		return ErrNotFound
+	}
+	if _, ok := s.translations[id][locale]; !ok {
+		return ErrNotFound
+	}
+	delete(s.translations[id], locale)
+	return nil
+}
+
+// Localize replaces titles and summaries with the locale's translation where one
+// exists and records the locale each listing is served in.
+func (s *InMemoryService) Localize(_ context.Context, listings []Listing, locale string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for idx := range listings {
+		listings[idx].Locale = i18n.DefaultLocale
+		if t, ok := s.translations[listings[idx].ID][locale]; ok {
+			applyTranslation(&listings[idx], t)
+		}
+	}
+	return nil
+}
+
+// PriceHistory returns the price changes of a listing, oldest first.
+func (s *InMemoryService) PriceHistory(_ context.Context, id uuid.UUID) ([]PriceChange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.indexOf(id) < 0 {
+		return nil, ErrNotFound
+	}
+	changes := make([]PriceChange, 0)
+	for _, c := range s.prices {
+		if c.ListingID == id {
+			changes = append(changes, c)
+		}
+	}
+	return changes, nil
+}
+
+// History returns the status changes of a listing, oldest first.
+func (s *InMemoryService) History(_ context.Context, id uuid.UUID) ([]StatusEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.indexOf(id) < 0 {
+		return nil, ErrNotFound
+	}
+	events := make([]StatusEvent, 0)
+	for _, e := range s.events {
+		if e.ListingID == id {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (s *InMemoryService) indexOf(id uuid.UUID) int {
+	for i, l := range s.listings {
+		if l.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// agencyName resolves the display name from listings already linked to the agency.
+func (s *InMemoryService) agencyName(agencyID uuid.UUID) string {
+	if agencyID == uuid.Nil {
+		return ""
+	}
+	for _, l := range s.listings {
+		if l.AgencyID == agencyID && l.AgencyName != "" {
+			return l.AgencyName
+		}
+	}
+	return ""
+}
+
+func (s *InMemoryService) generateUniqueSlug(title string, self uuid.UUID) string {
+	base := slugify(title)
+	if base == "" {
+		base = "listing"
+	}
+	slug := base
+	counter := 1
+	for s.slugExists(slug, self) {
+		counter++
+		slug = fmt.Sprintf("%s-%d", base, counter)
+	}
+	return slug
+}
+
+func (s *InMemoryService) slugExists(slug string, self uuid.UUID) bool {
+	for _, l := range s.listings {
+		if l.Slug == slug && l.ID != self {
+			return true
+		}
+	}
+	return false
 }
 
 // LocationString returns a formatted location string.
@@ -114,12 +588,37 @@ func (l Listing) LocationString() string {
 	return strings.Join(parts, ", ")
 }
 
-// DisplayPrice returns a formatted price string.
-func (l Listing) DisplayPrice() string {
+// FormattedPrice renders the price for a locale, with the rental period and the
+// converted amount when one was requested. Currency symbol, digit grouping and
+// decimals follow the formatter.
+func (l Listing) FormattedPrice(f format.Formatter) string {
 	if l.Price == 0 {
 		return "Price on request"
 	}
-	return fmt.Sprintf("%s %.0f", strings.ToUpper(l.Currency), l.Price)
+	price := f.Money(l.Price, l.Currency)
+	if l.IsRental() {
+		price += " / " + string(l.Rental.Period)
+	}
+	if l.ConvertedPrice != nil && !strings.EqualFold(l.ConvertedPrice.Currency, l.Currency) {
+		price += " (≈ " + f.Money(l.ConvertedPrice.Amount, l.ConvertedPrice.Currency) + ")"
+	}
+	return price
+}
+
+// FormattedArea renders the floor area in the formatter's unit, or "" when unknown.
+func (l Listing) FormattedArea(f format.Formatter) string {
+	if l.AreaSqM <= 0 {
+		return ""
+	}
+	return f.Area(l.AreaSqM)
+}
+
+// ApplyDisplay fills the display_* fields of the listings for a locale.
+func ApplyDisplay(listings []Listing, f format.Formatter) {
+	for i := range listings {
+		listings[i].PriceDisplay = listings[i].FormattedPrice(f)
+		listings[i].AreaDisplay = listings[i].FormattedArea(f)
+	}
 }
 
 func (s *InMemoryService) seed() {
@@ -134,7 +633,9 @@ func (s *InMemoryService) seed() {
 		{
 			ID:           uuid.New(),
 			Title:        "Palm Jumeirah Sky Villa",
+			Slug:         "palm-jumeirah-sky-villa",
 			Type:         ListingTypeResidential,
+			Status:       StatusPublished,
 			Country:      "AE",
 			City:         "Dubai",
 			Region:       "Dubai",
@@ -149,11 +650,14 @@ func (s *InMemoryService) seed() {
 			DetailsURL:   "/listings/palm-jumeirah-sky-villa",
 			AgencyName:   "Shanraq Global Realty",
 			Tags:         []string{"waterfront", "smart-home", "penthouse"},
+			Location:     &GeoPoint{Lat: 25.1124, Lng: 55.139},
 		},
 		{
 			ID:           uuid.New(),
 			Title:        "Östermalm Art Nouveau Residence",
+			Slug:         "ostermalm-art-nouveau",
 			Type:         ListingTypeResidential,
+			Status:       StatusPublished,
 			Country:      "SE",
 			City:         "Stockholm",
 			Region:       "Stockholm County",
@@ -168,11 +672,14 @@ func (s *InMemoryService) seed() {
 			DetailsURL:   "/listings/ostermalm-art-nouveau",
 			AgencyName:   "Nordic Skyline Partners",
 			Tags:         []string{"heritage", "city-center"},
+			Location:     &GeoPoint{Lat: 59.338, Lng: 18.086},
 		},
 		{
 			ID:           uuid.New(),
 			Title:        "Kyoto Machiya Boutique Hotel",
+			Slug:         "kyoto-machiya-boutique-hotel",
 			Type:         ListingTypeCommercial,
+			Status:       StatusPublished,
 			Country:      "JP",
 			City:         "Kyoto",
 			Region:       "Kansai",
@@ -184,14 +691,17 @@ func (s *InMemoryService) seed() {
 			Bathrooms:    6.5,
 			AreaSqM:      380,
 			ImageURL:     "https://images.shanraq.com/demo/kyoto-machiya.jpg",
-			DetailsURL:   "/listings/kyoto-machiya-hotel",
+			DetailsURL:   "/listings/kyoto-machiya-boutique-hotel",
 			AgencyName:   "Pacifica Urban Advisors",
 			Tags:         []string{"hospitality", "licensed", "turnkey"},
+			Location:     &GeoPoint{Lat: 35.0037, Lng: 135.7788},
 		},
 		{
 			ID:           uuid.New(),
 			Title:        "Lisbon Digital District Loft",
+			Slug:         "lisbon-digital-district-loft",
 			Type:         ListingTypeResidential,
+			Status:       StatusPublished,
 			Country:      "PT",
 			City:         "Lisbon",
 			Region:       "Lisbon",
@@ -203,14 +713,17 @@ func (s *InMemoryService) seed() {
 			Bathrooms:    2,
 			AreaSqM:      165,
 			ImageURL:     "https://images.shanraq.com/demo/lisbon-loft.jpg",
-			DetailsURL:   "/listings/lisbon-digital-loft",
+			DetailsURL:   "/listings/lisbon-digital-district-loft",
 			AgencyName:   "Pacifica Urban Advisors",
 			Tags:         []string{"smart-home", "waterfront", "digital-nomad"},
+			Location:     &GeoPoint{Lat: 38.768, Lng: -9.094},
 		},
 		{
 			ID:           uuid.New(),
 			Title:        "Tuscany Heritage Vineyard Estate",
+			Slug:         "tuscany-heritage-vineyard-estate",
 			Type:         ListingTypeCommercial,
+			Status:       StatusPublished,
 			Country:      "IT",
 			City:         "Siena",
 			Region:       "Tuscany",
@@ -222,14 +735,17 @@ func (s *InMemoryService) seed() {
 			Bathrooms:    7,
 			AreaSqM:      950,
 			ImageURL:     "https://images.shanraq.com/demo/tuscany-vineyard.jpg",
-			DetailsURL:   "/listings/tuscany-heritage-vineyard",
+			DetailsURL:   "/listings/tuscany-heritage-vineyard-estate",
 			AgencyName:   "Atlas Heritage Homes",
 			Tags:         []string{"vineyard", "heritage", "agritourism"},
+			Location:     &GeoPoint{Lat: 43.466, Lng: 11.253},
 		},
 		{
 			ID:           uuid.New(),
 			Title:        "Singapore Sky Garden Duplex",
+			Slug:         "singapore-sky-garden-duplex",
 			Type:         ListingTypeResidential,
+			Status:       StatusPublished,
 			Country:      "SG",
 			City:         "Singapore",
 			Region:       "Central Region",
@@ -241,14 +757,17 @@ func (s *InMemoryService) seed() {
 			Bathrooms:    4,
 			AreaSqM:      420,
 			ImageURL:     "https://images.shanraq.com/demo/singapore-sky-garden.jpg",
-			DetailsURL:   "/listings/singapore-sky-garden",
+			DetailsURL:   "/listings/singapore-sky-garden-duplex",
 			AgencyName:   "Shanraq Global Realty",
 			Tags:         []string{"biophilic", "city-center"},
+			Location:     &GeoPoint{Lat: 1.283, Lng: 103.86},
 		},
 		{
 			ID:           uuid.New(),
 			Title:        "Reykjavík Geothermal Retreat",
+			Slug:         "reykjavik-geothermal-retreat",
 			Type:         ListingTypeResidential,
+			Status:       StatusPublished,
 			Country:      "IS",
 			City:         "Reykjavík",
 			Region:       "Capital Region",
@@ -263,11 +782,14 @@ func (s *InMemoryService) seed() {
 			DetailsURL:   "/listings/reykjavik-geothermal-retreat",
 			AgencyName:   "Nordic Skyline Partners",
 			Tags:         []string{"net-zero", "luxury", "spa"},
+			Location:     &GeoPoint{Lat: 64.167, Lng: -21.7},
 		},
 		{
 			ID:           uuid.New(),
 			Title:        "Cape Town Atlantic Seaboard Villa",
+			Slug:         "cape-town-atlantic-seaboard-villa",
 			Type:         ListingTypeResidential,
+			Status:       StatusPublished,
 			Country:      "ZA",
 			City:         "Cape Town",
 			Region:       "Western Cape",
@@ -279,14 +801,17 @@ func (s *InMemoryService) seed() {
 			Bathrooms:    6.5,
 			AreaSqM:      720,
 			ImageURL:     "https://images.shanraq.com/demo/capetown-villa.jpg",
-			DetailsURL:   "/listings/cape-town-atlantic-villa",
+			DetailsURL:   "/listings/cape-town-atlantic-seaboard-villa",
 			AgencyName:   "Shanraq Global Realty",
 			Tags:         []string{"coastal", "security", "solar"},
+			Location:     &GeoPoint{Lat: -33.927, Lng: 18.378},
 		},
 		{
 			ID:           uuid.New(),
 			Title:        "São Paulo Innovation Hub Loft",
+			Slug:         "sao-paulo-innovation-hub-loft",
 			Type:         ListingTypeCommercial,
+			Status:       StatusPublished,
 			Country:      "BR",
 			City:         "São Paulo",
 			Region:       "São Paulo",
@@ -298,14 +823,17 @@ func (s *InMemoryService) seed() {
 			Bathrooms:    4,
 			AreaSqM:      980,
 			ImageURL:     "https://images.shanraq.com/demo/sao-paulo-hub.jpg",
-			DetailsURL:   "/listings/sao-paulo-innovation-hub",
+			DetailsURL:   "/listings/sao-paulo-innovation-hub-loft",
 			AgencyName:   "Pacifica Urban Advisors",
 			Tags:         []string{"innovation", "mixed-use"},
+			Location:     &GeoPoint{Lat: -23.595, Lng: -46.686},
 		},
 		{
 			ID:           uuid.New(),
 			Title:        "British Columbia Wilderness Lodge",
+			Slug:         "british-columbia-wilderness-lodge",
 			Type:         ListingTypeCommercial,
+			Status:       StatusPublished,
 			Country:      "CA",
 			City:         "Whistler",
 			Region:       "British Columbia",
@@ -317,11 +845,44 @@ func (s *InMemoryService) seed() {
 			Bathrooms:    12,
 			AreaSqM:      1250,
 			ImageURL:     "https://images.shanraq.com/demo/bc-wilderness.jpg",
-			DetailsURL:   "/listings/bc-wilderness-lodge",
+			DetailsURL:   "/listings/british-columbia-wilderness-lodge",
 			AgencyName:   "Nordic Skyline Partners",
 			Tags:         []string{"eco", "adventure", "hospitality"},
+			Location:     &GeoPoint{Lat: 50.145, Lng: -123.11},
 		},
 	}
+
+	// Stagger creation timestamps so "newest first" ordering is deterministic.
+	now := time.Now().UTC()
+	for idx := range s.listings {
+		created := now.Add(-time.Duration(idx) * time.Hour)
+		s.listings[idx].CreatedAt = created
+		s.listings[idx].UpdatedAt = created
+		s.listings[idx].PublishedAt = &created
+		s.listings[idx].Tenure = TenureSale
+	}
+
+	s.translations = make(map[uuid.UUID]map[string]Translation)
+	for _, l := range s.listings {
+		for locale, input := range seedTranslations[l.Slug] {
+			if s.translations[l.ID] == nil {
+				s.translations[l.ID] = make(map[string]Translation)
+			}
+			s.translations[l.ID][locale] = Translation{Locale: locale, Title: input.Title, Summary: input.Summary, UpdatedAt: now}
+		}
+	}
+}
+
+// collapsed returns the merged listings whose kept listing is public. Callers
+// must hold the lock.
+func (s *InMemoryService) collapsed(merged map[uuid.UUID]uuid.UUID) map[uuid.UUID]struct{} {
+	out := make(map[uuid.UUID]struct{}, len(merged))
+	for hidden, kept := range merged {
+		if idx := s.indexOf(kept); idx >= 0 && s.listings[idx].Status.Public() {
+			out[hidden] = struct{}{}
+		}
+	}
+	return out
 }
 
 var _ Service = (*InMemoryService)(nil)
@@ -0,0 +1,634 @@
+package listing
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"shanraq.com/internal/format"
+	"shanraq.com/internal/services/fx"
+)
+
+func TestInMemoryServiceCreateGeneratesUniqueSlug(t *testing.T) {
+	service := NewInMemoryService()
+	input := CreateInput{
+		Title:    "Östermalm Garden Flat",
+		Type:     ListingTypeResidential,
+		Country:  "se",
+		Currency: "sek",
+		Price:    7200000,
+		Tags:     []string{"Garden", "garden", " city-center "},
+	}
+
+	first, err := service.Create(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if first.Slug != "ostermalm-garden-flat" {
+		t.Errorf("Slug = %q, want %q", first.Slug, "ostermalm-garden-flat")
+	}
+	if first.DetailsURL != "/listings/ostermalm-garden-flat" {
+		t.Errorf("DetailsURL = %q", first.DetailsURL)
+	}
+	if first.Country != "SE" || first.Currency != "SEK" {
+		t.Errorf("codes not normalised: country=%q currency=%q", first.Country, first.Currency)
+	}
+	if len(first.Tags) != 2 {
+		t.Errorf("Tags = %v, want 2 deduplicated tags", first.Tags)
+	}
+
+	second, err := service.Create(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Create() second call error = %v", err)
+	}
+	if second.Slug != "ostermalm-garden-flat-2" {
+		t.Errorf("second Slug = %q, want %q", second.Slug, "ostermalm-garden-flat-2")
+	}
+}
+
+func TestInMemoryServiceGetBySlug(t *testing.T) {
+	service := NewInMemoryService()
+	ctx := context.Background()
+
+	listing, err := service.GetBySlug(ctx, "kyoto-machiya-boutique-hotel")
+	if err != nil {
+		t.Fatalf("GetBySlug() error = %v", err)
+	}
+	if listing.DetailsURL != "/listings/"+listing.Slug {
+		t.Errorf("DetailsURL = %q, want it to point at the slug", listing.DetailsURL)
+	}
+	if _, err := service.GetBySlug(ctx, "missing-listing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetBySlug(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestInMemoryServiceCreateValidatesInput(t *testing.T) {
+	service := NewInMemoryService()
+	cases := map[string]CreateInput{
+		"missing title": {Type: ListingTypeLand, Country: "PT"},
+		"unknown type":  {Title: "Plot", Type: "castle", Country: "PT"},
+		"bad country":   {Title: "Plot", Type: ListingTypeLand, Country: "PRT"},
+		"bad currency":  {Title: "Plot", Type: ListingTypeLand, Country: "PT", Currency: "E1"},
+		"negative area": {Title: "Plot", Type: ListingTypeLand, Country: "PT", AreaSqM: -5},
+	}
+	for name, input := range cases {
+		if _, err := service.Create(context.Background(), input); err == nil {
+			t.Errorf("%s: Create() error = nil, want validation error", name)
+		}
+	}
+}
+
+func TestInMemoryServiceUpdateAndDelete(t *testing.T) {
+	service := NewInMemoryService()
+	created, err := service.Create(context.Background(), CreateInput{
+		Title:   "Alfama Rooftop",
+		Type:    ListingTypeResidential,
+		Country: "PT",
+		Price:   640000,
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	title := "Alfama Rooftop Terrace"
+	price := 615000.0
+	updated, err := service.Update(context.Background(), created.ID, UpdateInput{Title: &title, Price: &price})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.Slug != "alfama-rooftop-terrace" {
+		t.Errorf("Slug = %q, want regenerated slug", updated.Slug)
+	}
+	if updated.Price != price {
+		t.Errorf("Price = %v, want %v", updated.Price, price)
+	}
+
+	if err := service.Delete(context.Background(), created.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := service.Get(context.Background(), created.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() after delete error = %v, want ErrNotFound", err)
+	}
+	if err := service.Delete(context.Background(), created.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Delete() twice error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestInMemoryServiceListFiltersAndPaginates(t *testing.T) {
+	service := NewInMemoryService()
+
+	all, total, err := service.List(context.Background(), ListFilter{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if total != len(all) || total != 10 {
+		t.Fatalf("List() total = %d len = %d, want 10 seeded listings", total, len(all))
+	}
+
+	filter := ListFilter{Type: ListingTypeResidential, MinBedrooms: 4, Sort: SortAreaDesc, Limit: 2}
+	page, total, err := service.List(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if total != 4 {
+		t.Fatalf("total = %d, want 4 residential listings with 4+ bedrooms", total)
+	}
+	if len(page) != 2 {
+		t.Fatalf("len(page) = %d, want 2", len(page))
+	}
+	if page[0].AreaSqM < page[1].AreaSqM {
+		t.Errorf("page not sorted by area desc: %v then %v", page[0].AreaSqM, page[1].AreaSqM)
+	}
+
+	filter.Offset = 4
+	page, _, err = service.List(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(page) != 0 {
+		t.Errorf("len(page) past the end = %d, want 0", len(page))
+	}
+
+	kyoto, err := service.GetBySlug(context.Background(), "kyoto-machiya-boutique-hotel")
+	if err != nil {
+		t.Fatalf("GetBySlug() error = %v", err)
+	}
+	if _, err := service.Transition(context.Background(), kyoto.ID, TransitionInput{Status: StatusArchived, ActorEmail: "agent@example.com"}); err != nil {
+		t.Fatalf("Transition(archived) error = %v", err)
+	}
+	ids := []uuid.UUID{kyoto.ID, all[0].ID}
+	if all[0].ID == kyoto.ID {
+		ids[1] = all[1].ID
+	}
+	for viewer, want := range map[string]int{"anonymous": 1, "staff": 2} {
+		found, _, err := service.List(context.Background(), ListFilter{IDs: ids, Viewer: Viewer{Staff: viewer == "staff"}})
+		if err != nil || len(found) != want {
+			t.Errorf("List(IDs) for %s viewer = %d listings, %v; want %d", viewer, len(found), err, want)
+		}
+	}
+}
+
+func TestInMemoryServiceFacets(t *testing.T) {
+	service := NewInMemoryService()
+
+	facets, err := service.Facets(context.Background(), ListFilter{Tags: []string{"waterfront"}})
+	if err != nil {
+		t.Fatalf("Facets() error = %v", err)
+	}
+	if len(facets.Countries) != 2 {
+		t.Fatalf("Countries = %v, want AE and PT", facets.Countries)
+	}
+	for _, tag := range facets.Tags {
+		if tag.Value == "waterfront" && tag.Count != 2 {
+			t.Errorf("waterfront count = %d, want 2", tag.Count)
+		}
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	values := url.Values{}
+	values.Set("country", "it")
+	values.Set("type", "commercial")
+	values.Set("min_price", "1000000")
+	values.Add("tags", "heritage,Vineyard")
+	values.Set("sort", "price_desc")
+	values.Set("limit", "500")
+
+	filter, err := ParseFilter(values)
+	if err != nil {
+		t.Fatalf("ParseFilter() error = %v", err)
+	}
+	if filter.Country != "IT" || filter.Type != ListingTypeCommercial || filter.MinPrice != 1000000 {
+		t.Errorf("unexpected filter %+v", filter)
+	}
+	if len(filter.Tags) != 2 || filter.Tags[1] != "vineyard" {
+		t.Errorf("Tags = %v", filter.Tags)
+	}
+	if filter.Limit != MaxPageSize {
+		t.Errorf("Limit = %d, want capped at %d", filter.Limit, MaxPageSize)
+	}
+	if filter, _ := ParseFilter(url.Values{}); filter.Limit != DefaultPageSize {
+		t.Errorf("default Limit = %d, want %d", filter.Limit, DefaultPageSize)
+	}
+
+	for _, bad := range []string{"min_price=abc", "type=castle", "sort=random", "agency_id=42", "min_area=90&max_area=10"} {
+		query, _ := url.ParseQuery(bad)
+		if _, err := ParseFilter(query); err == nil {
+			t.Errorf("ParseFilter(%q) error = nil, want error", bad)
+		}
+	}
+}
+
+func TestHighlightEscapesMarkup(t *testing.T) {
+	terms := tokenize("villa", "en")
+	text := `Villa <script>alert("x")</script> & <b onclick=x>pool</b>`
+	want := `<mark>Villa</mark> &lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; &lt;b onclick=x&gt;pool&lt;/b&gt;`
+	if got := highlight(text, terms, "en"); got != want {
+		t.Errorf("highlight() = %q, want %q", got, want)
+	}
+
+	fragment := headlineStart + "Villa" + headlineStop + ` <img src=x onerror="alert(1)">`
+	want = `<mark>Villa</mark> &lt;img src=x onerror=&#34;alert(1)&#34;&gt;`
+	if got := markHeadline(fragment); got != want {
+		t.Errorf("markHeadline() = %q, want %q", got, want)
+	}
+}
+
+func TestInMemoryServiceSearch(t *testing.T) {
+	service := NewInMemoryService()
+
+	results, total, err := service.Search(context.Background(), SearchQuery{Text: "vineyard Tuscany"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if total != 1 || results[0].Slug != "tuscany-heritage-vineyard-estate" {
+		t.Fatalf("Search(vineyard Tuscany) = %d results, want the Tuscany estate", total)
+	}
+	if results[0].Highlights.Title != "<mark>Tuscany</mark> Heritage <mark>Vineyard</mark> Estate" {
+		t.Errorf("title highlight = %q", results[0].Highlights.Title)
+	}
+
+	results, _, err = service.Search(context.Background(), SearchQuery{Text: "Ostermalm", Language: "sv-SE"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Country != "SE" {
+		t.Fatalf("accent-folded search returned %d results", len(results))
+	}
+
+	results, _, err = service.Search(context.Background(), SearchQuery{Text: "villas"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) < 2 {
+		t.Fatalf("stemmed search for villas returned %d results, want at least 2", len(results))
+	}
+	if results[0].Rank < results[len(results)-1].Rank {
+		t.Errorf("results not ordered by rank")
+	}
+
+	if _, _, err := service.Search(context.Background(), SearchQuery{Text: "  the "}); !errors.Is(err, ErrEmptyQuery) {
+		t.Errorf("Search(stop words) error = %v, want ErrEmptyQuery", err)
+	}
+}
+
+func TestInMemoryServiceGeoFilters(t *testing.T) {
+	service := NewInMemoryService()
+	stockholm := GeoPoint{Lat: 59.3293, Lng: 18.0686}
+
+	results, total, err := service.List(context.Background(), ListFilter{Near: &stockholm, RadiusKm: 25, Sort: SortDistance})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if total != 1 || results[0].Slug != "ostermalm-art-nouveau" {
+		t.Fatalf("radius search returned %d results, want the Östermalm listing", total)
+	}
+	if results[0].DistanceKm == nil || *results[0].DistanceKm > 2 {
+		t.Errorf("DistanceKm = %v, want about 1.5 km", results[0].DistanceKm)
+	}
+
+	all, _, err := service.List(context.Background(), ListFilter{Near: &stockholm, Sort: SortDistance})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(all) != 10 || *all[0].DistanceKm > *all[len(all)-1].DistanceKm {
+		t.Errorf("distance sort did not order nearest first")
+	}
+
+	pacific := &BoundingBox{MinLng: 170, MinLat: 30, MaxLng: -120, MaxLat: 55}
+	results, _, err = service.List(context.Background(), ListFilter{BBox: pacific})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Country != "CA" {
+		t.Errorf("antimeridian bbox returned %d results, want the British Columbia lodge", len(results))
+	}
+
+	query, _ := url.ParseQuery(`polygon={"type":"Polygon","coordinates":[[[-10,37],[-8,37],[-8,40],[-10,40],[-10,37]]]}`)
+	filter, err := ParseFilter(query)
+	if err != nil {
+		t.Fatalf("ParseFilter(polygon) error = %v", err)
+	}
+	results, _, err = service.List(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Country != "PT" {
+		t.Errorf("polygon search returned %d results, want the Lisbon loft", len(results))
+	}
+
+	for _, bad := range []string{"near=91,0", "radius_km=5", "sort=distance", "bbox=1,2,3", "polygon=[1,2]"} {
+		query, _ := url.ParseQuery(bad)
+		if _, err := ParseFilter(query); err == nil {
+			t.Errorf("ParseFilter(%q) error = nil, want error", bad)
+		}
+	}
+}
+
+func TestInMemoryServiceCurrencyConversion(t *testing.T) {
+	service := NewInMemoryService()
+
+	listings, _, err := service.List(context.Background(), ListFilter{Currency: "EUR", MaxPrice: 1000000})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(listings) != 1 || listings[0].Country != "PT" {
+		t.Fatalf("EUR price cap returned %d listings, want only the Lisbon loft", len(listings))
+	}
+	if listings[0].ConvertedPrice == nil || listings[0].ConvertedPrice.Amount != listings[0].Price {
+		t.Errorf("ConvertedPrice = %+v, want the EUR price unchanged", listings[0].ConvertedPrice)
+	}
+
+	sorted, _, err := service.List(context.Background(), ListFilter{Currency: "USD", Sort: SortPriceDesc})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i-1].ConvertedPrice.Amount < sorted[i].ConvertedPrice.Amount {
+			t.Fatalf("price_desc not ordered by normalised amount at %d: %v < %v", i, sorted[i-1].ConvertedPrice, sorted[i].ConvertedPrice)
+		}
+	}
+
+	if _, _, err := service.List(context.Background(), ListFilter{Currency: "XAU"}); !errors.Is(err, fx.ErrUnknownCurrency) {
+		t.Errorf("List(unknown currency) error = %v, want fx.ErrUnknownCurrency", err)
+	}
+}
+
+func TestInMemoryServiceLifecycle(t *testing.T) {
+	service := NewInMemoryService()
+	ctx := context.Background()
+	agencyID := uuid.New()
+
+	draft, err := service.Create(ctx, CreateInput{
+		Title:    "Lisbon Riverside Loft",
+		Type:     ListingTypeResidential,
+		Country:  "PT",
+		Currency: "EUR",
+		Price:    640000,
+		AgencyID: agencyID,
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if draft.Status != StatusDraft {
+		t.Fatalf("Status = %q, want draft", draft.Status)
+	}
+
+	visible := func(viewer Viewer) int {
+		_, total, err := service.List(ctx, ListFilter{AgencyID: agencyID, Viewer: viewer})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		return total
+	}
+	member := Viewer{AgencyIDs: []uuid.UUID{agencyID}}
+	if got := visible(Viewer{}); got != 0 {
+		t.Errorf("anonymous sees %d drafts, want 0", got)
+	}
+	if got := visible(member); got != 1 {
+		t.Errorf("member sees %d listings, want 1", got)
+	}
+
+	actor := TransitionInput{ActorEmail: "Agent@Example.com", ActorName: "Agent"}
+	skip := actor
+	skip.Status = StatusPublished
+	if _, err := service.Transition(ctx, draft.ID, skip); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("draft -> published error = %v, want ErrInvalidTransition", err)
+	}
+	for _, next := range []Status{StatusReview, StatusPublished} {
+		step := actor
+		step.Status = next
+		if _, err := service.Transition(ctx, draft.ID, step); err != nil {
+			t.Fatalf("Transition(%s) error = %v", next, err)
+		}
+	}
+	if got := visible(Viewer{}); got != 1 {
+		t.Errorf("anonymous sees %d published listings, want 1", got)
+	}
+
+	events, err := service.History(ctx, draft.ID)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(events) != 2 || events[1].FromStatus != StatusReview || events[1].ToStatus != StatusPublished {
+		t.Fatalf("unexpected history %+v", events)
+	}
+	if events[0].ActorEmail != "agent@example.com" {
+		t.Errorf("ActorEmail = %q", events[0].ActorEmail)
+	}
+}
+
+func TestInMemoryServicePriceHistory(t *testing.T) {
+	service := NewInMemoryService()
+	ctx := context.Background()
+	listings, _, err := service.List(ctx, ListFilter{Country: "AE"})
+	if err != nil || len(listings) == 0 {
+		t.Fatalf("List() = %d listings, err %v", len(listings), err)
+	}
+	target := listings[0]
+	since := time.Now().UTC().Add(-time.Minute)
+
+	lower := target.Price * 0.9
+	updated, err := service.Update(ctx, target.ID, UpdateInput{Price: &lower})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if !updated.PriceDropped() || updated.PriceDropPercent() != 10 {
+		t.Fatalf("expected a 10%% drop, got previous=%v price=%v", updated.PreviousPrice, updated.Price)
+	}
+
+	title := "Renamed Villa"
+	if _, err := service.Update(ctx, target.ID, UpdateInput{Title: &title}); err != nil {
+		t.Fatalf("Update(title) error = %v", err)
+	}
+	changes, err := service.PriceHistory(ctx, target.ID)
+	if err != nil {
+		t.Fatalf("PriceHistory() error = %v", err)
+	}
+	if len(changes) != 1 || !changes[0].Dropped() || changes[0].OldPrice != target.Price {
+		t.Fatalf("unexpected history %+v", changes)
+	}
+
+	dropped, total, err := service.List(ctx, ListFilter{PriceDroppedSince: since})
+	if err != nil {
+		t.Fatalf("List(price_dropped_since) error = %v", err)
+	}
+	if total != 1 || dropped[0].ID != target.ID {
+		t.Fatalf("expected only the reduced listing, got %d", total)
+	}
+	if _, total, _ := service.List(ctx, ListFilter{PriceDroppedSince: time.Now().UTC().Add(time.Hour)}); total != 0 {
+		t.Errorf("future cutoff matched %d listings", total)
+	}
+
+	higher := target.Price * 1.2
+	if _, err := service.Update(ctx, target.ID, UpdateInput{Price: &higher}); err != nil {
+		t.Fatalf("Update(raise) error = %v", err)
+	}
+	if _, total, _ := service.List(ctx, ListFilter{PriceDroppedSince: since}); total != 0 {
+		t.Errorf("raised listing still matched price_dropped_since")
+	}
+}
+
+func TestInMemoryServiceLocalize(t *testing.T) {
+	service := NewInMemoryService()
+	ctx := context.Background()
+	listings, _, err := service.List(ctx, ListFilter{Sort: SortTitle})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var target Listing
+	for _, l := range listings {
+		if l.Slug == "ostermalm-art-nouveau" {
+			target = l
+		}
+	}
+
+	if _, err := service.SetTranslation(ctx, target.ID, "de", TranslationInput{Title: "Jugendstilwohnung"}); !errors.Is(err, ErrUnsupportedLocale) {
+		t.Fatalf("SetTranslation(de) error = %v, want ErrUnsupportedLocale", err)
+	}
+
+	if err := service.Localize(ctx, listings, "sv"); err != nil {
+		t.Fatalf("Localize() error = %v", err)
+	}
+	for _, l := range listings {
+		switch {
+		case l.ID == target.ID && (l.Locale != "sv" || l.Title != "Jugendvåning på Östermalm"):
+			t.Errorf("translated listing = %q (%s)", l.Title, l.Locale)
+		case l.ID != target.ID && l.Locale == "sv":
+			t.Errorf("listing %q reported sv without a translation", l.Slug)
+		}
+	}
+
+	if err := service.DeleteTranslation(ctx, target.ID, "sv"); err != nil {
+		t.Fatalf("DeleteTranslation() error = %v", err)
+	}
+	single := []Listing{target}
+	if err := service.Localize(ctx, single, "sv"); err != nil {
+		t.Fatalf("Localize() error = %v", err)
+	}
+	if single[0].Locale != "en" || single[0].Title != target.Title {
+		t.Errorf("expected fallback to default copy, got %q (%s)", single[0].Title, single[0].Locale)
+	}
+}
+
+func TestInMemoryServiceRentals(t *testing.T) {
+	ctx := context.Background()
+	service := NewInMemoryService()
+	if _, err := service.Create(ctx, CreateInput{Title: "Loft", Type: ListingTypeResidential, Country: "PT", Tenure: TenureRent}); err == nil {
+		t.Fatal("Create() rent without terms error = nil, want error")
+	}
+	if _, err := service.Create(ctx, CreateInput{Title: "Loft", Type: ListingTypeResidential, Country: "PT", Rental: &RentalTerms{Period: "fortnight"}}); err == nil {
+		t.Fatal("Create() unknown rent period error = nil, want error")
+	}
+
+	agencyID := uuid.New()
+	rental, err := service.Create(ctx, CreateInput{
+		AgencyID: agencyID,
+		Title:    "Baixa Loft",
+		Type:     ListingTypeResidential,
+		Country:  "PT",
+		Price:    1800,
+		Currency: "EUR",
+		Rental:   &RentalTerms{Period: RentPerMonth, Deposit: 3600},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if !rental.IsRental() || rental.Rental.MinStay != 1 || rental.Rental.MinNights() != 30 {
+		t.Fatalf("Create() = %+v %+v, want monthly rental with one month minimum", rental.Tenure, rental.Rental)
+	}
+	if got := rental.FormattedPrice(format.Formatter{}); got != "€1,800 / month" {
+		t.Errorf("FormattedPrice() = %q, want €1,800 / month", got)
+	}
+	if got := rental.FormattedPrice(format.New("sv", "SE")); got != "1\u00a0800\u00a0€ / month" {
+		t.Errorf("FormattedPrice(sv) = %q, want 1 800 € / month", got)
+	}
+
+	rentals, total, err := service.List(ctx, ListFilter{Tenure: TenureRent, Viewer: Viewer{AgencyIDs: []uuid.UUID{agencyID}}})
+	if err != nil || total != 1 || rentals[0].ID != rental.ID {
+		t.Fatalf("List(rent) = %d listings, %v; want the rental", total, err)
+	}
+
+	sale := TenureSale
+	updated, err := service.Update(ctx, rental.ID, UpdateInput{Tenure: &sale})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.IsRental() || updated.Rental != nil {
+		t.Errorf("Update() to sale kept rental terms %+v", updated.Rental)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	table := fx.NewTable([]fx.Rate{{Currency: "USD", PerBase: 1.25}, {Currency: "SGD", PerBase: 1.5}})
+	lisbon := Listing{ID: uuid.New(), Title: "Lisbon loft", Type: ListingTypeResidential, Price: 500000, Currency: "EUR", AreaSqM: 100, Bedrooms: 2}
+	singapore := Listing{ID: uuid.New(), Title: "Singapore duplex", Type: ListingTypeResidential, Price: 1500000, Currency: "SGD", AreaSqM: 200, Bedrooms: 3}
+	unpriced := Listing{ID: uuid.New(), Title: "Mystery plot", Type: ListingTypeLand, Price: 90000, Currency: "XAU"}
+
+	comparison, err := Compare([]Listing{lisbon, singapore, unpriced}, table, "", format.Formatter{})
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	if comparison.Currency != "EUR" {
+		t.Errorf("Currency = %q, want the first listing's EUR", comparison.Currency)
+	}
+	loft, duplex := comparison.Listings[0], comparison.Listings[1]
+	if duplex.Price == nil || duplex.Price.Amount != 1000000 || duplex.Price.Currency != "EUR" {
+		t.Errorf("duplex Price = %+v, want EUR 1,000,000", duplex.Price)
+	}
+	if loft.PricePerSqM == nil || loft.PricePerSqM.Amount != 5000 {
+		t.Errorf("loft PricePerSqM = %+v, want 5000", loft.PricePerSqM)
+	}
+	if loft.AreaSqFt != 1076.4 {
+		t.Errorf("loft AreaSqFt = %v, want 1076.4", loft.AreaSqFt)
+	}
+	if comparison.Listings[2].Price != nil {
+		t.Errorf("listing without a rate got Price %+v", comparison.Listings[2].Price)
+	}
+
+	diffs := make(map[string]AttributeDiff)
+	for _, d := range comparison.Diff {
+		diffs[d.Attribute] = d
+	}
+	if d := diffs["price_per_sqm"]; d.Best == nil || *d.Best != 0 {
+		t.Errorf("price_per_sqm best = %v, want the loft", d.Best)
+	}
+	if d := diffs["area_sqm"]; d.Best == nil || *d.Best != 1 || d.Display[1] != "200\u00a0m²" {
+		t.Errorf("area_sqm diff = %+v, want the duplex as largest", d)
+	}
+	if d := diffs["price"]; d.Display[1] != "€1,000,000" {
+		t.Errorf("price display = %q, want €1,000,000", d.Display[1])
+	}
+	if d := diffs["bedrooms"]; d.Display[2] != "—" {
+		t.Errorf("bedrooms for land = %q, want a dash", d.Display[2])
+	}
+
+	if _, err := Compare([]Listing{lisbon, singapore}, table, "XAU", format.Formatter{}); !errors.Is(err, fx.ErrUnknownCurrency) {
+		t.Errorf("Compare(XAU) error = %v, want fx.ErrUnknownCurrency", err)
+	}
+}
+
+func TestParseCompareIDs(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	ids, err := ParseCompareIDs([]string{a.String() + ", " + b.String(), a.String()})
+	if err != nil || len(ids) != 2 || ids[0] != a || ids[1] != b {
+		t.Fatalf("ParseCompareIDs() = %v, %v, want [a b]", ids, err)
+	}
+	if _, err := ParseCompareIDs([]string{a.String()}); err == nil {
+		t.Error("ParseCompareIDs() accepted a single listing")
+	}
+	if _, err := ParseCompareIDs([]string{a.String() + ",nope"}); err == nil {
+		t.Error("ParseCompareIDs() accepted a malformed id")
+	}
+	many := make([]string, 0, MaxCompare+1)
+	for i := 0; i <= MaxCompare; i++ {
+		many = append(many, uuid.NewString())
+	}
+	if _, err := ParseCompareIDs(many); err == nil {
+		t.Errorf("ParseCompareIDs() accepted %d listings", len(many))
+	}
+}
@@ -0,0 +1,105 @@
+package listing
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Private-use sentinels stand in for <mark> tags while ts_headline runs, so the
+// listing text can be HTML-escaped before the real tags are put back.
+const (
+	headlineStart = "\ue000"
+	headlineStop  = "\ue001"
+)
+
+// headlineOptions configures ts_headline to match the in-memory highlighter.
+const headlineOptions = "StartSel=" + headlineStart + ", StopSel=" + headlineStop + ", MaxFragments=2, MinWords=8, MaxWords=24, FragmentDelimiter=\" … \""
+
+var headlineTags = strings.NewReplacer(headlineStart, "<mark>", headlineStop, "</mark>")
+
+// markHeadline escapes a ts_headline fragment and turns the sentinels into <mark> tags.
+func markHeadline(fragment string) string {
+	return headlineTags.Replace(html.EscapeString(fragment))
+}
+
+func (s *sqlService) Search(ctx context.Context, query SearchQuery) ([]SearchResult, int, error) {
+	text := strings.TrimSpace(query.Text)
+	if text == "" {
+		return nil, 0, ErrEmptyQuery
+	}
+	if err := s.checkCurrency(ctx, query.Filter.Currency); err != nil {
+		return nil, 0, err
+	}
+
+	b := &queryBuilder{}
+	textArg := b.arg(text)
+	langArg := b.arg(NormalizeSearchLanguage(query.Language))
+	tsQuery := fmt.Sprintf(
+		"(websearch_to_tsquery(shanraq_search_config(%[2]s), unaccent(%[1]s)) || websearch_to_tsquery('simple', unaccent(%[1]s)))",
+		textArg, langArg,
+	)
+	b.clauses = append(b.clauses, "l.search_vector @@ "+tsQuery)
+	applyFilter(b, query.Filter)
+	where := b.whereSQL()
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM property_listings l "+where, b.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	limitArg := b.arg(pageLimit(query.Filter.Limit))
+	offsetArg := b.arg(query.Filter.Offset)
+	optionsArg := b.arg(headlineOptions)
+
+	listQuery := fmt.Sprintf(`
+        SELECT %[1]s,
+               %[8]s, %[9]s,
+               ts_rank_cd(l.search_vector, %[3]s) AS rank,
+               ts_headline(shanraq_search_config(l.content_language), l.title, %[3]s, %[6]s),
+               ts_headline(shanraq_search_config(l.content_language), COALESCE(l.summary, ''), %[3]s, %[6]s)
+        %[2]s
+        %[7]s
+        ORDER BY rank DESC, l.id
+        LIMIT %[4]s OFFSET %[5]s`, listingColumns, listingFrom, tsQuery, limitArg, offsetArg, optionsArg, where, b.distanceSQL(), b.convertedSQL())
+
+	rows, err := s.db.QueryContext(ctx, listQuery, b.args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	results := make([]SearchResult, 0)
+	for rows.Next() {
+		var result SearchResult
+		var distance, converted sql.NullFloat64
+		listing, err := scanListing(rowWithExtras{rows, []any{&distance, &converted, &result.Rank, &result.Highlights.Title, &result.Highlights.Snippet}})
+		if err != nil {
+			return nil, 0, err
+		}
+		listing.DistanceKm = distanceKm(distance)
+		listing.ConvertedPrice = convertedPrice(converted, query.Filter.Currency)
+		result.Listing = listing
+		result.Highlights.Title = markHeadline(result.Highlights.Title)
+		result.Highlights.Snippet = markHeadline(result.Highlights.Snippet)
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return results, total, nil
+}
+
+// rowWithExtras scans additional trailing columns after the standard listing columns.
+type rowWithExtras struct {
+	row interface {
+		Scan(dest ...any) error
+	}
+	extras []any
+}
+
+func (r rowWithExtras) Scan(dest ...any) error {
+	return r.row.Scan(append(dest, r.extras...)...)
+}
@@ -6,11 +6,27 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"shanraq.com/internal/services/fx"
 )
 
+const listingColumns = `
+        l.id, l.title, l.slug, l.listing_type, l.status, l.country_code, l.city, l.region, l.neighborhood,
+        l.summary, l.price, l.currency, l.bedrooms, l.bathrooms, l.area_sqm,
+        l.hero_image_url, l.details_url, COALESCE(array_to_json(l.tags)::text, '[]'),
+        l.latitude, l.longitude, l.previous_price, l.price_changed_at, l.published_at, l.agency_id, COALESCE(a.name, ''), COALESCE(l.external_ref, ''),
+        l.tenure, l.rent_period, l.min_stay, l.deposit, l.created_at, l.updated_at`
+
+const listingFrom = `
+        FROM property_listings l
+        LEFT JOIN real_estate_agencies a ON a.id = l.agency_id`
+
 type sqlService struct {
 	db *sql.DB
 }
@@ -20,180 +36,826 @@ func NewSQLService(db *sql.DB) (Service, error) {
 	return &sqlService{db: db}, nil
 }
 
-func (s *sqlService) List(ctx context.Context) ([]Listing, error) {
-	rows, err := s.db.QueryContext(ctx, `
-        SELECT l.id, l.title, l.listing_type, l.country_code, l.city, l.region, l.neighborhood,
-               l.summary, l.price, l.currency, l.bedrooms, l.bathrooms, l.area_sqm,
-               l.hero_image_url, l.details_url, COALESCE(array_to_json(l.tags)::text, '[]'),
-               l.agency_id, COALESCE(a.name, '')
-        FROM property_listings l
-        LEFT JOIN real_estate_agencies a ON a.id = l.agency_id
-        ORDER BY l.created_at DESC`)
+func (s *sqlService) List(ctx context.Context, filter ListFilter) ([]Listing, int, error) {
+	if err := s.checkCurrency(ctx, filter.Currency); err != nil {
+		return nil, 0, err
+	}
+
+	b := &queryBuilder{}
+	applyFilter(b, filter)
+	where := b.whereSQL()
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM property_listings l "+where, b.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	limitArg := b.arg(pageLimit(filter.Limit))
+	offsetArg := b.arg(filter.Offset)
+
+	listQuery := fmt.Sprintf(`
+        SELECT %s, %s, %s %s
+        %s
+        ORDER BY %s
+        LIMIT %s OFFSET %s`, listingColumns, b.distanceSQL(), b.convertedSQL(), listingFrom, where, orderBy(filter.Sort, b), limitArg, offsetArg)
+
+	rows, err := s.db.QueryContext(ctx, listQuery, b.args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
 	listings := make([]Listing, 0)
 	for rows.Next() {
-		var record Listing
-		var agencyName string
-		var price, bathrooms, area sql.NullFloat64
-		var heroURL, detailsURL sql.NullString
-		var tagsJSON string
-		if err := rows.Scan(
-			&record.ID,
-			&record.Title,
-			&record.Type,
-			&record.Country,
-			&record.City,
-			&record.Region,
-			&record.Neighborhood,
-			&record.Summary,
-			&price,
-			&record.Currency,
-			&record.Bedrooms,
-			&bathrooms,
-			&area,
-			&heroURL,
-			&detailsURL,
-			&tagsJSON,
-			&record.AgencyID,
-			&agencyName,
-		); err != nil {
+		var distance, converted sql.NullFloat64
+		record, err := scanListing(rowWithExtras{rows, []any{&distance, &converted}})
+		if err != nil {
+			return nil, 0, err
+		}
+		record.DistanceKm = distanceKm(distance)
+		record.ConvertedPrice = convertedPrice(converted, filter.Currency)
+		listings = append(listings, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return listings, total, nil
+}
+
+func (s *sqlService) Facets(ctx context.Context, filter ListFilter) (Facets, error) {
+	if err := s.checkCurrency(ctx, filter.Currency); err != nil {
+		return Facets{}, err
+	}
+	where, args := buildWhere(filter)
+
+	var facets Facets
+	var err error
+	if facets.Countries, err = s.facet(ctx, "l.country_code", "property_listings l", where, args); err != nil {
+		return Facets{}, err
+	}
+	if facets.Types, err = s.facet(ctx, "l.listing_type", "property_listings l", where, args); err != nil {
+		return Facets{}, err
+	}
+	if facets.Tags, err = s.facet(ctx, "tag", "property_listings l CROSS JOIN LATERAL unnest(l.tags) AS tag", where, args); err != nil {
+		return Facets{}, err
+	}
+	return facets, nil
+}
+
+func (s *sqlService) facet(ctx context.Context, column, from, where string, args []interface{}) ([]FacetCount, error) {
+	query := fmt.Sprintf(`
+        SELECT %[1]s, COUNT(*)
+        FROM %[2]s
+        %[3]s
+        GROUP BY %[1]s
+        ORDER BY COUNT(*) DESC, %[1]s`, column, from, where)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]FacetCount, 0)
+	for rows.Next() {
+		var value sql.NullString
+		var count int
+		if err := rows.Scan(&value, &count); err != nil {
 			return nil, err
 		}
-		record.Price = price.Float64
-		record.Bathrooms = bathrooms.Float64
-		record.AreaSqM = area.Float64
-		record.ImageURL = strings.TrimSpace(heroURL.String)
-		record.DetailsURL = strings.TrimSpace(detailsURL.String)
-		record.AgencyName = agencyName
-		if err := json.Unmarshal([]byte(tagsJSON), &record.Tags); err != nil {
-			record.Tags = nil
+		if value.String == "" {
+			continue
 		}
-		listings = append(listings, record)
+		counts = append(counts, FacetCount{Value: strings.TrimSpace(value.String), Count: count})
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	return listings, nil
+	return counts, nil
+}
+
+func (s *sqlService) Get(ctx context.Context, id uuid.UUID) (Listing, error) {
+	row := s.db.QueryRowContext(ctx, `
+        SELECT `+listingColumns+listingFrom+`
+        WHERE l.id = $1`, id)
+	record, err := scanListing(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Listing{}, ErrNotFound
+		}
+		return Listing{}, err
+	}
+	return record, nil
+}
+
+func (s *sqlService) GetBySlug(ctx context.Context, slug string) (Listing, error) {
+	row := s.db.QueryRowContext(ctx, `
+        SELECT `+listingColumns+listingFrom+`
+        WHERE l.slug = $1`, slug)
+	record, err := scanListing(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Listing{}, ErrNotFound
+		}
+		return Listing{}, err
+	}
+	return record, nil
 }
 
-func (s *sqlService) Featured(ctx context.Context, limit int) ([]Listing, error) {
-	if limit <= 0 {
-		limit = 6
+func (s *sqlService) ReferenceIDs(ctx context.Context, agencyID uuid.UUID, refs []string) (map[string]uuid.UUID, error) {
+	out := make(map[string]uuid.UUID)
+	if len(refs) == 0 {
+		return out, nil
 	}
 	rows, err := s.db.QueryContext(ctx, `
-        SELECT l.id, l.title, l.listing_type, l.country_code, l.city, l.region, l.neighborhood,
-               l.summary, l.price, l.currency, l.bedrooms, l.bathrooms, l.area_sqm,
-               l.hero_image_url, l.details_url, COALESCE(array_to_json(l.tags)::text, '[]'),
-               l.agency_id, COALESCE(a.name, '')
-        FROM property_listings l
-        LEFT JOIN real_estate_agencies a ON a.id = l.agency_id
-        ORDER BY l.created_at DESC
-        LIMIT $1`, limit)
+        SELECT external_ref, id
+        FROM property_listings
+        WHERE agency_id = $1 AND external_ref = ANY($2::text[])`, agencyID, refs)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	listings := make([]Listing, 0, limit)
 	for rows.Next() {
-		var record Listing
-		var agencyName string
-		var price, bathrooms, area sql.NullFloat64
-		var heroURL, detailsURL sql.NullString
-		var tagsJSON string
-		if err := rows.Scan(
-			&record.ID,
-			&record.Title,
-			&record.Type,
-			&record.Country,
-			&record.City,
-			&record.Region,
-			&record.Neighborhood,
-			&record.Summary,
-			&price,
-			&record.Currency,
-			&record.Bedrooms,
-			&bathrooms,
-			&area,
-			&heroURL,
-			&detailsURL,
-			&tagsJSON,
-			&record.AgencyID,
-			&agencyName,
-		); err != nil {
+		var (
+			ref string
+			id  uuid.UUID
+		)
+		if err := rows.Scan(&ref, &id); err != nil {
 			return nil, err
 		}
-		record.Price = price.Float64
-		record.Bathrooms = bathrooms.Float64
-		record.AreaSqM = area.Float64
-		record.ImageURL = strings.TrimSpace(heroURL.String)
-		record.DetailsURL = strings.TrimSpace(detailsURL.String)
-		record.AgencyName = agencyName
-		if err := json.Unmarshal([]byte(tagsJSON), &record.Tags); err != nil {
-			record.Tags = nil
+		out[ref] = id
+	}
+	return out, rows.Err()
+}
+
+func (s *sqlService) Create(ctx context.Context, input CreateInput) (Listing, error) {
+	input, err := normalizeCreateInput(input)
+	if err != nil {
+		return Listing{}, err
+	}
+
+	slug, err := s.generateUniqueSlug(ctx, slugify(input.Title), uuid.Nil)
+	if err != nil {
+		return Listing{}, err
+	}
+
+	var id uuid.UUID
+	err = s.db.QueryRowContext(ctx, `
+        INSERT INTO property_listings
+            (agency_id, title, slug, summary, listing_type, country_code, city, region, neighborhood,
+             price, currency, bedrooms, bathrooms, area_sqm, hero_image_url, details_url, tags,
+             latitude, longitude, external_ref, tenure, rent_period, min_stay, deposit)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, NULLIF($20, ''),
+                $21, $22, $23, $24)
+        RETURNING id`,
+		nullableUUID(input.AgencyID),
+		input.Title,
+		slug,
+		input.Summary,
+		string(input.Type),
+		input.Country,
+		input.City,
+		input.Region,
+		input.Neighborhood,
+		input.Price,
+		input.Currency,
+		input.Bedrooms,
+		input.Bathrooms,
+		input.AreaSqM,
+		input.ImageURL,
+		detailsURL(slug),
+		input.Tags,
+		latitude(input.Location),
+		longitude(input.Location),
+		input.ExternalRef,
+		string(input.Tenure),
+		rentPeriod(input.Rental),
+		minStay(input.Rental),
+		deposit(input.Rental),
+	).Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err, "uq_property_listings_external_ref") {
+			return Listing{}, ErrDuplicateReference
 		}
-		listings = append(listings, record)
+		return Listing{}, err
+	}
+	return s.Get(ctx, id)
+}
+
+// Update locks the listing row for the rest of the transaction, so concurrent edits
+// apply one after the other and each price change is recorded against the price the
+// previous edit left behind.
+func (s *sqlService) Update(ctx context.Context, id uuid.UUID, input UpdateInput) (Listing, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Listing{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	existing, err := scanListing(tx.QueryRowContext(ctx, `
+        SELECT `+listingColumns+listingFrom+`
+        WHERE l.id = $1
+        FOR UPDATE OF l`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Listing{}, ErrNotFound
+	}
+	if err != nil {
+		return Listing{}, err
+	}
+
+	before := existing
+	titleChanged, err := applyUpdate(&existing, input)
+	if err != nil {
+		return Listing{}, err
+	}
+	change, priceChanged := trackPriceChange(before, &existing, time.Now().UTC())
+	if titleChanged {
+		slug, err := s.generateUniqueSlug(ctx, slugify(existing.Title), id)
+		if err != nil {
+			return Listing{}, err
+		}
+		existing.Slug = slug
+		existing.DetailsURL = detailsURL(slug)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+        UPDATE property_listings
+        SET agency_id = $1,
+            title = $2,
+            slug = $3,
+            summary = $4,
+            listing_type = $5,
+            country_code = $6,
+            city = $7,
+            region = $8,
+            neighborhood = $9,
+            price = $10,
+            currency = $11,
+            bedrooms = $12,
+            bathrooms = $13,
+            area_sqm = $14,
+            hero_image_url = $15,
+            details_url = $16,
+            tags = $17,
+            latitude = $18,
+            longitude = $19,
+            previous_price = $20,
+            price_changed_at = $21,
+            tenure = $22,
+            rent_period = $23,
+            min_stay = $24,
+            deposit = $25,
+            updated_at = NOW()
+        WHERE id = $26`,
+		nullableUUID(existing.AgencyID),
+		existing.Title,
+		existing.Slug,
+		existing.Summary,
+		string(existing.Type),
+		existing.Country,
+		existing.City,
+		existing.Region,
+		existing.Neighborhood,
+		existing.Price,
+		existing.Currency,
+		existing.Bedrooms,
+		existing.Bathrooms,
+		existing.AreaSqM,
+		existing.ImageURL,
+		existing.DetailsURL,
+		existing.Tags,
+		latitude(existing.Location),
+		longitude(existing.Location),
+		existing.PreviousPrice,
+		existing.PriceChangedAt,
+		string(existing.Tenure),
+		rentPeriod(existing.Rental),
+		minStay(existing.Rental),
+		deposit(existing.Rental),
+		id,
+	)
+	if err != nil {
+		return Listing{}, err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return Listing{}, ErrNotFound
+	}
+	if priceChanged {
+		if _, err := tx.ExecContext(ctx, `
+            INSERT INTO listing_price_history
+                (id, listing_id, old_price, old_currency, new_price, new_currency, changed_at)
+            VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			change.ID, id, change.OldPrice, change.OldCurrency, change.NewPrice, change.NewCurrency, change.ChangedAt); err != nil {
+			return Listing{}, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return Listing{}, err
+	}
+	return s.Get(ctx, id)
+}
+
+func (s *sqlService) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM property_listings WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Transition updates the status and appends an audit event in one transaction.
+func (s *sqlService) Transition(ctx context.Context, id uuid.UUID, input TransitionInput) (Listing, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Listing{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var current Status
+	err = tx.QueryRowContext(ctx, `SELECT status FROM property_listings WHERE id = $1 FOR UPDATE`, id).Scan(&current)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Listing{}, ErrNotFound
+		}
+		return Listing{}, err
+	}
+	input, err = normalizeTransition(current, input)
+	if err != nil {
+		return Listing{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+        UPDATE property_listings
+        SET status = $1,
+            published_at = CASE WHEN $1 = 'published' THEN COALESCE(published_at, NOW()) ELSE published_at END,
+            updated_at = NOW()
+        WHERE id = $2`,
+		string(input.Status), id); err != nil {
+		return Listing{}, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+        INSERT INTO listing_status_events (listing_id, from_status, to_status, actor_email, actor_name, note)
+        VALUES ($1, $2, $3, $4, $5, $6)`,
+		id, string(current), string(input.Status), input.ActorEmail, input.ActorName, input.Note); err != nil {
+		return Listing{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return Listing{}, err
+	}
+	return s.Get(ctx, id)
+}
+
+func (s *sqlService) PriceHistory(ctx context.Context, id uuid.UUID) ([]PriceChange, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT id, listing_id, old_price, old_currency, new_price, new_currency, changed_at
+        FROM listing_price_history
+        WHERE listing_id = $1
+        ORDER BY changed_at, id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]PriceChange, 0)
+	for rows.Next() {
+		var c PriceChange
+		if err := rows.Scan(&c.ID, &c.ListingID, &c.OldPrice, &c.OldCurrency, &c.NewPrice, &c.NewCurrency, &c.ChangedAt); err != nil {
+			return nil, err
+		}
+		c.OldCurrency = strings.TrimSpace(c.OldCurrency)
+		c.NewCurrency = strings.TrimSpace(c.NewCurrency)
+		changes = append(changes, c)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	return listings, nil
+	return changes, nil
 }
 
-func (s *sqlService) Get(ctx context.Context, id uuid.UUID) (Listing, error) {
+func (s *sqlService) History(ctx context.Context, id uuid.UUID) ([]StatusEvent, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT id, listing_id, from_status, to_status, actor_email, actor_name, note, created_at
+        FROM listing_status_events
+        WHERE listing_id = $1
+        ORDER BY created_at, id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]StatusEvent, 0)
+	for rows.Next() {
+		var e StatusEvent
+		if err := rows.Scan(&e.ID, &e.ListingID, &e.FromStatus, &e.ToStatus, &e.ActorEmail, &e.ActorName, &e.Note, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// checkCurrency rejects target currencies missing from fx_rates.
+func (s *sqlService) checkCurrency(ctx context.Context, currency string) error {
+	if currency == "" {
+		return nil
+	}
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM fx_rates WHERE currency = $1)`, currency).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return fx.ErrUnknownCurrency
+	}
+	return nil
+}
+
+func (s *sqlService) generateUniqueSlug(ctx context.Context, base string, self uuid.UUID) (string, error) {
+	if base == "" {
+		base = "listing"
+	}
+	slug := base
+	counter := 1
+	for {
+		var exists bool
+		err := s.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM property_listings WHERE slug = $1 AND id <> $2)`, slug, self).Scan(&exists)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return slug, nil
+		}
+		counter++
+		slug = fmt.Sprintf("%s-%d", base, counter)
+	}
+}
+
+// haversineSQL computes the great-circle distance in kilometres from a point given as
+// latitude and longitude placeholders. Casts keep parameter types resolvable when the
+// expression only appears in the select list.
+const haversineSQL = `(2 * 6371.0088 * asin(least(1, sqrt(
+            power(sin(radians(l.latitude - %[1]s::double precision) / 2), 2) +
+            cos(radians(%[1]s::double precision)) * cos(radians(l.latitude)) *
+            power(sin(radians(l.longitude - %[2]s::double precision) / 2), 2)))))`
+
+// rateSQL looks up the per-EUR rate of a currency expression in fx_rates.
+const rateSQL = `(SELECT r.rate::double precision FROM fx_rates r WHERE r.currency = %s)`
+
+// basePriceSQL normalises the listing price to EUR for cross-currency ordering.
+var basePriceSQL = "(l.price / " + fmt.Sprintf(rateSQL, "l.currency") + ")"
+
+// queryBuilder accumulates WHERE clauses and their positional arguments. The
+// distance and converted-price expressions register their arguments only when first
+// used, so a COUNT or facet query built from the WHERE clauses alone never carries
+// arguments that only the select list or ORDER BY refers to.
+type queryBuilder struct {
+	clauses   []string
+	args      []interface{}
+	near      *GeoPoint
+	currency  string
+	distance  string
+	converted string
+}
+
+// arg registers a positional argument and returns its placeholder.
+func (b *queryBuilder) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// where adds a clause whose %s verbs are replaced by placeholders for values.
+func (b *queryBuilder) where(clause string, values ...interface{}) {
+	placeholders := make([]interface{}, len(values))
+	for i, v := range values {
+		placeholders[i] = b.arg(v)
+	}
+	b.clauses = append(b.clauses, fmt.Sprintf(clause, placeholders...))
+}
+
+// distanceSQL returns the distance expression, or NULL when no origin was given.
+func (b *queryBuilder) distanceSQL() string {
+	if b.near == nil {
+		return "NULL::double precision"
+	}
+	if b.distance == "" {
+		b.distance = fmt.Sprintf(haversineSQL, b.arg(b.near.Lat), b.arg(b.near.Lng))
+	}
+	return b.distance
+}
+
+// convertedSQL returns the price in the requested currency, or NULL when none was requested.
+func (b *queryBuilder) convertedSQL() string {
+	if b.currency == "" {
+		return "NULL::double precision"
+	}
+	if b.converted == "" {
+		b.converted = basePriceSQL + " * " + fmt.Sprintf(rateSQL, b.arg(b.currency)+"::text")
+	}
+	return b.converted
+}
+
+func (b *queryBuilder) whereSQL() string {
+	if len(b.clauses) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(b.clauses, " AND ")
+}
+
+// applyViewer hides listings that are not public outside the viewer's agencies.
+func applyViewer(b *queryBuilder, viewer Viewer) {
+	if viewer.Staff {
+		return
+	}
+	if len(viewer.AgencyIDs) == 0 {
+		b.where("l.status IN (%s, %s)", string(StatusPublished), string(StatusUnderOffer))
+		return
+	}
+	ids := make([]string, 0, len(viewer.AgencyIDs))
+	for _, id := range viewer.AgencyIDs {
+		ids = append(ids, id.String())
+	}
+	b.where("(l.status IN (%s, %s) OR l.agency_id = ANY(%s::uuid[]))", string(StatusPublished), string(StatusUnderOffer), ids)
+}
+
+// applyCollapse hides listings merged into a public canonical listing from
+// everyone outside the hidden listing's agency.
+func applyCollapse(b *queryBuilder, viewer Viewer) {
+	const merged = `NOT EXISTS (
+            SELECT 1 FROM listing_duplicates d
+            JOIN property_listings k ON k.id = d.listing_id
+            WHERE d.duplicate_id = l.id AND d.status = 'merged' AND k.status IN ('published', 'under_offer'))`
+	if len(viewer.AgencyIDs) == 0 {
+		b.clauses = append(b.clauses, merged)
+		return
+	}
+	ids := make([]string, 0, len(viewer.AgencyIDs))
+	for _, id := range viewer.AgencyIDs {
+		ids = append(ids, id.String())
+	}
+	b.where("(l.agency_id = ANY(%s::uuid[]) OR "+merged+")", ids)
+}
+
+// applyFilter translates the filter into clauses over the "l" listings alias.
+func applyFilter(b *queryBuilder, filter ListFilter) {
+	b.near = filter.Near
+	b.currency = filter.Currency
+	price := "l.price"
+	if filter.Currency != "" && (filter.MinPrice > 0 || filter.MaxPrice > 0) {
+		price = b.convertedSQL()
+	}
+	applyViewer(b, filter.Viewer)
+	if filter.Status != "" {
+		b.where("l.status = %s", string(filter.Status))
+	}
+	if filter.Country != "" {
+		b.where("l.country_code = %s", strings.ToUpper(filter.Country))
+	}
+	if filter.City != "" {
+		b.where("LOWER(l.city) = LOWER(%s)", filter.City)
+	}
+	if filter.Type != "" {
+		b.where("l.listing_type = %s", string(filter.Type))
+	}
+	if filter.Tenure != "" {
+		b.where("l.tenure = %s", string(filter.Tenure))
+	}
+	if filter.MinPrice > 0 {
+		b.where(price+" >= %s", filter.MinPrice)
+	}
+	if filter.MaxPrice > 0 {
+		b.where(price+" <= %s", filter.MaxPrice)
+	}
+	if !filter.PriceDroppedSince.IsZero() {
+		b.where("l.previous_price > l.price AND l.price_changed_at >= %s", filter.PriceDroppedSince)
+	}
+	if !filter.PublishedSince.IsZero() {
+		b.where("l.published_at >= %s", filter.PublishedSince)
+	}
+	if filter.MinBedrooms > 0 {
+		b.where("l.bedrooms >= %s", filter.MinBedrooms)
+	}
+	if filter.MinBathrooms > 0 {
+		b.where("l.bathrooms >= %s", filter.MinBathrooms)
+	}
+	if filter.MinArea > 0 {
+		b.where("l.area_sqm >= %s", filter.MinArea)
+	}
+	if filter.MaxArea > 0 {
+		b.where("l.area_sqm <= %s", filter.MaxArea)
+	}
+	if len(filter.Tags) > 0 {
+		b.where("l.tags @> %s", filter.Tags)
+	}
+	if filter.AgencyID != uuid.Nil {
+		b.where("l.agency_id = %s", filter.AgencyID)
+	}
+	if len(filter.IDs) > 0 {
+		ids := make([]string, 0, len(filter.IDs))
+		for _, id := range filter.IDs {
+			ids = append(ids, id.String())
+		}
+		b.where("l.id = ANY(%s::uuid[])", ids)
+	}
+	if filter.CollapseDuplicates {
+		applyCollapse(b, filter.Viewer)
+	}
+	if filter.Near != nil && filter.RadiusKm > 0 {
+		b.where(b.distanceSQL()+" <= %s", filter.RadiusKm)
+	}
+	if box := filter.BBox; box != nil {
+		b.where("l.latitude BETWEEN %s AND %s", box.MinLat, box.MaxLat)
+		if box.MinLng <= box.MaxLng {
+			b.where("l.longitude BETWEEN %s AND %s", box.MinLng, box.MaxLng)
+		} else {
+			b.where("(l.longitude >= %s OR l.longitude <= %s)", box.MinLng, box.MaxLng)
+		}
+	}
+	if len(filter.Polygon) > 0 {
+		b.where("point(l.longitude, l.latitude) <@ %s::polygon", polygonLiteral(filter.Polygon))
+	}
+}
+
+func buildWhere(filter ListFilter) (string, []interface{}) {
+	b := &queryBuilder{}
+	applyFilter(b, filter)
+	return b.whereSQL(), b.args
+}
+
+func orderBy(order SortOrder, b *queryBuilder) string {
+	switch order {
+	case SortDistance:
+		if b.near != nil {
+			return b.distanceSQL() + " ASC NULLS LAST, l.id"
+		}
+		return "l.created_at DESC, l.id"
+	case SortPriceAsc:
+		return basePriceSQL + " ASC NULLS LAST, l.id"
+	case SortPriceDesc:
+		return basePriceSQL + " DESC NULLS LAST, l.id"
+	case SortAreaDesc:
+		return "l.area_sqm DESC NULLS LAST, l.id"
+	case SortTitle:
+		return "l.title, l.id"
+	default:
+		return "l.created_at DESC, l.id"
+	}
+}
+
+func scanListing(scanner interface {
+	Scan(dest ...any) error
+}) (Listing, error) {
 	var record Listing
+	var agencyID uuid.NullUUID
 	var agencyName string
+	var city, region, neighborhood, summary, currency sql.NullString
 	var price, bathrooms, area sql.NullFloat64
+	var bedrooms sql.NullInt64
 	var heroURL, detailsURL sql.NullString
 	var tagsJSON string
-	err := s.db.QueryRowContext(ctx, `
-        SELECT l.id, l.title, l.listing_type, l.country_code, l.city, l.region, l.neighborhood,
-               l.summary, l.price, l.currency, l.bedrooms, l.bathrooms, l.area_sqm,
-               l.hero_image_url, l.details_url, COALESCE(array_to_json(l.tags)::text, '[]'),
-               l.agency_id, COALESCE(a.name, '')
-        FROM property_listings l
-        LEFT JOIN real_estate_agencies a ON a.id = l.agency_id
-        WHERE l.id = $1`, id).
-		Scan(
-			&record.ID,
-			&record.Title,
-			&record.Type,
-			&record.Country,
-			&record.City,
-			&record.Region,
-			&record.Neighborhood,
-			&record.Summary,
-			&price,
-			&record.Currency,
-			&record.Bedrooms,
-			&bathrooms,
-			&area,
-			&heroURL,
-			&detailsURL,
-			&tagsJSON,
-			&record.AgencyID,
-			&agencyName,
-		)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return Listing{}, fmt.Errorf("listing %s not found", id)
-		}
+	var lat, lng, previousPrice sql.NullFloat64
+	var priceChangedAt, publishedAt sql.NullTime
+	var period sql.NullString
+	var stay sql.NullInt64
+	var rentDeposit sql.NullFloat64
+	if err := scanner.Scan(
+		&record.ID,
+		&record.Title,
+		&record.Slug,
+		&record.Type,
+		&record.Status,
+		&record.Country,
+		&city,
+		&region,
+		&neighborhood,
+		&summary,
+		&price,
+		&currency,
+		&bedrooms,
+		&bathrooms,
+		&area,
+		&heroURL,
+		&detailsURL,
+		&tagsJSON,
+		&lat,
+		&lng,
+		&previousPrice,
+		&priceChangedAt,
+		&publishedAt,
+		&agencyID,
+		&agencyName,
+		&record.ExternalRef,
+		&record.Tenure,
+		&period,
+		&stay,
+		&rentDeposit,
+		&record.CreatedAt,
+		&record.UpdatedAt,
+	); err != nil {
 		return Listing{}, err
 	}
+	record.City = city.String
+	record.Region = region.String
+	record.Neighborhood = neighborhood.String
+	record.Summary = summary.String
 	record.Price = price.Float64
+	record.Currency = strings.TrimSpace(currency.String)
+	record.Bedrooms = int(bedrooms.Int64)
 	record.Bathrooms = bathrooms.Float64
 	record.AreaSqM = area.Float64
 	record.ImageURL = strings.TrimSpace(heroURL.String)
 	record.DetailsURL = strings.TrimSpace(detailsURL.String)
+	record.AgencyID = agencyID.UUID
 	record.AgencyName = agencyName
 	if err := json.Unmarshal([]byte(tagsJSON), &record.Tags); err != nil {
 		record.Tags = nil
 	}
+	if previousPrice.Valid {
+		record.PreviousPrice = &previousPrice.Float64
+	}
+	if priceChangedAt.Valid {
+		changedAt := priceChangedAt.Time.UTC()
+		record.PriceChangedAt = &changedAt
+	}
+	if publishedAt.Valid {
+		published := publishedAt.Time.UTC()
+		record.PublishedAt = &published
+	}
+	if lat.Valid && lng.Valid {
+		record.Location = &GeoPoint{Lat: lat.Float64, Lng: lng.Float64}
+	}
+	if record.Tenure == TenureRent && period.Valid {
+		record.Rental = &RentalTerms{Period: RentPeriod(period.String), MinStay: int(stay.Int64), Deposit: rentDeposit.Float64}
+	}
 	return record, nil
 }
 
+// isUniqueViolation reports whether err is PostgreSQL rejecting a duplicate on the
+// named constraint or index.
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == constraint
+}
+
+func distanceKm(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+	rounded := roundKm(value.Float64)
+	return &rounded
+}
+
+func convertedPrice(value sql.NullFloat64, currency string) *fx.Money {
+	if !value.Valid || currency == "" {
+		return nil
+	}
+	return &fx.Money{Amount: math.Round(value.Float64*100) / 100, Currency: currency}
+}
+
+func rentPeriod(t *RentalTerms) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(t.Period), Valid: true}
+}
+
+func minStay(t *RentalTerms) sql.NullInt64 {
+	if t == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(t.MinStay), Valid: true}
+}
+
+func deposit(t *RentalTerms) sql.NullFloat64 {
+	if t == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: t.Deposit, Valid: true}
+}
+
+func latitude(p *GeoPoint) sql.NullFloat64 {
+	if p == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: p.Lat, Valid: true}
+}
+
+func longitude(p *GeoPoint) sql.NullFloat64 {
+	if p == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: p.Lng, Valid: true}
+}
+
 var _ Service = (*sqlService)(nil)
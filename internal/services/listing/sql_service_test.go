@@ -0,0 +1,129 @@
+package listing
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// placeholderPattern matches positional placeholders such as $3.
+var placeholderPattern = regexp.MustCompile(`\$(\d+)`)
+
+// recordingDriver accepts any statement, records it with its argument count, and
+// answers the scalar lookups the listing service scans with a single row.
+type recordingDriver struct {
+	mu         sync.Mutex
+	statements []recordedStatement
+}
+
+type recordedStatement struct {
+	query string
+	args  int
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) { return recordingConn{d}, nil }
+
+type recordingConn struct{ d *recordingDriver }
+
+func (c recordingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepare not supported")
+}
+func (c recordingConn) Close() error { return nil }
+func (c recordingConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("transactions not supported")
+}
+
+func (c recordingConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.d.mu.Lock()
+	c.d.statements = append(c.d.statements, recordedStatement{query: query, args: len(args)})
+	c.d.mu.Unlock()
+	switch {
+	case strings.Contains(query, "SELECT EXISTS"):
+		return &recordedRows{values: []driver.Value{true}}, nil
+	case strings.HasPrefix(strings.TrimSpace(query), "SELECT COUNT(*) FROM property_listings l"):
+		return &recordedRows{values: []driver.Value{int64(0)}}, nil
+	default:
+		return &recordedRows{}, nil
+	}
+}
+
+type recordedRows struct {
+	values []driver.Value
+	done   bool
+}
+
+func (r *recordedRows) Columns() []string {
+	return make([]string, len(r.values))
+}
+func (r *recordedRows) Close() error { return nil }
+
+func (r *recordedRows) Next(dest []driver.Value) error {
+	if r.done || len(r.values) == 0 {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.values)
+	return nil
+}
+
+func highestPlaceholder(query string) int {
+	highest := 0
+	for _, match := range placeholderPattern.FindAllStringSubmatch(query, -1) {
+		if n, _ := strconv.Atoi(match[1]); n > highest {
+			highest = n
+		}
+	}
+	return highest
+}
+
+func TestSQLServiceArgumentsMatchPlaceholders(t *testing.T) {
+	rec := &recordingDriver{}
+	name := "listing-recorder-" + t.Name()
+	sql.Register(name, rec)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer db.Close()
+	svc, err := NewSQLService(db)
+	if err != nil {
+		t.Fatalf("NewSQLService() error = %v", err)
+	}
+
+	origin := &GeoPoint{Lat: 38.72, Lng: -9.14}
+	filters := map[string]ListFilter{
+		"plain":               {},
+		"near":                {Near: origin},
+		"near sorted":         {Near: origin, Sort: SortDistance},
+		"near within radius":  {Near: origin, RadiusKm: 5, Sort: SortDistance},
+		"near in a country":   {Near: origin, Country: "pt", Sort: SortDistance},
+		"currency":            {Currency: "USD"},
+		"currency with price": {Currency: "USD", MinPrice: 100000, MaxPrice: 900000},
+		"currency near":       {Near: origin, RadiusKm: 5, Currency: "USD", MinPrice: 1, Country: "pt", Sort: SortDistance},
+	}
+	ctx := context.Background()
+	for label, filter := range filters {
+		rec.statements = nil
+		if _, _, err := svc.List(ctx, filter); err != nil {
+			t.Errorf("List(%s) error = %v", label, err)
+		}
+		if _, err := svc.Facets(ctx, filter); err != nil {
+			t.Errorf("Facets(%s) error = %v", label, err)
+		}
+		if _, _, err := svc.Search(ctx, SearchQuery{Text: "loft", Filter: filter}); err != nil {
+			t.Errorf("Search(%s) error = %v", label, err)
+		}
+		for _, stmt := range rec.statements {
+			if want := highestPlaceholder(stmt.query); stmt.args != want {
+				t.Errorf("%s: statement passes %d args for placeholders up to $%d:\n%s", label, stmt.args, want, stmt.query)
+			}
+		}
+	}
+}
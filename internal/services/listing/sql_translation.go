@@ -0,0 +1,111 @@
+package listing
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"shanraq.com/internal/i18n"
+)
+
+func (s *sqlService) Translations(ctx context.Context, id uuid.UUID) ([]Translation, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT locale, title, summary, updated_at
+        FROM listing_translations
+        WHERE listing_id = $1
+        ORDER BY locale`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := make([]Translation, 0)
+	for rows.Next() {
+		var t Translation
+		if err := rows.Scan(&t.Locale, &t.Title, &t.Summary, &t.UpdatedAt); err != nil {
+			return nil, err
+		}
+		translations = append(translations, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+func (s *sqlService) SetTranslation(ctx context.Context, id uuid.UUID, locale string, input TranslationInput) (Translation, error) {
+	locale, input, err := normalizeTranslation(locale, input)
+	if err != nil {
+		return Translation{}, err
+	}
+	if _, err := s.Get(ctx, id); err != nil {
+		return Translation{}, err
+	}
+
+	translation := Translation{Locale: locale, Title: input.Title, Summary: input.Summary}
+	err = s.db.QueryRowContext(ctx, `
+        INSERT INTO listing_translations (listing_id, locale, title, summary)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (listing_id, locale)
+        DO UPDATE SET title = EXCLUDED.title, summary = EXCLUDED.summary, updated_at = NOW()
+        RETURNING updated_at`, id, locale, input.Title, input.Summary).Scan(&translation.UpdatedAt)
+	if err != nil {
+		return Translation{}, err
+	}
+	return translation, nil
+}
+
+func (s *sqlService) DeleteTranslation(ctx context.Context, id uuid.UUID, locale string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM listing_translations WHERE listing_id = $1 AND locale = $2`, id, locale)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *sqlService) Localize(ctx context.Context, listings []Listing, locale string) error {
+	for idx := range listings {
+		listings[idx].Locale = i18n.DefaultLocale
+	}
+	if locale == i18n.DefaultLocale || len(listings) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(listings))
+	for _, l := range listings {
+		ids = append(ids, l.ID.String())
+	}
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT listing_id, title, summary
+        FROM listing_translations
+        WHERE locale = $1 AND listing_id = ANY($2::uuid[])`, locale, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	translations := make(map[uuid.UUID]Translation)
+	for rows.Next() {
+		var id uuid.UUID
+		t := Translation{Locale: locale}
+		if err := rows.Scan(&id, &t.Title, &t.Summary); err != nil {
+			return err
+		}
+		translations[id] = t
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for idx := range listings {
+		if t, ok := translations[listings[idx].ID]; ok {
+			applyTranslation(&listings[idx], t)
+		}
+	}
+	return nil
+}
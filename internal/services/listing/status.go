@@ -0,0 +1,143 @@
+package listing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status is the lifecycle state of a listing.
+type Status string
+
+const (
+	StatusDraft      Status = "draft"
+	StatusReview     Status = "review"
+	StatusPublished  Status = "published"
+	StatusUnderOffer Status = "under_offer"
+	StatusSold       Status = "sold"
+	StatusArchived   Status = "archived"
+)
+
+// transitions lists the statuses reachable from each status.
+var transitions = map[Status][]Status{
+	StatusDraft:      {StatusReview, StatusArchived},
+	StatusReview:     {StatusDraft, StatusPublished, StatusArchived},
+	StatusPublished:  {StatusUnderOffer, StatusSold, StatusDraft, StatusArchived},
+	StatusUnderOffer: {StatusPublished, StatusSold, StatusArchived},
+	StatusSold:       {StatusArchived},
+	StatusArchived:   {StatusDraft},
+}
+
+// Valid reports whether the status is part of the lifecycle.
+func (s Status) Valid() bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+// Public reports whether listings in this status are on the market and readable by
+// anyone. A listing under offer stays public so competing buyers can still follow it.
+func (s Status) Public() bool {
+	return s == StatusPublished || s == StatusUnderOffer
+}
+
+// CanTransition reports whether a listing may move from s to next.
+func (s Status) CanTransition(next Status) bool {
+	for _, candidate := range transitions[s] {
+		if candidate == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the statuses reachable from s.
+func (s Status) Next() []Status {
+	return append([]Status(nil), transitions[s]...)
+}
+
+// ErrInvalidTransition is returned when the requested status is not reachable.
+var ErrInvalidTransition = errors.New("status transition not allowed")
+
+// TransitionError describes a rejected status change.
+type TransitionError struct {
+	From Status
+	To   Status
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot move listing from %s to %s", e.From, e.To)
+}
+
+// Unwrap lets callers match the error with errors.Is(err, ErrInvalidTransition).
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// TransitionInput requests a status change on behalf of an actor.
+type TransitionInput struct {
+	Status     Status
+	ActorEmail string
+	ActorName  string
+	Note       string
+}
+
+// StatusEvent is an audit record of a status change.
+type StatusEvent struct {
+	ID         uuid.UUID `json:"id"`
+	ListingID  uuid.UUID `json:"listing_id"`
+	FromStatus Status    `json:"from_status"`
+	ToStatus   Status    `json:"to_status"`
+	ActorEmail string    `json:"actor_email"`
+	ActorName  string    `json:"actor_name"`
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Viewer describes who is reading listings. Anonymous viewers only see public
+// listings; members additionally see every listing of their agencies.
+type Viewer struct {
+	AgencyIDs []uuid.UUID
+	// Staff is set for platform admins, who read listings in every status.
+	Staff bool
+}
+
+// CanSee reports whether the viewer may read the listing.
+func (v Viewer) CanSee(l Listing) bool {
+	if l.Status.Public() || v.Staff {
+		return true
+	}
+	return v.IsMember(l.AgencyID)
+}
+
+// IsMember reports whether the viewer belongs to the agency.
+func (v Viewer) IsMember(agencyID uuid.UUID) bool {
+	if agencyID == uuid.Nil {
+		return false
+	}
+	for _, id := range v.AgencyIDs {
+		if id == agencyID {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeTransition(current Status, input TransitionInput) (TransitionInput, error) {
+	input.Status = Status(strings.ToLower(strings.TrimSpace(string(input.Status))))
+	if !input.Status.Valid() {
+		return TransitionInput{}, fmt.Errorf("unknown status %q", input.Status)
+	}
+	input.ActorEmail = strings.ToLower(strings.TrimSpace(input.ActorEmail))
+	if input.ActorEmail == "" {
+		return TransitionInput{}, errors.New("actor email is required")
+	}
+	input.ActorName = strings.TrimSpace(input.ActorName)
+	input.Note = strings.TrimSpace(input.Note)
+	if !current.CanTransition(input.Status) {
+		return TransitionInput{}, &TransitionError{From: current, To: input.Status}
+	}
+	return input, nil
+}
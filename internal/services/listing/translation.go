@@ -0,0 +1,70 @@
+package listing
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"shanraq.com/internal/i18n"
+)
+
+// Translation is localized listing copy for a single locale.
+type Translation struct {
+	Locale    string    `json:"locale"`
+	Title     string    `json:"title"`
+	Summary   string    `json:"summary"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TranslationInput carries localized copy to store.
+type TranslationInput struct {
+	Title   string
+	Summary string
+}
+
+// ErrUnsupportedLocale is returned for locales listings cannot be translated into.
+var ErrUnsupportedLocale = errors.New("unsupported locale")
+
+// applyTranslation swaps in the localized copy, keeping the original for empty fields.
+func applyTranslation(l *Listing, t Translation) {
+	if t.Title != "" {
+		l.Title = t.Title
+	}
+	if t.Summary != "" {
+		l.Summary = t.Summary
+	}
+	l.Locale = t.Locale
+}
+
+// normalizeTranslation validates the locale and copy of a translation.
+func normalizeTranslation(locale string, input TranslationInput) (string, TranslationInput, error) {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if !i18n.Supported(locale) || locale == i18n.DefaultLocale {
+		return "", TranslationInput{}, ErrUnsupportedLocale
+	}
+	input.Title = strings.TrimSpace(input.Title)
+	input.Summary = strings.TrimSpace(input.Summary)
+	if input.Title == "" {
+		return "", TranslationInput{}, errors.New("title is required")
+	}
+	return locale, input, nil
+}
+
+// seedTranslations holds demo copy for seeded listings, keyed by slug and locale.
+var seedTranslations = map[string]map[string]TranslationInput{
+	"palm-jumeirah-sky-villa": {
+		"ar": {Title: "فيلا سكاي في نخلة جميرا", Summary: "دوبلكس من أربع غرف نوم مع مسبح خاص لا متناهي وإطلالات مفتوحة على الخليج."},
+	},
+	"ostermalm-art-nouveau": {
+		"sv": {Title: "Jugendvåning på Östermalm", Summary: "Renoverad lägenhet från 1903 med moderna energisystem och vinterträdgård."},
+	},
+	"kyoto-machiya-boutique-hotel": {
+		"ja": {Title: "京都町家ブティックホテル", Summary: "伝統建築と現代的な設備を融合させた、営業許可済みの6室の町家ホテル。"},
+	},
+	"lisbon-digital-district-loft": {
+		"pt": {Title: "Loft no Distrito Digital de Lisboa", Summary: "Loft inteligente com vista para o Tejo, mezanino de coworking e estacionamento preparado para veículos elétricos."},
+	},
+	"sao-paulo-innovation-hub-loft": {
+		"pt": {Title: "Loft no Polo de Inovação de São Paulo", Summary: "Armazém reconvertido com infraestrutura 5G, estúdios e lounge de dados."},
+	},
+}
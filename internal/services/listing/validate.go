@@ -0,0 +1,248 @@
+package listing
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// normalizeCreateInput trims and validates create payloads shared by every Service implementation.
+func normalizeCreateInput(input CreateInput) (CreateInput, error) {
+	input.Title = strings.TrimSpace(input.Title)
+	if input.Title == "" {
+		return CreateInput{}, errors.New("title is required")
+	}
+	input.Type = ListingType(strings.ToLower(strings.TrimSpace(string(input.Type))))
+	if !input.Type.Valid() {
+		return CreateInput{}, errors.New("listing type must be residential, commercial, or land")
+	}
+	country, err := normalizeCountry(input.Country)
+	if err != nil {
+		return CreateInput{}, err
+	}
+	input.Country = country
+	if input.Tenure, input.Rental, err = normalizeTenure(input.Tenure, input.Rental); err != nil {
+		return CreateInput{}, err
+	}
+	currency, err := normalizeCurrency(input.Currency)
+	if err != nil {
+		return CreateInput{}, err
+	}
+	input.Currency = currency
+	if err := validateFigures(input.Price, input.Bedrooms, input.Bathrooms, input.AreaSqM); err != nil {
+		return CreateInput{}, err
+	}
+
+	input.City = strings.TrimSpace(input.City)
+	input.Region = strings.TrimSpace(input.Region)
+	input.Neighborhood = strings.TrimSpace(input.Neighborhood)
+	input.Summary = strings.TrimSpace(input.Summary)
+	input.ImageURL = strings.TrimSpace(input.ImageURL)
+	input.Tags = normalizeTags(input.Tags)
+	if input.Location != nil && !input.Location.Valid() {
+		return CreateInput{}, errors.New("location must contain a valid latitude and longitude")
+	}
+	input.ExternalRef = strings.TrimSpace(input.ExternalRef)
+	if input.ExternalRef != "" && input.AgencyID == uuid.Nil {
+		return CreateInput{}, errors.New("external reference requires an agency")
+	}
+	if len(input.ExternalRef) > 100 {
+		return CreateInput{}, errors.New("external reference must be at most 100 characters")
+	}
+	return input, nil
+}
+
+// applyUpdate mutates the listing with validated fields and reports whether the title changed.
+func applyUpdate(listing *Listing, input UpdateInput) (bool, error) {
+	titleChanged := false
+	if input.Title != nil {
+		title := strings.TrimSpace(*input.Title)
+		if title == "" {
+			return false, errors.New("title cannot be empty")
+		}
+		titleChanged = title != listing.Title
+		listing.Title = title
+	}
+	if input.Type != nil {
+		listingType := ListingType(strings.ToLower(strings.TrimSpace(string(*input.Type))))
+		if !listingType.Valid() {
+			return false, errors.New("listing type must be residential, commercial, or land")
+		}
+		listing.Type = listingType
+	}
+	if input.Tenure != nil || input.Rental != nil {
+		tenure, terms := listing.Tenure, listing.Rental
+		if input.Tenure != nil {
+			tenure = *input.Tenure
+			if strings.TrimSpace(string(tenure)) == "" {
+				tenure = TenureSale
+			}
+		}
+		if input.Rental != nil {
+			terms = input.Rental
+			if input.Tenure == nil {
+				tenure = TenureRent
+			}
+		}
+		tenure, terms, err := normalizeTenure(tenure, terms)
+		if err != nil {
+			return false, err
+		}
+		listing.Tenure, listing.Rental = tenure, terms
+	}
+	if input.Country != nil {
+		country, err := normalizeCountry(*input.Country)
+		if err != nil {
+			return false, err
+		}
+		listing.Country = country
+	}
+	if input.Currency != nil {
+		currency, err := normalizeCurrency(*input.Currency)
+		if err != nil {
+			return false, err
+		}
+		listing.Currency = currency
+	}
+	if input.City != nil {
+		listing.City = strings.TrimSpace(*input.City)
+	}
+	if input.Region != nil {
+		listing.Region = strings.TrimSpace(*input.Region)
+	}
+	if input.Neighborhood != nil {
+		listing.Neighborhood = strings.TrimSpace(*input.Neighborhood)
+	}
+	if input.Summary != nil {
+		listing.Summary = strings.TrimSpace(*input.Summary)
+	}
+	if input.Price != nil {
+		listing.Price = *input.Price
+	}
+	if input.Bedrooms != nil {
+		listing.Bedrooms = *input.Bedrooms
+	}
+	if input.Bathrooms != nil {
+		listing.Bathrooms = *input.Bathrooms
+	}
+	if input.AreaSqM != nil {
+		listing.AreaSqM = *input.AreaSqM
+	}
+	if input.ImageURL != nil {
+		listing.ImageURL = strings.TrimSpace(*input.ImageURL)
+	}
+	if input.AgencyID != nil {
+		listing.AgencyID = *input.AgencyID
+	}
+	if input.Tags != nil {
+		listing.Tags = normalizeTags(*input.Tags)
+	}
+	if input.Location != nil {
+		if !input.Location.Valid() {
+			return false, errors.New("location must contain a valid latitude and longitude")
+		}
+		location := *input.Location
+		listing.Location = &location
+	}
+	if err := validateFigures(listing.Price, listing.Bedrooms, listing.Bathrooms, listing.AreaSqM); err != nil {
+		return false, err
+	}
+	return titleChanged, nil
+}
+
+func normalizeCountry(value string) (string, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if !isAlphaCode(value, 2) {
+		return "", errors.New("country code must be ISO 3166-1 alpha-2")
+	}
+	return value, nil
+}
+
+func normalizeCurrency(value string) (string, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == "" {
+		return "USD", nil
+	}
+	if !isAlphaCode(value, 3) {
+		return "", errors.New("currency must be an ISO 4217 code")
+	}
+	return value, nil
+}
+
+func validateFigures(price float64, bedrooms int, bathrooms, area float64) error {
+	switch {
+	case price < 0:
+		return errors.New("price cannot be negative")
+	case bedrooms < 0:
+		return errors.New("bedrooms cannot be negative")
+	case bathrooms < 0:
+		return errors.New("bathrooms cannot be negative")
+	case area < 0:
+		return errors.New("area cannot be negative")
+	}
+	return nil
+}
+
+func isAlphaCode(value string, length int) bool {
+	if len(value) != length {
+		return false
+	}
+	for _, r := range value {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeTags(values []string) []string {
+	set := make(map[string]struct{})
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v == "" {
+			continue
+		}
+		if _, exists := set[v]; exists {
+			continue
+		}
+		set[v] = struct{}{}
+		result = append(result, v)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// foldAccents strips combining marks so "Östermalm" and "Ostermalm" compare equal.
+func foldAccents(value string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, value)
+	if err != nil {
+		return value
+	}
+	return folded
+}
+
+func slugify(value string) string {
+	value = strings.ToLower(foldAccents(strings.TrimSpace(value)))
+	value = strings.ReplaceAll(value, "&", " and ")
+	value = strings.ReplaceAll(value, "'", "")
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	return strings.Join(fields, "-")
+}
+
+func detailsURL(slug string) string {
+	return "/listings/" + slug
+}
+
+func nullableUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
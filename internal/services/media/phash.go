@@ -0,0 +1,47 @@
+package media
+
+import (
+	"fmt"
+	"image"
+	"math/bits"
+	"strconv"
+
+	"golang.org/x/image/draw"
+)
+
+// perceptualHash computes a 64-bit difference hash: the image is reduced to 9x8
+// grey pixels and each bit records whether a pixel is brighter than its right-hand
+// neighbour. Re-encoded, resized or lightly retouched copies of a photo land within
+// a few bits of each other, which is what duplicate detection relies on.
+func perceptualHash(src image.Image) string {
+	small := image.NewGray(image.Rect(0, 0, 9, 8))
+	draw.ApproxBiLinear.Scale(small, small.Bounds(), src, src.Bounds(), draw.Src, nil)
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if small.GrayAt(x, y).Y > small.GrayAt(x+1, y).Y {
+				hash |= 1
+			}
+		}
+	}
+	return fmt.Sprintf("%016x", hash)
+}
+
+// HashDistance is the number of differing bits between two perceptual hashes, or
+// -1 when either is not a valid hash.
+func HashDistance(a, b string) int {
+	x, errA := parseHash(a)
+	y, errB := parseHash(b)
+	if errA != nil || errB != nil {
+		return -1
+	}
+	return bits.OnesCount64(x ^ y)
+}
+
+func parseHash(h string) (uint64, error) {
+	if len(h) != 16 {
+		return 0, strconv.ErrSyntax
+	}
+	return strconv.ParseUint(h, 16, 64)
+}
@@ -0,0 +1,191 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"net/http"
+	"time"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/google/uuid"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+
+	"shanraq.com/internal/storage"
+)
+
+var (
+	// ErrUnsupportedType is returned for uploads that are not decodable images.
+	ErrUnsupportedType = errors.New("unsupported media type: upload a JPEG, PNG, GIF or WebP image")
+	// ErrImageTooLarge is returned for images above maxSourcePixels.
+	ErrImageTooLarge = errors.New("image exceeds 40 megapixels")
+)
+
+// Rendition sizes. Thumbnails are cropped to the 3:2 card ratio; display images
+// are scaled to fit without upscaling.
+const (
+	thumbnailWidth  = 480
+	thumbnailHeight = 320
+	displayMaxEdge  = 1280
+	maxSourcePixels = 40_000_000
+)
+
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// process validates the upload, stores the original and its variants, and returns
+// the media record without a position.
+func process(ctx context.Context, store storage.BlobStore, input UploadInput) (Media, error) {
+	contentType := http.DetectContentType(input.Data)
+	ext, ok := extensions[contentType]
+	if !ok {
+		return Media{}, ErrUnsupportedType
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(input.Data))
+	if err != nil {
+		return Media{}, ErrUnsupportedType
+	}
+	if config.Width*config.Height > maxSourcePixels {
+		return Media{}, ErrImageTooLarge
+	}
+	src, _, err := image.Decode(bytes.NewReader(input.Data))
+	if err != nil {
+		return Media{}, ErrUnsupportedType
+	}
+
+	now := time.Now().UTC()
+	item := Media{
+		ID:             uuid.New(),
+		ListingID:      input.ListingID,
+		Kind:           input.Kind,
+		Caption:        input.Caption,
+		ContentType:    contentType,
+		Width:          config.Width,
+		Height:         config.Height,
+		SizeBytes:      int64(len(input.Data)),
+		PerceptualHash: perceptualHash(src),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	prefix := fmt.Sprintf("listings/%s/%s/", input.ListingID, item.ID)
+	item.StorageKey = prefix + "original" + ext
+
+	if err := store.Put(ctx, item.StorageKey, bytes.NewReader(input.Data), contentType); err != nil {
+		return Media{}, fmt.Errorf("store original: %w", err)
+	}
+	item.URL = store.URL(item.StorageKey)
+
+	thumb := cropToFill(src, thumbnailWidth, thumbnailHeight)
+	display := scaleToFit(src, displayMaxEdge)
+	renditions := []struct {
+		name string
+		img  image.Image
+		webp bool
+	}{
+		{VariantThumbnail, thumb, false},
+		{VariantThumbnailWebP, thumb, true},
+		{VariantDisplayWebP, display, true},
+	}
+	for _, r := range renditions {
+		variant, err := storeVariant(ctx, store, prefix, r.name, r.img, r.webp)
+		if err != nil {
+			removeBlobs(ctx, store, item)
+			return Media{}, fmt.Errorf("store %s: %w", r.name, err)
+		}
+		item.Variants = append(item.Variants, variant)
+	}
+	return item, nil
+}
+
+func storeVariant(ctx context.Context, store storage.BlobStore, prefix, name string, img image.Image, asWebP bool) (Variant, error) {
+	var buf bytes.Buffer
+	variant := Variant{Name: name, Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}
+	if asWebP {
+		if err := encodeWebP(&buf, img); err != nil {
+			return Variant{}, err
+		}
+		variant.ContentType = "image/webp"
+		variant.Key = prefix + name + ".webp"
+	} else {
+		if err := jpeg.Encode(&buf, flatten(img), &jpeg.Options{Quality: 82}); err != nil {
+			return Variant{}, err
+		}
+		variant.ContentType = "image/jpeg"
+		variant.Key = prefix + name + ".jpg"
+	}
+	if err := store.Put(ctx, variant.Key, &buf, variant.ContentType); err != nil {
+		return Variant{}, err
+	}
+	variant.URL = store.URL(variant.Key)
+	return variant, nil
+}
+
+// removeBlobs deletes the original and every variant; failures are ignored because
+// orphaned blobs are harmless and the metadata is already gone.
+func removeBlobs(ctx context.Context, store storage.BlobStore, item Media) {
+	if item.StorageKey != "" {
+		_ = store.Delete(ctx, item.StorageKey)
+	}
+	for _, v := range item.Variants {
+		_ = store.Delete(ctx, v.Key)
+	}
+}
+
+// cropToFill scales the image to cover width x height and crops the centre.
+func cropToFill(src image.Image, width, height int) image.Image {
+	b := src.Bounds()
+	srcRatio := float64(b.Dx()) / float64(b.Dy())
+	dstRatio := float64(width) / float64(height)
+	crop := b
+	if srcRatio > dstRatio {
+		w := max(1, int(float64(b.Dy())*dstRatio))
+		crop.Min.X = b.Min.X + (b.Dx()-w)/2
+		crop.Max.X = crop.Min.X + w
+	} else {
+		h := max(1, int(float64(b.Dx())/dstRatio))
+		crop.Min.Y = b.Min.Y + (b.Dy()-h)/2
+		crop.Max.Y = crop.Min.Y + h
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+	return dst
+}
+
+// scaleToFit shrinks the image so its longest edge is at most maxEdge.
+func scaleToFit(src image.Image, maxEdge int) image.Image {
+	b := src.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if width <= maxEdge && height <= maxEdge {
+		dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+		return dst
+	}
+	if width >= height {
+		height = max(1, height*maxEdge/width)
+		width = maxEdge
+	} else {
+		width = max(1, width*maxEdge/height)
+		height = maxEdge
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+	return dst
+}
+
+// flatten composites transparent pixels onto white before JPEG encoding.
+func flatten(img image.Image) image.Image {
+	dst := image.NewRGBA(img.Bounds())
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Over)
+	return dst
+}
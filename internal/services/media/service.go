@@ -0,0 +1,350 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"shanraq.com/internal/storage"
+)
+
+// Kind distinguishes gallery photos from floor plans.
+type Kind string
+
+const (
+	KindPhoto     Kind = "photo"
+	KindFloorPlan Kind = "floor_plan"
+)
+
+// Valid reports whether the kind is supported.
+func (k Kind) Valid() bool {
+	return k == KindPhoto || k == KindFloorPlan
+}
+
+// Variant names generated for every upload.
+const (
+	VariantThumbnail     = "thumbnail"
+	VariantThumbnailWebP = "thumbnail_webp"
+	VariantDisplayWebP   = "display_webp"
+)
+
+// Variant is a derived rendition of an uploaded image.
+type Variant struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	URL         string `json:"url"`
+	Key         string `json:"-"`
+}
+
+// Media is an ordered image attached to a listing.
+type Media struct {
+	ID          uuid.UUID `json:"id"`
+	ListingID   uuid.UUID `json:"listing_id"`
+	Kind        Kind      `json:"kind"`
+	Position    int       `json:"position"`
+	Caption     string    `json:"caption"`
+	ContentType string    `json:"content_type"`
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	SizeBytes   int64     `json:"size_bytes"`
+	URL         string    `json:"url"`
+	Variants    []Variant `json:"variants"`
+	// PerceptualHash fingerprints the picture for duplicate detection.
+	PerceptualHash string    `json:"perceptual_hash,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	StorageKey     string    `json:"-"`
+}
+
+// VariantURL returns the URL of the named variant, or an empty string.
+func (m Media) VariantURL(name string) string {
+	for _, v := range m.Variants {
+		if v.Name == name {
+			return v.URL
+		}
+	}
+	return ""
+}
+
+// ThumbnailURL prefers the generated thumbnail and falls back to the original.
+func (m Media) ThumbnailURL() string {
+	if url := m.VariantURL(VariantThumbnail); url != "" {
+		return url
+	}
+	return m.URL
+}
+
+// UploadInput carries a single uploaded image.
+type UploadInput struct {
+	ListingID uuid.UUID
+	Kind      Kind
+	Caption   string
+	Filename  string
+	Data      []byte
+}
+
+// UpdateInput defines mutable media metadata.
+type UpdateInput struct {
+	Kind    *Kind
+	Caption *string
+}
+
+// Service manages listing media.
+type Service interface {
+	List(ctx context.Context, listingID uuid.UUID) ([]Media, error)
+	Get(ctx context.Context, id uuid.UUID) (Media, error)
+	Upload(ctx context.Context, input UploadInput) (Media, error)
+	Update(ctx context.Context, id uuid.UUID, input UpdateInput) (Media, error)
+	Reorder(ctx context.Context, listingID uuid.UUID, order []uuid.UUID) ([]Media, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Covers(ctx context.Context, listingIDs []uuid.UUID) (map[uuid.UUID]Media, error)
+	PerceptualHashes(ctx context.Context, listingIDs []uuid.UUID) (map[uuid.UUID][]string, error)
+}
+
+var (
+	// ErrNotFound is returned when a media item cannot be located.
+	ErrNotFound = errors.New("media not found")
+	// ErrInvalidOrder is returned when a reorder request does not list every item exactly once.
+	ErrInvalidOrder = errors.New("order must list every media item of the listing exactly once")
+)
+
+// InMemoryService keeps media metadata in memory and blobs in the configured store.
+type InMemoryService struct {
+	mu    sync.RWMutex
+	items []Media
+	store storage.BlobStore
+}
+
+// NewInMemoryService creates an empty media service writing blobs to store.
+func NewInMemoryService(store storage.BlobStore) *InMemoryService {
+	return &InMemoryService{store: store}
+}
+
+func (s *InMemoryService) List(_ context.Context, listingID uuid.UUID) ([]Media, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]Media, 0)
+	for _, m := range s.items {
+		if m.ListingID == listingID {
+			result = append(result, m)
+		}
+	}
+	sortMedia(result)
+	return result, nil
+}
+
+func (s *InMemoryService) Get(_ context.Context, id uuid.UUID) (Media, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, m := range s.items {
+		if m.ID == id {
+			return m, nil
+		}
+	}
+	return Media{}, ErrNotFound
+}
+
+func (s *InMemoryService) Upload(ctx context.Context, input UploadInput) (Media, error) {
+	input, err := normalizeUpload(input)
+	if err != nil {
+		return Media{}, err
+	}
+	item, err := process(ctx, s.store, input)
+	if err != nil {
+		return Media{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	position := 0
+	for _, m := range s.items {
+		if m.ListingID == input.ListingID && m.Position >= position {
+			position = m.Position + 1
+		}
+	}
+	item.Position = position
+	s.items = append(s.items, item)
+	return item, nil
+}
+
+func (s *InMemoryService) Update(_ context.Context, id uuid.UUID, input UpdateInput) (Media, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for idx, m := range s.items {
+		if m.ID != id {
+			continue
+		}
+		if err := applyUpdate(&m, input); err != nil {
+			return Media{}, err
+		}
+		m.UpdatedAt = time.Now().UTC()
+		s.items[idx] = m
+		return m, nil
+	}
+	return Media{}, ErrNotFound
+}
+
+func (s *InMemoryService) Reorder(ctx context.Context, listingID uuid.UUID, order []uuid.UUID) ([]Media, error) {
+	s.mu.Lock()
+	current := make(map[uuid.UUID]int)
+	for idx, m := range s.items {
+		if m.ListingID == listingID {
+			current[m.ID] = idx
+		}
+	}
+	if err := validateOrder(order, current); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	now := time.Now().UTC()
+	for position, id := range order {
+		s.items[current[id]].Position = position
+		s.items[current[id]].UpdatedAt = now
+	}
+	s.mu.Unlock()
+
+	return s.List(ctx, listingID)
+}
+
+func (s *InMemoryService) Delete(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	var removed *Media
+	for idx, m := range s.items {
+		if m.ID == id {
+			removed = &m
+			s.items = append(s.items[:idx], s.items[idx+1:]...)
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if removed == nil {
+		return ErrNotFound
+	}
+	removeBlobs(ctx, s.store, *removed)
+	return nil
+}
+
+func (s *InMemoryService) Covers(_ context.Context, listingIDs []uuid.UUID) (map[uuid.UUID]Media, error) {
+	wanted := make(map[uuid.UUID]struct{}, len(listingIDs))
+	for _, id := range listingIDs {
+		wanted[id] = struct{}{}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	covers := make(map[uuid.UUID]Media)
+	for _, m := range s.items {
+		if _, ok := wanted[m.ListingID]; !ok {
+			continue
+		}
+		if existing, ok := covers[m.ListingID]; !ok || coverBefore(m, existing) {
+			covers[m.ListingID] = m
+		}
+	}
+	return covers, nil
+}
+
+// PerceptualHashes returns the photo hashes of each listing in gallery order.
+func (s *InMemoryService) PerceptualHashes(_ context.Context, listingIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	wanted := make(map[uuid.UUID]struct{}, len(listingIDs))
+	for _, id := range listingIDs {
+		wanted[id] = struct{}{}
+	}
+
+	s.mu.RLock()
+	items := make([]Media, 0, len(s.items))
+	for _, m := range s.items {
+		if _, ok := wanted[m.ListingID]; ok && m.Kind == KindPhoto && m.PerceptualHash != "" {
+			items = append(items, m)
+		}
+	}
+	s.mu.RUnlock()
+
+	sortMedia(items)
+	hashes := make(map[uuid.UUID][]string)
+	for _, m := range items {
+		hashes[m.ListingID] = append(hashes[m.ListingID], m.PerceptualHash)
+	}
+	return hashes, nil
+}
+
+func normalizeUpload(input UploadInput) (UploadInput, error) {
+	if input.ListingID == uuid.Nil {
+		return UploadInput{}, errors.New("listing id is required")
+	}
+	input.Kind = Kind(strings.ToLower(strings.TrimSpace(string(input.Kind))))
+	if input.Kind == "" {
+		input.Kind = KindPhoto
+	}
+	if !input.Kind.Valid() {
+		return UploadInput{}, errors.New("kind must be photo or floor_plan")
+	}
+	input.Caption = strings.TrimSpace(input.Caption)
+	if len(input.Data) == 0 {
+		return UploadInput{}, errors.New("file is empty")
+	}
+	return input, nil
+}
+
+func applyUpdate(m *Media, input UpdateInput) error {
+	if input.Kind != nil {
+		kind := Kind(strings.ToLower(strings.TrimSpace(string(*input.Kind))))
+		if !kind.Valid() {
+			return errors.New("kind must be photo or floor_plan")
+		}
+		m.Kind = kind
+	}
+	if input.Caption != nil {
+		m.Caption = strings.TrimSpace(*input.Caption)
+	}
+	return nil
+}
+
+func validateOrder(order []uuid.UUID, current map[uuid.UUID]int) error {
+	if len(order) != len(current) {
+		return ErrInvalidOrder
+	}
+	seen := make(map[uuid.UUID]struct{}, len(order))
+	for _, id := range order {
+		if _, ok := current[id]; !ok {
+			return ErrInvalidOrder
+		}
+		if _, dup := seen[id]; dup {
+			return ErrInvalidOrder
+		}
+		seen[id] = struct{}{}
+	}
+	return nil
+}
+
+// coverBefore orders photos ahead of floor plans, then by position.
+func coverBefore(a, b Media) bool {
+	if (a.Kind == KindPhoto) != (b.Kind == KindPhoto) {
+		return a.Kind == KindPhoto
+	}
+	return a.Position < b.Position
+}
+
+func sortMedia(items []Media) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Position != items[j].Position {
+			return items[i].Position < items[j].Position
+		}
+		return items[i].CreatedAt.Before(items[j].CreatedAt)
+	})
+}
+
+var _ Service = (*InMemoryService)(nil)
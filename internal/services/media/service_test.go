@@ -0,0 +1,107 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"shanraq.com/internal/storage"
+)
+
+func samplePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 120, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestInMemoryServiceUploadGeneratesVariants(t *testing.T) {
+	root := t.TempDir()
+	svc := NewInMemoryService(storage.NewLocalStore(root, "/media"))
+	ctx := context.Background()
+	listingID := uuid.New()
+
+	plan, err := svc.Upload(ctx, UploadInput{ListingID: listingID, Kind: KindFloorPlan, Data: samplePNG(t, 300, 300)})
+	if err != nil {
+		t.Fatalf("Upload(floor plan) error = %v", err)
+	}
+	photo, err := svc.Upload(ctx, UploadInput{ListingID: listingID, Caption: " Living room ", Data: samplePNG(t, 1600, 900)})
+	if err != nil {
+		t.Fatalf("Upload(photo) error = %v", err)
+	}
+
+	if photo.Position != 1 || photo.Kind != KindPhoto || photo.Caption != "Living room" {
+		t.Fatalf("unexpected photo metadata: %+v", photo)
+	}
+	if len(photo.Variants) != 3 {
+		t.Fatalf("expected 3 variants, got %d", len(photo.Variants))
+	}
+	for _, v := range photo.Variants {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(v.Key))); err != nil {
+			t.Fatalf("variant %s not stored: %v", v.Name, err)
+		}
+		switch v.Name {
+		case VariantThumbnail, VariantThumbnailWebP:
+			if v.Width != thumbnailWidth || v.Height != thumbnailHeight {
+				t.Fatalf("%s size = %dx%d", v.Name, v.Width, v.Height)
+			}
+		case VariantDisplayWebP:
+			if v.Width != displayMaxEdge || v.Height != 720 {
+				t.Fatalf("%s size = %dx%d", v.Name, v.Width, v.Height)
+			}
+		}
+	}
+
+	covers, err := svc.Covers(ctx, []uuid.UUID{listingID})
+	if err != nil {
+		t.Fatalf("Covers() error = %v", err)
+	}
+	if covers[listingID].ID != photo.ID {
+		t.Fatalf("expected photo to be the cover over the floor plan")
+	}
+	if got := covers[listingID].ThumbnailURL(); got != photo.VariantURL(VariantThumbnail) || got == "" {
+		t.Fatalf("unexpected cover thumbnail %q", got)
+	}
+
+	if _, err := svc.Reorder(ctx, listingID, []uuid.UUID{photo.ID}); !errors.Is(err, ErrInvalidOrder) {
+		t.Fatalf("expected ErrInvalidOrder for partial order, got %v", err)
+	}
+	items, err := svc.Reorder(ctx, listingID, []uuid.UUID{photo.ID, plan.ID})
+	if err != nil {
+		t.Fatalf("Reorder() error = %v", err)
+	}
+	if items[0].ID != photo.ID || items[1].ID != plan.ID {
+		t.Fatalf("unexpected order after reorder")
+	}
+
+	if err := svc.Delete(ctx, photo.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(photo.StorageKey))); !os.IsNotExist(err) {
+		t.Fatalf("expected original to be removed, stat err = %v", err)
+	}
+}
+
+func TestInMemoryServiceRejectsNonImages(t *testing.T) {
+	svc := NewInMemoryService(storage.NewLocalStore(t.TempDir(), "/media"))
+	_, err := svc.Upload(context.Background(), UploadInput{ListingID: uuid.New(), Data: []byte("%PDF-1.7 not an image")})
+	if !errors.Is(err, ErrUnsupportedType) {
+		t.Fatalf("expected ErrUnsupportedType, got %v", err)
+	}
+}
@@ -0,0 +1,290 @@
+package media
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"shanraq.com/internal/storage"
+)
+
+const mediaColumns = `
+        id, listing_id, kind, position, caption, content_type, width, height, size_bytes,
+        storage_key, url, variants::text, phash, created_at, updated_at`
+
+// storedVariant keeps the storage key that the API representation hides.
+type storedVariant struct {
+	Variant
+	Key string `json:"key"`
+}
+
+type sqlService struct {
+	db    *sql.DB
+	store storage.BlobStore
+}
+
+// NewSQLService builds a media service backed by the listing_media table.
+func NewSQLService(db *sql.DB, store storage.BlobStore) (Service, error) {
+	return &sqlService{db: db, store: store}, nil
+}
+
+func (s *sqlService) List(ctx context.Context, listingID uuid.UUID) ([]Media, error) {
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT `+mediaColumns+`
+        FROM listing_media
+        WHERE listing_id = $1
+        ORDER BY position, created_at`, listingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return collectMedia(rows)
+}
+
+func (s *sqlService) Get(ctx context.Context, id uuid.UUID) (Media, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+mediaColumns+` FROM listing_media WHERE id = $1`, id)
+	item, err := scanMedia(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Media{}, ErrNotFound
+		}
+		return Media{}, err
+	}
+	return item, nil
+}
+
+func (s *sqlService) Upload(ctx context.Context, input UploadInput) (Media, error) {
+	input, err := normalizeUpload(input)
+	if err != nil {
+		return Media{}, err
+	}
+	item, err := process(ctx, s.store, input)
+	if err != nil {
+		return Media{}, err
+	}
+
+	stored := make([]storedVariant, 0, len(item.Variants))
+	for _, v := range item.Variants {
+		stored = append(stored, storedVariant{Variant: v, Key: v.Key})
+	}
+	variants, err := json.Marshal(stored)
+	if err != nil {
+		removeBlobs(ctx, s.store, item)
+		return Media{}, err
+	}
+
+	err = s.db.QueryRowContext(ctx, `
+        INSERT INTO listing_media
+            (id, listing_id, kind, position, caption, content_type, width, height, size_bytes,
+             storage_key, url, variants, phash, created_at, updated_at)
+        VALUES ($1, $2, $3,
+                (SELECT COALESCE(MAX(position) + 1, 0) FROM listing_media WHERE listing_id = $2),
+                $4, $5, $6, $7, $8, $9, $10, $11::jsonb, $12, $13, $13)
+        RETURNING position`,
+		item.ID,
+		item.ListingID,
+		string(item.Kind),
+		item.Caption,
+		item.ContentType,
+		item.Width,
+		item.Height,
+		item.SizeBytes,
+		item.StorageKey,
+		item.URL,
+		string(variants),
+		item.PerceptualHash,
+		item.CreatedAt,
+	).Scan(&item.Position)
+	if err != nil {
+		removeBlobs(ctx, s.store, item)
+		return Media{}, err
+	}
+	return item, nil
+}
+
+func (s *sqlService) Update(ctx context.Context, id uuid.UUID, input UpdateInput) (Media, error) {
+	item, err := s.Get(ctx, id)
+	if err != nil {
+		return Media{}, err
+	}
+	if err := applyUpdate(&item, input); err != nil {
+		return Media{}, err
+	}
+	result, err := s.db.ExecContext(ctx, `
+        UPDATE listing_media
+        SET kind = $1, caption = $2, updated_at = NOW()
+        WHERE id = $3`, string(item.Kind), item.Caption, id)
+	if err != nil {
+		return Media{}, err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return Media{}, ErrNotFound
+	}
+	return s.Get(ctx, id)
+}
+
+func (s *sqlService) Reorder(ctx context.Context, listingID uuid.UUID, order []uuid.UUID) ([]Media, error) {
+	current, err := s.List(ctx, listingID)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make(map[uuid.UUID]int, len(current))
+	for idx, m := range current {
+		indexes[m.ID] = idx
+	}
+	if err := validateOrder(order, indexes); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for position, id := range order {
+		if _, err := tx.ExecContext(ctx, `
+            UPDATE listing_media SET position = $1, updated_at = NOW()
+            WHERE id = $2 AND listing_id = $3`, position, id, listingID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s.List(ctx, listingID)
+}
+
+func (s *sqlService) Delete(ctx context.Context, id uuid.UUID) error {
+	item, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	result, err := s.db.ExecContext(ctx, `DELETE FROM listing_media WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	removeBlobs(ctx, s.store, item)
+	return nil
+}
+
+func (s *sqlService) Covers(ctx context.Context, listingIDs []uuid.UUID) (map[uuid.UUID]Media, error) {
+	covers := make(map[uuid.UUID]Media)
+	if len(listingIDs) == 0 {
+		return covers, nil
+	}
+	ids := make([]string, 0, len(listingIDs))
+	for _, id := range listingIDs {
+		ids = append(ids, id.String())
+	}
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT DISTINCT ON (listing_id) `+mediaColumns+`
+        FROM listing_media
+        WHERE listing_id = ANY($1::uuid[])
+        ORDER BY listing_id, (kind = 'photo') DESC, position, created_at`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items, err := collectMedia(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		covers[item.ListingID] = item
+	}
+	return covers, nil
+}
+
+func (s *sqlService) PerceptualHashes(ctx context.Context, listingIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	hashes := make(map[uuid.UUID][]string)
+	if len(listingIDs) == 0 {
+		return hashes, nil
+	}
+	ids := make([]string, 0, len(listingIDs))
+	for _, id := range listingIDs {
+		ids = append(ids, id.String())
+	}
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT listing_id, phash
+        FROM listing_media
+        WHERE listing_id = ANY($1::uuid[]) AND kind = 'photo' AND phash <> ''
+        ORDER BY listing_id, position, created_at`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			listingID uuid.UUID
+			hash      string
+		)
+		if err := rows.Scan(&listingID, &hash); err != nil {
+			return nil, err
+		}
+		hashes[listingID] = append(hashes[listingID], hash)
+	}
+	return hashes, rows.Err()
+}
+
+func collectMedia(rows *sql.Rows) ([]Media, error) {
+	items := make([]Media, 0)
+	for rows.Next() {
+		item, err := scanMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func scanMedia(scanner interface {
+	Scan(dest ...any) error
+}) (Media, error) {
+	var item Media
+	var kind, variantsJSON string
+	if err := scanner.Scan(
+		&item.ID,
+		&item.ListingID,
+		&kind,
+		&item.Position,
+		&item.Caption,
+		&item.ContentType,
+		&item.Width,
+		&item.Height,
+		&item.SizeBytes,
+		&item.StorageKey,
+		&item.URL,
+		&variantsJSON,
+		&item.PerceptualHash,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	); err != nil {
+		return Media{}, err
+	}
+	item.Kind = Kind(kind)
+
+	var stored []storedVariant
+	if err := json.Unmarshal([]byte(variantsJSON), &stored); err == nil {
+		for _, v := range stored {
+			variant := v.Variant
+			variant.Key = v.Key
+			item.Variants = append(item.Variants, variant)
+		}
+	}
+	return item, nil
+}
+
+var _ Service = (*sqlService)(nil)
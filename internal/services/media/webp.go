@@ -0,0 +1,446 @@
+package media
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+	"math/bits"
+)
+
+// encodeWebP writes img as a lossless WebP (VP8L) bitstream. The encoder applies the
+// subtract-green and predictor transforms and codes the residuals with canonical
+// Huffman codes; backward references and colour caches are not used, which keeps it
+// small at the cost of larger files than libwebp produces.
+func encodeWebP(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width < 1 || height < 1 || width > 1<<14 || height > 1<<14 {
+		return errors.New("webp: image dimensions out of range")
+	}
+
+	pixels := make([]uint32, 0, width*height)
+	opaque := true
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A != 0xff {
+				opaque = false
+			}
+			// Subtract-green transform: red and blue are stored relative to green.
+			red := uint32(c.R-c.G) & 0xff
+			blue := uint32(c.B-c.G) & 0xff
+			pixels = append(pixels, uint32(c.A)<<24|red<<16|uint32(c.G)<<8|blue)
+		}
+	}
+	modes, blocksWide := choosePredictors(pixels, width, height)
+	residuals := applyPredictors(pixels, width, height, modes, blocksWide)
+
+	bw := &bitWriter{}
+	bw.write(0x2f, 8)
+	bw.write(uint32(width-1), 14)
+	bw.write(uint32(height-1), 14)
+	if opaque {
+		bw.write(0, 1)
+	} else {
+		bw.write(1, 1)
+	}
+	bw.write(0, 3)
+
+	bw.write(1, 1) // transform present
+	bw.write(2, 2) // SUBTRACT_GREEN
+	bw.write(1, 1) // transform present
+	bw.write(0, 2) // PREDICTOR
+	bw.write(predictorBits-2, 3)
+	modeImage := make([]uint32, len(modes))
+	for i, mode := range modes {
+		modeImage[i] = uint32(mode) << 8
+	}
+	writeEntropyImage(bw, modeImage, false)
+	bw.write(0, 1) // no further transforms
+
+	writeEntropyImage(bw, residuals, true)
+	data := bw.bytes()
+
+	chunkSize := len(data)
+	padded := chunkSize + chunkSize&1
+	header := make([]byte, 20)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], uint32(4+8+padded))
+	copy(header[8:12], "WEBP")
+	copy(header[12:16], "VP8L")
+	binary.LittleEndian.PutUint32(header[16:20], uint32(chunkSize))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if padded != chunkSize {
+		_, err := w.Write([]byte{0})
+		return err
+	}
+	return nil
+}
+
+// predictorBits sets the predictor block size to 16x16 pixels.
+const predictorBits = 4
+
+// candidateModes are the VP8L predictor modes tried per block: left, top,
+// average of left and top, and clamped gradient.
+var candidateModes = []int{1, 2, 7, 12}
+
+func choosePredictors(pixels []uint32, width, height int) ([]int, int) {
+	blocksWide := (width + 1<<predictorBits - 1) >> predictorBits
+	blocksHigh := (height + 1<<predictorBits - 1) >> predictorBits
+	modes := make([]int, blocksWide*blocksHigh)
+	for by := 0; by < blocksHigh; by++ {
+		for bx := 0; bx < blocksWide; bx++ {
+			best, bestCost := candidateModes[0], -1
+			for _, mode := range candidateModes {
+				cost := 0
+				for y := by << predictorBits; y < height && y < (by+1)<<predictorBits; y++ {
+					for x := bx << predictorBits; x < width && x < (bx+1)<<predictorBits; x++ {
+						cost += residualCost(subPixels(pixels[y*width+x], predict(pixels, width, x, y, mode)))
+					}
+				}
+				if bestCost < 0 || cost < bestCost {
+					best, bestCost = mode, cost
+				}
+			}
+			modes[by*blocksWide+bx] = best
+		}
+	}
+	return modes, blocksWide
+}
+
+func applyPredictors(pixels []uint32, width, height int, modes []int, blocksWide int) []uint32 {
+	residuals := make([]uint32, len(pixels))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			mode := modes[(y>>predictorBits)*blocksWide+x>>predictorBits]
+			residuals[y*width+x] = subPixels(pixels[y*width+x], predict(pixels, width, x, y, mode))
+		}
+	}
+	return residuals
+}
+
+// predict follows the VP8L rules: the first pixel predicts opaque black, the top
+// row predicts from the left and the left column from the top.
+func predict(pixels []uint32, width, x, y, mode int) uint32 {
+	switch {
+	case x == 0 && y == 0:
+		return 0xff000000
+	case y == 0:
+		return pixels[x-1]
+	case x == 0:
+		return pixels[(y-1)*width]
+	}
+	left := pixels[y*width+x-1]
+	top := pixels[(y-1)*width+x]
+	switch mode {
+	case 1:
+		return left
+	case 2:
+		return top
+	case 7:
+		return average2(left, top)
+	case 12:
+		return clampAddSubtractFull(left, top, pixels[(y-1)*width+x-1])
+	default:
+		return 0xff000000
+	}
+}
+
+func average2(a, b uint32) uint32 {
+	return (((a ^ b) & 0xfefefefe) >> 1) + (a & b)
+}
+
+func clampAddSubtractFull(a, b, c uint32) uint32 {
+	var out uint32
+	for shift := 0; shift < 32; shift += 8 {
+		v := int(a>>shift&0xff) + int(b>>shift&0xff) - int(c>>shift&0xff)
+		if v < 0 {
+			v = 0
+		} else if v > 255 {
+			v = 255
+		}
+		out |= uint32(v) << shift
+	}
+	return out
+}
+
+func subPixels(a, b uint32) uint32 {
+	var out uint32
+	for shift := 0; shift < 32; shift += 8 {
+		out |= ((a>>shift - b>>shift) & 0xff) << shift
+	}
+	return out
+}
+
+func residualCost(p uint32) int {
+	cost := 0
+	for shift := 0; shift < 32; shift += 8 {
+		v := int(int8(p >> shift))
+		if v < 0 {
+			v = -v
+		}
+		cost += v
+	}
+	return cost
+}
+
+// writeEntropyImage writes an ARGB image using literal codes only.
+func writeEntropyImage(bw *bitWriter, pixels []uint32, mainImage bool) {
+	bw.write(0, 1) // no colour cache
+	if mainImage {
+		bw.write(0, 1) // single prefix code group
+	}
+
+	green := make([]int, 256+24)
+	red := make([]int, 256)
+	blue := make([]int, 256)
+	alpha := make([]int, 256)
+	for _, p := range pixels {
+		green[p>>8&0xff]++
+		red[p>>16&0xff]++
+		blue[p&0xff]++
+		alpha[p>>24]++
+	}
+	codes := [][]prefixCode{
+		writePrefixCode(bw, green),
+		writePrefixCode(bw, red),
+		writePrefixCode(bw, blue),
+		writePrefixCode(bw, alpha),
+		writePrefixCode(bw, make([]int, 40)),
+	}
+	for _, p := range pixels {
+		bw.writeCode(codes[0][p>>8&0xff])
+		bw.writeCode(codes[1][p>>16&0xff])
+		bw.writeCode(codes[2][p&0xff])
+		bw.writeCode(codes[3][p>>24])
+	}
+}
+
+type prefixCode struct {
+	code   uint32
+	length uint
+}
+
+// codeLengthOrder is the order in which code length code lengths are stored.
+var codeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// writePrefixCode emits a prefix code for the histogram and returns the codes to use.
+func writePrefixCode(bw *bitWriter, histogram []int) []prefixCode {
+	codes := make([]prefixCode, len(histogram))
+	used := make([]int, 0, 2)
+	for symbol, count := range histogram {
+		if count > 0 {
+			used = append(used, symbol)
+		}
+	}
+	if len(used) <= 1 {
+		// A simple code with one symbol consumes no bits per pixel.
+		symbol := 0
+		if len(used) == 1 {
+			symbol = used[0]
+		}
+		bw.write(1, 1)
+		bw.write(0, 1)
+		if symbol < 2 {
+			bw.write(0, 1)
+			bw.write(uint32(symbol), 1)
+		} else {
+			bw.write(1, 1)
+			bw.write(uint32(symbol), 8)
+		}
+		return codes
+	}
+
+	lengths := huffmanLengths(histogram, 15)
+	lengthHistogram := make([]int, 19)
+	for _, l := range lengths {
+		lengthHistogram[l]++
+	}
+	if nonZero(lengthHistogram) < 2 {
+		// Keep the code length code a proper two-symbol tree.
+		if lengthHistogram[0] == 0 {
+			lengthHistogram[0] = 1
+		} else {
+			lengthHistogram[1] = 1
+		}
+	}
+	codeLengthLengths := huffmanLengths(lengthHistogram, 7)
+	codeLengthCodes := canonicalCodes(codeLengthLengths)
+
+	count := len(codeLengthOrder)
+	for count > 4 && codeLengthLengths[codeLengthOrder[count-1]] == 0 {
+		count--
+	}
+	bw.write(0, 1)
+	bw.write(uint32(count-4), 4)
+	for i := 0; i < count; i++ {
+		bw.write(uint32(codeLengthLengths[codeLengthOrder[i]]), 3)
+	}
+	bw.write(0, 1) // code lengths cover the whole alphabet
+	for _, l := range lengths {
+		bw.writeCode(codeLengthCodes[l])
+	}
+
+	for symbol, code := range canonicalCodes(lengths) {
+		codes[symbol] = code
+	}
+	return codes
+}
+
+func nonZero(values []int) int {
+	n := 0
+	for _, v := range values {
+		if v > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// huffmanLengths builds code lengths no longer than maxLength, flattening the
+// histogram until the tree fits.
+func huffmanLengths(histogram []int, maxLength int) []int {
+	counts := append([]int(nil), histogram...)
+	for {
+		lengths := treeLengths(counts)
+		longest := 0
+		for _, l := range lengths {
+			if l > longest {
+				longest = l
+			}
+		}
+		if longest <= maxLength {
+			return lengths
+		}
+		for i, c := range counts {
+			if c > 0 {
+				counts[i] = (c + 1) / 2
+			}
+		}
+	}
+}
+
+type huffmanNode struct {
+	count       int
+	symbol      int
+	left, right *huffmanNode
+}
+
+type nodeHeap []*huffmanNode
+
+func (h nodeHeap) Len() int { return len(h) }
+func (h nodeHeap) Less(i, j int) bool {
+	if h[i].count != h[j].count {
+		return h[i].count < h[j].count
+	}
+	return h[i].symbol < h[j].symbol
+}
+func (h nodeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *nodeHeap) Push(x any)   { *h = append(*h, x.(*huffmanNode)) }
+func (h *nodeHeap) Pop() any {
+	old := *h
+	node := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return node
+}
+
+func treeLengths(counts []int) []int {
+	lengths := make([]int, len(counts))
+	h := &nodeHeap{}
+	for symbol, c := range counts {
+		if c > 0 {
+			*h = append(*h, &huffmanNode{count: c, symbol: symbol})
+		}
+	}
+	if h.Len() == 1 {
+		lengths[(*h)[0].symbol] = 1
+		return lengths
+	}
+	heap.Init(h)
+	next := len(counts)
+	for h.Len() > 1 {
+		a := heap.Pop(h).(*huffmanNode)
+		b := heap.Pop(h).(*huffmanNode)
+		heap.Push(h, &huffmanNode{count: a.count + b.count, symbol: next, left: a, right: b})
+		next++
+	}
+	var walk func(n *huffmanNode, depth int)
+	walk = func(n *huffmanNode, depth int) {
+		if n.left == nil {
+			lengths[n.symbol] = depth
+			return
+		}
+		walk(n.left, depth+1)
+		walk(n.right, depth+1)
+	}
+	if h.Len() == 1 {
+		walk((*h)[0], 0)
+	}
+	return lengths
+}
+
+// canonicalCodes assigns codes in (length, symbol) order, as in DEFLATE.
+func canonicalCodes(lengths []int) []prefixCode {
+	var lengthCount [16]int
+	for _, l := range lengths {
+		if l > 0 {
+			lengthCount[l]++
+		}
+	}
+	var nextCode [16]uint32
+	code := uint32(0)
+	for l := 1; l < 16; l++ {
+		code = (code + uint32(lengthCount[l-1])) << 1
+		nextCode[l] = code
+	}
+	codes := make([]prefixCode, len(lengths))
+	for symbol, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		codes[symbol] = prefixCode{code: nextCode[l], length: uint(l)}
+		nextCode[l]++
+	}
+	return codes
+}
+
+// bitWriter packs values least-significant bit first, as VP8L requires.
+type bitWriter struct {
+	buf   []byte
+	acc   uint64
+	nbits uint
+}
+
+func (w *bitWriter) write(value uint32, n uint) {
+	w.acc |= uint64(value) << w.nbits
+	w.nbits += n
+	for w.nbits >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.nbits -= 8
+	}
+}
+
+// writeCode emits a Huffman code most-significant bit first.
+func (w *bitWriter) writeCode(c prefixCode) {
+	if c.length == 0 {
+		return
+	}
+	w.write(bits.Reverse32(c.code)>>(32-c.length), c.length)
+}
+
+func (w *bitWriter) bytes() []byte {
+	if w.nbits > 0 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc, w.nbits = 0, 0
+	}
+	return w.buf
+}
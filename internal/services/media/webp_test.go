@@ -0,0 +1,49 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/webp"
+)
+
+func TestEncodeWebPRoundTrip(t *testing.T) {
+	for name, alpha := range map[string]bool{"opaque": false, "translucent": true} {
+		img := image.NewNRGBA(image.Rect(0, 0, 67, 41))
+		for y := 0; y < 41; y++ {
+			for x := 0; x < 67; x++ {
+				a := uint8(255)
+				if alpha {
+					a = uint8(x * 3)
+				}
+				img.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 4), G: uint8(y*6 + x), B: uint8((x * y) % 251), A: a})
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := encodeWebP(&buf, img); err != nil {
+			t.Fatalf("%s: encodeWebP() error = %v", name, err)
+		}
+		decoded, err := webp.Decode(&buf)
+		if err != nil {
+			t.Fatalf("%s: webp.Decode() error = %v", name, err)
+		}
+		if decoded.Bounds() != img.Bounds() {
+			t.Fatalf("%s: bounds = %v, want %v", name, decoded.Bounds(), img.Bounds())
+		}
+		for y := 0; y < 41; y++ {
+			for x := 0; x < 67; x++ {
+				got := color.NRGBAModel.Convert(decoded.At(x, y)).(color.NRGBA)
+				want := img.NRGBAAt(x, y)
+				if want.A == 0 {
+					continue
+				}
+				if got != want {
+					t.Fatalf("%s: pixel (%d,%d) = %v, want %v", name, x, y, got, want)
+				}
+			}
+		}
+	}
+}
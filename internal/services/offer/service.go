@@ -0,0 +1,491 @@
+package offer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxExpiry is the furthest an offer or counter may stay open.
+	MaxExpiry = 90 * 24 * time.Hour
+	// DefaultCounterExpiry applies to counters sent without an expiry.
+	DefaultCounterExpiry = 72 * time.Hour
+	// MaxConditions caps the conditions attached to one set of terms.
+	MaxConditions = 10
+)
+
+// Status tracks an offer. Submitted and countered offers are open and wait for
+// the other party; the rest are final.
+type Status string
+
+const (
+	StatusSubmitted Status = "submitted"
+	StatusCountered Status = "countered"
+	StatusAccepted  Status = "accepted"
+	StatusRejected  Status = "rejected"
+	StatusWithdrawn Status = "withdrawn"
+	StatusExpired   Status = "expired"
+)
+
+// Valid reports whether the status is supported.
+func (s Status) Valid() bool {
+	switch s {
+	case StatusSubmitted, StatusCountered, StatusAccepted, StatusRejected, StatusWithdrawn, StatusExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// Open reports whether the negotiation is still running.
+func (s Status) Open() bool {
+	return s == StatusSubmitted || s == StatusCountered
+}
+
+// Party is a side of the negotiation.
+type Party string
+
+const (
+	PartyBuyer  Party = "buyer"
+	PartyAgency Party = "agency"
+)
+
+// Action is what a party does with an open offer.
+type Action string
+
+const (
+	ActionSubmit   Action = "submitted"
+	ActionCounter  Action = "countered"
+	ActionAccept   Action = "accepted"
+	ActionReject   Action = "rejected"
+	ActionWithdraw Action = "withdrawn"
+	ActionExpire   Action = "expired"
+)
+
+// Terms are the price and conditions on the table.
+type Terms struct {
+	Amount     float64   `json:"amount"`
+	Currency   string    `json:"currency"`
+	Conditions []string  `json:"conditions"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Offer is one buyer's negotiation on a listing. The embedded terms are the latest
+// on the table; History is the append-only record of every step.
+type Offer struct {
+	ID         uuid.UUID `json:"id"`
+	ListingID  uuid.UUID `json:"listing_id"`
+	AgencyID   uuid.UUID `json:"agency_id"`
+	BuyerID    string    `json:"-"`
+	BuyerEmail string    `json:"buyer_email"`
+	BuyerName  string    `json:"buyer_name,omitempty"`
+	Status     Status    `json:"status"`
+	Awaiting   Party     `json:"awaiting,omitempty"`
+	Terms
+	History   []Event   `json:"history,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Event is one immutable step of a negotiation.
+type Event struct {
+	ID         uuid.UUID `json:"id"`
+	OfferID    uuid.UUID `json:"offer_id"`
+	Sequence   int       `json:"sequence"`
+	Action     Action    `json:"action"`
+	Party      Party     `json:"party,omitempty"`
+	ActorEmail string    `json:"actor_email,omitempty"`
+	Terms
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SubmitInput opens a negotiation.
+type SubmitInput struct {
+	ListingID  uuid.UUID
+	AgencyID   uuid.UUID
+	BuyerID    string
+	BuyerEmail string
+	BuyerName  string
+	Terms      Terms
+	Note       string
+}
+
+// Response is a party's answer to an open offer. Terms are only read for
+// counters; missing currency and conditions carry over from the current terms.
+type Response struct {
+	Action     Action
+	Party      Party
+	ActorEmail string
+	Terms      Terms
+	Note       string
+}
+
+// Filter narrows the offers returned.
+type Filter struct {
+	ListingID uuid.UUID
+	AgencyID  uuid.UUID
+	BuyerID   string
+	Status    Status
+	OpenOnly  bool
+}
+
+// Service stores offers and their negotiation history.
+type Service interface {
+	Submit(ctx context.Context, input SubmitInput) (Offer, error)
+	Get(ctx context.Context, id uuid.UUID) (Offer, error)
+	List(ctx context.Context, filter Filter) ([]Offer, error)
+	Respond(ctx context.Context, id uuid.UUID, response Response) (Offer, error)
+	ExpireDue(ctx context.Context, now time.Time) (int, error)
+}
+
+var (
+	// ErrNotFound is returned when an offer does not exist.
+	ErrNotFound = errors.New("offer not found")
+	// ErrClosed is returned when responding to an offer that is no longer open.
+	ErrClosed = errors.New("offer is closed")
+	// ErrExpired is returned when responding to an offer past its expiry.
+	ErrExpired = errors.New("offer has expired")
+	// ErrNotYourTurn is returned when a party answers its own terms.
+	ErrNotYourTurn = errors.New("offer is waiting for the other party")
+	// ErrOpenOffer is returned when a buyer already negotiates on the listing.
+	ErrOpenOffer = errors.New("buyer already has an open offer on this listing")
+	// ErrAlreadyAccepted is returned when the listing already has an accepted offer.
+	ErrAlreadyAccepted = errors.New("listing already has an accepted offer")
+)
+
+// Matches reports whether the offer passes the filter.
+func (f Filter) Matches(o Offer) bool {
+	if f.ListingID != uuid.Nil && o.ListingID != f.ListingID {
+		return false
+	}
+	if f.AgencyID != uuid.Nil && o.AgencyID != f.AgencyID {
+		return false
+	}
+	if f.BuyerID != "" && o.BuyerID != f.BuyerID {
+		return false
+	}
+	if f.Status != "" && o.Status != f.Status {
+		return false
+	}
+	if f.OpenOnly && !o.Status.Open() {
+		return false
+	}
+	return true
+}
+
+func normalizeTerms(terms Terms, now time.Time) (Terms, error) {
+	if terms.Amount <= 0 {
+		return Terms{}, errors.New("amount must be positive")
+	}
+	terms.Currency = strings.ToUpper(strings.TrimSpace(terms.Currency))
+	if len(terms.Currency) != 3 || strings.Trim(terms.Currency, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return Terms{}, errors.New("currency must be an ISO 4217 code")
+	}
+	if len(terms.Conditions) > MaxConditions {
+		return Terms{}, fmt.Errorf("at most %d conditions are allowed", MaxConditions)
+	}
+	conditions := make([]string, 0, len(terms.Conditions))
+	for _, condition := range terms.Conditions {
+		condition = strings.TrimSpace(condition)
+		if condition == "" {
+			continue
+		}
+		if utf8.RuneCountInString(condition) > 300 {
+			return Terms{}, errors.New("conditions cannot exceed 300 characters")
+		}
+		conditions = append(conditions, condition)
+	}
+	terms.Conditions = conditions
+	terms.ExpiresAt = terms.ExpiresAt.UTC().Truncate(time.Second)
+	if !terms.ExpiresAt.After(now) {
+		return Terms{}, errors.New("expires_at must be in the future")
+	}
+	if terms.ExpiresAt.Sub(now) > MaxExpiry {
+		return Terms{}, errors.New("offers cannot stay open for more than 90 days")
+	}
+	return terms, nil
+}
+
+func normalizeSubmit(input SubmitInput, now time.Time) (SubmitInput, error) {
+	if input.ListingID == uuid.Nil {
+		return SubmitInput{}, errors.New("listing is required")
+	}
+	if input.AgencyID == uuid.Nil {
+		return SubmitInput{}, errors.New("listing has no agency to negotiate with")
+	}
+	input.BuyerID = strings.TrimSpace(input.BuyerID)
+	input.BuyerEmail = strings.TrimSpace(input.BuyerEmail)
+	if input.BuyerID == "" || input.BuyerEmail == "" {
+		return SubmitInput{}, errors.New("buyer is required")
+	}
+	terms, err := normalizeTerms(input.Terms, now)
+	if err != nil {
+		return SubmitInput{}, err
+	}
+	input.Terms = terms
+	input.BuyerName = strings.TrimSpace(input.BuyerName)
+	input.Note = strings.TrimSpace(input.Note)
+	return input, nil
+}
+
+// apply validates the response against the offer and returns the updated offer
+// together with the event recording it. The offer is not modified.
+func apply(o Offer, response Response, now time.Time) (Offer, Event, error) {
+	if !o.Status.Open() {
+		return Offer{}, Event{}, ErrClosed
+	}
+	if !o.ExpiresAt.After(now) {
+		return Offer{}, Event{}, ErrExpired
+	}
+	if response.Party != PartyBuyer && response.Party != PartyAgency {
+		return Offer{}, Event{}, errors.New("party must be buyer or agency")
+	}
+	switch response.Action {
+	case ActionWithdraw:
+		if response.Party != PartyBuyer {
+			return Offer{}, Event{}, errors.New("only the buyer can withdraw an offer")
+		}
+	case ActionCounter, ActionAccept, ActionReject:
+		if response.Party != o.Awaiting {
+			return Offer{}, Event{}, ErrNotYourTurn
+		}
+	default:
+		return Offer{}, Event{}, errors.New("action must be counter, accept, reject or withdraw")
+	}
+
+	next := o
+	event := Event{
+		OfferID:    o.ID,
+		Sequence:   len(o.History) + 1,
+		Action:     response.Action,
+		Party:      response.Party,
+		ActorEmail: strings.TrimSpace(response.ActorEmail),
+		Terms:      o.Terms,
+		Note:       strings.TrimSpace(response.Note),
+		CreatedAt:  now,
+	}
+	switch response.Action {
+	case ActionCounter:
+		terms := response.Terms
+		if terms.Currency == "" {
+			terms.Currency = o.Currency
+		}
+		if terms.Conditions == nil {
+			terms.Conditions = o.Conditions
+		}
+		if terms.ExpiresAt.IsZero() {
+			terms.ExpiresAt = now.Add(DefaultCounterExpiry)
+		}
+		terms, err := normalizeTerms(terms, now)
+		if err != nil {
+			return Offer{}, Event{}, err
+		}
+		next.Terms = terms
+		event.Terms = terms
+		if response.Party == PartyAgency {
+			next.Status, next.Awaiting = StatusCountered, PartyBuyer
+		} else {
+			next.Status, next.Awaiting = StatusSubmitted, PartyAgency
+		}
+	case ActionAccept:
+		next.Status, next.Awaiting = StatusAccepted, ""
+	case ActionReject:
+		next.Status, next.Awaiting = StatusRejected, ""
+	case ActionWithdraw:
+		next.Status, next.Awaiting = StatusWithdrawn, ""
+	}
+	next.UpdatedAt = now
+	return next, event, nil
+}
+
+// expire closes an open offer whose terms ran out.
+func expire(o Offer, at time.Time) (Offer, Event) {
+	next := o
+	next.Status, next.Awaiting, next.UpdatedAt = StatusExpired, "", at
+	return next, Event{
+		OfferID:   o.ID,
+		Sequence:  len(o.History) + 1,
+		Action:    ActionExpire,
+		Terms:     o.Terms,
+		CreatedAt: at,
+	}
+}
+
+// InMemoryService keeps offers in process memory.
+type InMemoryService struct {
+	mu     sync.RWMutex
+	offers []Offer
+}
+
+// NewInMemoryService returns an empty offer store.
+func NewInMemoryService() *InMemoryService {
+	return &InMemoryService{}
+}
+
+// Submit opens a negotiation; the buyer may hold one open offer per listing.
+func (s *InMemoryService) Submit(_ context.Context, input SubmitInput) (Offer, error) {
+	now := time.Now().UTC()
+	input, err := normalizeSubmit(input, now)
+	if err != nil {
+		return Offer{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expireLocked(now)
+	for _, o := range s.offers {
+		if o.ListingID == input.ListingID && o.BuyerID == input.BuyerID && o.Status.Open() {
+			return Offer{}, ErrOpenOffer
+		}
+	}
+	o := Offer{
+		ID:         uuid.New(),
+		ListingID:  input.ListingID,
+		AgencyID:   input.AgencyID,
+		BuyerID:    input.BuyerID,
+		BuyerEmail: input.BuyerEmail,
+		BuyerName:  input.BuyerName,
+		Status:     StatusSubmitted,
+		Awaiting:   PartyAgency,
+		Terms:      input.Terms,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	o.History = []Event{{
+		ID:         uuid.New(),
+		OfferID:    o.ID,
+		Sequence:   1,
+		Action:     ActionSubmit,
+		Party:      PartyBuyer,
+		ActorEmail: input.BuyerEmail,
+		Terms:      input.Terms,
+		Note:       input.Note,
+		CreatedAt:  now,
+	}}
+	s.offers = append(s.offers, o)
+	return clone(o), nil
+}
+
+func (s *InMemoryService) Get(_ context.Context, id uuid.UUID) (Offer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, o := range s.offers {
+		if o.ID == id {
+			return clone(o), nil
+		}
+	}
+	return Offer{}, ErrNotFound
+}
+
+// List returns matching offers without their history, newest first.
+func (s *InMemoryService) List(_ context.Context, filter Filter) ([]Offer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Offer, 0)
+	for _, o := range s.offers {
+		if filter.Matches(o) {
+			o.History = nil
+			out = append(out, clone(o))
+		}
+	}
+	sortOffers(out)
+	return out, nil
+}
+
+// Respond records a counter, acceptance, rejection or withdrawal. Only one offer
+// per listing can be accepted.
+func (s *InMemoryService) Respond(_ context.Context, id uuid.UUID, response Response) (Offer, error) {
+	now := time.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := -1
+	for i := range s.offers {
+		if s.offers[i].ID == id {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return Offer{}, ErrNotFound
+	}
+	next, event, err := apply(s.offers[idx], response, now)
+	if errors.Is(err, ErrExpired) {
+		s.expireAt(idx)
+		return Offer{}, ErrExpired
+	}
+	if err != nil {
+		return Offer{}, err
+	}
+	if next.Status == StatusAccepted {
+		for _, o := range s.offers {
+			if o.ListingID == next.ListingID && o.Status == StatusAccepted {
+				return Offer{}, ErrAlreadyAccepted
+			}
+		}
+	}
+	event.ID = uuid.New()
+	next.History = append(append([]Event(nil), s.offers[idx].History...), event)
+	s.offers[idx] = next
+	return clone(next), nil
+}
+
+// ExpireDue closes open offers whose terms have run out.
+func (s *InMemoryService) ExpireDue(_ context.Context, now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.expireLocked(now.UTC()), nil
+}
+
+func (s *InMemoryService) expireLocked(now time.Time) int {
+	expired := 0
+	for i, o := range s.offers {
+		if o.Status.Open() && !o.ExpiresAt.After(now) {
+			s.expireAt(i)
+			expired++
+		}
+	}
+	return expired
+}
+
+func (s *InMemoryService) expireAt(idx int) {
+	o := s.offers[idx]
+	next, event := expire(o, o.ExpiresAt)
+	event.ID = uuid.New()
+	next.History = append(append([]Event(nil), o.History...), event)
+	s.offers[idx] = next
+}
+
+// clone copies the slices so callers cannot rewrite the stored history.
+func clone(o Offer) Offer {
+	o.Conditions = append([]string(nil), o.Conditions...)
+	if o.History != nil {
+		history := make([]Event, len(o.History))
+		for i, e := range o.History {
+			e.Conditions = append([]string(nil), e.Conditions...)
+			history[i] = e
+		}
+		o.History = history
+	}
+	return o
+}
+
+func sortOffers(offers []Offer) {
+	sort.SliceStable(offers, func(i, j int) bool { return offers[i].CreatedAt.After(offers[j].CreatedAt) })
+}
+
+var _ Service = (*InMemoryService)(nil)
@@ -0,0 +1,137 @@
+package offer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestInMemoryServiceNegotiation(t *testing.T) {
+	ctx := context.Background()
+	service := NewInMemoryService()
+	listingID, agencyID := uuid.New(), uuid.New()
+	expires := time.Now().Add(48 * time.Hour)
+	valid := SubmitInput{
+		ListingID:  listingID,
+		AgencyID:   agencyID,
+		BuyerID:    "buyer-1",
+		BuyerEmail: "ana@example.com",
+		Terms:      Terms{Amount: 450000, Currency: "eur", Conditions: []string{" subject to survey ", ""}, ExpiresAt: expires},
+	}
+
+	cases := map[string]func(*SubmitInput){
+		"no agency":     func(in *SubmitInput) { in.AgencyID = uuid.Nil },
+		"zero amount":   func(in *SubmitInput) { in.Terms.Amount = 0 },
+		"bad currency":  func(in *SubmitInput) { in.Terms.Currency = "EURO" },
+		"past expiry":   func(in *SubmitInput) { in.Terms.ExpiresAt = time.Now().Add(-time.Minute) },
+		"distant":       func(in *SubmitInput) { in.Terms.ExpiresAt = time.Now().Add(MaxExpiry + time.Hour) },
+		"no buyer mail": func(in *SubmitInput) { in.BuyerEmail = "" },
+	}
+	for name, mutate := range cases {
+		input := valid
+		mutate(&input)
+		if _, err := service.Submit(ctx, input); err == nil {
+			t.Errorf("%s: Submit() error = nil, want validation error", name)
+		}
+	}
+
+	first, err := service.Submit(ctx, valid)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if first.Status != StatusSubmitted || first.Awaiting != PartyAgency || first.Currency != "EUR" || len(first.Conditions) != 1 {
+		t.Fatalf("Submit() = %+v, want submitted EUR offer with one condition awaiting the agency", first)
+	}
+	if _, err := service.Submit(ctx, valid); !errors.Is(err, ErrOpenOffer) {
+		t.Fatalf("second Submit() error = %v, want ErrOpenOffer", err)
+	}
+	rival := valid
+	rival.BuyerID, rival.BuyerEmail = "buyer-2", "rui@example.com"
+	second, err := service.Submit(ctx, rival)
+	if err != nil {
+		t.Fatalf("Submit(rival) error = %v", err)
+	}
+
+	if _, err := service.Respond(ctx, first.ID, Response{Action: ActionCounter, Party: PartyBuyer, Terms: Terms{Amount: 460000}}); !errors.Is(err, ErrNotYourTurn) {
+		t.Fatalf("buyer counter error = %v, want ErrNotYourTurn", err)
+	}
+	countered, err := service.Respond(ctx, first.ID, Response{Action: ActionCounter, Party: PartyAgency, ActorEmail: "layla@shanraq.com", Terms: Terms{Amount: 480000}})
+	if err != nil {
+		t.Fatalf("agency counter error = %v", err)
+	}
+	if countered.Status != StatusCountered || countered.Awaiting != PartyBuyer || countered.Amount != 480000 || countered.Currency != "EUR" {
+		t.Fatalf("counter = %+v, want countered 480000 EUR awaiting the buyer", countered)
+	}
+	if countered.ExpiresAt.Sub(time.Now()) < DefaultCounterExpiry-time.Minute {
+		t.Errorf("counter ExpiresAt = %v, want the default counter window", countered.ExpiresAt)
+	}
+
+	accepted, err := service.Respond(ctx, first.ID, Response{Action: ActionAccept, Party: PartyBuyer, ActorEmail: "ana@example.com"})
+	if err != nil {
+		t.Fatalf("accept error = %v", err)
+	}
+	if accepted.Status != StatusAccepted || len(accepted.History) != 3 {
+		t.Fatalf("accept = %s with %d events, want accepted with 3", accepted.Status, len(accepted.History))
+	}
+	if accepted.History[0].Amount != 450000 || accepted.History[1].Amount != 480000 || accepted.History[2].Action != ActionAccept {
+		t.Errorf("history = %+v, want submit, counter, accept", accepted.History)
+	}
+	if _, err := service.Respond(ctx, first.ID, Response{Action: ActionWithdraw, Party: PartyBuyer}); !errors.Is(err, ErrClosed) {
+		t.Errorf("withdraw after accept error = %v, want ErrClosed", err)
+	}
+	if _, err := service.Respond(ctx, second.ID, Response{Action: ActionAccept, Party: PartyAgency}); !errors.Is(err, ErrAlreadyAccepted) {
+		t.Errorf("second accept error = %v, want ErrAlreadyAccepted", err)
+	}
+
+	accepted.History[0].Amount = 1
+	stored, err := service.Get(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if stored.History[0].Amount != 450000 {
+		t.Error("history was modified through a returned offer")
+	}
+
+	open, err := service.List(ctx, Filter{ListingID: listingID, OpenOnly: true})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(open) != 1 || open[0].ID != second.ID || open[0].History != nil {
+		t.Fatalf("List(open) = %+v, want the rival offer without history", open)
+	}
+}
+
+func TestInMemoryServiceExpiry(t *testing.T) {
+	ctx := context.Background()
+	service := NewInMemoryService()
+	o, err := service.Submit(ctx, SubmitInput{
+		ListingID:  uuid.New(),
+		AgencyID:   uuid.New(),
+		BuyerID:    "buyer-1",
+		BuyerEmail: "ana@example.com",
+		Terms:      Terms{Amount: 1000, Currency: "USD", ExpiresAt: time.Now().Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	if n, _ := service.ExpireDue(ctx, time.Now()); n != 0 {
+		t.Fatalf("ExpireDue(now) = %d, want 0", n)
+	}
+	if n, _ := service.ExpireDue(ctx, time.Now().Add(2*time.Hour)); n != 1 {
+		t.Fatalf("ExpireDue(later) = %d, want 1", n)
+	}
+	expired, err := service.Get(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if expired.Status != StatusExpired || len(expired.History) != 2 || expired.History[1].Action != ActionExpire {
+		t.Fatalf("Get() = %s with %+v, want expired with an expiry event", expired.Status, expired.History)
+	}
+	if _, err := service.Respond(ctx, o.ID, Response{Action: ActionAccept, Party: PartyAgency}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Respond() on expired offer error = %v, want ErrClosed", err)
+	}
+}
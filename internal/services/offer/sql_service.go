@@ -0,0 +1,316 @@
+package offer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const offerColumns = `id, listing_id, agency_id, buyer_id, buyer_email, buyer_name, status, awaiting, amount, currency,
+        COALESCE(array_to_json(conditions)::text, '[]'), expires_at, created_at, updated_at`
+
+const eventColumns = `id, offer_id, sequence, action, party, actor_email, amount, currency,
+        COALESCE(array_to_json(conditions)::text, '[]'), expires_at, note, created_at`
+
+type sqlService struct {
+	db *sql.DB
+}
+
+// NewSQLService builds an offer service backed by PostgreSQL.
+func NewSQLService(db *sql.DB) (Service, error) {
+	return &sqlService{db: db}, nil
+}
+
+// Submit relies on the partial unique index on open offers to keep one running
+// negotiation per buyer and listing.
+func (s *sqlService) Submit(ctx context.Context, input SubmitInput) (Offer, error) {
+	now := time.Now().UTC()
+	input, err := normalizeSubmit(input, now)
+	if err != nil {
+		return Offer{}, err
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Offer{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := expireDue(ctx, tx, now, "listing_id = $2 AND buyer_id = $3", input.ListingID, input.BuyerID); err != nil {
+		return Offer{}, err
+	}
+	o, err := scanOffer(tx.QueryRowContext(ctx, `
+        INSERT INTO offers (listing_id, agency_id, buyer_id, buyer_email, buyer_name, amount, currency, conditions, expires_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        RETURNING `+offerColumns,
+		input.ListingID, input.AgencyID, input.BuyerID, input.BuyerEmail, input.BuyerName,
+		input.Terms.Amount, input.Terms.Currency, input.Terms.Conditions, input.Terms.ExpiresAt))
+	if err != nil {
+		switch pgCode(err) {
+		case "23505":
+			return Offer{}, ErrOpenOffer
+		case "23503":
+			return Offer{}, ErrNotFound
+		}
+		return Offer{}, err
+	}
+	event, err := insertEvent(ctx, tx, Event{
+		OfferID:    o.ID,
+		Sequence:   1,
+		Action:     ActionSubmit,
+		Party:      PartyBuyer,
+		ActorEmail: input.BuyerEmail,
+		Terms:      o.Terms,
+		Note:       input.Note,
+		CreatedAt:  o.CreatedAt,
+	})
+	if err != nil {
+		return Offer{}, err
+	}
+	o.History = []Event{event}
+	return o, tx.Commit()
+}
+
+func (s *sqlService) Get(ctx context.Context, id uuid.UUID) (Offer, error) {
+	o, err := scanOffer(s.db.QueryRowContext(ctx, `SELECT `+offerColumns+` FROM offers WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Offer{}, ErrNotFound
+	}
+	if err != nil {
+		return Offer{}, err
+	}
+	if o.History, err = history(ctx, s.db, id); err != nil {
+		return Offer{}, err
+	}
+	return o, nil
+}
+
+func (s *sqlService) List(ctx context.Context, filter Filter) ([]Offer, error) {
+	var (
+		clauses []string
+		args    []any
+	)
+	add := func(clause string, value any) {
+		args = append(args, value)
+		clauses = append(clauses, fmt.Sprintf(clause, len(args)))
+	}
+	if filter.ListingID != uuid.Nil {
+		add("listing_id = $%d", filter.ListingID)
+	}
+	if filter.AgencyID != uuid.Nil {
+		add("agency_id = $%d", filter.AgencyID)
+	}
+	if filter.BuyerID != "" {
+		add("buyer_id = $%d", filter.BuyerID)
+	}
+	if filter.Status != "" {
+		add("status = $%d", string(filter.Status))
+	}
+	if filter.OpenOnly {
+		clauses = append(clauses, "status IN ('submitted', 'countered')")
+	}
+	where := ""
+	if len(clauses) > 0 {
+		where = "WHERE " + strings.Join(clauses, " AND ")
+	}
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT `+offerColumns+`
+        FROM offers
+        `+where+`
+        ORDER BY created_at DESC, id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	offers := make([]Offer, 0)
+	for rows.Next() {
+		o, err := scanOffer(rows)
+		if err != nil {
+			return nil, err
+		}
+		offers = append(offers, o)
+	}
+	return offers, rows.Err()
+}
+
+// Respond locks the offer row so concurrent answers are applied one after the
+// other; the partial unique index on accepted offers rejects a second acceptance
+// on the same listing.
+func (s *sqlService) Respond(ctx context.Context, id uuid.UUID, response Response) (Offer, error) {
+	now := time.Now().UTC()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Offer{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	current, err := scanOffer(tx.QueryRowContext(ctx, `SELECT `+offerColumns+` FROM offers WHERE id = $1 FOR UPDATE`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Offer{}, ErrNotFound
+	}
+	if err != nil {
+		return Offer{}, err
+	}
+	if current.History, err = history(ctx, tx, id); err != nil {
+		return Offer{}, err
+	}
+	next, event, err := apply(current, response, now)
+	if errors.Is(err, ErrExpired) {
+		if _, err := expireDue(ctx, tx, now, "id = $2", id); err != nil {
+			return Offer{}, err
+		}
+		if err := tx.Commit(); err != nil {
+			return Offer{}, err
+		}
+		return Offer{}, ErrExpired
+	}
+	if err != nil {
+		return Offer{}, err
+	}
+
+	updated, err := scanOffer(tx.QueryRowContext(ctx, `
+        UPDATE offers
+        SET status = $2, awaiting = $3, amount = $4, currency = $5, conditions = $6, expires_at = $7, updated_at = $8
+        WHERE id = $1
+        RETURNING `+offerColumns,
+		id, string(next.Status), string(next.Awaiting), next.Amount, next.Currency, next.Conditions, next.ExpiresAt, now))
+	if err != nil {
+		if pgCode(err) == "23505" {
+			return Offer{}, ErrAlreadyAccepted
+		}
+		return Offer{}, err
+	}
+	if event, err = insertEvent(ctx, tx, event); err != nil {
+		return Offer{}, err
+	}
+	updated.History = append(current.History, event)
+	return updated, tx.Commit()
+}
+
+func (s *sqlService) ExpireDue(ctx context.Context, now time.Time) (int, error) {
+	return expireDue(ctx, s.db, now.UTC(), "")
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// expireDue closes open offers past their expiry and records the expiry in each
+// offer's history. The optional clause narrows the offers and numbers its
+// placeholders from $2.
+func expireDue(ctx context.Context, db execer, now time.Time, clause string, args ...any) (int, error) {
+	if clause != "" {
+		clause = " AND " + clause
+	}
+	result, err := db.ExecContext(ctx, `
+        WITH due AS (
+            UPDATE offers
+            SET status = 'expired', awaiting = '', updated_at = expires_at
+            WHERE status IN ('submitted', 'countered') AND expires_at <= $1`+clause+`
+            RETURNING id, amount, currency, conditions, expires_at
+        )
+        INSERT INTO offer_events (offer_id, sequence, action, amount, currency, conditions, expires_at, created_at)
+        SELECT due.id,
+               (SELECT COALESCE(MAX(e.sequence), 0) + 1 FROM offer_events e WHERE e.offer_id = due.id),
+               'expired', due.amount, due.currency, due.conditions, due.expires_at, due.expires_at
+        FROM due`, append([]any{now}, args...)...)
+	if err != nil {
+		return 0, err
+	}
+	expired, _ := result.RowsAffected()
+	return int(expired), nil
+}
+
+func insertEvent(ctx context.Context, tx *sql.Tx, e Event) (Event, error) {
+	return scanEvent(tx.QueryRowContext(ctx, `
+        INSERT INTO offer_events
+            (offer_id, sequence, action, party, actor_email, amount, currency, conditions, expires_at, note, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        RETURNING `+eventColumns,
+		e.OfferID, e.Sequence, string(e.Action), string(e.Party), e.ActorEmail, e.Amount, e.Currency, e.Conditions,
+		e.ExpiresAt, e.Note, e.CreatedAt))
+}
+
+func history(ctx context.Context, db querier, offerID uuid.UUID) ([]Event, error) {
+	rows, err := db.QueryContext(ctx, `SELECT `+eventColumns+` FROM offer_events WHERE offer_id = $1 ORDER BY sequence`, offerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]Event, 0)
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func scanOffer(row interface {
+	Scan(dest ...any) error
+}) (Offer, error) {
+	var (
+		o              Offer
+		listingID      uuid.NullUUID
+		agencyID       uuid.NullUUID
+		status         string
+		awaiting       string
+		conditionsJSON string
+	)
+	if err := row.Scan(&o.ID, &listingID, &agencyID, &o.BuyerID, &o.BuyerEmail, &o.BuyerName, &status, &awaiting,
+		&o.Amount, &o.Currency, &conditionsJSON, &o.ExpiresAt, &o.CreatedAt, &o.UpdatedAt); err != nil {
+		return Offer{}, err
+	}
+	if err := json.Unmarshal([]byte(conditionsJSON), &o.Conditions); err != nil {
+		return Offer{}, err
+	}
+	o.ListingID, o.AgencyID = listingID.UUID, agencyID.UUID
+	o.Status, o.Awaiting = Status(status), Party(awaiting)
+	o.ExpiresAt = o.ExpiresAt.UTC()
+	return o, nil
+}
+
+func scanEvent(row interface {
+	Scan(dest ...any) error
+}) (Event, error) {
+	var (
+		e              Event
+		action         string
+		party          string
+		conditionsJSON string
+	)
+	if err := row.Scan(&e.ID, &e.OfferID, &e.Sequence, &action, &party, &e.ActorEmail, &e.Amount, &e.Currency,
+		&conditionsJSON, &e.ExpiresAt, &e.Note, &e.CreatedAt); err != nil {
+		return Event{}, err
+	}
+	if err := json.Unmarshal([]byte(conditionsJSON), &e.Conditions); err != nil {
+		return Event{}, err
+	}
+	e.Action, e.Party = Action(action), Party(party)
+	e.ExpiresAt = e.ExpiresAt.UTC()
+	return e, nil
+}
+
+func pgCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
+
+var _ Service = (*sqlService)(nil)
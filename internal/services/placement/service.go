@@ -0,0 +1,452 @@
+package placement
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"shanraq.com/internal/i18n"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+// MaxPosition bounds the homepage slot a placement can claim.
+const MaxPosition = 24
+
+// Placement pins a listing to a featured slot for a scheduled window. Country and
+// Locale narrow the audience; empty values match everyone.
+type Placement struct {
+	ID        uuid.UUID  `json:"id"`
+	ListingID uuid.UUID  `json:"listing_id"`
+	Position  int        `json:"position"`
+	Country   string     `json:"country,omitempty"`
+	Locale    string     `json:"locale,omitempty"`
+	StartsAt  time.Time  `json:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+	Note      string     `json:"note,omitempty"`
+	CreatedBy string     `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Input carries the editable fields of a placement.
+type Input struct {
+	ListingID uuid.UUID
+	Position  int
+	Country   string
+	Locale    string
+	StartsAt  time.Time
+	EndsAt    *time.Time
+	Note      string
+	CreatedBy string
+}
+
+// Filter narrows the placements returned to editors. A zero ActiveAt lists every
+// placement regardless of schedule.
+type Filter struct {
+	ActiveAt time.Time
+	Country  string
+	Locale   string
+}
+
+// FeaturedQuery describes who the featured listings are for.
+type FeaturedQuery struct {
+	Limit   int
+	Viewer  listingservice.Viewer
+	Country string
+	Locale  string
+	At      time.Time
+}
+
+// Service schedules featured-listing placements and fills the featured slots.
+type Service interface {
+	List(ctx context.Context, filter Filter) ([]Placement, error)
+	Create(ctx context.Context, input Input) (Placement, error)
+	Update(ctx context.Context, id uuid.UUID, input Input) (Placement, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Featured(ctx context.Context, query FeaturedQuery) ([]listingservice.Listing, error)
+}
+
+// ErrNotFound is returned when a placement cannot be located.
+var ErrNotFound = errors.New("placement not found")
+
+// ErrUnsupportedLocale is returned when a placement targets a locale the site does
+// not serve.
+var ErrUnsupportedLocale = errors.New("unsupported locale")
+
+// ActiveAt reports whether the placement's schedule covers the instant.
+func (p Placement) ActiveAt(at time.Time) bool {
+	if at.Before(p.StartsAt) {
+		return false
+	}
+	return p.EndsAt == nil || at.Before(*p.EndsAt)
+}
+
+// Targets reports whether the placement is meant for the country and locale.
+func (p Placement) Targets(country, locale string) bool {
+	if p.Country != "" && p.Country != country {
+		return false
+	}
+	return p.Locale == "" || p.Locale == locale
+}
+
+// specificity ranks audience-targeted placements above broader ones competing for
+// the same slot.
+func (p Placement) specificity() int {
+	score := 0
+	if p.Country != "" {
+		score += 2
+	}
+	if p.Locale != "" {
+		score++
+	}
+	return score
+}
+
+// Matches reports whether the placement passes the filter.
+func (f Filter) Matches(p Placement) bool {
+	if !f.ActiveAt.IsZero() && !p.ActiveAt(f.ActiveAt) {
+		return false
+	}
+	if f.Country != "" && p.Country != "" && p.Country != f.Country {
+		return false
+	}
+	return f.Locale == "" || p.Locale == "" || p.Locale == f.Locale
+}
+
+func (q FeaturedQuery) normalize() FeaturedQuery {
+	if q.Limit <= 0 {
+		q.Limit = 6
+	}
+	if q.At.IsZero() {
+		q.At = time.Now().UTC()
+	}
+	q.Country = strings.ToUpper(strings.TrimSpace(q.Country))
+	q.Locale = i18n.Normalize(q.Locale)
+	return q
+}
+
+func normalizeInput(input Input, now time.Time) (Input, error) {
+	if input.ListingID == uuid.Nil {
+		return Input{}, errors.New("listing_id is required")
+	}
+	if input.Position < 1 || input.Position > MaxPosition {
+		return Input{}, errors.New("position must be between 1 and 24")
+	}
+	input.Country = strings.ToUpper(strings.TrimSpace(input.Country))
+	if input.Country != "" && !isCountryCode(input.Country) {
+		return Input{}, errors.New("country code must be ISO 3166-1 alpha-2")
+	}
+	input.Locale = strings.ToLower(strings.TrimSpace(input.Locale))
+	if input.Locale != "" && !i18n.Supported(input.Locale) {
+		return Input{}, ErrUnsupportedLocale
+	}
+	if input.StartsAt.IsZero() {
+		input.StartsAt = now
+	}
+	input.StartsAt = input.StartsAt.UTC()
+	if input.EndsAt != nil {
+		ends := input.EndsAt.UTC()
+		if !ends.After(input.StartsAt) {
+			return Input{}, errors.New("ends_at must be after starts_at")
+		}
+		input.EndsAt = &ends
+	}
+	input.Note = strings.TrimSpace(input.Note)
+	input.CreatedBy = strings.TrimSpace(input.CreatedBy)
+	return input, nil
+}
+
+func isCountryCode(value string) bool {
+	if len(value) != 2 {
+		return false
+	}
+	for _, r := range value {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func applyInput(p *Placement, input Input, now time.Time) {
+	p.ListingID = input.ListingID
+	p.Position = input.Position
+	p.Country = input.Country
+	p.Locale = input.Locale
+	p.StartsAt = input.StartsAt
+	p.EndsAt = input.EndsAt
+	p.Note = input.Note
+	p.CreatedBy = input.CreatedBy
+	p.UpdatedAt = now
+}
+
+// sortPlacements orders placements by slot, then by how narrowly they target the
+// audience, then by schedule.
+func sortPlacements(placements []Placement) {
+	sort.SliceStable(placements, func(i, j int) bool {
+		a, b := placements[i], placements[j]
+		if a.Position != b.Position {
+			return a.Position < b.Position
+		}
+		if a.specificity() != b.specificity() {
+			return a.specificity() > b.specificity()
+		}
+		return a.StartsAt.Before(b.StartsAt)
+	})
+}
+
+// featured fills the slots from the placements active for the audience and tops up
+// empty slots with the fallback ranking. Both implementations share it so the
+// homepage looks the same whichever store backs the placements.
+func featured(ctx context.Context, listings listingservice.Service, placements []Placement, query FeaturedQuery) ([]listingservice.Listing, error) {
+	active := make([]Placement, 0, len(placements))
+	for _, p := range placements {
+		if p.ActiveAt(query.At) && p.Targets(query.Country, query.Locale) {
+			active = append(active, p)
+		}
+	}
+	sortPlacements(active)
+
+	candidates := make(map[uuid.UUID]listingservice.Listing, len(active))
+	if len(active) > 0 {
+		ids := make([]uuid.UUID, 0, len(active))
+		for _, p := range active {
+			ids = append(ids, p.ListingID)
+		}
+		placed, _, err := listings.List(ctx, listingservice.ListFilter{IDs: ids, Viewer: query.Viewer, Limit: len(ids)})
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range placed {
+			candidates[l.ID] = l
+		}
+	}
+
+	size := query.Limit + len(candidates)
+	fallback := make([]listingservice.Listing, 0, 2*size)
+	if query.Country != "" {
+		local, _, err := listings.List(ctx, listingservice.ListFilter{
+			Country: query.Country,
+			Viewer:  query.Viewer,
+			Sort:    listingservice.SortNewest,
+			Limit:   size,
+		})
+		if err != nil {
+			return nil, err
+		}
+		fallback = append(fallback, local...)
+	}
+	newest, _, err := listings.List(ctx, listingservice.ListFilter{Viewer: query.Viewer, Sort: listingservice.SortNewest, Limit: size})
+	if err != nil {
+		return nil, err
+	}
+	fallback = append(fallback, newest...)
+	rankFallback(fallback, query.Country)
+	return arrangeFeatured(query.Limit, active, candidates, fallback), nil
+}
+
+// rankFallback orders the listings used for slots no placement claims: listings in
+// the audience's country first, then the newest.
+func rankFallback(listings []listingservice.Listing, country string) {
+	sort.SliceStable(listings, func(i, j int) bool {
+		a, b := listings[i], listings[j]
+		if country != "" && (a.Country == country) != (b.Country == country) {
+			return a.Country == country
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.Title < b.Title
+	})
+}
+
+// arrangeFeatured fills limit slots. Placements take their requested position (or
+// the next free one when a more specific placement already holds it) and the
+// remaining slots are filled from the ranked fallback. Placements whose listing is
+// not in candidates, typically because the viewer cannot see it, are skipped.
+func arrangeFeatured(limit int, placements []Placement, candidates map[uuid.UUID]listingservice.Listing, fallback []listingservice.Listing) []listingservice.Listing {
+	slots := make([]*listingservice.Listing, limit)
+	used := make(map[uuid.UUID]struct{}, limit)
+	for _, p := range placements {
+		listing, ok := candidates[p.ListingID]
+		if !ok {
+			continue
+		}
+		if _, taken := used[listing.ID]; taken {
+			continue
+		}
+		for slot := p.Position - 1; slot < limit; slot++ {
+			if slots[slot] == nil {
+				l := listing
+				slots[slot] = &l
+				used[listing.ID] = struct{}{}
+				break
+			}
+		}
+	}
+
+	next := 0
+	for slot := range slots {
+		if slots[slot] != nil {
+			continue
+		}
+		for next < len(fallback) {
+			candidate := fallback[next]
+			next++
+			if _, taken := used[candidate.ID]; taken {
+				continue
+			}
+			slots[slot] = &candidate
+			used[candidate.ID] = struct{}{}
+			break
+		}
+	}
+
+	out := make([]listingservice.Listing, 0, limit)
+	for _, l := range slots {
+		if l != nil {
+			out = append(out, *l)
+		}
+	}
+	return out
+}
+
+// InMemoryService keeps placements in process memory.
+type InMemoryService struct {
+	mu         sync.RWMutex
+	placements []Placement
+	listings   listingservice.Service
+}
+
+// NewInMemoryService creates an empty placement store over the listing service.
+func NewInMemoryService(listings listingservice.Service) *InMemoryService {
+	return &InMemoryService{listings: listings}
+}
+
+// List returns the placements passing the filter ordered by slot. Placements of
+// deleted listings are dropped, as the foreign key does in PostgreSQL.
+func (s *InMemoryService) List(ctx context.Context, filter Filter) ([]Placement, error) {
+	if err := s.prune(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Placement, 0, len(s.placements))
+	for _, p := range s.placements {
+		if filter.Matches(p) {
+			out = append(out, p)
+		}
+	}
+	sortPlacements(out)
+	return out, nil
+}
+
+// Create schedules a listing into a featured slot.
+func (s *InMemoryService) Create(ctx context.Context, input Input) (Placement, error) {
+	now := time.Now().UTC()
+	input, err := normalizeInput(input, now)
+	if err != nil {
+		return Placement{}, err
+	}
+	if _, err := s.listings.Get(ctx, input.ListingID); err != nil {
+		return Placement{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	placement := Placement{ID: uuid.New(), CreatedAt: now}
+	applyInput(&placement, input, now)
+	s.placements = append(s.placements, placement)
+	return placement, nil
+}
+
+// Update replaces the slot, audience and schedule of a placement.
+func (s *InMemoryService) Update(ctx context.Context, id uuid.UUID, input Input) (Placement, error) {
+	now := time.Now().UTC()
+	input, err := normalizeInput(input, now)
+	if err != nil {
+		return Placement{}, err
+	}
+	if _, err := s.listings.Get(ctx, input.ListingID); err != nil {
+		return Placement{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for idx := range s.placements {
+		if s.placements[idx].ID != id {
+			continue
+		}
+		input.CreatedBy = s.placements[idx].CreatedBy
+		applyInput(&s.placements[idx], input, now)
+		return s.placements[idx], nil
+	}
+	return Placement{}, ErrNotFound
+}
+
+// Delete removes a placement.
+func (s *InMemoryService) Delete(_ context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for idx, p := range s.placements {
+		if p.ID == id {
+			s.placements = append(s.placements[:idx], s.placements[idx+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// Featured fills the featured slots for the audience.
+func (s *InMemoryService) Featured(ctx context.Context, query FeaturedQuery) ([]listingservice.Listing, error) {
+	s.mu.RLock()
+	placements := append([]Placement(nil), s.placements...)
+	s.mu.RUnlock()
+
+	return featured(ctx, s.listings, placements, query.normalize())
+}
+
+// prune drops placements whose listing no longer exists.
+func (s *InMemoryService) prune(ctx context.Context) error {
+	s.mu.RLock()
+	ids := make([]uuid.UUID, 0, len(s.placements))
+	for _, p := range s.placements {
+		ids = append(ids, p.ListingID)
+	}
+	s.mu.RUnlock()
+
+	gone := make(map[uuid.UUID]struct{})
+	for _, id := range ids {
+		if _, err := s.listings.Get(ctx, id); errors.Is(err, listingservice.ErrNotFound) {
+			gone[id] = struct{}{}
+		} else if err != nil {
+			return err
+		}
+	}
+	if len(gone) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	kept := s.placements[:0]
+	for _, p := range s.placements {
+		if _, ok := gone[p.ListingID]; !ok {
+			kept = append(kept, p)
+		}
+	}
+	s.placements = kept
+	return nil
+}
+
+var _ Service = (*InMemoryService)(nil)
@@ -0,0 +1,86 @@
+package placement
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+func TestInMemoryServiceFeatured(t *testing.T) {
+	listings := listingservice.NewInMemoryService()
+	service := NewInMemoryService(listings)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	fallback, err := service.Featured(ctx, FeaturedQuery{Limit: 3})
+	if err != nil {
+		t.Fatalf("Featured() error = %v", err)
+	}
+	if len(fallback) != 3 {
+		t.Fatalf("Featured() returned %d listings, want 3", len(fallback))
+	}
+
+	kyoto, err := listings.GetBySlug(ctx, "kyoto-machiya-boutique-hotel")
+	if err != nil {
+		t.Fatalf("GetBySlug() error = %v", err)
+	}
+	stockholm, err := listings.GetBySlug(ctx, "ostermalm-art-nouveau")
+	if err != nil {
+		t.Fatalf("GetBySlug() error = %v", err)
+	}
+	if _, err := service.Create(ctx, Input{ListingID: kyoto.ID, Position: 2, StartsAt: now.Add(-time.Hour)}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := service.Create(ctx, Input{ListingID: stockholm.ID, Position: 1, Country: "se", Locale: "sv"}); err != nil {
+		t.Fatalf("Create(targeted) error = %v", err)
+	}
+	expired := now.Add(-time.Minute)
+	if _, err := service.Create(ctx, Input{ListingID: stockholm.ID, Position: 3, StartsAt: now.Add(-2 * time.Hour), EndsAt: &expired}); err != nil {
+		t.Fatalf("Create(expired) error = %v", err)
+	}
+
+	global, err := service.Featured(ctx, FeaturedQuery{Limit: 3, Locale: "en"})
+	if err != nil {
+		t.Fatalf("Featured(global) error = %v", err)
+	}
+	if global[0].ID != fallback[0].ID || global[1].ID != kyoto.ID {
+		t.Errorf("global slots = %q, %q; want fallback then the placed listing", global[0].Slug, global[1].Slug)
+	}
+
+	swedish, err := service.Featured(ctx, FeaturedQuery{Limit: 3, Country: "SE", Locale: "sv-SE"})
+	if err != nil {
+		t.Fatalf("Featured(swedish) error = %v", err)
+	}
+	if swedish[0].ID != stockholm.ID || swedish[1].ID != kyoto.ID {
+		t.Errorf("swedish slots = %q, %q", swedish[0].Slug, swedish[1].Slug)
+	}
+
+	if _, err := service.Create(ctx, Input{ListingID: kyoto.ID, Position: 0}); err == nil {
+		t.Error("Create(position 0) succeeded, want validation error")
+	}
+	if _, err := service.Create(ctx, Input{ListingID: uuid.New(), Position: 1}); !errors.Is(err, listingservice.ErrNotFound) {
+		t.Errorf("Create(unknown listing) error = %v, want ErrNotFound", err)
+	}
+	if _, err := service.Create(ctx, Input{ListingID: kyoto.ID, Position: 1, StartsAt: now, EndsAt: &expired}); err == nil {
+		t.Error("Create(ends before start) succeeded, want validation error")
+	}
+
+	active, err := service.List(ctx, Filter{ActiveAt: time.Now().UTC()})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(active) != 2 {
+		t.Errorf("active placements = %d, want 2", len(active))
+	}
+	if err := listings.Delete(ctx, kyoto.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if remaining, _ := service.List(ctx, Filter{}); len(remaining) != 2 {
+		t.Errorf("placements after listing delete = %d, want 2", len(remaining))
+	}
+}
@@ -0,0 +1,153 @@
+package placement
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+const placementColumns = `id, listing_id, position, country, locale, starts_at, ends_at, note, created_by, created_at, updated_at`
+
+type sqlService struct {
+	db       *sql.DB
+	listings listingservice.Service
+}
+
+// NewSQLService builds a placement service backed by PostgreSQL.
+func NewSQLService(db *sql.DB, listings listingservice.Service) (Service, error) {
+	return &sqlService{db: db, listings: listings}, nil
+}
+
+func (s *sqlService) List(ctx context.Context, filter Filter) ([]Placement, error) {
+	var (
+		clauses []string
+		args    []any
+	)
+	if !filter.ActiveAt.IsZero() {
+		args = append(args, filter.ActiveAt)
+		clauses = append(clauses, fmt.Sprintf("starts_at <= $%[1]d AND (ends_at IS NULL OR ends_at > $%[1]d)", len(args)))
+	}
+	if filter.Country != "" {
+		args = append(args, filter.Country)
+		clauses = append(clauses, fmt.Sprintf("(country = '' OR country = $%d)", len(args)))
+	}
+	if filter.Locale != "" {
+		args = append(args, filter.Locale)
+		clauses = append(clauses, fmt.Sprintf("(locale = '' OR locale = $%d)", len(args)))
+	}
+	where := ""
+	if len(clauses) > 0 {
+		where = "WHERE " + strings.Join(clauses, " AND ")
+	}
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT `+placementColumns+`
+        FROM featured_placements
+        `+where+`
+        ORDER BY position, starts_at, id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	placements := make([]Placement, 0)
+	for rows.Next() {
+		p, err := scanPlacement(rows)
+		if err != nil {
+			return nil, err
+		}
+		placements = append(placements, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sortPlacements(placements)
+	return placements, nil
+}
+
+func (s *sqlService) Create(ctx context.Context, input Input) (Placement, error) {
+	input, err := normalizeInput(input, time.Now().UTC())
+	if err != nil {
+		return Placement{}, err
+	}
+	if _, err := s.listings.Get(ctx, input.ListingID); err != nil {
+		return Placement{}, err
+	}
+	row := s.db.QueryRowContext(ctx, `
+        INSERT INTO featured_placements (listing_id, position, country, locale, starts_at, ends_at, note, created_by)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING `+placementColumns,
+		input.ListingID, input.Position, input.Country, input.Locale, input.StartsAt, nullableTime(input.EndsAt), input.Note, input.CreatedBy)
+	return scanPlacement(row)
+}
+
+func (s *sqlService) Update(ctx context.Context, id uuid.UUID, input Input) (Placement, error) {
+	input, err := normalizeInput(input, time.Now().UTC())
+	if err != nil {
+		return Placement{}, err
+	}
+	if _, err := s.listings.Get(ctx, input.ListingID); err != nil {
+		return Placement{}, err
+	}
+	row := s.db.QueryRowContext(ctx, `
+        UPDATE featured_placements
+        SET listing_id = $2, position = $3, country = $4, locale = $5, starts_at = $6, ends_at = $7, note = $8, updated_at = NOW()
+        WHERE id = $1
+        RETURNING `+placementColumns,
+		id, input.ListingID, input.Position, input.Country, input.Locale, input.StartsAt, nullableTime(input.EndsAt), input.Note)
+	placement, err := scanPlacement(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Placement{}, ErrNotFound
+	}
+	return placement, err
+}
+
+func (s *sqlService) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM featured_placements WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *sqlService) Featured(ctx context.Context, query FeaturedQuery) ([]listingservice.Listing, error) {
+	query = query.normalize()
+	placements, err := s.List(ctx, Filter{ActiveAt: query.At, Country: query.Country, Locale: query.Locale})
+	if err != nil {
+		return nil, err
+	}
+	return featured(ctx, s.listings, placements, query)
+}
+
+func scanPlacement(row interface {
+	Scan(dest ...any) error
+}) (Placement, error) {
+	var (
+		p      Placement
+		endsAt sql.NullTime
+	)
+	if err := row.Scan(&p.ID, &p.ListingID, &p.Position, &p.Country, &p.Locale, &p.StartsAt, &endsAt, &p.Note, &p.CreatedBy, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return Placement{}, err
+	}
+	if endsAt.Valid {
+		ends := endsAt.Time
+		p.EndsAt = &ends
+	}
+	return p, nil
+}
+
+func nullableTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
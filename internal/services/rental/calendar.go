@@ -0,0 +1,63 @@
+package rental
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DayState describes one day of the availability calendar.
+type DayState string
+
+const (
+	DayAvailable DayState = "available"
+	DayBlocked   DayState = "blocked"
+	DayBooked    DayState = "booked"
+)
+
+// CalendarDay is one night of the availability calendar.
+type CalendarDay struct {
+	Date  string   `json:"date"`
+	State DayState `json:"state"`
+}
+
+// CalendarRange validates an availability window. A zero from starts today; a zero
+// to covers the following 90 days.
+func CalendarRange(from, to, now time.Time) (time.Time, time.Time, error) {
+	if from.IsZero() {
+		from = now
+	}
+	from = Day(from)
+	if to.IsZero() {
+		to = from.AddDate(0, 0, 90)
+	}
+	to = Day(to)
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, errors.New("to must be after from")
+	}
+	if nights(from, to) > MaxCalendarDays {
+		return time.Time{}, time.Time{}, errors.New("calendar range cannot exceed 366 days")
+	}
+	return from, to, nil
+}
+
+// Calendar lays out the nights in [from, to). Blocks win over bookings, and only
+// accepted bookings occupy a night.
+func Calendar(from, to time.Time, blocks []Block, bookings []Booking) []CalendarDay {
+	from, to = Day(from), Day(to)
+	days := make([]CalendarDay, 0, nights(from, to))
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+		state := DayAvailable
+		blocked, booked := Conflicts(day, next, blocks, bookings, uuid.Nil)
+		switch {
+		case len(blocked) > 0:
+			state = DayBlocked
+		case len(booked) > 0:
+			state = DayBooked
+		}
+		days = append(days, CalendarDay{Date: day.Format(DateLayout), State: state})
+	}
+	return days
+}
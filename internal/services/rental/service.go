@@ -0,0 +1,498 @@
+// Package rental manages the availability calendar and booking requests of rental
+// listings.
+package rental
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DateLayout is the wire format of calendar days.
+const DateLayout = "2006-01-02"
+
+// MaxCalendarDays bounds the range of a single availability request.
+const MaxCalendarDays = 366
+
+// Block reserves a date range the landlord does not rent out, e.g. for maintenance
+// or bookings taken elsewhere. Ranges are half-open: End is the first free day.
+type Block struct {
+	ID        uuid.UUID `json:"id"`
+	ListingID uuid.UUID `json:"listing_id"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BlockInput describes a new block.
+type BlockInput struct {
+	ListingID uuid.UUID
+	Start     time.Time
+	End       time.Time
+	Reason    string
+	CreatedBy string
+}
+
+// BookingStatus tracks a booking request through the landlord's decision.
+type BookingStatus string
+
+const (
+	BookingPending   BookingStatus = "pending"
+	BookingAccepted  BookingStatus = "accepted"
+	BookingDeclined  BookingStatus = "declined"
+	BookingCancelled BookingStatus = "cancelled"
+)
+
+// Valid reports whether the status is supported.
+func (s BookingStatus) Valid() bool {
+	switch s {
+	case BookingPending, BookingAccepted, BookingDeclined, BookingCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// Booking is a request to rent a listing between CheckIn and CheckOut. The
+// check-out day is free for the next guest to check in.
+type Booking struct {
+	ID             uuid.UUID     `json:"id"`
+	ListingID      uuid.UUID     `json:"listing_id"`
+	RequesterID    string        `json:"-"`
+	RequesterEmail string        `json:"requester_email"`
+	RequesterName  string        `json:"requester_name,omitempty"`
+	CheckIn        time.Time     `json:"check_in"`
+	CheckOut       time.Time     `json:"check_out"`
+	Guests         int           `json:"guests"`
+	Message        string        `json:"message,omitempty"`
+	Status         BookingStatus `json:"status"`
+	DecidedBy      string        `json:"decided_by,omitempty"`
+	DecidedAt      *time.Time    `json:"decided_at,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+// Nights is the length of the stay.
+func (b Booking) Nights() int {
+	return nights(b.CheckIn, b.CheckOut)
+}
+
+// BookingInput describes a booking request. MinNights comes from the listing's
+// rental terms.
+type BookingInput struct {
+	ListingID      uuid.UUID
+	RequesterID    string
+	RequesterEmail string
+	RequesterName  string
+	CheckIn        time.Time
+	CheckOut       time.Time
+	Guests         int
+	Message        string
+	MinNights      int
+}
+
+// BookingFilter narrows the bookings returned. From and To keep bookings overlapping
+// the range; zero values leave that side open.
+type BookingFilter struct {
+	ListingID   uuid.UUID
+	RequesterID string
+	Status      BookingStatus
+	From        time.Time
+	To          time.Time
+}
+
+// Decision is the landlord's answer to a pending booking request.
+type Decision struct {
+	Status     BookingStatus
+	ActorEmail string
+}
+
+// Service manages rental calendars and booking requests.
+type Service interface {
+	Blocks(ctx context.Context, listingID uuid.UUID, from, to time.Time) ([]Block, error)
+	AddBlock(ctx context.Context, input BlockInput) (Block, error)
+	RemoveBlock(ctx context.Context, listingID, id uuid.UUID) error
+	Bookings(ctx context.Context, filter BookingFilter) ([]Booking, error)
+	GetBooking(ctx context.Context, id uuid.UUID) (Booking, error)
+	RequestBooking(ctx context.Context, input BookingInput) (Booking, error)
+	DecideBooking(ctx context.Context, id uuid.UUID, decision Decision) (Booking, error)
+	CancelBooking(ctx context.Context, id uuid.UUID, requesterID string) (Booking, error)
+}
+
+var (
+	// ErrNotFound is returned when a block or booking cannot be located.
+	ErrNotFound = errors.New("not found")
+	// ErrUnavailable is returned when the dates overlap a block or an accepted booking.
+	ErrUnavailable = errors.New("the listing is not available for those dates")
+	// ErrNotPending is returned when deciding a booking that was already decided.
+	ErrNotPending = errors.New("booking is no longer pending")
+)
+
+// ParseDate reads a YYYY-MM-DD calendar day.
+func ParseDate(value string) (time.Time, error) {
+	return time.Parse(DateLayout, strings.TrimSpace(value))
+}
+
+// Day truncates an instant to its UTC calendar day.
+func Day(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func nights(from, to time.Time) int {
+	return int(Day(to).Sub(Day(from)).Hours() / 24)
+}
+
+// overlaps reports whether the half-open ranges [aStart, aEnd) and [bStart, bEnd)
+// share a day.
+func overlaps(aStart, aEnd, bStart, bEnd time.Time) bool {
+	return aStart.Before(bEnd) && bStart.Before(aEnd)
+}
+
+// Conflicts returns the blocks and accepted bookings overlapping the range, ignoring
+// the booking named by skip.
+func Conflicts(start, end time.Time, blocks []Block, bookings []Booking, skip uuid.UUID) ([]Block, []Booking) {
+	var (
+		blocked []Block
+		booked  []Booking
+	)
+	for _, b := range blocks {
+		if overlaps(start, end, b.Start, b.End) {
+			blocked = append(blocked, b)
+		}
+	}
+	for _, b := range bookings {
+		if b.ID != skip && b.Status == BookingAccepted && overlaps(start, end, b.CheckIn, b.CheckOut) {
+			booked = append(booked, b)
+		}
+	}
+	return blocked, booked
+}
+
+func normalizeRange(start, end time.Time) (time.Time, time.Time, error) {
+	if start.IsZero() || end.IsZero() {
+		return time.Time{}, time.Time{}, errors.New("start and end dates are required")
+	}
+	start, end = Day(start), Day(end)
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, errors.New("end date must be after the start date")
+	}
+	return start, end, nil
+}
+
+func normalizeBlockInput(input BlockInput) (BlockInput, error) {
+	if input.ListingID == uuid.Nil {
+		return BlockInput{}, errors.New("listing is required")
+	}
+	start, end, err := normalizeRange(input.Start, input.End)
+	if err != nil {
+		return BlockInput{}, err
+	}
+	input.Start, input.End = start, end
+	input.Reason = strings.TrimSpace(input.Reason)
+	input.CreatedBy = strings.TrimSpace(input.CreatedBy)
+	return input, nil
+}
+
+func normalizeBookingInput(input BookingInput, now time.Time) (BookingInput, error) {
+	if input.ListingID == uuid.Nil {
+		return BookingInput{}, errors.New("listing is required")
+	}
+	if strings.TrimSpace(input.RequesterID) == "" || strings.TrimSpace(input.RequesterEmail) == "" {
+		return BookingInput{}, errors.New("requester is required")
+	}
+	checkIn, checkOut, err := normalizeRange(input.CheckIn, input.CheckOut)
+	if err != nil {
+		return BookingInput{}, errors.New("check_out must be after check_in")
+	}
+	if checkIn.Before(Day(now)) {
+		return BookingInput{}, errors.New("check_in cannot be in the past")
+	}
+	if input.MinNights > 0 && nights(checkIn, checkOut) < input.MinNights {
+		return BookingInput{}, errors.New("stay is shorter than the minimum stay")
+	}
+	if input.Guests == 0 {
+		input.Guests = 1
+	}
+	if input.Guests < 0 {
+		return BookingInput{}, errors.New("guests cannot be negative")
+	}
+	input.CheckIn, input.CheckOut = checkIn, checkOut
+	input.RequesterEmail = strings.TrimSpace(input.RequesterEmail)
+	input.RequesterName = strings.TrimSpace(input.RequesterName)
+	input.Message = strings.TrimSpace(input.Message)
+	return input, nil
+}
+
+func normalizeDecision(decision Decision) (Decision, error) {
+	if decision.Status != BookingAccepted && decision.Status != BookingDeclined {
+		return Decision{}, errors.New("decision must be accepted or declined")
+	}
+	decision.ActorEmail = strings.TrimSpace(decision.ActorEmail)
+	if decision.ActorEmail == "" {
+		return Decision{}, errors.New("actor email is required")
+	}
+	return decision, nil
+}
+
+// Matches reports whether the booking passes the filter.
+func (f BookingFilter) Matches(b Booking) bool {
+	if f.ListingID != uuid.Nil && b.ListingID != f.ListingID {
+		return false
+	}
+	if f.RequesterID != "" && b.RequesterID != f.RequesterID {
+		return false
+	}
+	if f.Status != "" && b.Status != f.Status {
+		return false
+	}
+	if !f.From.IsZero() && !b.CheckOut.After(Day(f.From)) {
+		return false
+	}
+	return f.To.IsZero() || b.CheckIn.Before(Day(f.To))
+}
+
+func sortBookings(bookings []Booking) {
+	sort.SliceStable(bookings, func(i, j int) bool {
+		if !bookings[i].CheckIn.Equal(bookings[j].CheckIn) {
+			return bookings[i].CheckIn.Before(bookings[j].CheckIn)
+		}
+		return bookings[i].CreatedAt.Before(bookings[j].CreatedAt)
+	})
+}
+
+// InMemoryService keeps calendars and bookings in process memory.
+type InMemoryService struct {
+	mu       sync.RWMutex
+	blocks   []Block
+	bookings []Booking
+}
+
+// NewInMemoryService creates an empty rental store.
+func NewInMemoryService() *InMemoryService {
+	return &InMemoryService{}
+}
+
+// Blocks returns the listing's blocks overlapping the range, earliest first.
+func (s *InMemoryService) Blocks(_ context.Context, listingID uuid.UUID, from, to time.Time) ([]Block, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Block, 0)
+	for _, b := range s.blocks {
+		if b.ListingID != listingID {
+			continue
+		}
+		if !from.IsZero() && !b.End.After(Day(from)) {
+			continue
+		}
+		if !to.IsZero() && !b.Start.Before(Day(to)) {
+			continue
+		}
+		out = append(out, b)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	return out, nil
+}
+
+// AddBlock closes a date range. Ranges overlapping an accepted booking are refused;
+// decline or cancel the booking first.
+func (s *InMemoryService) AddBlock(_ context.Context, input BlockInput) (Block, error) {
+	input, err := normalizeBlockInput(input)
+	if err != nil {
+		return Block{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, booked := Conflicts(input.Start, input.End, nil, s.listingBookings(input.ListingID), uuid.Nil); len(booked) > 0 {
+		return Block{}, ErrUnavailable
+	}
+	block := Block{
+		ID:        uuid.New(),
+		ListingID: input.ListingID,
+		Start:     input.Start,
+		End:       input.End,
+		Reason:    input.Reason,
+		CreatedBy: input.CreatedBy,
+		CreatedAt: time.Now().UTC(),
+	}
+	s.blocks = append(s.blocks, block)
+	return block, nil
+}
+
+// RemoveBlock reopens a blocked range.
+func (s *InMemoryService) RemoveBlock(_ context.Context, listingID, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for idx, b := range s.blocks {
+		if b.ID == id && b.ListingID == listingID {
+			s.blocks = append(s.blocks[:idx], s.blocks[idx+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// Bookings returns the bookings passing the filter ordered by check-in.
+func (s *InMemoryService) Bookings(_ context.Context, filter BookingFilter) ([]Booking, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Booking, 0)
+	for _, b := range s.bookings {
+		if filter.Matches(b) {
+			out = append(out, b)
+		}
+	}
+	sortBookings(out)
+	return out, nil
+}
+
+func (s *InMemoryService) GetBooking(_ context.Context, id uuid.UUID) (Booking, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if idx := s.bookingIndex(id); idx >= 0 {
+		return s.bookings[idx], nil
+	}
+	return Booking{}, ErrNotFound
+}
+
+// RequestBooking records a pending request. Requests overlapping a block or an
+// accepted booking are refused; overlapping pending requests are allowed and left
+// for the landlord to choose between.
+func (s *InMemoryService) RequestBooking(_ context.Context, input BookingInput) (Booking, error) {
+	now := time.Now().UTC()
+	input, err := normalizeBookingInput(input, now)
+	if err != nil {
+		return Booking{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	blocked, booked := Conflicts(input.CheckIn, input.CheckOut, s.listingBlocks(input.ListingID), s.listingBookings(input.ListingID), uuid.Nil)
+	if len(blocked) > 0 || len(booked) > 0 {
+		return Booking{}, ErrUnavailable
+	}
+	booking := Booking{
+		ID:             uuid.New(),
+		ListingID:      input.ListingID,
+		RequesterID:    input.RequesterID,
+		RequesterEmail: input.RequesterEmail,
+		RequesterName:  input.RequesterName,
+		CheckIn:        input.CheckIn,
+		CheckOut:       input.CheckOut,
+		Guests:         input.Guests,
+		Message:        input.Message,
+		Status:         BookingPending,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	s.bookings = append(s.bookings, booking)
+	return booking, nil
+}
+
+// DecideBooking accepts or declines a pending request. Accepting re-checks the dates
+// and declines the other pending requests that overlap them.
+func (s *InMemoryService) DecideBooking(_ context.Context, id uuid.UUID, decision Decision) (Booking, error) {
+	decision, err := normalizeDecision(decision)
+	if err != nil {
+		return Booking{}, err
+	}
+	now := time.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := s.bookingIndex(id)
+	if idx < 0 {
+		return Booking{}, ErrNotFound
+	}
+	booking := s.bookings[idx]
+	if booking.Status != BookingPending {
+		return Booking{}, ErrNotPending
+	}
+	if decision.Status == BookingAccepted {
+		blocked, booked := Conflicts(booking.CheckIn, booking.CheckOut, s.listingBlocks(booking.ListingID), s.listingBookings(booking.ListingID), booking.ID)
+		if len(blocked) > 0 || len(booked) > 0 {
+			return Booking{}, ErrUnavailable
+		}
+		for i := range s.bookings {
+			other := &s.bookings[i]
+			if other.ID != booking.ID && other.ListingID == booking.ListingID && other.Status == BookingPending &&
+				overlaps(booking.CheckIn, booking.CheckOut, other.CheckIn, other.CheckOut) {
+				decide(other, BookingDeclined, decision.ActorEmail, now)
+			}
+		}
+	}
+	decide(&s.bookings[idx], decision.Status, decision.ActorEmail, now)
+	return s.bookings[idx], nil
+}
+
+// CancelBooking lets the requester withdraw a pending or accepted booking.
+func (s *InMemoryService) CancelBooking(_ context.Context, id uuid.UUID, requesterID string) (Booking, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := s.bookingIndex(id)
+	if idx < 0 || s.bookings[idx].RequesterID != requesterID {
+		return Booking{}, ErrNotFound
+	}
+	booking := &s.bookings[idx]
+	if booking.Status != BookingPending && booking.Status != BookingAccepted {
+		return Booking{}, ErrNotPending
+	}
+	now := time.Now().UTC()
+	booking.Status = BookingCancelled
+	booking.UpdatedAt = now
+	return *booking, nil
+}
+
+func decide(b *Booking, status BookingStatus, actor string, now time.Time) {
+	b.Status = status
+	b.DecidedBy = actor
+	b.DecidedAt = &now
+	b.UpdatedAt = now
+}
+
+func (s *InMemoryService) bookingIndex(id uuid.UUID) int {
+	for idx, b := range s.bookings {
+		if b.ID == id {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (s *InMemoryService) listingBlocks(listingID uuid.UUID) []Block {
+	out := make([]Block, 0)
+	for _, b := range s.blocks {
+		if b.ListingID == listingID {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+func (s *InMemoryService) listingBookings(listingID uuid.UUID) []Booking {
+	out := make([]Booking, 0)
+	for _, b := range s.bookings {
+		if b.ListingID == listingID {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+var _ Service = (*InMemoryService)(nil)
@@ -0,0 +1,118 @@
+package rental
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestInMemoryServiceBookingConflicts(t *testing.T) {
+	ctx := context.Background()
+	service := NewInMemoryService()
+	listingID := uuid.New()
+	start := Day(time.Now().UTC()).AddDate(0, 0, 10)
+	at := func(days int) time.Time { return start.AddDate(0, 0, days) }
+	request := func(requester string, checkIn, checkOut int) (Booking, error) {
+		return service.RequestBooking(ctx, BookingInput{
+			ListingID:      listingID,
+			RequesterID:    requester,
+			RequesterEmail: requester + "@example.com",
+			CheckIn:        at(checkIn),
+			CheckOut:       at(checkOut),
+			MinNights:      2,
+		})
+	}
+
+	if _, err := request("ana", 0, 1); err == nil {
+		t.Fatal("RequestBooking() below minimum stay error = nil, want error")
+	}
+	if _, err := service.AddBlock(ctx, BlockInput{ListingID: listingID, Start: at(20), End: at(25)}); err != nil {
+		t.Fatalf("AddBlock() error = %v", err)
+	}
+	if _, err := request("ana", 22, 27); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("RequestBooking() over block error = %v, want ErrUnavailable", err)
+	}
+
+	first, err := request("ana", 0, 4)
+	if err != nil {
+		t.Fatalf("RequestBooking() error = %v", err)
+	}
+	rival, err := request("rui", 2, 6)
+	if err != nil {
+		t.Fatalf("RequestBooking() overlapping pending error = %v, want nil", err)
+	}
+	adjacent, err := request("eva", 4, 6)
+	if err != nil {
+		t.Fatalf("RequestBooking() adjacent error = %v", err)
+	}
+
+	accepted, err := service.DecideBooking(ctx, first.ID, Decision{Status: BookingAccepted, ActorEmail: "agent@example.com"})
+	if err != nil {
+		t.Fatalf("DecideBooking() error = %v", err)
+	}
+	if accepted.Status != BookingAccepted || accepted.DecidedBy != "agent@example.com" {
+		t.Fatalf("DecideBooking() = %+v, want accepted by agent", accepted)
+	}
+	if got, _ := service.GetBooking(ctx, rival.ID); got.Status != BookingDeclined {
+		t.Errorf("overlapping request status = %q, want declined", got.Status)
+	}
+	if got, _ := service.GetBooking(ctx, adjacent.ID); got.Status != BookingPending {
+		t.Errorf("adjacent request status = %q, want pending", got.Status)
+	}
+	if _, err := service.DecideBooking(ctx, rival.ID, Decision{Status: BookingAccepted, ActorEmail: "agent@example.com"}); !errors.Is(err, ErrNotPending) {
+		t.Errorf("DecideBooking() on declined error = %v, want ErrNotPending", err)
+	}
+	if _, err := request("rui", 3, 6); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("RequestBooking() over accepted error = %v, want ErrUnavailable", err)
+	}
+	if _, err := service.AddBlock(ctx, BlockInput{ListingID: listingID, Start: at(1), End: at(2)}); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("AddBlock() over accepted error = %v, want ErrUnavailable", err)
+	}
+
+	if _, err := service.CancelBooking(ctx, first.ID, "rui"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("CancelBooking() by stranger error = %v, want ErrNotFound", err)
+	}
+	if _, err := service.CancelBooking(ctx, first.ID, "ana"); err != nil {
+		t.Fatalf("CancelBooking() error = %v", err)
+	}
+	if _, err := request("rui", 3, 6); err != nil {
+		t.Errorf("RequestBooking() after cancel error = %v", err)
+	}
+
+	mine, err := service.Bookings(ctx, BookingFilter{RequesterID: "rui"})
+	if err != nil {
+		t.Fatalf("Bookings() error = %v", err)
+	}
+	if len(mine) != 2 {
+		t.Errorf("Bookings(rui) = %d, want 2", len(mine))
+	}
+}
+
+func TestCalendar(t *testing.T) {
+	from := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	blocks := []Block{{Start: from.AddDate(0, 0, 1), End: from.AddDate(0, 0, 2)}}
+	bookings := []Booking{
+		{ID: uuid.New(), CheckIn: from.AddDate(0, 0, 3), CheckOut: from.AddDate(0, 0, 5), Status: BookingAccepted},
+		{ID: uuid.New(), CheckIn: from, CheckOut: from.AddDate(0, 0, 1), Status: BookingPending},
+	}
+	days := Calendar(from, from.AddDate(0, 0, 6), blocks, bookings)
+	want := []DayState{DayAvailable, DayBlocked, DayAvailable, DayBooked, DayBooked, DayAvailable}
+	if len(days) != len(want) {
+		t.Fatalf("Calendar() = %d days, want %d", len(days), len(want))
+	}
+	for i, day := range days {
+		if day.State != want[i] {
+			t.Errorf("%s state = %q, want %q", day.Date, day.State, want[i])
+		}
+	}
+
+	if _, _, err := CalendarRange(from, from.AddDate(0, 0, 400), from); err == nil {
+		t.Error("CalendarRange() over limit error = nil, want error")
+	}
+	if gotFrom, gotTo, err := CalendarRange(time.Time{}, time.Time{}, from.Add(5*time.Hour)); err != nil || !gotFrom.Equal(from) || !gotTo.Equal(from.AddDate(0, 0, 90)) {
+		t.Errorf("CalendarRange() defaults = %v, %v, %v", gotFrom, gotTo, err)
+	}
+}
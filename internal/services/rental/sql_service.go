@@ -0,0 +1,331 @@
+package rental
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const blockColumns = `id, listing_id, start_date, end_date, reason, created_by, created_at`
+
+const bookingColumns = `id, listing_id, requester_id, requester_email, requester_name, check_in, check_out,
+        guests, message, status, decided_by, decided_at, created_at, updated_at`
+
+type sqlService struct {
+	db *sql.DB
+}
+
+// NewSQLService builds a rental service backed by PostgreSQL.
+func NewSQLService(db *sql.DB) (Service, error) {
+	return &sqlService{db: db}, nil
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func (s *sqlService) Blocks(ctx context.Context, listingID uuid.UUID, from, to time.Time) ([]Block, error) {
+	return queryBlocks(ctx, s.db, listingID, from, to)
+}
+
+func queryBlocks(ctx context.Context, q querier, listingID uuid.UUID, from, to time.Time) ([]Block, error) {
+	clauses := []string{"listing_id = $1"}
+	args := []any{listingID}
+	if !from.IsZero() {
+		args = append(args, Day(from))
+		clauses = append(clauses, fmt.Sprintf("end_date > $%d", len(args)))
+	}
+	if !to.IsZero() {
+		args = append(args, Day(to))
+		clauses = append(clauses, fmt.Sprintf("start_date < $%d", len(args)))
+	}
+	rows, err := q.QueryContext(ctx, `
+        SELECT `+blockColumns+`
+        FROM rental_blocks
+        WHERE `+strings.Join(clauses, " AND ")+`
+        ORDER BY start_date, id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocks := make([]Block, 0)
+	for rows.Next() {
+		var b Block
+		if err := rows.Scan(&b.ID, &b.ListingID, &b.Start, &b.End, &b.Reason, &b.CreatedBy, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		b.Start, b.End = Day(b.Start), Day(b.End)
+		blocks = append(blocks, b)
+	}
+	return blocks, rows.Err()
+}
+
+func (s *sqlService) AddBlock(ctx context.Context, input BlockInput) (Block, error) {
+	input, err := normalizeBlockInput(input)
+	if err != nil {
+		return Block{}, err
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Block{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockListing(ctx, tx, input.ListingID); err != nil {
+		return Block{}, err
+	}
+	booked, err := queryBookings(ctx, tx, BookingFilter{ListingID: input.ListingID, Status: BookingAccepted, From: input.Start, To: input.End})
+	if err != nil {
+		return Block{}, err
+	}
+	if len(booked) > 0 {
+		return Block{}, ErrUnavailable
+	}
+	var b Block
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO rental_blocks (listing_id, start_date, end_date, reason, created_by)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING `+blockColumns,
+		input.ListingID, input.Start, input.End, input.Reason, input.CreatedBy,
+	).Scan(&b.ID, &b.ListingID, &b.Start, &b.End, &b.Reason, &b.CreatedBy, &b.CreatedAt)
+	if err != nil {
+		return Block{}, err
+	}
+	b.Start, b.End = Day(b.Start), Day(b.End)
+	return b, tx.Commit()
+}
+
+func (s *sqlService) RemoveBlock(ctx context.Context, listingID, id uuid.UUID) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM rental_blocks WHERE id = $1 AND listing_id = $2`, id, listingID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *sqlService) Bookings(ctx context.Context, filter BookingFilter) ([]Booking, error) {
+	return queryBookings(ctx, s.db, filter)
+}
+
+func queryBookings(ctx context.Context, q querier, filter BookingFilter) ([]Booking, error) {
+	var (
+		clauses []string
+		args    []any
+	)
+	add := func(clause string, value any) {
+		args = append(args, value)
+		clauses = append(clauses, fmt.Sprintf(clause, len(args)))
+	}
+	if filter.ListingID != uuid.Nil {
+		add("listing_id = $%d", filter.ListingID)
+	}
+	if filter.RequesterID != "" {
+		add("requester_id = $%d", filter.RequesterID)
+	}
+	if filter.Status != "" {
+		add("status = $%d", string(filter.Status))
+	}
+	if !filter.From.IsZero() {
+		add("check_out > $%d", Day(filter.From))
+	}
+	if !filter.To.IsZero() {
+		add("check_in < $%d", Day(filter.To))
+	}
+	where := ""
+	if len(clauses) > 0 {
+		where = "WHERE " + strings.Join(clauses, " AND ")
+	}
+	rows, err := q.QueryContext(ctx, `
+        SELECT `+bookingColumns+`
+        FROM rental_bookings
+        `+where+`
+        ORDER BY check_in, created_at, id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bookings := make([]Booking, 0)
+	for rows.Next() {
+		b, err := scanBooking(rows)
+		if err != nil {
+			return nil, err
+		}
+		bookings = append(bookings, b)
+	}
+	return bookings, rows.Err()
+}
+
+func (s *sqlService) GetBooking(ctx context.Context, id uuid.UUID) (Booking, error) {
+	b, err := scanBooking(s.db.QueryRowContext(ctx, `SELECT `+bookingColumns+` FROM rental_bookings WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Booking{}, ErrNotFound
+	}
+	return b, err
+}
+
+func (s *sqlService) RequestBooking(ctx context.Context, input BookingInput) (Booking, error) {
+	input, err := normalizeBookingInput(input, time.Now().UTC())
+	if err != nil {
+		return Booking{}, err
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Booking{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := lockListing(ctx, tx, input.ListingID); err != nil {
+		return Booking{}, err
+	}
+	if err := checkAvailable(ctx, tx, input.ListingID, input.CheckIn, input.CheckOut, uuid.Nil); err != nil {
+		return Booking{}, err
+	}
+	b, err := scanBooking(tx.QueryRowContext(ctx, `
+        INSERT INTO rental_bookings
+            (listing_id, requester_id, requester_email, requester_name, check_in, check_out, guests, message)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING `+bookingColumns,
+		input.ListingID, input.RequesterID, input.RequesterEmail, input.RequesterName,
+		input.CheckIn, input.CheckOut, input.Guests, input.Message))
+	if err != nil {
+		return Booking{}, err
+	}
+	return b, tx.Commit()
+}
+
+func (s *sqlService) DecideBooking(ctx context.Context, id uuid.UUID, decision Decision) (Booking, error) {
+	decision, err := normalizeDecision(decision)
+	if err != nil {
+		return Booking{}, err
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Booking{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	booking, err := scanBooking(tx.QueryRowContext(ctx, `SELECT `+bookingColumns+` FROM rental_bookings WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Booking{}, ErrNotFound
+	}
+	if err != nil {
+		return Booking{}, err
+	}
+	if err := lockListing(ctx, tx, booking.ListingID); err != nil {
+		return Booking{}, err
+	}
+	// Re-read under the listing lock so two decisions cannot race.
+	if err := tx.QueryRowContext(ctx, `SELECT status FROM rental_bookings WHERE id = $1`, id).Scan(&booking.Status); err != nil {
+		return Booking{}, err
+	}
+	if booking.Status != BookingPending {
+		return Booking{}, ErrNotPending
+	}
+	if decision.Status == BookingAccepted {
+		if err := checkAvailable(ctx, tx, booking.ListingID, booking.CheckIn, booking.CheckOut, booking.ID); err != nil {
+			return Booking{}, err
+		}
+		if _, err := tx.ExecContext(ctx, `
+            UPDATE rental_bookings
+            SET status = 'declined', decided_by = $2, decided_at = NOW(), updated_at = NOW()
+            WHERE listing_id = $1 AND id <> $3 AND status = 'pending' AND check_in < $5 AND check_out > $4`,
+			booking.ListingID, decision.ActorEmail, booking.ID, booking.CheckIn, booking.CheckOut); err != nil {
+			return Booking{}, err
+		}
+	}
+	updated, err := scanBooking(tx.QueryRowContext(ctx, `
+        UPDATE rental_bookings
+        SET status = $2, decided_by = $3, decided_at = NOW(), updated_at = NOW()
+        WHERE id = $1
+        RETURNING `+bookingColumns, id, string(decision.Status), decision.ActorEmail))
+	if err != nil {
+		if isExclusionViolation(err) {
+			return Booking{}, ErrUnavailable
+		}
+		return Booking{}, err
+	}
+	return updated, tx.Commit()
+}
+
+func (s *sqlService) CancelBooking(ctx context.Context, id uuid.UUID, requesterID string) (Booking, error) {
+	b, err := scanBooking(s.db.QueryRowContext(ctx, `
+        UPDATE rental_bookings
+        SET status = 'cancelled', updated_at = NOW()
+        WHERE id = $1 AND requester_id = $2 AND status IN ('pending', 'accepted')
+        RETURNING `+bookingColumns, id, requesterID))
+	if !errors.Is(err, sql.ErrNoRows) {
+		return b, err
+	}
+	existing, err := s.GetBooking(ctx, id)
+	if err != nil || existing.RequesterID != requesterID {
+		return Booking{}, ErrNotFound
+	}
+	return Booking{}, ErrNotPending
+}
+
+// lockListing serialises calendar changes per listing for the rest of the
+// transaction.
+func lockListing(ctx context.Context, tx *sql.Tx, listingID uuid.UUID) error {
+	var id uuid.UUID
+	err := tx.QueryRowContext(ctx, `SELECT id FROM property_listings WHERE id = $1 FOR UPDATE`, listingID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func checkAvailable(ctx context.Context, tx *sql.Tx, listingID uuid.UUID, start, end time.Time, skip uuid.UUID) error {
+	blocks, err := queryBlocks(ctx, tx, listingID, start, end)
+	if err != nil {
+		return err
+	}
+	bookings, err := queryBookings(ctx, tx, BookingFilter{ListingID: listingID, Status: BookingAccepted, From: start, To: end})
+	if err != nil {
+		return err
+	}
+	if blocked, booked := Conflicts(start, end, blocks, bookings, skip); len(blocked) > 0 || len(booked) > 0 {
+		return ErrUnavailable
+	}
+	return nil
+}
+
+func scanBooking(row interface {
+	Scan(dest ...any) error
+}) (Booking, error) {
+	var (
+		b         Booking
+		status    string
+		decidedAt sql.NullTime
+	)
+	if err := row.Scan(&b.ID, &b.ListingID, &b.RequesterID, &b.RequesterEmail, &b.RequesterName, &b.CheckIn, &b.CheckOut,
+		&b.Guests, &b.Message, &status, &b.DecidedBy, &decidedAt, &b.CreatedAt, &b.UpdatedAt); err != nil {
+		return Booking{}, err
+	}
+	b.Status = BookingStatus(status)
+	b.CheckIn, b.CheckOut = Day(b.CheckIn), Day(b.CheckOut)
+	if decidedAt.Valid {
+		at := decidedAt.Time
+		b.DecidedAt = &at
+	}
+	return b, nil
+}
+
+// isExclusionViolation reports whether PostgreSQL rejected overlapping accepted
+// bookings.
+func isExclusionViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23P01"
+}
+
+var _ Service = (*sqlService)(nil)
@@ -0,0 +1,103 @@
+package savedsearch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"shanraq.com/internal/notify"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+// alertKind tags outbox notifications produced by the matcher.
+const alertKind = "saved_search_alert"
+
+// maxAlertListings caps how many matches a single alert spells out.
+const maxAlertListings = 10
+
+// Matcher evaluates newly published listings against saved searches and queues an
+// alert per search that gained matches.
+type Matcher struct {
+	searches Service
+	listings listingservice.Service
+	outbox   notify.Outbox
+	baseURL  string
+}
+
+// NewMatcher wires a matcher. baseURL prefixes listing links in alert bodies.
+func NewMatcher(searches Service, listings listingservice.Service, outbox notify.Outbox, baseURL string) *Matcher {
+	return &Matcher{
+		searches: searches,
+		listings: listings,
+		outbox:   outbox,
+		baseURL:  strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Run checks every alert-enabled search for listings published since it was last
+// checked and returns how many alerts were queued. A search whose query no longer
+// parses is skipped rather than failing the run.
+func (m *Matcher) Run(ctx context.Context, now time.Time) (int, error) {
+	searches, err := m.searches.Subscribed(ctx)
+	if err != nil {
+		return 0, err
+	}
+	queued := 0
+	for _, search := range searches {
+		filter, err := search.Filter()
+		if err != nil {
+			continue
+		}
+		filter.PublishedSince = search.LastCheckedAt
+		filter.Sort = listingservice.SortNewest
+		filter.Limit = maxAlertListings
+		filter.Offset = 0
+		filter.Viewer = listingservice.Viewer{}
+
+		matches, total, err := m.listings.List(ctx, filter)
+		if err != nil {
+			return queued, fmt.Errorf("match saved search %s: %w", search.ID, err)
+		}
+		if total > 0 {
+			if _, err := m.outbox.Enqueue(ctx, m.alert(search, matches, total)); err != nil {
+				return queued, err
+			}
+			queued++
+		}
+		if err := m.searches.MarkChecked(ctx, search.ID, now, total > 0); err != nil {
+			return queued, err
+		}
+	}
+	return queued, nil
+}
+
+func (m *Matcher) alert(search SavedSearch, matches []listingservice.Listing, total int) notify.Notification {
+	subject := fmt.Sprintf("%d new listing matches \"%s\"", total, search.Name)
+	if total > 1 {
+		subject = fmt.Sprintf("%d new listings match \"%s\"", total, search.Name)
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "New listings were published for your saved search \"%s\".\n\n", search.Name)
+	f := search.Formatter()
+	for _, l := range matches {
+		price := l.FormattedPrice(f)
+		if area := l.FormattedArea(f); area != "" {
+			price += ", " + area
+		}
+		fmt.Fprintf(&body, "- %s (%s) — %s\n  %s%s\n", l.Title, l.LocationString(), price, m.baseURL, l.DetailsURL)
+	}
+	if total > len(matches) {
+		fmt.Fprintf(&body, "\n…and %d more.\n", total-len(matches))
+	}
+	fmt.Fprintf(&body, "\nSee all matches: %s/api/v1/listings?%s\n", m.baseURL, search.Query)
+	fmt.Fprintf(&body, "Manage your alerts at %s/api/v1/workspaces/me/searches\n", m.baseURL)
+
+	return notify.Notification{
+		Kind:      alertKind,
+		Recipient: search.OwnerEmail,
+		Subject:   subject,
+		Body:      body.String(),
+	}
+}
@@ -0,0 +1,279 @@
+package savedsearch
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"shanraq.com/internal/auth"
+	"shanraq.com/internal/format"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+// SavedSearch is a listing query a user keeps for later and may be alerted about.
+type SavedSearch struct {
+	ID            uuid.UUID  `json:"id"`
+	OwnerID       string     `json:"owner_id"`
+	OwnerEmail    string     `json:"owner_email"`
+	Name          string     `json:"name"`
+	Query         string     `json:"query"`
+	Alerts        bool       `json:"alerts"`
+	Locale        string     `json:"locale"`
+	Region        string     `json:"region,omitempty"`
+	LastCheckedAt time.Time  `json:"last_checked_at"`
+	LastAlertedAt *time.Time `json:"last_alerted_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// Input carries the editable fields of a saved search. Query uses the same
+// parameters as GET /api/v1/listings, e.g. "country=AE&min_bedrooms=3".
+// Locale and Region decide how prices and areas are written in alert e-mails.
+type Input struct {
+	Name   string
+	Query  string
+	Alerts *bool
+	Locale string
+	Region string
+}
+
+// Service manages saved searches owned by signed-in users.
+type Service interface {
+	List(ctx context.Context, identity auth.Identity) ([]SavedSearch, error)
+	Get(ctx context.Context, identity auth.Identity, id uuid.UUID) (SavedSearch, error)
+	Create(ctx context.Context, identity auth.Identity, input Input) (SavedSearch, error)
+	Update(ctx context.Context, identity auth.Identity, id uuid.UUID, input Input) (SavedSearch, error)
+	Delete(ctx context.Context, identity auth.Identity, id uuid.UUID) error
+	Subscribed(ctx context.Context) ([]SavedSearch, error)
+	MarkChecked(ctx context.Context, id uuid.UUID, checkedAt time.Time, alerted bool) error
+}
+
+// ErrNotFound is returned when a saved search does not exist or belongs to someone else.
+var ErrNotFound = errors.New("saved search not found")
+
+// ignoredParams are controlled by the matcher rather than the saved query.
+var ignoredParams = []string{"limit", "offset", "sort", "lang", "published_since"}
+
+// Filter parses the saved query into a listing filter.
+func (s SavedSearch) Filter() (listingservice.ListFilter, error) {
+	values, err := url.ParseQuery(s.Query)
+	if err != nil {
+		return listingservice.ListFilter{}, err
+	}
+	return listingservice.ParseFilter(values)
+}
+
+// Formatter writes prices and areas the way the owner saw them when saving.
+func (s SavedSearch) Formatter() format.Formatter {
+	return format.New(s.Locale, s.Region)
+}
+
+// normalizeQuery validates the listing query and returns it in canonical form.
+func normalizeQuery(raw string) (string, error) {
+	values, err := url.ParseQuery(strings.TrimPrefix(strings.TrimSpace(raw), "?"))
+	if err != nil {
+		return "", errors.New("query must be a URL query string")
+	}
+	for _, key := range ignoredParams {
+		values.Del(key)
+	}
+	for key, vals := range values {
+		if len(vals) == 0 || strings.TrimSpace(vals[0]) == "" {
+			values.Del(key)
+		}
+	}
+	if _, err := listingservice.ParseFilter(values); err != nil {
+		return "", err
+	}
+	return values.Encode(), nil
+}
+
+func normalizeInput(identity auth.Identity, input Input, requireAll bool) (Input, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if requireAll && input.Name == "" {
+		return Input{}, errors.New("name is required")
+	}
+	if len(input.Name) > 120 {
+		return Input{}, errors.New("name must be at most 120 characters")
+	}
+	if requireAll || input.Query != "" {
+		query, err := normalizeQuery(input.Query)
+		if err != nil {
+			return Input{}, err
+		}
+		input.Query = query
+	}
+	if input.Alerts != nil && *input.Alerts && identity.Email == "" {
+		return Input{}, errors.New("an e-mail address is required for alerts")
+	}
+	if requireAll || input.Locale != "" {
+		f := format.New(input.Locale, input.Region)
+		input.Locale, input.Region = f.Locale, f.Region
+	}
+	return input, nil
+}
+
+func applyInput(s *SavedSearch, input Input, now time.Time) {
+	if input.Name != "" {
+		s.Name = input.Name
+	}
+	if input.Query != "" && input.Query != s.Query {
+		s.Query = input.Query
+		// Only alert about listings published after the criteria changed.
+		s.LastCheckedAt = now
+	}
+	if input.Alerts != nil {
+		if *input.Alerts && !s.Alerts {
+			s.LastCheckedAt = now
+		}
+		s.Alerts = *input.Alerts
+	}
+	if input.Locale != "" {
+		s.Locale, s.Region = input.Locale, input.Region
+	}
+	s.UpdatedAt = now
+}
+
+// InMemoryService keeps saved searches in process memory.
+type InMemoryService struct {
+	mu       sync.RWMutex
+	searches []SavedSearch
+}
+
+// NewInMemoryService creates an empty saved-search store.
+func NewInMemoryService() *InMemoryService {
+	return &InMemoryService{}
+}
+
+func (s *InMemoryService) List(_ context.Context, identity auth.Identity) ([]SavedSearch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]SavedSearch, 0)
+	for _, search := range s.searches {
+		if search.OwnerID == identity.Key() {
+			out = append(out, search)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (s *InMemoryService) Get(_ context.Context, identity auth.Identity, id uuid.UUID) (SavedSearch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	idx := s.indexOf(identity, id)
+	if idx < 0 {
+		return SavedSearch{}, ErrNotFound
+	}
+	return s.searches[idx], nil
+}
+
+func (s *InMemoryService) Create(_ context.Context, identity auth.Identity, input Input) (SavedSearch, error) {
+	input, err := normalizeInput(identity, input, true)
+	if err != nil {
+		return SavedSearch{}, err
+	}
+	now := time.Now().UTC()
+	search := SavedSearch{
+		ID:            uuid.New(),
+		OwnerID:       identity.Key(),
+		OwnerEmail:    identity.Email,
+		Name:          input.Name,
+		Query:         input.Query,
+		Alerts:        input.Alerts == nil || *input.Alerts,
+		Locale:        input.Locale,
+		Region:        input.Region,
+		LastCheckedAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if search.Alerts && identity.Email == "" {
+		search.Alerts = false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.searches = append(s.searches, search)
+	return search, nil
+}
+
+func (s *InMemoryService) Update(_ context.Context, identity auth.Identity, id uuid.UUID, input Input) (SavedSearch, error) {
+	input, err := normalizeInput(identity, input, false)
+	if err != nil {
+		return SavedSearch{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := s.indexOf(identity, id)
+	if idx < 0 {
+		return SavedSearch{}, ErrNotFound
+	}
+	applyInput(&s.searches[idx], input, time.Now().UTC())
+	return s.searches[idx], nil
+}
+
+func (s *InMemoryService) Delete(_ context.Context, identity auth.Identity, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := s.indexOf(identity, id)
+	if idx < 0 {
+		return ErrNotFound
+	}
+	s.searches = append(s.searches[:idx], s.searches[idx+1:]...)
+	return nil
+}
+
+// Subscribed returns every saved search with alerts switched on.
+func (s *InMemoryService) Subscribed(_ context.Context) ([]SavedSearch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]SavedSearch, 0)
+	for _, search := range s.searches {
+		if search.Alerts && search.OwnerEmail != "" {
+			out = append(out, search)
+		}
+	}
+	return out, nil
+}
+
+// MarkChecked advances the point from which new matches are looked for.
+func (s *InMemoryService) MarkChecked(_ context.Context, id uuid.UUID, checkedAt time.Time, alerted bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for idx := range s.searches {
+		if s.searches[idx].ID != id {
+			continue
+		}
+		s.searches[idx].LastCheckedAt = checkedAt
+		if alerted {
+			at := checkedAt
+			s.searches[idx].LastAlertedAt = &at
+		}
+		return nil
+	}
+	return ErrNotFound
+}
+
+func (s *InMemoryService) indexOf(identity auth.Identity, id uuid.UUID) int {
+	for idx, search := range s.searches {
+		if search.ID == id && search.OwnerID == identity.Key() {
+			return idx
+		}
+	}
+	return -1
+}
+
+var _ Service = (*InMemoryService)(nil)
@@ -0,0 +1,113 @@
+package savedsearch
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"shanraq.com/internal/auth"
+	"shanraq.com/internal/format"
+	"shanraq.com/internal/notify"
+	listingservice "shanraq.com/internal/services/listing"
+)
+
+func TestInMemoryServiceCRUD(t *testing.T) {
+	service := NewInMemoryService()
+	ctx := context.Background()
+	owner := auth.Identity{Subject: "buyer-1", Email: "buyer@example.com"}
+	other := auth.Identity{Subject: "buyer-2", Email: "other@example.com"}
+
+	search, err := service.Create(ctx, owner, Input{Name: "Dubai villas", Query: "?type=residential&country=AE&limit=5"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if search.Query != "country=AE&type=residential" {
+		t.Errorf("Query = %q, want canonical form without pagination", search.Query)
+	}
+	if !search.Alerts {
+		t.Error("Alerts should default to on for users with an e-mail")
+	}
+	if _, err := service.Create(ctx, owner, Input{Name: "Broken", Query: "min_price=cheap"}); err == nil {
+		t.Error("Create() accepted an invalid listing query")
+	}
+	if _, err := service.Get(ctx, other, search.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() by another user error = %v, want ErrNotFound", err)
+	}
+
+	off := false
+	updated, err := service.Update(ctx, owner, search.ID, Input{Alerts: &off})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.Alerts || updated.Name != "Dubai villas" {
+		t.Errorf("Update() = %+v, want alerts off and name kept", updated)
+	}
+
+	list, _ := service.List(ctx, owner)
+	if len(list) != 1 {
+		t.Fatalf("List() = %d searches, want 1", len(list))
+	}
+	if err := service.Delete(ctx, other, search.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete() by another user error = %v, want ErrNotFound", err)
+	}
+	if err := service.Delete(ctx, owner, search.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+}
+
+func TestMatcherQueuesAlertsForNewlyPublishedListings(t *testing.T) {
+	ctx := context.Background()
+	searches := NewInMemoryService()
+	listings := listingservice.NewInMemoryService()
+	outbox := notify.NewInMemoryOutbox()
+	matcher := NewMatcher(searches, listings, outbox, "https://shanraq.test/")
+	owner := auth.Identity{Subject: "buyer-1", Email: "buyer@example.com"}
+
+	if _, err := searches.Create(ctx, owner, Input{Name: "Swedish homes", Query: "country=SE", Locale: "de"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// Seeded listings were published before the search was saved.
+	if queued, err := matcher.Run(ctx, time.Now().UTC()); err != nil || queued != 0 {
+		t.Fatalf("Run() = %d, %v; want no alerts for existing listings", queued, err)
+	}
+
+	created, err := listings.Create(ctx, listingservice.CreateInput{
+		Title:    "Gamla Stan Loft",
+		Type:     listingservice.ListingTypeResidential,
+		Country:  "SE",
+		Currency: "SEK",
+		Price:    9500000,
+	})
+	if err != nil {
+		t.Fatalf("listing Create() error = %v", err)
+	}
+	for _, status := range []listingservice.Status{listingservice.StatusReview, listingservice.StatusPublished} {
+		if _, err := listings.Transition(ctx, created.ID, listingservice.TransitionInput{Status: status, ActorEmail: "agent@example.com"}); err != nil {
+			t.Fatalf("Transition(%s) error = %v", status, err)
+		}
+	}
+
+	queued, err := matcher.Run(ctx, time.Now().UTC())
+	if err != nil || queued != 1 {
+		t.Fatalf("Run() = %d, %v; want one alert", queued, err)
+	}
+	pending, _ := outbox.Pending(ctx, 10, 0)
+	if len(pending) != 1 {
+		t.Fatalf("outbox has %d pending notifications, want 1", len(pending))
+	}
+	alert := pending[0]
+	if alert.Recipient != owner.Email || !strings.Contains(alert.Body, "Gamla Stan Loft") ||
+		!strings.Contains(alert.Body, "https://shanraq.test/listings/gamla-stan-loft") {
+		t.Errorf("unexpected alert: %+v", alert)
+	}
+	if price := format.New("de", "").Money(9500000, "SEK"); !strings.Contains(alert.Body, price) {
+		t.Errorf("alert body %q does not carry the price in the saved locale (%q)", alert.Body, price)
+	}
+
+	if queued, _ := matcher.Run(ctx, time.Now().UTC()); queued != 0 {
+		t.Errorf("second Run() queued %d alerts, want 0", queued)
+	}
+}
@@ -0,0 +1,153 @@
+package savedsearch
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"shanraq.com/internal/auth"
+)
+
+const searchColumns = `id, owner_id, owner_email, name, query, alerts, locale, region, last_checked_at, last_alerted_at, created_at, updated_at`
+
+type sqlService struct {
+	db *sql.DB
+}
+
+// NewSQLService builds a saved-search service backed by PostgreSQL.
+func NewSQLService(db *sql.DB) (Service, error) {
+	return &sqlService{db: db}, nil
+}
+
+func (s *sqlService) List(ctx context.Context, identity auth.Identity) ([]SavedSearch, error) {
+	return s.query(ctx, `
+        SELECT `+searchColumns+`
+        FROM saved_searches
+        WHERE owner_id = $1
+        ORDER BY created_at DESC`, identity.Key())
+}
+
+func (s *sqlService) Get(ctx context.Context, identity auth.Identity, id uuid.UUID) (SavedSearch, error) {
+	row := s.db.QueryRowContext(ctx, `
+        SELECT `+searchColumns+`
+        FROM saved_searches
+        WHERE id = $1 AND owner_id = $2`, id, identity.Key())
+	search, err := scanSearch(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return SavedSearch{}, ErrNotFound
+	}
+	return search, err
+}
+
+func (s *sqlService) Create(ctx context.Context, identity auth.Identity, input Input) (SavedSearch, error) {
+	input, err := normalizeInput(identity, input, true)
+	if err != nil {
+		return SavedSearch{}, err
+	}
+	alerts := (input.Alerts == nil || *input.Alerts) && identity.Email != ""
+	row := s.db.QueryRowContext(ctx, `
+        INSERT INTO saved_searches (owner_id, owner_email, name, query, alerts, locale, region)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING `+searchColumns, identity.Key(), identity.Email, input.Name, input.Query, alerts, input.Locale, input.Region)
+	return scanSearch(row)
+}
+
+func (s *sqlService) Update(ctx context.Context, identity auth.Identity, id uuid.UUID, input Input) (SavedSearch, error) {
+	input, err := normalizeInput(identity, input, false)
+	if err != nil {
+		return SavedSearch{}, err
+	}
+	search, err := s.Get(ctx, identity, id)
+	if err != nil {
+		return SavedSearch{}, err
+	}
+	applyInput(&search, input, time.Now().UTC())
+	row := s.db.QueryRowContext(ctx, `
+        UPDATE saved_searches
+        SET name = $3, query = $4, alerts = $5, locale = $6, region = $7, last_checked_at = $8, updated_at = $9
+        WHERE id = $1 AND owner_id = $2
+        RETURNING `+searchColumns,
+		id, identity.Key(), search.Name, search.Query, search.Alerts, search.Locale, search.Region,
+		search.LastCheckedAt, search.UpdatedAt)
+	updated, err := scanSearch(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return SavedSearch{}, ErrNotFound
+	}
+	return updated, err
+}
+
+func (s *sqlService) Delete(ctx context.Context, identity auth.Identity, id uuid.UUID) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM saved_searches WHERE id = $1 AND owner_id = $2`, id, identity.Key())
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *sqlService) Subscribed(ctx context.Context) ([]SavedSearch, error) {
+	return s.query(ctx, `
+        SELECT `+searchColumns+`
+        FROM saved_searches
+        WHERE alerts AND owner_email <> ''
+        ORDER BY last_checked_at`)
+}
+
+func (s *sqlService) MarkChecked(ctx context.Context, id uuid.UUID, checkedAt time.Time, alerted bool) error {
+	result, err := s.db.ExecContext(ctx, `
+        UPDATE saved_searches
+        SET last_checked_at = $2,
+            last_alerted_at = CASE WHEN $3 THEN $2 ELSE last_alerted_at END
+        WHERE id = $1`, id, checkedAt, alerted)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *sqlService) query(ctx context.Context, query string, args ...any) ([]SavedSearch, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]SavedSearch, 0)
+	for rows.Next() {
+		search, err := scanSearch(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, search)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func scanSearch(row interface {
+	Scan(dest ...any) error
+}) (SavedSearch, error) {
+	var (
+		search    SavedSearch
+		alertedAt sql.NullTime
+	)
+	if err := row.Scan(&search.ID, &search.OwnerID, &search.OwnerEmail, &search.Name, &search.Query, &search.Alerts, &search.Locale, &search.Region,
+		&search.LastCheckedAt, &alertedAt, &search.CreatedAt, &search.UpdatedAt); err != nil {
+		return SavedSearch{}, err
+	}
+	if alertedAt.Valid {
+		at := alertedAt.Time
+		search.LastAlertedAt = &at
+	}
+	return search, nil
+}
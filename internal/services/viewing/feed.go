@@ -0,0 +1,25 @@
+package viewing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// FeedToken signs a realtor's calendar feed URL. Calendar apps cannot send session
+// cookies, so the token in the URL is what authorises the subscription. Bumping
+// the realtor's feed generation revokes their URL; rotating the secret revokes
+// every one.
+func FeedToken(secret string, realtorID uuid.UUID, generation int) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("viewing-feed:" + realtorID.String() + ":" + strconv.Itoa(generation)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ValidFeedToken reports whether token was issued for the realtor's current generation.
+func ValidFeedToken(secret string, realtorID uuid.UUID, generation int, token string) bool {
+	return token != "" && hmac.Equal([]byte(token), []byte(FeedToken(secret, realtorID, generation)))
+}
@@ -0,0 +1,536 @@
+package viewing
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	// Embedded zone data keeps realtor time zones resolvable on hosts without
+	// /usr/share/zoneinfo.
+	_ "time/tzdata"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MinSlotLength and MaxSlotLength bound one viewing.
+	MinSlotLength = 15 * time.Minute
+	MaxSlotLength = 4 * time.Hour
+	// MaxSlotsPerRequest caps how many slots one availability window is split into.
+	MaxSlotsPerRequest = 96
+	// localLayout is a wall-clock time without offset, read in the given zone.
+	localLayout = "2006-01-02T15:04"
+)
+
+// Slot is a time a realtor is available for one viewing. Start and End are
+// instants; TimeZone is the realtor's zone the slot was planned in.
+type Slot struct {
+	ID        uuid.UUID `json:"id"`
+	RealtorID uuid.UUID `json:"realtor_id"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	TimeZone  string    `json:"time_zone"`
+	Booked    bool      `json:"booked"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SlotInput opens availability between Start and End. With a Length the window is
+// split into consecutive slots of that length; otherwise it is a single slot.
+type SlotInput struct {
+	RealtorID uuid.UUID
+	Start     time.Time
+	End       time.Time
+	Length    time.Duration
+	TimeZone  string
+}
+
+// SlotFilter narrows the slots returned. From and To keep slots overlapping the
+// range; zero values leave that side open.
+type SlotFilter struct {
+	RealtorID     uuid.UUID
+	From          time.Time
+	To            time.Time
+	AvailableOnly bool
+}
+
+// Status tracks an appointment.
+type Status string
+
+const (
+	StatusScheduled Status = "scheduled"
+	StatusCancelled Status = "cancelled"
+)
+
+// Valid reports whether the status is supported.
+func (s Status) Valid() bool {
+	return s == StatusScheduled || s == StatusCancelled
+}
+
+// Appointment is a viewing booked into a realtor's slot. The times are copied from
+// the slot so the appointment survives the slot being removed after cancellation.
+type Appointment struct {
+	ID             uuid.UUID  `json:"id"`
+	SlotID         uuid.UUID  `json:"slot_id"`
+	RealtorID      uuid.UUID  `json:"realtor_id"`
+	ListingID      *uuid.UUID `json:"listing_id,omitempty"`
+	RequesterID    string     `json:"-"`
+	RequesterEmail string     `json:"requester_email"`
+	RequesterName  string     `json:"requester_name,omitempty"`
+	RequesterPhone string     `json:"requester_phone,omitempty"`
+	Note           string     `json:"note,omitempty"`
+	Start          time.Time  `json:"start"`
+	End            time.Time  `json:"end"`
+	TimeZone       string     `json:"time_zone"`
+	Status         Status     `json:"status"`
+	CancelledBy    string     `json:"cancelled_by,omitempty"`
+	CancelReason   string     `json:"cancel_reason,omitempty"`
+	CancelledAt    *time.Time `json:"cancelled_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// BookingInput books a slot of the realtor. TimeZone is the requester's zone, used when showing
+// the appointment back to them; it defaults to the slot's zone.
+type BookingInput struct {
+	SlotID         uuid.UUID
+	RealtorID      uuid.UUID
+	ListingID      *uuid.UUID
+	RequesterID    string
+	RequesterEmail string
+	RequesterName  string
+	RequesterPhone string
+	Note           string
+	TimeZone       string
+}
+
+// AppointmentFilter narrows the appointments returned.
+type AppointmentFilter struct {
+	RealtorID   uuid.UUID
+	RequesterID string
+	Status      Status
+	From        time.Time
+	To          time.Time
+}
+
+// Cancellation records who called a viewing off and why.
+type Cancellation struct {
+	ActorEmail string
+	Reason     string
+}
+
+// Service manages realtor availability and viewing appointments.
+type Service interface {
+	Slots(ctx context.Context, filter SlotFilter) ([]Slot, error)
+	AddSlots(ctx context.Context, input SlotInput) ([]Slot, error)
+	RemoveSlot(ctx context.Context, realtorID, id uuid.UUID) error
+	Book(ctx context.Context, input BookingInput) (Appointment, error)
+	Appointments(ctx context.Context, filter AppointmentFilter) ([]Appointment, error)
+	GetAppointment(ctx context.Context, id uuid.UUID) (Appointment, error)
+	Cancel(ctx context.Context, id uuid.UUID, cancellation Cancellation) (Appointment, error)
+	// FeedGeneration returns the realtor's current calendar feed generation and
+	// RotateFeed advances it, invalidating previously issued feed URLs.
+	FeedGeneration(ctx context.Context, realtorID uuid.UUID) (int, error)
+	RotateFeed(ctx context.Context, realtorID uuid.UUID) (int, error)
+}
+
+var (
+	// ErrNotFound is returned when a slot or appointment does not exist.
+	ErrNotFound = errors.New("viewing not found")
+	// ErrSlotOverlap is returned when new availability overlaps an existing slot.
+	ErrSlotOverlap = errors.New("slot overlaps existing availability")
+	// ErrSlotTaken is returned when a slot already holds a scheduled viewing.
+	ErrSlotTaken = errors.New("slot is already booked")
+	// ErrRequesterBusy is returned when the requester has another viewing at that time.
+	ErrRequesterBusy = errors.New("requester already has a viewing at that time")
+	// ErrAlreadyCancelled is returned when cancelling a cancelled appointment.
+	ErrAlreadyCancelled = errors.New("appointment is already cancelled")
+)
+
+// LoadLocation resolves an IANA zone name, treating an empty name as UTC.
+func LoadLocation(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.New("unknown time zone " + name)
+	}
+	return loc, nil
+}
+
+// ParseTime reads an RFC 3339 timestamp, or a wall-clock time such as
+// 2026-10-20T10:00 in loc.
+func ParseTime(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	for _, layout := range []string{localLayout, localLayout + ":05"} {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, errors.New("times must be RFC 3339 or YYYY-MM-DDTHH:MM")
+}
+
+// LocalSlot is a slot with its times on the wall clock of a zone.
+type LocalSlot struct {
+	Slot
+	LocalStart  string `json:"local_start"`
+	LocalEnd    string `json:"local_end"`
+	DisplayZone string `json:"display_time_zone"`
+}
+
+// Local renders the slot in the named zone, falling back to UTC for unknown zones.
+func (s Slot) Local(zone string) LocalSlot {
+	start, end, name := localTimes(s.Start, s.End, zone)
+	return LocalSlot{Slot: s, LocalStart: start, LocalEnd: end, DisplayZone: name}
+}
+
+// LocalAppointment is an appointment with its times on the wall clock of a zone.
+type LocalAppointment struct {
+	Appointment
+	LocalStart  string `json:"local_start"`
+	LocalEnd    string `json:"local_end"`
+	DisplayZone string `json:"display_time_zone"`
+}
+
+// Local renders the appointment in the named zone, falling back to UTC for unknown
+// zones.
+func (a Appointment) Local(zone string) LocalAppointment {
+	start, end, name := localTimes(a.Start, a.End, zone)
+	return LocalAppointment{Appointment: a, LocalStart: start, LocalEnd: end, DisplayZone: name}
+}
+
+func localTimes(start, end time.Time, zone string) (string, string, string) {
+	loc, err := LoadLocation(zone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return start.In(loc).Format(time.RFC3339), end.In(loc).Format(time.RFC3339), loc.String()
+}
+
+func overlaps(aStart, aEnd, bStart, bEnd time.Time) bool {
+	return aStart.Before(bEnd) && bStart.Before(aEnd)
+}
+
+// splitSlots validates the window and cuts it into slots.
+func splitSlots(input SlotInput, now time.Time) ([]Slot, error) {
+	if input.RealtorID == uuid.Nil {
+		return nil, errors.New("realtor is required")
+	}
+	input.TimeZone = strings.TrimSpace(input.TimeZone)
+	if _, err := LoadLocation(input.TimeZone); err != nil {
+		return nil, err
+	}
+	if input.TimeZone == "" {
+		input.TimeZone = "UTC"
+	}
+	start, end := input.Start.UTC().Truncate(time.Minute), input.End.UTC().Truncate(time.Minute)
+	if !end.After(start) {
+		return nil, errors.New("end must be after start")
+	}
+	if start.Before(now) {
+		return nil, errors.New("slots must start in the future")
+	}
+	length := input.Length
+	if length <= 0 {
+		length = end.Sub(start)
+	}
+	if length < MinSlotLength || length > MaxSlotLength {
+		return nil, errors.New("slots must last between 15 minutes and 4 hours")
+	}
+	if end.Sub(start)/length > MaxSlotsPerRequest {
+		return nil, errors.New("too many slots in one request")
+	}
+	var slots []Slot
+	for at := start; !at.Add(length).After(end); at = at.Add(length) {
+		slots = append(slots, Slot{
+			RealtorID: input.RealtorID,
+			Start:     at,
+			End:       at.Add(length),
+			TimeZone:  input.TimeZone,
+		})
+	}
+	return slots, nil
+}
+
+func normalizeBookingInput(input BookingInput) (BookingInput, error) {
+	if input.SlotID == uuid.Nil || input.RealtorID == uuid.Nil {
+		return BookingInput{}, errors.New("slot is required")
+	}
+	input.RequesterID = strings.TrimSpace(input.RequesterID)
+	input.RequesterEmail = strings.TrimSpace(input.RequesterEmail)
+	if input.RequesterID == "" || input.RequesterEmail == "" {
+		return BookingInput{}, errors.New("requester is required")
+	}
+	if _, err := LoadLocation(input.TimeZone); err != nil {
+		return BookingInput{}, err
+	}
+	if input.ListingID != nil && *input.ListingID == uuid.Nil {
+		input.ListingID = nil
+	}
+	input.TimeZone = strings.TrimSpace(input.TimeZone)
+	input.RequesterName = strings.TrimSpace(input.RequesterName)
+	input.RequesterPhone = strings.TrimSpace(input.RequesterPhone)
+	input.Note = strings.TrimSpace(input.Note)
+	return input, nil
+}
+
+// Matches reports whether the slot passes the filter.
+func (f SlotFilter) Matches(s Slot) bool {
+	if f.RealtorID != uuid.Nil && s.RealtorID != f.RealtorID {
+		return false
+	}
+	if f.AvailableOnly && s.Booked {
+		return false
+	}
+	if !f.From.IsZero() && !s.End.After(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !s.Start.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// Matches reports whether the appointment passes the filter.
+func (f AppointmentFilter) Matches(a Appointment) bool {
+	if f.RealtorID != uuid.Nil && a.RealtorID != f.RealtorID {
+		return false
+	}
+	if f.RequesterID != "" && a.RequesterID != f.RequesterID {
+		return false
+	}
+	if f.Status != "" && a.Status != f.Status {
+		return false
+	}
+	if !f.From.IsZero() && !a.End.After(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !a.Start.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// InMemoryService keeps slots and appointments in process memory.
+type InMemoryService struct {
+	mu           sync.RWMutex
+	slots        []Slot
+	appointments []Appointment
+	feeds        map[uuid.UUID]int
+}
+
+// NewInMemoryService returns an empty viewing service.
+func NewInMemoryService() *InMemoryService {
+	return &InMemoryService{feeds: make(map[uuid.UUID]int)}
+}
+
+func (s *InMemoryService) FeedGeneration(_ context.Context, realtorID uuid.UUID) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.feeds[realtorID], nil
+}
+
+func (s *InMemoryService) RotateFeed(_ context.Context, realtorID uuid.UUID) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.feeds[realtorID]++
+	return s.feeds[realtorID], nil
+}
+
+func (s *InMemoryService) Slots(_ context.Context, filter SlotFilter) ([]Slot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Slot, 0)
+	for _, slot := range s.slots {
+		slot.Booked = s.scheduledIndex(slot.ID) >= 0
+		if filter.Matches(slot) {
+			out = append(out, slot)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	return out, nil
+}
+
+// AddSlots opens availability, refusing windows that overlap existing slots of the
+// realtor.
+func (s *InMemoryService) AddSlots(_ context.Context, input SlotInput) ([]Slot, error) {
+	now := time.Now().UTC()
+	slots, err := splitSlots(input, now)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.slots {
+		if existing.RealtorID == input.RealtorID && overlaps(existing.Start, existing.End, slots[0].Start, slots[len(slots)-1].End) {
+			return nil, ErrSlotOverlap
+		}
+	}
+	for i := range slots {
+		slots[i].ID = uuid.New()
+		slots[i].CreatedAt = now
+	}
+	s.slots = append(s.slots, slots...)
+	return slots, nil
+}
+
+// RemoveSlot withdraws availability that has not been booked.
+func (s *InMemoryService) RemoveSlot(_ context.Context, realtorID, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, slot := range s.slots {
+		if slot.ID != id || slot.RealtorID != realtorID {
+			continue
+		}
+		if s.scheduledIndex(id) >= 0 {
+			return ErrSlotTaken
+		}
+		s.slots = append(s.slots[:i], s.slots[i+1:]...)
+		return nil
+	}
+	return ErrNotFound
+}
+
+// Book schedules a viewing in a free future slot.
+func (s *InMemoryService) Book(_ context.Context, input BookingInput) (Appointment, error) {
+	input, err := normalizeBookingInput(input)
+	if err != nil {
+		return Appointment{}, err
+	}
+	now := time.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var slot *Slot
+	for i := range s.slots {
+		if s.slots[i].ID == input.SlotID && s.slots[i].RealtorID == input.RealtorID {
+			slot = &s.slots[i]
+			break
+		}
+	}
+	if slot == nil {
+		return Appointment{}, ErrNotFound
+	}
+	if !slot.Start.After(now) {
+		return Appointment{}, errors.New("slot has already started")
+	}
+	if s.scheduledIndex(slot.ID) >= 0 {
+		return Appointment{}, ErrSlotTaken
+	}
+	for _, a := range s.appointments {
+		if a.RequesterID == input.RequesterID && a.Status == StatusScheduled && overlaps(a.Start, a.End, slot.Start, slot.End) {
+			return Appointment{}, ErrRequesterBusy
+		}
+	}
+	appointment := newAppointment(input, *slot)
+	appointment.ID = uuid.New()
+	appointment.CreatedAt = now
+	appointment.UpdatedAt = now
+	s.appointments = append(s.appointments, appointment)
+	return appointment, nil
+}
+
+func (s *InMemoryService) Appointments(_ context.Context, filter AppointmentFilter) ([]Appointment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Appointment, 0)
+	for _, a := range s.appointments {
+		if filter.Matches(a) {
+			out = append(out, a)
+		}
+	}
+	sortAppointments(out)
+	return out, nil
+}
+
+func (s *InMemoryService) GetAppointment(_ context.Context, id uuid.UUID) (Appointment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, a := range s.appointments {
+		if a.ID == id {
+			return a, nil
+		}
+	}
+	return Appointment{}, ErrNotFound
+}
+
+// Cancel calls a scheduled viewing off, freeing its slot.
+func (s *InMemoryService) Cancel(_ context.Context, id uuid.UUID, cancellation Cancellation) (Appointment, error) {
+	now := time.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.appointments {
+		a := &s.appointments[i]
+		if a.ID != id {
+			continue
+		}
+		if a.Status == StatusCancelled {
+			return Appointment{}, ErrAlreadyCancelled
+		}
+		a.Status = StatusCancelled
+		a.CancelledBy = strings.TrimSpace(cancellation.ActorEmail)
+		a.CancelReason = strings.TrimSpace(cancellation.Reason)
+		a.CancelledAt = &now
+		a.UpdatedAt = now
+		return *a, nil
+	}
+	return Appointment{}, ErrNotFound
+}
+
+func (s *InMemoryService) scheduledIndex(slotID uuid.UUID) int {
+	for i, a := range s.appointments {
+		if a.SlotID == slotID && a.Status == StatusScheduled {
+			return i
+		}
+	}
+	return -1
+}
+
+func newAppointment(input BookingInput, slot Slot) Appointment {
+	timeZone := input.TimeZone
+	if timeZone == "" {
+		timeZone = slot.TimeZone
+	}
+	return Appointment{
+		SlotID:         slot.ID,
+		RealtorID:      slot.RealtorID,
+		ListingID:      input.ListingID,
+		RequesterID:    input.RequesterID,
+		RequesterEmail: input.RequesterEmail,
+		RequesterName:  input.RequesterName,
+		RequesterPhone: input.RequesterPhone,
+		Note:           input.Note,
+		Start:          slot.Start,
+		End:            slot.End,
+		TimeZone:       timeZone,
+		Status:         StatusScheduled,
+	}
+}
+
+func sortAppointments(appointments []Appointment) {
+	sort.Slice(appointments, func(i, j int) bool {
+		if !appointments[i].Start.Equal(appointments[j].Start) {
+			return appointments[i].Start.Before(appointments[j].Start)
+		}
+		return appointments[i].CreatedAt.Before(appointments[j].CreatedAt)
+	})
+}
+
+var _ Service = (*InMemoryService)(nil)
@@ -0,0 +1,127 @@
+package viewing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestInMemoryServiceDoubleBooking(t *testing.T) {
+	ctx := context.Background()
+	service := NewInMemoryService()
+	realtor, other := uuid.New(), uuid.New()
+	start := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Hour)
+
+	slots, err := service.AddSlots(ctx, SlotInput{RealtorID: realtor, Start: start, End: start.Add(2 * time.Hour), Length: 30 * time.Minute, TimeZone: "Asia/Almaty"})
+	if err != nil {
+		t.Fatalf("AddSlots() error = %v", err)
+	}
+	if len(slots) != 4 || !slots[3].End.Equal(start.Add(2*time.Hour)) {
+		t.Fatalf("AddSlots() = %d slots, want 4 half-hour slots", len(slots))
+	}
+	if _, err := service.AddSlots(ctx, SlotInput{RealtorID: realtor, Start: start.Add(90 * time.Minute), End: start.Add(3 * time.Hour)}); !errors.Is(err, ErrSlotOverlap) {
+		t.Fatalf("AddSlots() overlapping error = %v, want ErrSlotOverlap", err)
+	}
+	if _, err := service.AddSlots(ctx, SlotInput{RealtorID: other, Start: start, End: start.Add(time.Hour)}); err != nil {
+		t.Fatalf("AddSlots() for another realtor error = %v", err)
+	}
+
+	book := func(requester string, slot Slot) (Appointment, error) {
+		return service.Book(ctx, BookingInput{SlotID: slot.ID, RealtorID: realtor, RequesterID: requester, RequesterEmail: requester + "@example.com"})
+	}
+	first, err := book("ana", slots[0])
+	if err != nil {
+		t.Fatalf("Book() error = %v", err)
+	}
+	if first.TimeZone != "Asia/Almaty" || !first.Start.Equal(slots[0].Start) {
+		t.Errorf("Book() = %+v, want slot times in the realtor zone", first)
+	}
+	if _, err := book("rui", slots[0]); !errors.Is(err, ErrSlotTaken) {
+		t.Errorf("Book() taken slot error = %v, want ErrSlotTaken", err)
+	}
+	if _, err := service.Book(ctx, BookingInput{SlotID: slots[1].ID, RealtorID: other, RequesterID: "rui", RequesterEmail: "rui@example.com"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Book() through another realtor error = %v, want ErrNotFound", err)
+	}
+	elsewhere, _ := service.Slots(ctx, SlotFilter{RealtorID: other})
+	if _, err := service.Book(ctx, BookingInput{SlotID: elsewhere[0].ID, RealtorID: other, RequesterID: "ana", RequesterEmail: "ana@example.com"}); !errors.Is(err, ErrRequesterBusy) {
+		t.Errorf("Book() overlapping viewing error = %v, want ErrRequesterBusy", err)
+	}
+	if err := service.RemoveSlot(ctx, realtor, slots[0].ID); !errors.Is(err, ErrSlotTaken) {
+		t.Errorf("RemoveSlot() booked error = %v, want ErrSlotTaken", err)
+	}
+
+	available, _ := service.Slots(ctx, SlotFilter{RealtorID: realtor, AvailableOnly: true})
+	if len(available) != 3 {
+		t.Errorf("Slots(available) = %d, want 3", len(available))
+	}
+
+	cancelled, err := service.Cancel(ctx, first.ID, Cancellation{ActorEmail: "ana@example.com", Reason: "sick"})
+	if err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	if cancelled.Status != StatusCancelled || cancelled.CancelledAt == nil {
+		t.Errorf("Cancel() = %+v, want cancelled", cancelled)
+	}
+	if _, err := service.Cancel(ctx, first.ID, Cancellation{}); !errors.Is(err, ErrAlreadyCancelled) {
+		t.Errorf("Cancel() twice error = %v, want ErrAlreadyCancelled", err)
+	}
+	if _, err := book("rui", slots[0]); err != nil {
+		t.Errorf("Book() freed slot error = %v", err)
+	}
+}
+
+func TestParseTimeAcrossZones(t *testing.T) {
+	berlin, err := LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+	cases := map[string]time.Time{
+		"2026-03-28T10:00":          time.Date(2026, 3, 28, 9, 0, 0, 0, time.UTC),
+		"2026-03-30T10:00":          time.Date(2026, 3, 30, 8, 0, 0, 0, time.UTC),
+		"2026-03-30T10:00:00+05:00": time.Date(2026, 3, 30, 5, 0, 0, 0, time.UTC),
+	}
+	for raw, want := range cases {
+		got, err := ParseTime(raw, berlin)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ParseTime(%q) = %v, %v; want %v", raw, got, err, want)
+		}
+	}
+	if _, err := LoadLocation("Mars/Olympus"); err == nil {
+		t.Error("LoadLocation() unknown zone error = nil, want error")
+	}
+
+	slot := Slot{Start: time.Date(2026, 3, 30, 8, 0, 0, 0, time.UTC), End: time.Date(2026, 3, 30, 8, 30, 0, 0, time.UTC)}
+	if local := slot.Local("Europe/Berlin"); local.LocalStart != "2026-03-30T10:00:00+02:00" {
+		t.Errorf("Local().LocalStart = %q, want Berlin summer time", local.LocalStart)
+	}
+}
+
+func TestFeedToken(t *testing.T) {
+	ctx := context.Background()
+	service := NewInMemoryService()
+	realtor := uuid.New()
+	generation, err := service.FeedGeneration(ctx, realtor)
+	if err != nil || generation != 0 {
+		t.Fatalf("FeedGeneration() = %d, %v; want 0", generation, err)
+	}
+	token := FeedToken("secret", realtor, generation)
+	if !ValidFeedToken("secret", realtor, generation, token) {
+		t.Error("ValidFeedToken() = false for the issued token")
+	}
+	if ValidFeedToken("rotated", realtor, generation, token) || ValidFeedToken("secret", uuid.New(), generation, token) || ValidFeedToken("secret", realtor, generation, "") {
+		t.Error("ValidFeedToken() accepted a token for another secret, realtor, or an empty token")
+	}
+
+	if generation, err = service.RotateFeed(ctx, realtor); err != nil || generation != 1 {
+		t.Fatalf("RotateFeed() = %d, %v; want 1", generation, err)
+	}
+	if ValidFeedToken("secret", realtor, generation, token) {
+		t.Error("ValidFeedToken() accepted a token from before the rotation")
+	}
+	if other, _ := service.FeedGeneration(ctx, uuid.New()); other != 0 {
+		t.Errorf("RotateFeed() changed another realtor's generation to %d", other)
+	}
+}
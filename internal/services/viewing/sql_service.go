@@ -0,0 +1,307 @@
+package viewing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const slotColumns = `s.id, s.realtor_id, s.starts_at, s.ends_at, s.time_zone, s.created_at,
+        EXISTS (SELECT 1 FROM viewing_appointments a WHERE a.slot_id = s.id AND a.status = 'scheduled')`
+
+const appointmentColumns = `id, slot_id, realtor_id, listing_id, requester_id, requester_email, requester_name,
+        requester_phone, note, starts_at, ends_at, time_zone, status, cancelled_by, cancel_reason, cancelled_at,
+        created_at, updated_at`
+
+type sqlService struct {
+	db *sql.DB
+}
+
+// NewSQLService builds a viewing service backed by PostgreSQL.
+func NewSQLService(db *sql.DB) (Service, error) {
+	return &sqlService{db: db}, nil
+}
+
+func (s *sqlService) Slots(ctx context.Context, filter SlotFilter) ([]Slot, error) {
+	var (
+		clauses []string
+		args    []any
+	)
+	add := func(clause string, value any) {
+		args = append(args, value)
+		clauses = append(clauses, fmt.Sprintf(clause, len(args)))
+	}
+	if filter.RealtorID != uuid.Nil {
+		add("s.realtor_id = $%d", filter.RealtorID)
+	}
+	if !filter.From.IsZero() {
+		add("s.ends_at > $%d", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		add("s.starts_at < $%d", filter.To.UTC())
+	}
+	if filter.AvailableOnly {
+		clauses = append(clauses, "NOT EXISTS (SELECT 1 FROM viewing_appointments a WHERE a.slot_id = s.id AND a.status = 'scheduled')")
+	}
+	where := ""
+	if len(clauses) > 0 {
+		where = "WHERE " + strings.Join(clauses, " AND ")
+	}
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT `+slotColumns+`
+        FROM viewing_slots s
+        `+where+`
+        ORDER BY s.starts_at, s.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	slots := make([]Slot, 0)
+	for rows.Next() {
+		var slot Slot
+		if err := rows.Scan(&slot.ID, &slot.RealtorID, &slot.Start, &slot.End, &slot.TimeZone, &slot.CreatedAt, &slot.Booked); err != nil {
+			return nil, err
+		}
+		slot.Start, slot.End = slot.Start.UTC(), slot.End.UTC()
+		slots = append(slots, slot)
+	}
+	return slots, rows.Err()
+}
+
+// AddSlots inserts all slots of the window in one transaction; the exclusion
+// constraint on viewing_slots rejects overlaps with existing availability.
+func (s *sqlService) AddSlots(ctx context.Context, input SlotInput) ([]Slot, error) {
+	slots, err := splitSlots(input, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for i := range slots {
+		err := tx.QueryRowContext(ctx, `
+            INSERT INTO viewing_slots (realtor_id, starts_at, ends_at, time_zone)
+            VALUES ($1, $2, $3, $4)
+            RETURNING id, created_at`,
+			slots[i].RealtorID, slots[i].Start, slots[i].End, slots[i].TimeZone,
+		).Scan(&slots[i].ID, &slots[i].CreatedAt)
+		if err != nil {
+			switch pgCode(err) {
+			case "23P01":
+				return nil, ErrSlotOverlap
+			case "23503":
+				return nil, ErrNotFound
+			}
+			return nil, err
+		}
+	}
+	return slots, tx.Commit()
+}
+
+func (s *sqlService) RemoveSlot(ctx context.Context, realtorID, id uuid.UUID) error {
+	result, err := s.db.ExecContext(ctx, `
+        DELETE FROM viewing_slots s
+        WHERE s.id = $1 AND s.realtor_id = $2
+          AND NOT EXISTS (SELECT 1 FROM viewing_appointments a WHERE a.slot_id = s.id AND a.status = 'scheduled')`,
+		id, realtorID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		return nil
+	}
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM viewing_slots WHERE id = $1 AND realtor_id = $2)`, id, realtorID).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return ErrSlotTaken
+	}
+	return ErrNotFound
+}
+
+// Book relies on the partial unique index on scheduled slots and the exclusion
+// constraint on the requester's scheduled viewings to reject double bookings made
+// concurrently.
+func (s *sqlService) Book(ctx context.Context, input BookingInput) (Appointment, error) {
+	input, err := normalizeBookingInput(input)
+	if err != nil {
+		return Appointment{}, err
+	}
+	var slot Slot
+	err = s.db.QueryRowContext(ctx, `SELECT id, realtor_id, starts_at, ends_at, time_zone FROM viewing_slots WHERE id = $1 AND realtor_id = $2`,
+		input.SlotID, input.RealtorID).
+		Scan(&slot.ID, &slot.RealtorID, &slot.Start, &slot.End, &slot.TimeZone)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Appointment{}, ErrNotFound
+	}
+	if err != nil {
+		return Appointment{}, err
+	}
+	if !slot.Start.After(time.Now()) {
+		return Appointment{}, errors.New("slot has already started")
+	}
+	a := newAppointment(input, slot)
+	booked, err := scanAppointment(s.db.QueryRowContext(ctx, `
+        INSERT INTO viewing_appointments
+            (slot_id, realtor_id, listing_id, requester_id, requester_email, requester_name, requester_phone,
+             note, starts_at, ends_at, time_zone)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        RETURNING `+appointmentColumns,
+		a.SlotID, a.RealtorID, a.ListingID, a.RequesterID, a.RequesterEmail, a.RequesterName, a.RequesterPhone,
+		a.Note, a.Start, a.End, a.TimeZone))
+	if err != nil {
+		switch pgCode(err) {
+		case "23505":
+			return Appointment{}, ErrSlotTaken
+		case "23P01":
+			return Appointment{}, ErrRequesterBusy
+		case "23503":
+			return Appointment{}, ErrNotFound
+		}
+		return Appointment{}, err
+	}
+	return booked, nil
+}
+
+func (s *sqlService) Appointments(ctx context.Context, filter AppointmentFilter) ([]Appointment, error) {
+	var (
+		clauses []string
+		args    []any
+	)
+	add := func(clause string, value any) {
+		args = append(args, value)
+		clauses = append(clauses, fmt.Sprintf(clause, len(args)))
+	}
+	if filter.RealtorID != uuid.Nil {
+		add("realtor_id = $%d", filter.RealtorID)
+	}
+	if filter.RequesterID != "" {
+		add("requester_id = $%d", filter.RequesterID)
+	}
+	if filter.Status != "" {
+		add("status = $%d", string(filter.Status))
+	}
+	if !filter.From.IsZero() {
+		add("ends_at > $%d", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		add("starts_at < $%d", filter.To.UTC())
+	}
+	where := ""
+	if len(clauses) > 0 {
+		where = "WHERE " + strings.Join(clauses, " AND ")
+	}
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT `+appointmentColumns+`
+        FROM viewing_appointments
+        `+where+`
+        ORDER BY starts_at, created_at`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	appointments := make([]Appointment, 0)
+	for rows.Next() {
+		a, err := scanAppointment(rows)
+		if err != nil {
+			return nil, err
+		}
+		appointments = append(appointments, a)
+	}
+	return appointments, rows.Err()
+}
+
+func (s *sqlService) GetAppointment(ctx context.Context, id uuid.UUID) (Appointment, error) {
+	a, err := scanAppointment(s.db.QueryRowContext(ctx, `SELECT `+appointmentColumns+` FROM viewing_appointments WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Appointment{}, ErrNotFound
+	}
+	return a, err
+}
+
+func (s *sqlService) Cancel(ctx context.Context, id uuid.UUID, cancellation Cancellation) (Appointment, error) {
+	a, err := scanAppointment(s.db.QueryRowContext(ctx, `
+        UPDATE viewing_appointments
+        SET status = 'cancelled', cancelled_by = $2, cancel_reason = $3, cancelled_at = NOW(), updated_at = NOW()
+        WHERE id = $1 AND status = 'scheduled'
+        RETURNING `+appointmentColumns,
+		id, strings.TrimSpace(cancellation.ActorEmail), strings.TrimSpace(cancellation.Reason)))
+	if !errors.Is(err, sql.ErrNoRows) {
+		return a, err
+	}
+	if _, err := s.GetAppointment(ctx, id); err != nil {
+		return Appointment{}, err
+	}
+	return Appointment{}, ErrAlreadyCancelled
+}
+
+func scanAppointment(row interface {
+	Scan(dest ...any) error
+}) (Appointment, error) {
+	var (
+		a           Appointment
+		slotID      uuid.NullUUID
+		listingID   uuid.NullUUID
+		status      string
+		cancelledAt sql.NullTime
+	)
+	if err := row.Scan(&a.ID, &slotID, &a.RealtorID, &listingID, &a.RequesterID, &a.RequesterEmail, &a.RequesterName,
+		&a.RequesterPhone, &a.Note, &a.Start, &a.End, &a.TimeZone, &status, &a.CancelledBy, &a.CancelReason, &cancelledAt,
+		&a.CreatedAt, &a.UpdatedAt); err != nil {
+		return Appointment{}, err
+	}
+	a.SlotID = slotID.UUID
+	if listingID.Valid {
+		id := listingID.UUID
+		a.ListingID = &id
+	}
+	a.Status = Status(status)
+	a.Start, a.End = a.Start.UTC(), a.End.UTC()
+	if cancelledAt.Valid {
+		at := cancelledAt.Time
+		a.CancelledAt = &at
+	}
+	return a, nil
+}
+
+func (s *sqlService) FeedGeneration(ctx context.Context, realtorID uuid.UUID) (int, error) {
+	var generation int
+	err := s.db.QueryRowContext(ctx, `SELECT generation FROM viewing_feeds WHERE realtor_id = $1`, realtorID).Scan(&generation)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return generation, err
+}
+
+func (s *sqlService) RotateFeed(ctx context.Context, realtorID uuid.UUID) (int, error) {
+	var generation int
+	err := s.db.QueryRowContext(ctx, `
+        INSERT INTO viewing_feeds (realtor_id, generation, rotated_at)
+        VALUES ($1, 1, NOW())
+        ON CONFLICT (realtor_id) DO UPDATE
+        SET generation = viewing_feeds.generation + 1, rotated_at = NOW()
+        RETURNING generation`, realtorID).Scan(&generation)
+	return generation, err
+}
+
+func pgCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
+
+var _ Service = (*sqlService)(nil)
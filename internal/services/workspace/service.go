@@ -35,6 +35,7 @@ type Metrics struct {
 	ActivePlans    int `json:"active_plans"`
 	CompletedPlans int `json:"completed_plans"`
 	Watchers       int `json:"watchers"`
+	Favorites      int `json:"favorites"`
 }
 
 // Service defines behaviour for managing user workspaces.
@@ -55,13 +56,7 @@ func NewInMemoryService() *InMemoryService {
 }
 
 func (s *InMemoryService) GetOrCreate(_ context.Context, identity auth.Identity) (Workspace, error) {
-	key := identity.Subject
-	if key == "" {
-		key = identity.Email
-	}
-	if key == "" {
-		key = identity.Provider
-	}
+	key := identity.Key()
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -85,7 +80,6 @@ func (s *InMemoryService) GetOrCreate(_ context.Context, identity auth.Identity)
 			Metrics: Metrics{
 				ActivePlans:    1,
 				CompletedPlans: 0,
-				Watchers:       3,
 			},
 			UpdatedAt: time.Now().UTC(),
 		}
@@ -95,13 +89,7 @@ func (s *InMemoryService) GetOrCreate(_ context.Context, identity auth.Identity)
 }
 
 func (s *InMemoryService) AddPlan(_ context.Context, identity auth.Identity, plan BusinessPlan) (Workspace, error) {
-	key := identity.Subject
-	if key == "" {
-		key = identity.Email
-	}
-	if key == "" {
-		key = identity.Provider
-	}
+	key := identity.Key()
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
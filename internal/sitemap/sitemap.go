@@ -0,0 +1,200 @@
+// Package sitemap builds the XML sitemap index and the per-section, per-locale
+// sitemaps crawlers read from it.
+package sitemap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"shanraq.com/internal/i18n"
+)
+
+const (
+	namespace      = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	xhtmlNamespace = "http://www.w3.org/1999/xhtml"
+
+	// PerFile caps the URLs in one sitemap. The protocol allows 50,000; the lower
+	// cap keeps each file cheap to generate.
+	PerFile = 5000
+)
+
+// Kind names a sitemap section.
+type Kind string
+
+const (
+	KindPages     Kind = "pages"
+	KindListings  Kind = "listings"
+	KindAgencies  Kind = "agencies"
+	KindTransport Kind = "transport"
+)
+
+// Kinds lists the sections in index order.
+var Kinds = []Kind{KindPages, KindListings, KindAgencies, KindTransport}
+
+// File identifies one sitemap: a section, a locale and a 1-based page.
+type File struct {
+	Kind   Kind
+	Locale string
+	Page   int
+}
+
+// Name is the file name, e.g. "listings-en.xml" or "listings-en-2.xml".
+func (f File) Name() string {
+	name := string(f.Kind) + "-" + f.Locale
+	if f.Page > 1 {
+		name += "-" + strconv.Itoa(f.Page)
+	}
+	return name + ".xml"
+}
+
+// Offset is the index of the first entry the file covers.
+func (f File) Offset() int {
+	return (f.Page - 1) * PerFile
+}
+
+// ParseName is the inverse of File.Name.
+func ParseName(name string) (File, error) {
+	base, ok := strings.CutSuffix(name, ".xml")
+	if !ok {
+		return File{}, fmt.Errorf("sitemap %q must end in .xml", name)
+	}
+	parts := strings.Split(base, "-")
+	if len(parts) < 2 || len(parts) > 3 {
+		return File{}, fmt.Errorf("unknown sitemap %q", name)
+	}
+	file := File{Kind: Kind(parts[0]), Locale: parts[1], Page: 1}
+	if !validKind(file.Kind) || !i18n.Supported(file.Locale) {
+		return File{}, fmt.Errorf("unknown sitemap %q", name)
+	}
+	if len(parts) == 3 {
+		page, err := strconv.Atoi(parts[2])
+		if err != nil || page < 2 {
+			return File{}, fmt.Errorf("unknown sitemap %q", name)
+		}
+		file.Page = page
+	}
+	return file, nil
+}
+
+func validKind(kind Kind) bool {
+	for _, k := range Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Files splits a section of total entries into files for every supported locale.
+// Empty sections produce no files.
+func Files(kind Kind, total int) []File {
+	pages := (total + PerFile - 1) / PerFile
+	files := make([]File, 0, pages*len(i18n.SupportedLocales))
+	for _, locale := range i18n.SupportedLocales {
+		for page := 1; page <= pages; page++ {
+			files = append(files, File{Kind: kind, Locale: locale, Page: page})
+		}
+	}
+	return files
+}
+
+// Entry is one page of the site. Path is relative to the base URL.
+type Entry struct {
+	Path    string
+	LastMod time.Time
+}
+
+// Index is a <sitemapindex> document.
+type Index struct {
+	XMLName  xml.Name   `xml:"sitemapindex"`
+	Xmlns    string     `xml:"xmlns,attr"`
+	Sitemaps []Location `xml:"sitemap"`
+}
+
+// Location points the index at one sitemap.
+type Location struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// URLSet is a <urlset> document.
+type URLSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	XHTML   string   `xml:"xmlns:xhtml,attr"`
+	URLs    []URL    `xml:"url"`
+}
+
+// URL is one page in a sitemap together with its translations.
+type URL struct {
+	Loc        string      `xml:"loc"`
+	LastMod    string      `xml:"lastmod,omitempty"`
+	Alternates []Alternate `xml:"xhtml:link"`
+}
+
+// Alternate links a translation of the page.
+type Alternate struct {
+	Rel      string `xml:"rel,attr"`
+	Hreflang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
+}
+
+// NewIndex lists the files under baseURL + dir.
+func NewIndex(baseURL, dir string, files []File) Index {
+	index := Index{Xmlns: namespace, Sitemaps: make([]Location, 0, len(files))}
+	prefix := strings.TrimRight(baseURL, "/") + "/" + strings.Trim(dir, "/") + "/"
+	for _, f := range files {
+		index.Sitemaps = append(index.Sitemaps, Location{Loc: prefix + f.Name()})
+	}
+	return index
+}
+
+// NewURLSet builds the sitemap of entries in one locale. Every URL carries hreflang
+// alternates for all supported locales plus x-default.
+func NewURLSet(baseURL, locale string, entries []Entry) URLSet {
+	set := URLSet{Xmlns: namespace, XHTML: xhtmlNamespace, URLs: make([]URL, 0, len(entries))}
+	for _, e := range entries {
+		u := URL{Loc: LocalizedURL(baseURL, e.Path, locale)}
+		if !e.LastMod.IsZero() {
+			u.LastMod = e.LastMod.UTC().Format("2006-01-02")
+		}
+		for _, alt := range i18n.SupportedLocales {
+			u.Alternates = append(u.Alternates, Alternate{Rel: "alternate", Hreflang: alt, Href: LocalizedURL(baseURL, e.Path, alt)})
+		}
+		u.Alternates = append(u.Alternates, Alternate{Rel: "alternate", Hreflang: "x-default", Href: LocalizedURL(baseURL, e.Path, i18n.DefaultLocale)})
+		set.URLs = append(set.URLs, u)
+	}
+	return set
+}
+
+// LocalizedURL is the absolute URL of path in locale. Pages pick their locale from
+// ?lang=, so the default locale needs no parameter.
+func LocalizedURL(baseURL, path, locale string) string {
+	u := strings.TrimRight(baseURL, "/") + path
+	if locale == "" || locale == i18n.DefaultLocale {
+		return u
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return u + sep + "lang=" + locale
+}
+
+// Write encodes a sitemap document with the XML declaration.
+func Write(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
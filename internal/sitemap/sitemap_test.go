@@ -0,0 +1,61 @@
+package sitemap
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileNamesRoundTrip(t *testing.T) {
+	files := Files(KindListings, PerFile+1)
+	if len(files) != 10 {
+		t.Fatalf("Files() returned %d files, want 2 pages for each of 5 locales", len(files))
+	}
+	for _, f := range files {
+		parsed, err := ParseName(f.Name())
+		if err != nil || parsed != f {
+			t.Fatalf("ParseName(%q) = %+v, %v; want %+v", f.Name(), parsed, err, f)
+		}
+	}
+	if got := (File{Kind: KindListings, Locale: "sv", Page: 2}).Name(); got != "listings-sv-2.xml" {
+		t.Fatalf("Name() = %q", got)
+	}
+	if len(Files(KindTransport, 0)) != 0 {
+		t.Fatal("empty section produced files")
+	}
+	for _, name := range []string{"listings-en", "listings-xx.xml", "secret-en.xml", "listings-en-1.xml", "listings-en-two.xml"} {
+		if _, err := ParseName(name); err == nil {
+			t.Errorf("ParseName(%q) succeeded, want error", name)
+		}
+	}
+}
+
+func TestURLSetCarriesLocaleAlternates(t *testing.T) {
+	set := NewURLSet("https://shanraq.example/", "ja", []Entry{
+		{Path: "/listings/alfama-loft", LastMod: time.Date(2026, 3, 14, 22, 0, 0, 0, time.FixedZone("UTC+5", 5*3600))},
+	})
+	var buf bytes.Buffer
+	if err := Write(&buf, set); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	xml := buf.String()
+	mustContain := []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`xmlns:xhtml="http://www.w3.org/1999/xhtml"`,
+		"<loc>https://shanraq.example/listings/alfama-loft?lang=ja</loc>",
+		"<lastmod>2026-03-14</lastmod>",
+		`<xhtml:link rel="alternate" hreflang="en" href="https://shanraq.example/listings/alfama-loft">`,
+		`<xhtml:link rel="alternate" hreflang="x-default" href="https://shanraq.example/listings/alfama-loft">`,
+	}
+	for _, token := range mustContain {
+		if !strings.Contains(xml, token) {
+			t.Fatalf("sitemap missing %q:\n%s", token, xml)
+		}
+	}
+
+	index := NewIndex("https://shanraq.example", "/sitemaps", []File{{Kind: KindPages, Locale: "en", Page: 1}})
+	if got := index.Sitemaps[0].Loc; got != "https://shanraq.example/sitemaps/pages-en.xml" {
+		t.Fatalf("index loc = %q", got)
+	}
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"shanraq.com/internal/config"
+)
+
+// ErrInvalidKey is returned for keys that are empty or escape the store root.
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// BlobStore persists binary objects addressed by slash-separated keys.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// New builds the blob store selected by configuration.
+func New(cfg config.Storage) (BlobStore, error) {
+	switch strings.ToLower(cfg.Driver) {
+	case "", "local":
+		return NewLocalStore(cfg.LocalDir, cfg.PublicURL), nil
+	default:
+		return nil, fmt.Errorf("unsupported storage driver %q", cfg.Driver)
+	}
+}
+
+// LocalStore writes blobs below a directory that is served over HTTP at baseURL.
+type LocalStore struct {
+	root    string
+	baseURL string
+}
+
+// NewLocalStore creates a filesystem-backed store.
+func NewLocalStore(root, baseURL string) *LocalStore {
+	return &LocalStore{root: root, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+func (s *LocalStore) Put(_ context.Context, key string, r io.Reader, _ string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never observe a partial blob.
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStore) URL(key string) string {
+	return s.baseURL + "/" + strings.TrimLeft(path.Clean("/"+key), "/")
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if key == "" || cleaned == "/" || cleaned != "/"+key {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
+
+var _ BlobStore = (*LocalStore)(nil)
@@ -0,0 +1,168 @@
+package web
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+
+	agencyservice "shanraq.com/internal/services/agency"
+	listingservice "shanraq.com/internal/services/listing"
+	transportservice "shanraq.com/internal/services/transport"
+)
+
+const schemaContext = "https://schema.org"
+
+// jsonLD is a schema.org object the layout renders as a JSON-LD block.
+type jsonLD map[string]any
+
+// AgencyURL is the path of an agency's public profile.
+func AgencyURL(a agencyservice.Agency) string {
+	return "/agencies/" + a.ID.String()
+}
+
+// TransportURL is the path of a moving company's public profile.
+func TransportURL(c transportservice.Company) string {
+	return "/transport/" + c.Slug
+}
+
+// AbsoluteURL joins the public base URL and a site path.
+func AbsoluteURL(baseURL, path string) string {
+	if path == "" {
+		return ""
+	}
+	return strings.TrimRight(baseURL, "/") + path
+}
+
+// ListingJSONLD describes a listing as a schema.org RealEstateListing.
+func ListingJSONLD(l listingservice.Listing, baseURL string) any {
+	offer := jsonLD{
+		"@type":         "Offer",
+		"price":         l.Price,
+		"priceCurrency": l.Currency,
+		"availability":  availability(l.Status),
+	}
+	place := jsonLD{
+		"@type": "Place",
+		"address": compact(jsonLD{
+			"@type":           "PostalAddress",
+			"addressLocality": l.City,
+			"addressRegion":   l.Region,
+			"addressCountry":  l.Country,
+		}),
+	}
+	if l.Location != nil {
+		place["geo"] = jsonLD{"@type": "GeoCoordinates", "latitude": l.Location.Lat, "longitude": l.Location.Lng}
+	}
+	doc := jsonLD{
+		"@context":        schemaContext,
+		"@type":           "RealEstateListing",
+		"name":            l.Title,
+		"description":     l.Summary,
+		"url":             AbsoluteURL(baseURL, l.DetailsURL),
+		"image":           l.ImageURL,
+		"offers":          offer,
+		"contentLocation": place,
+	}
+	if l.PublishedAt != nil {
+		doc["datePosted"] = l.PublishedAt.UTC().Format("2006-01-02")
+	}
+	if l.AgencyName != "" {
+		agent := jsonLD{"@type": "RealEstateAgent", "name": l.AgencyName}
+		if l.AgencyID != uuid.Nil {
+			agent["url"] = AbsoluteURL(baseURL, "/agencies/"+l.AgencyID.String())
+		}
+		doc["offeredBy"] = agent
+	}
+	return compact(doc)
+}
+
+// AgencyJSONLD describes an agency and its realtors as a schema.org RealEstateAgent.
+func AgencyJSONLD(a agencyservice.Agency, realtors []agencyservice.Realtor, baseURL string) any {
+	doc := jsonLD{
+		"@context":    schemaContext,
+		"@type":       "RealEstateAgent",
+		"name":        a.Name,
+		"description": a.Tagline,
+		"url":         AbsoluteURL(baseURL, AgencyURL(a)),
+		"logo":        a.LogoURL,
+		"address": compact(jsonLD{
+			"@type":          "PostalAddress",
+			"streetAddress":  a.HeadOffice,
+			"addressCountry": a.Country,
+		}),
+	}
+	if a.Website != "" {
+		doc["sameAs"] = []string{a.Website}
+	}
+	if len(realtors) > 0 {
+		people := make([]any, 0, len(realtors))
+		for _, r := range realtors {
+			person := jsonLD{
+				"@type":     "Person",
+				"name":      r.FullName,
+				"email":     r.Email,
+				"telephone": r.Phone,
+				"image":     r.PhotoURL,
+			}
+			if len(r.Languages) > 0 {
+				person["knowsLanguage"] = r.Languages
+			}
+			people = append(people, compact(person))
+		}
+		doc["employee"] = people
+	}
+	return compact(doc)
+}
+
+// TransportJSONLD describes a logistics partner as a schema.org MovingCompany.
+func TransportJSONLD(c transportservice.Company, baseURL string) any {
+	doc := jsonLD{
+		"@context":    schemaContext,
+		"@type":       "MovingCompany",
+		"name":        c.Name,
+		"description": c.Description,
+		"url":         AbsoluteURL(baseURL, TransportURL(c)),
+		"email":       c.ContactEmail,
+		"telephone":   c.ContactPhone,
+		"address":     jsonLD{"@type": "PostalAddress", "addressCountry": c.CountryCode},
+	}
+	if c.Website != "" {
+		doc["sameAs"] = []string{c.Website}
+	}
+	if len(c.CoverageRegions) > 0 {
+		doc["areaServed"] = c.CoverageRegions
+	}
+	if len(c.ServicesOffered) > 0 {
+		offers := make([]any, 0, len(c.ServicesOffered))
+		for _, service := range c.ServicesOffered {
+			offers = append(offers, jsonLD{"@type": "Offer", "itemOffered": jsonLD{"@type": "Service", "name": service}})
+		}
+		doc["makesOffer"] = offers
+	}
+	return compact(doc)
+}
+
+// availability maps a listing status onto a schema.org ItemAvailability.
+func availability(status listingservice.Status) string {
+	switch status {
+	case listingservice.StatusUnderOffer:
+		return "https://schema.org/LimitedAvailability"
+	case listingservice.StatusSold:
+		return "https://schema.org/SoldOut"
+	case listingservice.StatusArchived:
+		return "https://schema.org/Discontinued"
+	default:
+		return "https://schema.org/InStock"
+	}
+}
+
+// compact removes empty strings so optional properties are omitted rather than
+// published blank.
+func compact(doc jsonLD) jsonLD {
+	for key, value := range doc {
+		if s, ok := value.(string); ok && s == "" {
+			delete(doc, key)
+		}
+	}
+	return doc
+}
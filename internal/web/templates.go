@@ -1,16 +1,22 @@
 package web
 
 import (
+	"fmt"
 	"html/template"
 	"io"
 	"io/fs"
+	"math"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
+	"shanraq.com/internal/format"
 	agencyservice "shanraq.com/internal/services/agency"
+	"shanraq.com/internal/services/finance"
 	listingservice "shanraq.com/internal/services/listing"
+	mediaservice "shanraq.com/internal/services/media"
 	transportservice "shanraq.com/internal/services/transport"
 )
 
@@ -24,11 +30,17 @@ type Renderer struct {
 // BasePageData carries common metadata consumed by the shared layout.
 type BasePageData struct {
 	Theme       string
+	Lang        string
+	Dir         string
 	PageTitle   string
 	BrandName   string
 	Description string
 	PageID      string
 	CurrentYear int
+	// CanonicalURL is the absolute URL crawlers should index the page under.
+	CanonicalURL string
+	// StructuredData holds schema.org objects emitted as JSON-LD blocks.
+	StructuredData []any
 }
 
 // HomePageData captures the dynamic properties injected into the landing page.
@@ -40,8 +52,39 @@ type HomePageData struct {
 	FeaturedTransport []TransportCard
 }
 
-// NewRenderer parses templates from the web directory.
+// ListingPageData captures the dynamic properties of a listing detail page.
+type ListingPageData struct {
+	BasePageData
+	Listing  ListingDetail
+	Gallery  []GalleryImage
+	Agency   *AgencyCard
+	Realtors []RealtorCard
+	Mortgage *MortgageEstimate
+}
+
+// ComparePageData captures a side-by-side comparison of several listings.
+type ComparePageData struct {
+	BasePageData
+	Currency string
+	Columns  []CompareColumn
+	Rows     []CompareRow
+}
 
+// AgencyPageData captures the public profile of an agency.
+type AgencyPageData struct {
+	BasePageData
+	Agency   AgencyCard
+	Realtors []RealtorCard
+	Listings []ListingCard
+}
+
+// TransportPageData captures the public profile of a moving company.
+type TransportPageData struct {
+	BasePageData
+	Company TransportCard
+}
+
+// NewRenderer parses templates from the web directory.
 func NewRenderer() (*Renderer, error) {
 	webRoot := locateWebDir()
 	fsys := os.DirFS(webRoot)
@@ -69,24 +112,99 @@ func (r *Renderer) RenderHome(w io.Writer, data *HomePageData) error {
 	if data == nil {
 		data = &HomePageData{}
 	}
+	if data.PageTitle == "" {
+		data.PageTitle = "Discover Global Properties · "
+	}
+	if data.PageID == "" {
+		data.PageID = "home"
+	}
+	return r.render(w, "pages/home.html", &data.BasePageData, data)
+}
 
-	if data.BrandName == "" {
-		data.BrandName = "Shanraq"
+// RenderListing renders the detail page of a single listing.
+func (r *Renderer) RenderListing(w io.Writer, data *ListingPageData) error {
+	if data == nil {
+		data = &ListingPageData{}
+	}
+	if data.PageTitle == "" && data.Listing.Title != "" {
+		data.PageTitle = data.Listing.Title + " · "
+	}
+	if data.Description == "" {
+		data.Description = data.Listing.Summary
+	}
+	if data.PageID == "" {
+		data.PageID = "listing"
+	}
+	return r.render(w, "pages/listing.html", &data.BasePageData, data)
+}
+
+// RenderCompare renders the listing comparison page.
+func (r *Renderer) RenderCompare(w io.Writer, data *ComparePageData) error {
+	if data == nil {
+		data = &ComparePageData{}
 	}
 	if data.PageTitle == "" {
-		data.PageTitle = "Discover Global Properties · "
+		data.PageTitle = "Compare Listings · "
+	}
+	if data.PageID == "" {
+		data.PageID = "compare"
+	}
+	return r.render(w, "pages/compare.html", &data.BasePageData, data)
+}
+
+// RenderAgency renders the public profile of an agency.
+func (r *Renderer) RenderAgency(w io.Writer, data *AgencyPageData) error {
+	if data == nil {
+		data = &AgencyPageData{}
+	}
+	if data.PageTitle == "" && data.Agency.Name != "" {
+		data.PageTitle = data.Agency.Name + " · "
 	}
 	if data.Description == "" {
-		data.Description = "Search, compare, and manage international real estate listings from a single platform."
+		data.Description = data.Agency.Tagline
 	}
 	if data.PageID == "" {
-		data.PageID = "home"
+		data.PageID = "agency"
 	}
-	if data.CurrentYear == 0 {
-		data.CurrentYear = time.Now().Year()
+	return r.render(w, "pages/agency.html", &data.BasePageData, data)
+}
+
+// RenderTransport renders the public profile of a moving company.
+func (r *Renderer) RenderTransport(w io.Writer, data *TransportPageData) error {
+	if data == nil {
+		data = &TransportPageData{}
 	}
-	if data.Theme == "" {
-		data.Theme = "auto"
+	if data.PageTitle == "" && data.Company.Name != "" {
+		data.PageTitle = data.Company.Name + " · "
+	}
+	if data.Description == "" {
+		data.Description = data.Company.Description
+	}
+	if data.PageID == "" {
+		data.PageID = "transport"
+	}
+	return r.render(w, "pages/transport.html", &data.BasePageData, data)
+}
+
+// render fills layout defaults and executes the page inside the shared layout.
+func (r *Renderer) render(w io.Writer, page string, base *BasePageData, data any) error {
+	if base.BrandName == "" {
+		base.BrandName = "Shanraq"
+	}
+	if base.Description == "" {
+		base.Description = "Search, compare, and manage international real estate listings from a single platform."
+	}
+	if base.CurrentYear == 0 {
+		base.CurrentYear = time.Now().Year()
+	}
+	if base.Theme == "" {
+		base.Theme = "auto"
+	}
+	if base.Lang == "" {
+		base.Lang = "en"
+	}
+	if base.Dir == "" {
+		base.Dir = "ltr"
 	}
 
 	r.mu.RLock()
@@ -96,7 +214,7 @@ func (r *Renderer) RenderHome(w io.Writer, data *HomePageData) error {
 		return err
 	}
 
-	if _, err := clone.ParseFS(r.fsys, "pages/home.html"); err != nil {
+	if _, err := clone.ParseFS(r.fsys, page); err != nil {
 		return err
 	}
 
@@ -155,18 +273,82 @@ type ListingCard struct {
 	Location    string
 	Summary     string
 	Price       string
+	PriceDrop   string
+	Area        string
 	Thumbnail   string
 	PropertyURL string
+	CompareURL  string
+}
+
+// ListingDetail carries the facts shown on a listing detail page.
+type ListingDetail struct {
+	ID          string
+	Title       string
+	Summary     string
+	Location    string
+	Price       string
+	PriceDrop   string
+	Type        string
+	Status      string
+	Bedrooms    int
+	Bathrooms   float64
+	AreaSqM     float64
+	Area        string
+	Tags        []string
+	AgencyName  string
+	HasLocation bool
+	Latitude    float64
+	Longitude   float64
+	MapURL      string
+}
+
+// MortgageEstimate is the typical monthly payment for a listing under the
+// market's default mortgage terms.
+type MortgageEstimate struct {
+	MonthlyPayment string
+	Terms          string
+}
+
+// GalleryImage is a single image in a listing gallery.
+type GalleryImage struct {
+	URL       string
+	Thumbnail string
+	Caption   string
+	FloorPlan bool
+}
+
+// CompareColumn heads one listing column of the comparison table.
+type CompareColumn struct {
+	ID          string
+	Title       string
+	Location    string
+	Thumbnail   string
+	PropertyURL string
+}
+
+// CompareRow is one attribute across the compared listings.
+type CompareRow struct {
+	Label string
+	Same  bool
+	Cells []CompareCell
+}
+
+// CompareCell is a single value in the comparison table.
+type CompareCell struct {
+	Value string
+	Best  bool
 }
 
 // AgencyCard represents an agency highlight.
 type AgencyCard struct {
-	ID      string
-	Name    string
-	Country string
-	Website string
-	Tagline string
-	LogoURL string
+	ID         string
+	Name       string
+	Country    string
+	Website    string
+	Tagline    string
+	LogoURL    string
+	HeadOffice string
+	PageURL    string
 }
 
 // RealtorCard represents a featured realtor profile.
@@ -181,41 +363,189 @@ type RealtorCard struct {
 
 // TransportCard represents a moving/logistics provider.
 type TransportCard struct {
-	ID          string
-	Name        string
-	CountryCode string
-	Services    []string
-	Coverage    []string
+	ID           string
+	Name         string
+	CountryCode  string
+	Services     []string
+	Coverage     []string
+	Description  string
+	Website      string
+	ContactEmail string
+	ContactPhone string
+	PageURL      string
 }
 
-// MapListings converts listing service models into template cards.
-func MapListings(listings []listingservice.Listing) []ListingCard {
+// MapListings converts listing service models into template cards with prices and
+// areas formatted for the visitor. Each card links a comparison of its listing
+// against the others in the set.
+func MapListings(listings []listingservice.Listing, f format.Formatter) []ListingCard {
 	result := make([]ListingCard, 0, len(listings))
-	for _, l := range listings {
+	for idx, l := range listings {
+		others := make([]listingservice.Listing, 0, len(listings))
+		others = append(others, l)
+		others = append(others, listings[:idx]...)
+		others = append(others, listings[idx+1:]...)
 		result = append(result, ListingCard{
 			ID:          l.ID.String(),
 			Title:       l.Title,
 			Location:    l.LocationString(),
 			Summary:     l.Summary,
-			Price:       l.DisplayPrice(),
+			Price:       l.FormattedPrice(f),
+			PriceDrop:   priceDrop(l),
+			Area:        l.FormattedArea(f),
+			Thumbnail:   l.ImageURL,
+			PropertyURL: l.DetailsURL,
+			CompareURL:  CompareURL(others),
+		})
+	}
+	return result
+}
+
+// compareLabels names the comparison attributes on the page.
+var compareLabels = map[string]string{
+	"type":           "Type",
+	"status":         "Status",
+	"country":        "Country",
+	"city":           "City",
+	"price":          "Price",
+	"area_sqm":       "Area (m²)",
+	"area_sqft":      "Area (sq ft)",
+	"price_per_sqm":  "Price per m²",
+	"price_per_sqft": "Price per sq ft",
+	"bedrooms":       "Bedrooms",
+	"bathrooms":      "Bathrooms",
+}
+
+// MapComparison converts a listing comparison into table columns and rows.
+func MapComparison(c listingservice.Comparison) ([]CompareColumn, []CompareRow) {
+	columns := make([]CompareColumn, 0, len(c.Listings))
+	for _, entry := range c.Listings {
+		l := entry.Listing
+		columns = append(columns, CompareColumn{
+			ID:          l.ID.String(),
+			Title:       l.Title,
+			Location:    l.LocationString(),
 			Thumbnail:   l.ImageURL,
 			PropertyURL: l.DetailsURL,
 		})
 	}
+	rows := make([]CompareRow, 0, len(c.Diff))
+	for _, diff := range c.Diff {
+		label := compareLabels[diff.Attribute]
+		if label == "" {
+			label = diff.Attribute
+		}
+		row := CompareRow{Label: label, Same: diff.Same, Cells: make([]CompareCell, 0, len(diff.Display))}
+		for idx, value := range diff.Display {
+			row.Cells = append(row.Cells, CompareCell{
+				Value: value,
+				Best:  diff.Best != nil && *diff.Best == idx,
+			})
+		}
+		rows = append(rows, row)
+	}
+	return columns, rows
+}
+
+// CompareURL links the comparison page for the given listings, or returns "" when
+// there are fewer than two of them.
+func CompareURL(listings []listingservice.Listing) string {
+	if len(listings) < 2 {
+		return ""
+	}
+	ids := make([]string, 0, listingservice.MaxCompare)
+	for _, l := range listings {
+		if len(ids) == listingservice.MaxCompare {
+			break
+		}
+		ids = append(ids, l.ID.String())
+	}
+	return "/compare?ids=" + strings.Join(ids, ",")
+}
+
+// MapListingDetail converts a listing into the detail page model.
+func MapListingDetail(l listingservice.Listing, f format.Formatter) ListingDetail {
+	detail := ListingDetail{
+		ID:         l.ID.String(),
+		Title:      l.Title,
+		Summary:    l.Summary,
+		Location:   l.LocationString(),
+		Price:      l.FormattedPrice(f),
+		PriceDrop:  priceDrop(l),
+		Type:       string(l.Type),
+		Status:     strings.ReplaceAll(string(l.Status), "_", " "),
+		Bedrooms:   l.Bedrooms,
+		Bathrooms:  l.Bathrooms,
+		AreaSqM:    l.AreaSqM,
+		Area:       l.FormattedArea(f),
+		Tags:       append([]string(nil), l.Tags...),
+		AgencyName: l.AgencyName,
+	}
+	if l.Location != nil {
+		detail.HasLocation = true
+		detail.Latitude = l.Location.Lat
+		detail.Longitude = l.Location.Lng
+		detail.MapURL = fmt.Sprintf("https://www.openstreetmap.org/?mlat=%.5f&mlon=%.5f#map=15/%.5f/%.5f",
+			l.Location.Lat, l.Location.Lng, l.Location.Lat, l.Location.Lng)
+	}
+	return detail
+}
+
+// MapMortgageEstimate summarises a calculated mortgage for the listing page.
+func MapMortgageEstimate(m finance.Mortgage, f format.Formatter) *MortgageEstimate {
+	return &MortgageEstimate{
+		MonthlyPayment: f.Money(math.Round(m.MonthlyPayment), m.Currency),
+		Terms: fmt.Sprintf("%.0f%% down, %.2f%% over %d years (%s)",
+			(1-m.LTV)*100, m.Rate, m.TermYears, m.Method),
+	}
+}
+
+// MapGallery converts listing media into gallery images, falling back to the
+// listing's hero image when nothing has been uploaded.
+func MapGallery(items []mediaservice.Media, fallback string) []GalleryImage {
+	result := make([]GalleryImage, 0, len(items))
+	for _, m := range items {
+		display := m.VariantURL(mediaservice.VariantDisplayWebP)
+		if display == "" {
+			display = m.URL
+		}
+		result = append(result, GalleryImage{
+			URL:       display,
+			Thumbnail: m.ThumbnailURL(),
+			Caption:   m.Caption,
+			FloorPlan: m.Kind == mediaservice.KindFloorPlan,
+		})
+	}
+	if len(result) == 0 && fallback != "" {
+		result = append(result, GalleryImage{URL: fallback, Thumbnail: fallback})
+	}
 	return result
 }
 
+// priceDrop labels listings whose last price change was a reduction.
+func priceDrop(l listingservice.Listing) string {
+	if !l.PriceDropped() {
+		return ""
+	}
+	if pct := l.PriceDropPercent(); pct > 0 {
+		return fmt.Sprintf("Reduced %d%%", pct)
+	}
+	return "Reduced"
+}
+
 // MapAgencies converts agency service models into template cards.
 func MapAgencies(agencies []agencyservice.Agency) []AgencyCard {
 	result := make([]AgencyCard, 0, len(agencies))
 	for _, a := range agencies {
 		result = append(result, AgencyCard{
-			ID:      a.ID.String(),
-			Name:    a.Name,
-			Country: a.Country,
-			Website: a.Website,
-			Tagline: a.Tagline,
-			LogoURL: a.LogoURL,
+			ID:         a.ID.String(),
+			Name:       a.Name,
+			Country:    a.Country,
+			Website:    a.Website,
+			Tagline:    a.Tagline,
+			LogoURL:    a.LogoURL,
+			HeadOffice: a.HeadOffice,
+			PageURL:    AgencyURL(a),
 		})
 	}
 	return result
@@ -242,11 +572,16 @@ func MapTransportCompanies(companies []transportservice.Company) []TransportCard
 	result := make([]TransportCard, 0, len(companies))
 	for _, c := range companies {
 		result = append(result, TransportCard{
-			ID:          c.ID.String(),
-			Name:        c.Name,
-			CountryCode: c.CountryCode,
-			Services:    append([]string(nil), c.ServicesOffered...),
-			Coverage:    append([]string(nil), c.CoverageRegions...),
+			ID:           c.ID.String(),
+			Name:         c.Name,
+			CountryCode:  c.CountryCode,
+			Services:     append([]string(nil), c.ServicesOffered...),
+			Coverage:     append([]string(nil), c.CoverageRegions...),
+			Description:  c.Description,
+			Website:      c.Website,
+			ContactEmail: c.ContactEmail,
+			ContactPhone: c.ContactPhone,
+			PageURL:      TransportURL(c),
 		})
 	}
 	return result
@@ -4,6 +4,13 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+
+	"github.com/google/uuid"
+
+	"shanraq.com/internal/format"
+	agencyservice "shanraq.com/internal/services/agency"
+	"shanraq.com/internal/services/finance"
+	listingservice "shanraq.com/internal/services/listing"
 )
 
 func TestRenderHome(t *testing.T) {
@@ -75,3 +82,157 @@ func TestRenderHome(t *testing.T) {
 		t.Fatalf("layout main container not found in rendered output")
 	}
 }
+
+func TestRenderListing(t *testing.T) {
+	renderer, err := NewRenderer()
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+
+	data := &ListingPageData{
+		Listing: ListingDetail{
+			ID:          "listing-1",
+			Title:       "Palm Jumeirah Sky Villa",
+			Summary:     "Infinity pool with skyline views.",
+			Location:    "Palm Jumeirah, Dubai, AE",
+			Price:       "USD 6,800,000",
+			Type:        "residential",
+			Status:      "published",
+			Bedrooms:    4,
+			HasLocation: true,
+			Latitude:    25.1124,
+			Longitude:   55.139,
+			MapURL:      "https://www.openstreetmap.org/?mlat=25.11240&mlon=55.13900#map=15/25.11240/55.13900",
+		},
+		Gallery: []GalleryImage{
+			{URL: "/media/hero.webp", Thumbnail: "/media/hero-thumb.jpg"},
+			{URL: "/media/plan.webp", Thumbnail: "/media/plan-thumb.jpg", FloorPlan: true},
+		},
+		Agency: &AgencyCard{Name: "Shanraq Global Realty", Country: "AE"},
+		Realtors: []RealtorCard{{
+			Name:  "Layla Al-Mansouri",
+			Email: "layla@example.com",
+		}},
+		Mortgage: MapMortgageEstimate(finance.Mortgage{Currency: "USD", MonthlyPayment: 30221.4, LTV: 0.8, Rate: 4.5, TermYears: 25, Method: finance.MethodAnnuity}, format.Formatter{}),
+	}
+	data.BrandName = "Shanraq"
+
+	var buf bytes.Buffer
+	if err := renderer.RenderListing(&buf, data); err != nil {
+		t.Fatalf("RenderListing() error = %v", err)
+	}
+
+	html := buf.String()
+	mustContain := []string{
+		"<title>Palm Jumeirah Sky Villa",
+		"USD 6,800,000",
+		"/media/plan-thumb.jpg",
+		"Floor plan",
+		`data-lat="25.1124"`,
+		"Shanraq Global Realty",
+		"mailto:layla@example.com",
+		"$30,221</strong> / month",
+		"20% down, 4.50% over 25 years (annuity)",
+	}
+	for _, token := range mustContain {
+		if !strings.Contains(html, token) {
+			t.Fatalf("rendered listing page missing %q", token)
+		}
+	}
+}
+
+func TestRenderCompare(t *testing.T) {
+	renderer, err := NewRenderer()
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+
+	best := 1
+	columns, rows := MapComparison(listingservice.Comparison{
+		Currency: "EUR",
+		Listings: []listingservice.ComparedListing{
+			{Listing: listingservice.Listing{Title: "Lisbon loft", DetailsURL: "/listings/lisbon-loft"}},
+			{Listing: listingservice.Listing{Title: "Singapore duplex", DetailsURL: "/listings/singapore-duplex"}},
+		},
+		Diff: []listingservice.AttributeDiff{
+			{Attribute: "price_per_sqm", Display: []string{"EUR 5,000", "EUR 4,500"}, Best: &best},
+		},
+	})
+	data := &ComparePageData{Currency: "EUR", Columns: columns, Rows: rows}
+	data.BrandName = "Shanraq"
+
+	var buf bytes.Buffer
+	if err := renderer.RenderCompare(&buf, data); err != nil {
+		t.Fatalf("RenderCompare() error = %v", err)
+	}
+
+	html := buf.String()
+	mustContain := []string{
+		"<title>Compare Listings",
+		"/listings/singapore-duplex",
+		"Price per m²",
+		`<span class="badge text-bg-success">EUR 4,500</span>`,
+		"Prices in EUR",
+	}
+	for _, token := range mustContain {
+		if !strings.Contains(html, token) {
+			t.Fatalf("rendered compare page missing %q", token)
+		}
+	}
+}
+
+func TestRenderAgencyEmitsJSONLD(t *testing.T) {
+	renderer, err := NewRenderer()
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+
+	agency := agencyservice.Agency{ID: uuid.New(), Name: "Atlas Heritage Homes", Country: "IT", Tagline: "Historic residences"}
+	realtors := []agencyservice.Realtor{{FullName: "Giulia Romano", Email: "giulia@example.com", Languages: []string{"Italian"}}}
+	listing := listingservice.Listing{
+		Title:      "Florence </script> loft",
+		Price:      850000,
+		Currency:   "EUR",
+		City:       "Florence",
+		Country:    "IT",
+		Status:     listingservice.StatusUnderOffer,
+		DetailsURL: "/listings/florence-loft",
+	}
+	data := &AgencyPageData{
+		Agency:   MapAgencies([]agencyservice.Agency{agency})[0],
+		Realtors: MapRealtors(realtors),
+		Listings: MapListings([]listingservice.Listing{listing}, format.New("sv", "SE")),
+	}
+	data.BrandName = "Shanraq"
+	data.CanonicalURL = "https://shanraq.example" + AgencyURL(agency)
+	data.StructuredData = []any{
+		AgencyJSONLD(agency, realtors, "https://shanraq.example"),
+		ListingJSONLD(listing, "https://shanraq.example/"),
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.RenderAgency(&buf, data); err != nil {
+		t.Fatalf("RenderAgency() error = %v", err)
+	}
+
+	html := buf.String()
+	mustContain := []string{
+		"<title>Atlas Heritage Homes",
+		`<link rel="canonical" href="https://shanraq.example/agencies/` + agency.ID.String() + `">`,
+		`"@type":"RealEstateAgent"`,
+		`"employee":[{"@type":"Person"`,
+		`"@type":"RealEstateListing"`,
+		`"url":"https://shanraq.example/listings/florence-loft"`,
+		`"availability":"https://schema.org/LimitedAvailability"`,
+		"mailto:giulia@example.com",
+		"850\u00a0000\u00a0€",
+	}
+	for _, token := range mustContain {
+		if !strings.Contains(html, token) {
+			t.Fatalf("rendered agency page missing %q", token)
+		}
+	}
+	if !strings.Contains(html, `Florence \u003c/script\u003e loft`) {
+		t.Fatal("JSON-LD block was not escaped")
+	}
+}
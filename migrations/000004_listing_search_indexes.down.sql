@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_property_listings_created_at;
+DROP INDEX IF EXISTS idx_property_listings_agency;
+DROP INDEX IF EXISTS idx_property_listings_price;
+DROP INDEX IF EXISTS idx_property_listings_tags;
@@ -0,0 +1,4 @@
+CREATE INDEX IF NOT EXISTS idx_property_listings_tags ON property_listings USING GIN (tags);
+CREATE INDEX IF NOT EXISTS idx_property_listings_price ON property_listings(price);
+CREATE INDEX IF NOT EXISTS idx_property_listings_agency ON property_listings(agency_id);
+CREATE INDEX IF NOT EXISTS idx_property_listings_created_at ON property_listings(created_at DESC);
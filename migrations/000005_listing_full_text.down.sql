@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_property_listings_search_vector;
+DROP TRIGGER IF EXISTS trg_property_listings_search_vector ON property_listings;
+DROP FUNCTION IF EXISTS property_listings_search_vector_update();
+ALTER TABLE property_listings
+    DROP COLUMN IF EXISTS search_vector,
+    DROP COLUMN IF EXISTS content_language;
+DROP FUNCTION IF EXISTS shanraq_search_config(TEXT);
@@ -0,0 +1,46 @@
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- Maps ISO 639-1 language codes to text search configurations.
+CREATE OR REPLACE FUNCTION shanraq_search_config(lang TEXT) RETURNS regconfig
+LANGUAGE sql IMMUTABLE AS $$
+    SELECT CASE lower(split_part(coalesce(lang, ''), '-', 1))
+        WHEN 'en' THEN 'english'::regconfig
+        WHEN 'sv' THEN 'swedish'::regconfig
+        WHEN 'pt' THEN 'portuguese'::regconfig
+        WHEN 'it' THEN 'italian'::regconfig
+        WHEN 'ar' THEN 'arabic'::regconfig
+        ELSE 'simple'::regconfig
+    END
+$$;
+
+ALTER TABLE property_listings
+    ADD COLUMN content_language TEXT NOT NULL DEFAULT 'en',
+    ADD COLUMN search_vector tsvector;
+
+-- Each field is indexed twice: stemmed with the content language and verbatim
+-- with the "simple" configuration so place names match in any language.
+CREATE OR REPLACE FUNCTION property_listings_search_vector_update() RETURNS trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+    cfg regconfig := shanraq_search_config(NEW.content_language);
+    location TEXT := unaccent(concat_ws(' ', NEW.neighborhood, NEW.city, NEW.region, array_to_string(NEW.tags, ' ')));
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector(cfg, unaccent(coalesce(NEW.title, ''))), 'A') ||
+        setweight(to_tsvector('simple', unaccent(coalesce(NEW.title, ''))), 'A') ||
+        setweight(to_tsvector(cfg, location), 'B') ||
+        setweight(to_tsvector('simple', location), 'B') ||
+        setweight(to_tsvector(cfg, unaccent(coalesce(NEW.summary, ''))), 'C') ||
+        setweight(to_tsvector('simple', unaccent(coalesce(NEW.summary, ''))), 'C');
+    RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER trg_property_listings_search_vector
+    BEFORE INSERT OR UPDATE OF title, summary, neighborhood, city, region, tags, content_language
+    ON property_listings
+    FOR EACH ROW EXECUTE FUNCTION property_listings_search_vector_update();
+
+UPDATE property_listings SET content_language = content_language;
+
+CREATE INDEX idx_property_listings_search_vector ON property_listings USING GIN (search_vector);
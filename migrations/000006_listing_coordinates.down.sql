@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_property_listings_point;
+DROP INDEX IF EXISTS idx_property_listings_lat_lng;
+ALTER TABLE property_listings
+    DROP CONSTRAINT IF EXISTS property_listings_coordinates_pair,
+    DROP CONSTRAINT IF EXISTS property_listings_longitude_range,
+    DROP CONSTRAINT IF EXISTS property_listings_latitude_range,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS latitude;
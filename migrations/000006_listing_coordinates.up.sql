@@ -0,0 +1,28 @@
+-- WGS84 coordinates. Distances use haversine math and polygon queries use the
+-- built-in geometric types, so PostGIS is not required.
+ALTER TABLE property_listings
+    ADD COLUMN latitude DOUBLE PRECISION,
+    ADD COLUMN longitude DOUBLE PRECISION,
+    ADD CONSTRAINT property_listings_latitude_range CHECK (latitude BETWEEN -90 AND 90),
+    ADD CONSTRAINT property_listings_longitude_range CHECK (longitude BETWEEN -180 AND 180),
+    ADD CONSTRAINT property_listings_coordinates_pair CHECK ((latitude IS NULL) = (longitude IS NULL));
+
+CREATE INDEX IF NOT EXISTS idx_property_listings_lat_lng ON property_listings(latitude, longitude);
+CREATE INDEX IF NOT EXISTS idx_property_listings_point ON property_listings USING GIST (point(longitude, latitude));
+
+UPDATE property_listings AS l
+SET latitude = c.latitude,
+    longitude = c.longitude
+FROM (VALUES
+    ('palm-jumeirah-sky-villa', 25.1124, 55.1390),
+    ('ostermalm-art-nouveau', 59.3380, 18.0860),
+    ('kyoto-machiya-boutique-hotel', 35.0037, 135.7788),
+    ('lisbon-digital-district-loft', 38.7680, -9.0940),
+    ('tuscany-heritage-vineyard-estate', 43.4660, 11.2530),
+    ('singapore-sky-garden-duplex', 1.2830, 103.8600),
+    ('reykjavik-geothermal-retreat', 64.1670, -21.7000),
+    ('cape-town-atlantic-seaboard-villa', -33.9270, 18.3780),
+    ('sao-paulo-innovation-hub-loft', -23.5950, -46.6860),
+    ('british-columbia-wilderness-lodge', 50.1450, -123.1100)
+) AS c(slug, latitude, longitude)
+WHERE l.slug = c.slug;
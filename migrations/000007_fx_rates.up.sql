@@ -0,0 +1,23 @@
+-- Reference FX rates quoted as units of currency per 1 EUR (ECB convention).
+CREATE TABLE IF NOT EXISTS fx_rates (
+    currency CHAR(3) PRIMARY KEY,
+    rate NUMERIC(20, 8) NOT NULL CHECK (rate > 0),
+    as_of DATE NOT NULL DEFAULT CURRENT_DATE,
+    source TEXT NOT NULL DEFAULT 'manual',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO fx_rates (currency, rate, as_of, source) VALUES
+    ('EUR', 1, '2025-01-02', 'seed'),
+    ('USD', 1.0321, '2025-01-02', 'seed'),
+    ('GBP', 0.8285, '2025-01-02', 'seed'),
+    ('SEK', 11.4865, '2025-01-02', 'seed'),
+    ('JPY', 162.74, '2025-01-02', 'seed'),
+    ('SGD', 1.4121, '2025-01-02', 'seed'),
+    ('ISK', 144.1, '2025-01-02', 'seed'),
+    ('ZAR', 19.4586, '2025-01-02', 'seed'),
+    ('BRL', 6.4093, '2025-01-02', 'seed'),
+    ('CAD', 1.4872, '2025-01-02', 'seed'),
+    ('AED', 3.7904, '2025-01-02', 'seed'),
+    ('KZT', 541.2, '2025-01-02', 'seed')
+ON CONFLICT (currency) DO NOTHING;
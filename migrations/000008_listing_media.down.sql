@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_listing_media_listing_position;
+DROP TABLE IF EXISTS listing_media;
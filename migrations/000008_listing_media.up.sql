@@ -0,0 +1,20 @@
+-- Ordered gallery images and floor plans attached to listings. Blobs live in the
+-- configured storage backend; variants holds the generated renditions as JSON.
+CREATE TABLE IF NOT EXISTS listing_media (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    listing_id UUID NOT NULL REFERENCES property_listings(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL DEFAULT 'photo' CHECK (kind IN ('photo', 'floor_plan')),
+    position INTEGER NOT NULL DEFAULT 0 CHECK (position >= 0),
+    caption TEXT NOT NULL DEFAULT '',
+    content_type TEXT NOT NULL,
+    width INTEGER NOT NULL CHECK (width > 0),
+    height INTEGER NOT NULL CHECK (height > 0),
+    size_bytes BIGINT NOT NULL CHECK (size_bytes > 0),
+    storage_key TEXT NOT NULL UNIQUE,
+    url TEXT NOT NULL,
+    variants JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_listing_media_listing_position ON listing_media (listing_id, position);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS listing_status_events;
+DROP INDEX IF EXISTS idx_property_listings_status;
+ALTER TABLE property_listings DROP COLUMN IF EXISTS status;
@@ -0,0 +1,22 @@
+-- Listing lifecycle: draft -> review -> published -> under_offer -> sold/archived.
+ALTER TABLE property_listings
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'review', 'published', 'under_offer', 'sold', 'archived'));
+
+-- Everything listed before the lifecycle existed was already public.
+UPDATE property_listings SET status = 'published';
+
+CREATE INDEX IF NOT EXISTS idx_property_listings_status ON property_listings (status);
+
+CREATE TABLE IF NOT EXISTS listing_status_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    listing_id UUID NOT NULL REFERENCES property_listings(id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    actor_email TEXT NOT NULL,
+    actor_name TEXT NOT NULL DEFAULT '',
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_listing_status_events_listing ON listing_status_events (listing_id, created_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_property_listings_price_changed_at;
+ALTER TABLE property_listings
+    DROP COLUMN IF EXISTS price_changed_at,
+    DROP COLUMN IF EXISTS previous_price;
+DROP TABLE IF EXISTS listing_price_history;
@@ -0,0 +1,21 @@
+-- Every asking-price change, plus the last one denormalised onto the listing so
+-- price-drop filters and badges do not need to scan the history.
+CREATE TABLE IF NOT EXISTS listing_price_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    listing_id UUID NOT NULL REFERENCES property_listings(id) ON DELETE CASCADE,
+    old_price NUMERIC(20,2) NOT NULL,
+    old_currency CHAR(3) NOT NULL,
+    new_price NUMERIC(20,2) NOT NULL,
+    new_currency CHAR(3) NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_listing_price_history_listing ON listing_price_history (listing_id, changed_at);
+
+ALTER TABLE property_listings
+    ADD COLUMN IF NOT EXISTS previous_price NUMERIC(20,2),
+    ADD COLUMN IF NOT EXISTS price_changed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_property_listings_price_changed_at
+    ON property_listings (price_changed_at)
+    WHERE previous_price IS NOT NULL;
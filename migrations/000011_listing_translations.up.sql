@@ -0,0 +1,25 @@
+-- Localized listing copy. The base row in property_listings is the default (English)
+-- version; a missing translation falls back to it.
+CREATE TABLE IF NOT EXISTS listing_translations (
+    listing_id UUID NOT NULL REFERENCES property_listings(id) ON DELETE CASCADE,
+    locale TEXT NOT NULL CHECK (locale IN ('ar', 'sv', 'ja', 'pt')),
+    title TEXT NOT NULL,
+    summary TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (listing_id, locale)
+);
+
+CREATE INDEX IF NOT EXISTS idx_listing_translations_locale ON listing_translations (locale);
+
+INSERT INTO listing_translations (listing_id, locale, title, summary)
+SELECT l.id, t.locale, t.title, t.summary
+FROM (VALUES
+    ('palm-jumeirah-sky-villa', 'ar', 'فيلا سكاي في نخلة جميرا', 'دوبلكس من أربع غرف نوم مع مسبح خاص لا متناهي وإطلالات مفتوحة على الخليج.'),
+    ('ostermalm-art-nouveau', 'sv', 'Jugendvåning på Östermalm', 'Renoverad lägenhet från 1903 med moderna energisystem och vinterträdgård.'),
+    ('kyoto-machiya-boutique-hotel', 'ja', '京都町家ブティックホテル', '伝統建築と現代的な設備を融合させた、営業許可済みの6室の町家ホテル。'),
+    ('lisbon-digital-district-loft', 'pt', 'Loft no Distrito Digital de Lisboa', 'Loft inteligente com vista para o Tejo, mezanino de coworking e estacionamento preparado para veículos elétricos.'),
+    ('sao-paulo-innovation-hub-loft', 'pt', 'Loft no Polo de Inovação de São Paulo', 'Armazém reconvertido com infraestrutura 5G, estúdios e lounge de dados.')
+) AS t(slug, locale, title, summary)
+JOIN property_listings l ON l.slug = t.slug
+ON CONFLICT (listing_id, locale) DO NOTHING;
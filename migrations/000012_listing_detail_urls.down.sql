@@ -0,0 +1,2 @@
+-- Detail URLs are derived from slugs; the previous values are not restored.
+SELECT 1;
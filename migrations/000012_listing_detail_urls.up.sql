@@ -0,0 +1,4 @@
+-- Listing pages are served at /listings/{slug}; point stale demo links at them.
+UPDATE property_listings
+SET details_url = '/listings/' || slug
+WHERE details_url IS DISTINCT FROM '/listings/' || slug;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS featured_placements;
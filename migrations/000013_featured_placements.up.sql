@@ -0,0 +1,18 @@
+-- Editorial homepage placements. Empty country/locale values target every audience.
+CREATE TABLE IF NOT EXISTS featured_placements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    listing_id UUID NOT NULL REFERENCES property_listings(id) ON DELETE CASCADE,
+    position SMALLINT NOT NULL CHECK (position BETWEEN 1 AND 24),
+    country TEXT NOT NULL DEFAULT '',
+    locale TEXT NOT NULL DEFAULT '',
+    starts_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ends_at TIMESTAMPTZ,
+    note TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_featured_placements_schedule ON featured_placements (starts_at, ends_at);
+CREATE INDEX IF NOT EXISTS idx_featured_placements_listing ON featured_placements (listing_id);
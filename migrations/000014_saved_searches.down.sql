@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS notification_outbox;
+DROP TABLE IF EXISTS saved_searches;
+DROP INDEX IF EXISTS idx_property_listings_published_at;
+ALTER TABLE property_listings DROP COLUMN IF EXISTS published_at;
@@ -0,0 +1,44 @@
+-- When a listing was first published; saved-search alerts look for listings
+-- published since each search was last checked.
+ALTER TABLE property_listings ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ;
+
+UPDATE property_listings
+SET published_at = created_at
+WHERE status = 'published' AND published_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_property_listings_published_at
+    ON property_listings (published_at)
+    WHERE published_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id TEXT NOT NULL,
+    owner_email TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL,
+    query TEXT NOT NULL DEFAULT '',
+    alerts BOOLEAN NOT NULL DEFAULT TRUE,
+    last_checked_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_alerted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_owner ON saved_searches (owner_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_saved_searches_alerts ON saved_searches (last_checked_at) WHERE alerts;
+
+-- Outbound messages waiting for delivery by the notify dispatcher.
+CREATE TABLE IF NOT EXISTS notification_outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind TEXT NOT NULL,
+    recipient TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_outbox_pending
+    ON notification_outbox (created_at)
+    WHERE sent_at IS NULL;
@@ -0,0 +1,11 @@
+-- Per-user watchlists. listing_id deliberately has no foreign key: the snapshot
+-- keeps a saved listing visible on the watchlist after it is deleted.
+CREATE TABLE IF NOT EXISTS listing_favorites (
+    owner_id TEXT NOT NULL,
+    listing_id UUID NOT NULL,
+    snapshot JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (owner_id, listing_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_listing_favorites_listing ON listing_favorites (listing_id);
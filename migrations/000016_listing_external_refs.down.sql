@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS uq_property_listings_external_ref;
+
+ALTER TABLE property_listings
+    DROP COLUMN IF EXISTS external_ref;
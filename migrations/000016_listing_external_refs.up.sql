@@ -0,0 +1,6 @@
+ALTER TABLE property_listings
+    ADD COLUMN IF NOT EXISTS external_ref TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_property_listings_external_ref
+    ON property_listings (agency_id, external_ref)
+    WHERE external_ref IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS listing_duplicates;
+
+ALTER TABLE listing_media
+    DROP COLUMN IF EXISTS phash;
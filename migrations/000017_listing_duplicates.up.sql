@@ -0,0 +1,22 @@
+ALTER TABLE listing_media
+    ADD COLUMN IF NOT EXISTS phash TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS listing_duplicates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    listing_id UUID NOT NULL REFERENCES property_listings(id) ON DELETE CASCADE,
+    duplicate_id UUID NOT NULL REFERENCES property_listings(id) ON DELETE CASCADE,
+    score DOUBLE PRECISION NOT NULL,
+    reasons TEXT[] NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'merged', 'distinct')),
+    resolved_by TEXT NOT NULL DEFAULT '',
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (listing_id <> duplicate_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_listing_duplicates_pair
+    ON listing_duplicates (LEAST(listing_id, duplicate_id), GREATEST(listing_id, duplicate_id));
+
+CREATE INDEX IF NOT EXISTS idx_listing_duplicates_duplicate
+    ON listing_duplicates (duplicate_id, status);
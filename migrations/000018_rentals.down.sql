@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS rental_bookings;
+DROP TABLE IF EXISTS rental_blocks;
+
+DROP INDEX IF EXISTS idx_property_listings_tenure;
+
+ALTER TABLE property_listings
+    DROP COLUMN IF EXISTS deposit,
+    DROP COLUMN IF EXISTS min_stay,
+    DROP COLUMN IF EXISTS rent_period,
+    DROP COLUMN IF EXISTS tenure;
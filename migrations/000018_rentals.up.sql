@@ -0,0 +1,47 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+ALTER TABLE property_listings
+    ADD COLUMN IF NOT EXISTS tenure TEXT NOT NULL DEFAULT 'sale' CHECK (tenure IN ('sale', 'rent')),
+    ADD COLUMN IF NOT EXISTS rent_period TEXT CHECK (rent_period IN ('night', 'week', 'month', 'year')),
+    ADD COLUMN IF NOT EXISTS min_stay INTEGER,
+    ADD COLUMN IF NOT EXISTS deposit NUMERIC(14, 2);
+
+CREATE INDEX IF NOT EXISTS idx_property_listings_tenure ON property_listings (tenure);
+
+CREATE TABLE IF NOT EXISTS rental_blocks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    listing_id UUID NOT NULL REFERENCES property_listings(id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (end_date > start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rental_blocks_listing ON rental_blocks (listing_id, start_date);
+
+CREATE TABLE IF NOT EXISTS rental_bookings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    listing_id UUID NOT NULL REFERENCES property_listings(id) ON DELETE CASCADE,
+    requester_id TEXT NOT NULL,
+    requester_email TEXT NOT NULL,
+    requester_name TEXT NOT NULL DEFAULT '',
+    check_in DATE NOT NULL,
+    check_out DATE NOT NULL,
+    guests INTEGER NOT NULL DEFAULT 1,
+    message TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'cancelled')),
+    decided_by TEXT NOT NULL DEFAULT '',
+    decided_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (check_out > check_in),
+    CONSTRAINT ex_rental_bookings_accepted_overlap EXCLUDE USING gist (
+        listing_id WITH =,
+        daterange(check_in, check_out) WITH &&
+    ) WHERE (status = 'accepted')
+);
+
+CREATE INDEX IF NOT EXISTS idx_rental_bookings_listing ON rental_bookings (listing_id, check_in);
+CREATE INDEX IF NOT EXISTS idx_rental_bookings_requester ON rental_bookings (requester_id, created_at DESC);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS viewing_appointments;
+DROP TABLE IF EXISTS viewing_slots;
+ALTER TABLE realtors DROP COLUMN IF EXISTS time_zone;
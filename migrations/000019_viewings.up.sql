@@ -0,0 +1,55 @@
+ALTER TABLE realtors ADD COLUMN IF NOT EXISTS time_zone TEXT NOT NULL DEFAULT 'UTC';
+
+UPDATE realtors SET time_zone = v.zone
+FROM (VALUES
+    ('layla@shanraq.com', 'Asia/Dubai'),
+    ('karl@nordicskyline.com', 'Europe/Stockholm'),
+    ('maya@pacificaurban.com', 'America/Los_Angeles'),
+    ('giulia@atlasheritage.it', 'Europe/Rome'),
+    ('diego@pacificaurban.com', 'America/New_York')
+) AS v(email, zone)
+WHERE realtors.email = v.email;
+
+CREATE TABLE IF NOT EXISTS viewing_slots (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    realtor_id UUID NOT NULL REFERENCES realtors(id) ON DELETE CASCADE,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    time_zone TEXT NOT NULL DEFAULT 'UTC',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_viewing_slots_range CHECK (ends_at > starts_at),
+    CONSTRAINT ex_viewing_slots_overlap EXCLUDE USING gist (
+        realtor_id WITH =,
+        tstzrange(starts_at, ends_at) WITH &&
+    )
+);
+
+CREATE TABLE IF NOT EXISTS viewing_appointments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    slot_id UUID REFERENCES viewing_slots(id) ON DELETE SET NULL,
+    realtor_id UUID NOT NULL REFERENCES realtors(id) ON DELETE CASCADE,
+    listing_id UUID REFERENCES property_listings(id) ON DELETE SET NULL,
+    requester_id TEXT NOT NULL,
+    requester_email TEXT NOT NULL,
+    requester_name TEXT NOT NULL DEFAULT '',
+    requester_phone TEXT NOT NULL DEFAULT '',
+    note TEXT NOT NULL DEFAULT '',
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    time_zone TEXT NOT NULL DEFAULT 'UTC',
+    status TEXT NOT NULL DEFAULT 'scheduled' CHECK (status IN ('scheduled', 'cancelled')),
+    cancelled_by TEXT NOT NULL DEFAULT '',
+    cancel_reason TEXT NOT NULL DEFAULT '',
+    cancelled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT ex_viewing_appointments_requester_overlap EXCLUDE USING gist (
+        requester_id WITH =,
+        tstzrange(starts_at, ends_at) WITH &&
+    ) WHERE (status = 'scheduled')
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_viewing_appointments_scheduled_slot
+    ON viewing_appointments (slot_id) WHERE status = 'scheduled';
+CREATE INDEX IF NOT EXISTS idx_viewing_appointments_realtor ON viewing_appointments (realtor_id, starts_at);
+CREATE INDEX IF NOT EXISTS idx_viewing_appointments_requester ON viewing_appointments (requester_id, starts_at);
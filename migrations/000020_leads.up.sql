@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS listing_leads (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    listing_id UUID REFERENCES property_listings(id) ON DELETE SET NULL,
+    agency_id UUID NOT NULL REFERENCES real_estate_agencies(id) ON DELETE CASCADE,
+    listing_title TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    phone TEXT NOT NULL DEFAULT '',
+    message TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'new' CHECK (status IN ('new', 'contacted', 'qualified', 'lost', 'won')),
+    note TEXT NOT NULL DEFAULT '',
+    updated_by TEXT NOT NULL DEFAULT '',
+    requester_id TEXT NOT NULL DEFAULT '',
+    source_ip TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_listing_leads_agency ON listing_leads (agency_id, status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_listing_leads_listing ON listing_leads (listing_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS offer_events;
+DROP FUNCTION IF EXISTS offer_events_immutable();
+DROP TABLE IF EXISTS offers;
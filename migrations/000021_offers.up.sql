@@ -0,0 +1,54 @@
+-- Offers hold the terms currently on the table; offer_events is the append-only
+-- negotiation history and refuses updates and deletes.
+CREATE TABLE IF NOT EXISTS offers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    listing_id UUID REFERENCES property_listings(id) ON DELETE SET NULL,
+    agency_id UUID REFERENCES real_estate_agencies(id) ON DELETE SET NULL,
+    buyer_id TEXT NOT NULL,
+    buyer_email TEXT NOT NULL,
+    buyer_name TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'submitted'
+        CHECK (status IN ('submitted', 'countered', 'accepted', 'rejected', 'withdrawn', 'expired')),
+    awaiting TEXT NOT NULL DEFAULT 'agency' CHECK (awaiting IN ('', 'buyer', 'agency')),
+    amount NUMERIC(20,2) NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    conditions TEXT[] NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_offers_accepted ON offers (listing_id) WHERE status = 'accepted';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_offers_open_buyer ON offers (listing_id, buyer_id)
+    WHERE status IN ('submitted', 'countered');
+CREATE INDEX IF NOT EXISTS idx_offers_agency ON offers (agency_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_offers_buyer ON offers (buyer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_offers_open_expiry ON offers (expires_at) WHERE status IN ('submitted', 'countered');
+
+CREATE TABLE IF NOT EXISTS offer_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    offer_id UUID NOT NULL REFERENCES offers(id) ON DELETE RESTRICT,
+    sequence INT NOT NULL,
+    action TEXT NOT NULL
+        CHECK (action IN ('submitted', 'countered', 'accepted', 'rejected', 'withdrawn', 'expired')),
+    party TEXT NOT NULL DEFAULT '' CHECK (party IN ('', 'buyer', 'agency')),
+    actor_email TEXT NOT NULL DEFAULT '',
+    amount NUMERIC(20,2) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    conditions TEXT[] NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMPTZ NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (offer_id, sequence)
+);
+
+CREATE OR REPLACE FUNCTION offer_events_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'offer history is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS offer_events_immutable ON offer_events;
+CREATE TRIGGER offer_events_immutable
+    BEFORE UPDATE OR DELETE ON offer_events
+    FOR EACH ROW EXECUTE FUNCTION offer_events_immutable();
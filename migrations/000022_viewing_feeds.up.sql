@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS viewing_feeds (
+    realtor_id UUID PRIMARY KEY REFERENCES realtors(id) ON DELETE CASCADE,
+    generation INTEGER NOT NULL DEFAULT 0,
+    rotated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
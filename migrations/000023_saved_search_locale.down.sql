@@ -0,0 +1,3 @@
+ALTER TABLE saved_searches
+    DROP COLUMN IF EXISTS region,
+    DROP COLUMN IF EXISTS locale;
@@ -0,0 +1,3 @@
+ALTER TABLE saved_searches
+    ADD COLUMN IF NOT EXISTS locale TEXT NOT NULL DEFAULT 'en',
+    ADD COLUMN IF NOT EXISTS region TEXT NOT NULL DEFAULT '';